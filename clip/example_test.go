@@ -4,7 +4,7 @@ import (
 	"fmt"
 
 	"github.com/dadadamarine/orb"
-	"github.com/paulmach/orb/clip"
+	"github.com/dadadamarine/orb/clip"
 )
 
 func ExampleGeometry() {
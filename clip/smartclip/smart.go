@@ -7,7 +7,7 @@ import (
 	"sort"
 
 	"github.com/dadadamarine/orb"
-	"github.com/paulmach/orb/clip"
+	"github.com/dadadamarine/orb/clip"
 )
 
 // Geometry will do a smart more involved clipping and wrapping of the geometry.
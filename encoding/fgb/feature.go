@@ -0,0 +1,563 @@
+package fgb
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+)
+
+// geometryTypeOf returns the type of the geometry, Unknown if not supported.
+func geometryTypeOf(g orb.Geometry) GeometryType {
+	switch g.(type) {
+	case orb.Point:
+		return Point
+	case orb.MultiPoint:
+		return MultiPoint
+	case orb.LineString:
+		return LineString
+	case orb.MultiLineString:
+		return MultiLineString
+	case orb.Ring, orb.Polygon, orb.Bound:
+		return Polygon
+	case orb.MultiPolygon:
+		return MultiPolygon
+	case orb.Collection:
+		return GeometryCollection
+	}
+
+	return Unknown
+}
+
+// encodeGeometry returns the flatbuffer table of the geometry.
+func encodeGeometry(g orb.Geometry) (*fbNode, error) {
+	t := newTable(geometryFields)
+
+	typ := geometryTypeOf(g)
+	if typ == Unknown {
+		return nil, fmt.Errorf("fgb: geometry type not supported: %T", g)
+	}
+	t.setUint8(geometryType, uint8(typ), 0)
+
+	switch g := g.(type) {
+	case orb.Point:
+		t.setFloat64s(geometryXY, []float64{g[0], g[1]})
+	case orb.MultiPoint:
+		t.setFloat64s(geometryXY, flatten(g))
+	case orb.LineString:
+		t.setFloat64s(geometryXY, flatten(g))
+	case orb.MultiLineString:
+		parts := make([][]orb.Point, 0, len(g))
+		for _, ls := range g {
+			parts = append(parts, ls)
+		}
+		setParts(t, parts)
+	case orb.Ring:
+		setParts(t, [][]orb.Point{g})
+	case orb.Bound:
+		setParts(t, [][]orb.Point{g.ToRing()})
+	case orb.Polygon:
+		parts := make([][]orb.Point, 0, len(g))
+		for _, r := range g {
+			parts = append(parts, r)
+		}
+		setParts(t, parts)
+	case orb.MultiPolygon:
+		polygons := make([]*fbNode, 0, len(g))
+		for _, p := range g {
+			n, err := encodeGeometry(p)
+			if err != nil {
+				return nil, err
+			}
+
+			polygons = append(polygons, n)
+		}
+		t.setTables(geometryParts, polygons)
+	case orb.Collection:
+		geoms := make([]*fbNode, 0, len(g))
+		for _, c := range g {
+			n, err := encodeGeometry(c)
+			if err != nil {
+				return nil, err
+			}
+
+			geoms = append(geoms, n)
+		}
+		t.setTables(geometryParts, geoms)
+	}
+
+	return t, nil
+}
+
+// setParts sets the coordinates of the parts and, if more than one,
+// the ends of each part as the number of points up to its end.
+func setParts(t *fbNode, parts [][]orb.Point) {
+	var (
+		xy   []float64
+		ends []uint32
+	)
+
+	for _, p := range parts {
+		xy = append(xy, flatten(p)...)
+		ends = append(ends, uint32(len(xy)/2))
+	}
+
+	t.setFloat64s(geometryXY, xy)
+	if len(parts) > 1 {
+		t.setUint32s(geometryEnds, ends)
+	}
+}
+
+func flatten(points []orb.Point) []float64 {
+	xy := make([]float64, 0, 2*len(points))
+	for _, p := range points {
+		xy = append(xy, p[0], p[1])
+	}
+
+	return xy
+}
+
+// decodeGeometry decodes the geometry table, the type of the geometry
+// is used if set, otherwise the type from the header.
+func decodeGeometry(t fbTable, typ GeometryType) (orb.Geometry, error) {
+	if gt := GeometryType(t.uint8(geometryType, 0)); gt != Unknown {
+		typ = gt
+	}
+
+	switch typ {
+	case Point:
+		xy := t.float64s(geometryXY)
+		if len(xy) < 2 {
+			return nil, nil
+		}
+
+		return orb.Point{xy[0], xy[1]}, nil
+	case MultiPoint:
+		return orb.MultiPoint(points(t.float64s(geometryXY))), nil
+	case LineString:
+		return orb.LineString(points(t.float64s(geometryXY))), nil
+	case MultiLineString:
+		parts, err := splitParts(t)
+		if err != nil {
+			return nil, err
+		}
+
+		mls := make(orb.MultiLineString, 0, len(parts))
+		for _, p := range parts {
+			mls = append(mls, orb.LineString(p))
+		}
+
+		return mls, nil
+	case Polygon:
+		parts, err := splitParts(t)
+		if err != nil {
+			return nil, err
+		}
+
+		p := make(orb.Polygon, 0, len(parts))
+		for _, r := range parts {
+			p = append(p, orb.Ring(r))
+		}
+
+		return p, nil
+	case MultiPolygon:
+		parts := t.tables(geometryParts)
+		mp := make(orb.MultiPolygon, 0, len(parts))
+		for _, part := range parts {
+			g, err := decodeGeometry(part, Polygon)
+			if err != nil {
+				return nil, err
+			}
+
+			p, ok := g.(orb.Polygon)
+			if !ok {
+				return nil, fmt.Errorf("fgb: multipolygon part is not a polygon")
+			}
+
+			mp = append(mp, p)
+		}
+
+		return mp, nil
+	case GeometryCollection:
+		parts := t.tables(geometryParts)
+		c := make(orb.Collection, 0, len(parts))
+		for _, part := range parts {
+			g, err := decodeGeometry(part, Unknown)
+			if err != nil {
+				return nil, err
+			}
+
+			if g != nil {
+				c = append(c, g)
+			}
+		}
+
+		return c, nil
+	}
+
+	return nil, fmt.Errorf("fgb: unsupported geometry type: %s", typ)
+}
+
+func points(xy []float64) []orb.Point {
+	ps := make([]orb.Point, len(xy)/2)
+	for i := range ps {
+		ps[i] = orb.Point{xy[2*i], xy[2*i+1]}
+	}
+
+	return ps
+}
+
+// splitParts splits the points by the ends of the parts, one part if no ends.
+func splitParts(t fbTable) ([][]orb.Point, error) {
+	ps := points(t.float64s(geometryXY))
+
+	ends := t.uint32s(geometryEnds)
+	if len(ends) == 0 {
+		if len(ps) == 0 {
+			return nil, nil
+		}
+
+		return [][]orb.Point{ps}, nil
+	}
+
+	parts := make([][]orb.Point, 0, len(ends))
+	start := 0
+	for _, e := range ends {
+		end := int(e)
+		if end < start || end > len(ps) {
+			return nil, fmt.Errorf("fgb: invalid geometry part ends")
+		}
+
+		parts = append(parts, ps[start:end])
+		start = end
+	}
+
+	return parts, nil
+}
+
+// propertyColumns returns the columns for the properties of the
+// features, sorted by name, with the types of the values.
+func propertyColumns(fc *geojson.FeatureCollection) []Column {
+	values := map[string][]interface{}{}
+	nullable := map[string]bool{}
+	for _, f := range fc.Features {
+		for k, v := range f.Properties {
+			values[k] = append(values[k], v)
+			if v == nil {
+				nullable[k] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(values))
+	for k := range values {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	columns := make([]Column, 0, len(names))
+	for _, n := range names {
+		columns = append(columns, Column{
+			Name:     n,
+			Type:     valuesType(values[n]),
+			Nullable: nullable[n] || len(values[n]) < len(fc.Features),
+		})
+	}
+
+	return columns
+}
+
+func valuesType(values []interface{}) ColumnType {
+	types := map[ColumnType]bool{}
+	for _, v := range values {
+		switch v.(type) {
+		case nil:
+		case bool:
+			types[Bool] = true
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32:
+			types[Long] = true
+		case uint64:
+			types[ULong] = true
+		case float32, float64:
+			types[Double] = true
+		case time.Time:
+			types[DateTime] = true
+		case []byte:
+			types[Binary] = true
+		case map[string]interface{}, []interface{}:
+			types[JSON] = true
+		default:
+			types[String] = true
+		}
+	}
+
+	if len(types) == 2 && types[Long] && types[Double] {
+		return Double
+	}
+
+	if len(types) == 1 {
+		for t := range types {
+			return t
+		}
+	}
+
+	// mixed types, or all null
+	return String
+}
+
+// encodeProperties encodes the non nil properties as the column
+// index followed by the value.
+func encodeProperties(columns []Column, props geojson.Properties) ([]byte, error) {
+	var buf []byte
+	for i, c := range columns {
+		v, ok := props[c.Name]
+		if !ok || v == nil {
+			continue
+		}
+
+		var b [8]byte
+		binary.LittleEndian.PutUint16(b[:], uint16(i))
+		buf = append(buf, b[:2]...)
+
+		switch c.Type {
+		case Bool:
+			if v.(bool) {
+				buf = append(buf, 1)
+			} else {
+				buf = append(buf, 0)
+			}
+		case Long, ULong:
+			binary.LittleEndian.PutUint64(b[:], toUint64(v))
+			buf = append(buf, b[:]...)
+		case Double:
+			binary.LittleEndian.PutUint64(b[:], math.Float64bits(toFloat64(v)))
+			buf = append(buf, b[:]...)
+		case DateTime:
+			buf = appendString(buf, v.(time.Time).Format(time.RFC3339Nano))
+		case Binary:
+			buf = appendString(buf, string(v.([]byte)))
+		case JSON:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			buf = appendString(buf, string(data))
+		default:
+			if s, ok := v.(string); ok {
+				buf = appendString(buf, s)
+			} else {
+				buf = appendString(buf, fmt.Sprint(v))
+			}
+		}
+	}
+
+	return buf, nil
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = appendUint32(buf, uint32(len(s)))
+	return append(buf, s...)
+}
+
+func toUint64(v interface{}) uint64 {
+	switch v := v.(type) {
+	case int:
+		return uint64(v)
+	case int8:
+		return uint64(v)
+	case int16:
+		return uint64(v)
+	case int32:
+		return uint64(v)
+	case int64:
+		return uint64(v)
+	case uint:
+		return uint64(v)
+	case uint8:
+		return uint64(v)
+	case uint16:
+		return uint64(v)
+	case uint32:
+		return uint64(v)
+	case uint64:
+		return v
+	}
+
+	return 0
+}
+
+func toFloat64(v interface{}) float64 {
+	switch v := v.(type) {
+	case float32:
+		return float64(v)
+	case float64:
+		return v
+	case uint64:
+		return float64(v)
+	}
+
+	return float64(int64(toUint64(v)))
+}
+
+// decodeProperties decodes the properties of a feature. Integer types are
+// read as int64, except ULong as uint64, and Float and Double as float64.
+func decodeProperties(columns []Column, data []byte) (geojson.Properties, error) {
+	props := geojson.Properties{}
+
+	for len(data) > 0 {
+		if len(data) < 2 {
+			return nil, fmt.Errorf("fgb: invalid properties")
+		}
+
+		i := int(binary.LittleEndian.Uint16(data))
+		if i >= len(columns) {
+			return nil, fmt.Errorf("fgb: invalid property column: %d", i)
+		}
+		data = data[2:]
+
+		c := columns[i]
+		size := columnSize(c.Type)
+		if size == 0 {
+			if len(data) < 4 {
+				return nil, fmt.Errorf("fgb: invalid property %s", c.Name)
+			}
+
+			size = int(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+		}
+
+		if size < 0 || len(data) < size {
+			return nil, fmt.Errorf("fgb: invalid property %s", c.Name)
+		}
+
+		v, err := propertyValue(c, data[:size])
+		if err != nil {
+			return nil, err
+		}
+
+		props[c.Name] = v
+		data = data[size:]
+	}
+
+	return props, nil
+}
+
+// columnSize returns the size of the values of the type, 0 if variable.
+func columnSize(t ColumnType) int {
+	switch t {
+	case Byte, UByte, Bool:
+		return 1
+	case Short, UShort:
+		return 2
+	case Int, UInt, Float:
+		return 4
+	case Long, ULong, Double:
+		return 8
+	}
+
+	return 0
+}
+
+func propertyValue(c Column, b []byte) (interface{}, error) {
+	switch c.Type {
+	case Byte:
+		return int64(int8(b[0])), nil
+	case UByte:
+		return int64(b[0]), nil
+	case Bool:
+		return b[0] != 0, nil
+	case Short:
+		return int64(int16(binary.LittleEndian.Uint16(b))), nil
+	case UShort:
+		return int64(binary.LittleEndian.Uint16(b)), nil
+	case Int:
+		return int64(int32(binary.LittleEndian.Uint32(b))), nil
+	case UInt:
+		return int64(binary.LittleEndian.Uint32(b)), nil
+	case Long:
+		return int64(binary.LittleEndian.Uint64(b)), nil
+	case ULong:
+		return binary.LittleEndian.Uint64(b), nil
+	case Float:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), nil
+	case Double:
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case DateTime:
+		if t, err := time.Parse(time.RFC3339Nano, string(b)); err == nil {
+			return t, nil
+		}
+
+		return string(b), nil
+	case JSON:
+		var v interface{}
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, fmt.Errorf("fgb: invalid json in property %s: %v", c.Name, err)
+		}
+
+		return v, nil
+	case Binary:
+		return append([]byte(nil), b...), nil
+	}
+
+	return string(b), nil
+}
+
+// encodeFeature returns the size prefixed feature flatbuffer.
+func encodeFeature(f *geojson.Feature, columns []Column) ([]byte, error) {
+	t := newTable(featureFields)
+
+	if f.Geometry != nil {
+		g, err := encodeGeometry(f.Geometry)
+		if err != nil {
+			return nil, err
+		}
+
+		t.setTable(featureGeometry, g)
+	}
+
+	props, err := encodeProperties(columns, f.Properties)
+	if err != nil {
+		return nil, err
+	}
+	t.setBytes(featureProperties, props)
+
+	return finishSizePrefixed(t), nil
+}
+
+// decodeFeature decodes the feature flatbuffer, without the size prefix.
+func decodeFeature(buf []byte, h *Header) (*geojson.Feature, error) {
+	f := geojson.NewFeature(nil)
+	err := readRoot(buf, func(t fbTable) error {
+		if g, ok := t.table(featureGeometry); ok {
+			geom, err := decodeGeometry(g, h.GeometryType)
+			if err != nil {
+				return err
+			}
+
+			f.Geometry = geom
+		}
+
+		columns := h.Columns
+		if c := decodeColumns(t.tables(featureColumns)); c != nil {
+			columns = c
+		}
+
+		props, err := decodeProperties(columns, t.bytes(featureProperties))
+		if err != nil {
+			return err
+		}
+
+		f.Properties = props
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
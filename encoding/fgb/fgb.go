@@ -0,0 +1,279 @@
+// Package fgb reads and writes FlatGeobuf, a binary encoding of features
+// with an optional packed Hilbert R-tree index for bounding box queries.
+// Specification at https://flatgeobuf.org
+package fgb
+
+import (
+	"fmt"
+
+	"github.com/dadadamarine/orb"
+)
+
+// magic is the start of every file, "fgb", the major version, "fgb" and the patch version.
+var magic = [8]byte{0x66, 0x67, 0x62, 0x03, 0x66, 0x67, 0x62, 0x00}
+
+const (
+	// maxHeaderSize limits the header so that bad data can't
+	// come in and preallocate tons of memory.
+	maxHeaderSize = 10 * 1024 * 1024
+
+	// DefaultIndexNodeSize is the number of children of the index nodes.
+	DefaultIndexNodeSize = 16
+)
+
+// GeometryType is the type of the feature geometries.
+type GeometryType uint8
+
+// The geometry types. Unknown is used for headers with features of different types.
+// The curve and surface types of the specification are not supported.
+const (
+	Unknown            GeometryType = 0
+	Point              GeometryType = 1
+	LineString         GeometryType = 2
+	Polygon            GeometryType = 3
+	MultiPoint         GeometryType = 4
+	MultiLineString    GeometryType = 5
+	MultiPolygon       GeometryType = 6
+	GeometryCollection GeometryType = 7
+)
+
+func (t GeometryType) String() string {
+	switch t {
+	case Unknown:
+		return "Unknown"
+	case Point:
+		return "Point"
+	case LineString:
+		return "LineString"
+	case Polygon:
+		return "Polygon"
+	case MultiPoint:
+		return "MultiPoint"
+	case MultiLineString:
+		return "MultiLineString"
+	case MultiPolygon:
+		return "MultiPolygon"
+	case GeometryCollection:
+		return "GeometryCollection"
+	}
+
+	return fmt.Sprintf("GeometryType(%d)", uint8(t))
+}
+
+// ColumnType is the type of the values of a column.
+type ColumnType uint8
+
+// The column types.
+const (
+	Byte     ColumnType = 0
+	UByte    ColumnType = 1
+	Bool     ColumnType = 2
+	Short    ColumnType = 3
+	UShort   ColumnType = 4
+	Int      ColumnType = 5
+	UInt     ColumnType = 6
+	Long     ColumnType = 7
+	ULong    ColumnType = 8
+	Float    ColumnType = 9
+	Double   ColumnType = 10
+	String   ColumnType = 11
+	JSON     ColumnType = 12
+	DateTime ColumnType = 13
+	Binary   ColumnType = 14
+)
+
+// A Column describes a feature property.
+type Column struct {
+	Name        string
+	Type        ColumnType
+	Title       string
+	Description string
+	Nullable    bool
+}
+
+// A CRS is the coordinate reference system of the features.
+type CRS struct {
+	// Org is the organization of the code, EPSG if empty.
+	Org  string
+	Code int32
+
+	Name        string
+	Description string
+	WKT         string
+}
+
+// Header describes the features of the file.
+type Header struct {
+	Name        string
+	Title       string
+	Description string
+
+	// Bound is the extent of the features, the zero bound if not set.
+	Bound orb.Bound
+
+	// GeometryType is the type of all the geometries, Unknown if mixed.
+	GeometryType GeometryType
+
+	// HasZ and HasM are set if the geometries have z and m values,
+	// these are dropped when reading.
+	HasZ bool
+	HasM bool
+
+	Columns []Column
+
+	// FeaturesCount is the number of features, 0 if unknown.
+	FeaturesCount uint64
+
+	// IndexNodeSize is the number of children of each index node,
+	// 0 if there is no index.
+	IndexNodeSize uint16
+
+	CRS *CRS
+}
+
+// the field ids of the tables in the schema.
+const (
+	headerName          = 0
+	headerEnvelope      = 1
+	headerGeometryType  = 2
+	headerHasZ          = 3
+	headerHasM          = 4
+	headerColumns       = 7
+	headerFeaturesCount = 8
+	headerIndexNodeSize = 9
+	headerCRS           = 10
+	headerTitle         = 11
+	headerDescription   = 12
+	headerFields        = 14
+
+	columnName        = 0
+	columnType        = 1
+	columnTitle       = 2
+	columnDescription = 3
+	columnNullable    = 7
+	columnFields      = 11
+
+	crsOrg         = 0
+	crsCode        = 1
+	crsName        = 2
+	crsDescription = 3
+	crsWKT         = 4
+	crsFields      = 6
+
+	geometryEnds   = 0
+	geometryXY     = 1
+	geometryType   = 6
+	geometryParts  = 7
+	geometryFields = 8
+
+	featureGeometry   = 0
+	featureProperties = 1
+	featureColumns    = 2
+	featureFields     = 3
+)
+
+func (h *Header) encode() []byte {
+	t := newTable(headerFields)
+	t.setString(headerName, h.Name)
+	if h.Bound != (orb.Bound{}) {
+		t.setFloat64s(headerEnvelope, []float64{h.Bound.Min[0], h.Bound.Min[1], h.Bound.Max[0], h.Bound.Max[1]})
+	}
+	t.setUint8(headerGeometryType, uint8(h.GeometryType), 0)
+	t.setBool(headerHasZ, h.HasZ, false)
+	t.setBool(headerHasM, h.HasM, false)
+	t.setTables(headerColumns, encodeColumns(h.Columns))
+	t.setUint64(headerFeaturesCount, h.FeaturesCount, 0)
+
+	t.setUint16(headerIndexNodeSize, h.IndexNodeSize, DefaultIndexNodeSize)
+
+	if h.CRS != nil {
+		c := newTable(crsFields)
+		c.setString(crsOrg, h.CRS.Org)
+		c.setInt32(crsCode, h.CRS.Code, 0)
+		c.setString(crsName, h.CRS.Name)
+		c.setString(crsDescription, h.CRS.Description)
+		c.setString(crsWKT, h.CRS.WKT)
+		t.setTable(headerCRS, c)
+	}
+
+	t.setString(headerTitle, h.Title)
+	t.setString(headerDescription, h.Description)
+
+	return finishSizePrefixed(t)
+}
+
+func encodeColumns(columns []Column) []*fbNode {
+	nodes := make([]*fbNode, 0, len(columns))
+	for _, c := range columns {
+		t := newTable(columnFields)
+		t.setString(columnName, c.Name)
+		t.setUint8(columnType, uint8(c.Type), 0)
+		t.setString(columnTitle, c.Title)
+		t.setString(columnDescription, c.Description)
+		t.setBool(columnNullable, c.Nullable, true)
+		nodes = append(nodes, t)
+	}
+
+	return nodes
+}
+
+// decodeHeader decodes the header flatbuffer, without the size prefix.
+func decodeHeader(buf []byte) (*Header, error) {
+	h := &Header{}
+	err := readRoot(buf, func(t fbTable) error {
+		h.Name = t.string(headerName)
+		h.Title = t.string(headerTitle)
+		h.Description = t.string(headerDescription)
+
+		if e := t.float64s(headerEnvelope); len(e) >= 4 {
+			h.Bound = orb.Bound{Min: orb.Point{e[0], e[1]}, Max: orb.Point{e[2], e[3]}}
+		}
+
+		h.GeometryType = GeometryType(t.uint8(headerGeometryType, 0))
+		h.HasZ = t.bool(headerHasZ, false)
+		h.HasM = t.bool(headerHasM, false)
+		h.Columns = decodeColumns(t.tables(headerColumns))
+		h.FeaturesCount = t.uint64(headerFeaturesCount, 0)
+		h.IndexNodeSize = t.uint16(headerIndexNodeSize, DefaultIndexNodeSize)
+
+		if c, ok := t.table(headerCRS); ok {
+			h.CRS = &CRS{
+				Org:         c.string(crsOrg),
+				Code:        c.int32(crsCode, 0),
+				Name:        c.string(crsName),
+				Description: c.string(crsDescription),
+				WKT:         c.string(crsWKT),
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if h.IndexNodeSize == 1 {
+		return nil, fmt.Errorf("fgb: invalid index node size: %d", h.IndexNodeSize)
+	}
+
+	return h, nil
+}
+
+func decodeColumns(tables []fbTable) []Column {
+	if len(tables) == 0 {
+		return nil
+	}
+
+	columns := make([]Column, 0, len(tables))
+	for _, t := range tables {
+		columns = append(columns, Column{
+			Name:        t.string(columnName),
+			Type:        ColumnType(t.uint8(columnType, 0)),
+			Title:       t.string(columnTitle),
+			Description: t.string(columnDescription),
+			Nullable:    t.bool(columnNullable, true),
+		})
+	}
+
+	return columns
+}
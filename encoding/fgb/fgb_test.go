@@ -0,0 +1,449 @@
+package fgb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+)
+
+func TestMarshal(t *testing.T) {
+	when := time.Date(2020, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	fc := geojson.NewFeatureCollection()
+
+	f := geojson.NewFeature(orb.Point{1, 2})
+	f.Properties["name"] = "a"
+	f.Properties["count"] = 1
+	f.Properties["score"] = 1
+	f.Properties["ok"] = true
+	f.Properties["when"] = when
+	f.Properties["data"] = []byte{1, 2}
+	fc.Append(f)
+
+	f = geojson.NewFeature(orb.LineString{{1, 2}, {3, 4}})
+	f.Properties["name"] = "b"
+	f.Properties["score"] = 2.5
+	f.Properties["tags"] = []interface{}{"x", "y"}
+	fc.Append(f)
+
+	f = geojson.NewFeature(orb.Polygon{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{2, 2}, {2, 3}, {3, 3}, {2, 2}},
+	})
+	f.Properties["name"] = nil
+	fc.Append(f)
+
+	fc.Append(geojson.NewFeature(orb.MultiPolygon{
+		{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+		{{{5, 5}, {6, 5}, {6, 6}, {5, 5}}, {{5.2, 5.1}, {5.8, 5.1}, {5.8, 5.7}, {5.2, 5.1}}},
+	}))
+	fc.Append(geojson.NewFeature(orb.MultiLineString{{{1, 1}, {2, 2}}, {{3, 3}, {4, 4}, {5, 5}}}))
+	fc.Append(geojson.NewFeature(orb.MultiPoint{{1, 1}, {-2, -2}}))
+	fc.Append(geojson.NewFeature(orb.Collection{orb.Point{1, 2}, orb.LineString{{3, 4}, {5, 6}}}))
+	fc.Append(geojson.NewFeature(nil))
+
+	for _, size := range []uint16{0, 2, 16} {
+		data, err := Marshal(fc, IndexNodeSize(size), Name("test"), EPSG(4326))
+		if err != nil {
+			t.Fatalf("marshal error: %v", err)
+		}
+
+		r, err := NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("new reader error: %v", err)
+		}
+
+		h := r.Header
+		if h.Name != "test" || h.CRS == nil || h.CRS.Org != "EPSG" || h.CRS.Code != 4326 {
+			t.Errorf("incorrect header: %v %v", h.Name, h.CRS)
+		}
+
+		if h.GeometryType != Unknown {
+			t.Errorf("incorrect geometry type: %v", h.GeometryType)
+		}
+
+		if h.FeaturesCount != uint64(len(fc.Features)) || h.IndexNodeSize != size {
+			t.Errorf("incorrect counts: %v %v", h.FeaturesCount, h.IndexNodeSize)
+		}
+
+		expectedBound := orb.Bound{Min: orb.Point{-2, -2}, Max: orb.Point{10, 10}}
+		if !h.Bound.Equal(expectedBound) {
+			t.Errorf("incorrect bound: %v", h.Bound)
+		}
+
+		expectedColumns := []Column{
+			{Name: "count", Type: Long, Nullable: true},
+			{Name: "data", Type: Binary, Nullable: true},
+			{Name: "name", Type: String, Nullable: true},
+			{Name: "ok", Type: Bool, Nullable: true},
+			{Name: "score", Type: Double, Nullable: true},
+			{Name: "tags", Type: JSON, Nullable: true},
+			{Name: "when", Type: DateTime, Nullable: true},
+		}
+		if !reflect.DeepEqual(h.Columns, expectedColumns) {
+			t.Errorf("incorrect columns: %v", h.Columns)
+		}
+
+		result, err := r.ReadAll()
+		if err != nil {
+			t.Fatalf("read all error: %v", err)
+		}
+
+		if len(result.Features) != len(fc.Features) {
+			t.Fatalf("incorrect number of features: %d", len(result.Features))
+		}
+
+		// the features are in hilbert order if there is an index
+		for _, f := range fc.Features {
+			found := false
+			for _, rf := range result.Features {
+				if orb.Equal(f.Geometry, rf.Geometry) || (f.Geometry == nil && rf.Geometry == nil) {
+					found = true
+					compareProperties(t, rf.Properties, f.Properties)
+				}
+			}
+
+			if !found {
+				t.Errorf("feature not found: %v", f.Geometry)
+			}
+		}
+
+		if size == 0 {
+			for i := range fc.Features {
+				if !reflect.DeepEqual(fc.Features[i].Geometry, result.Features[i].Geometry) {
+					t.Errorf("without an index the order should be kept")
+				}
+			}
+		}
+	}
+}
+
+func compareProperties(t testing.TB, got, expected geojson.Properties) {
+	t.Helper()
+
+	exp := geojson.Properties{}
+	for k, v := range expected {
+		switch v := v.(type) {
+		case nil:
+			continue
+		case int:
+			exp[k] = int64(v)
+		default:
+			exp[k] = v
+		}
+	}
+
+	// score is a double column
+	if v, ok := exp["score"].(int64); ok {
+		exp["score"] = float64(v)
+	}
+
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("incorrect properties: %v != %v", got, exp)
+	}
+}
+
+func TestReader_Search(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	fc := geojson.NewFeatureCollection()
+	for i := 0; i < 1000; i++ {
+		x, y := 360*r.Float64()-180, 180*r.Float64()-90
+
+		var g orb.Geometry = orb.Point{x, y}
+		if i%3 == 0 {
+			g = orb.LineString{{x, y}, {x + r.Float64(), y + r.Float64()}}
+		}
+
+		f := geojson.NewFeature(g)
+		f.Properties["i"] = i
+		fc.Append(f)
+	}
+	fc.Append(geojson.NewFeature(nil))
+
+	for _, size := range []uint16{0, 2, 16} {
+		data, err := Marshal(fc, IndexNodeSize(size))
+		if err != nil {
+			t.Fatalf("marshal error: %v", err)
+		}
+
+		reader, err := NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("new reader error: %v", err)
+		}
+
+		for i := 0; i < 20; i++ {
+			x, y := 360*r.Float64()-180, 180*r.Float64()-90
+			b := orb.Bound{Min: orb.Point{x, y}, Max: orb.Point{x + 20*r.Float64(), y + 20*r.Float64()}}
+
+			result, err := reader.Search(b)
+			if err != nil {
+				t.Fatalf("search error: %v", err)
+			}
+
+			expected := map[int64]bool{}
+			for _, f := range fc.Features {
+				if f.Geometry != nil && f.Geometry.Bound().Intersects(b) {
+					expected[int64(f.Properties["i"].(int))] = true
+				}
+			}
+
+			if len(result.Features) != len(expected) {
+				t.Errorf("size %d: incorrect number of features: %d != %d", size, len(result.Features), len(expected))
+			}
+
+			for _, f := range result.Features {
+				if !expected[f.Properties["i"].(int64)] {
+					t.Errorf("size %d: feature should not be found: %v", size, f.Properties["i"])
+				}
+			}
+		}
+	}
+}
+
+func TestReader_streaming(t *testing.T) {
+	// a features count of 0 means unknown, read until the end
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(orb.Point{1, 2}))
+	fc.Append(geojson.NewFeature(orb.Point{3, 4}))
+
+	h := &Header{GeometryType: Point}
+	data := append(magic[:], h.encode()...)
+	for _, f := range fc.Features {
+		b, err := encodeFeature(f, nil)
+		if err != nil {
+			t.Fatalf("encode error: %v", err)
+		}
+
+		data = append(data, b...)
+	}
+
+	result, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if len(result.Features) != 2 || !orb.Equal(result.Features[1].Geometry, orb.Point{3, 4}) {
+		t.Errorf("incorrect features: %v", result.Features)
+	}
+}
+
+func TestNewReader_errors(t *testing.T) {
+	if _, err := NewReader(bytes.NewReader([]byte("not flatgeobuf"))); err != ErrNotFlatGeobuf {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	data, err := Marshal(geojson.NewFeatureCollection())
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	// truncated header
+	if _, err := NewReader(bytes.NewReader(data[:len(data)-4])); err == nil {
+		t.Errorf("should error on truncated header")
+	}
+
+	// corrupt header offsets
+	data[12] = 0xff
+	if _, err := NewReader(bytes.NewReader(data)); err != errInvalidBuffer {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	if _, err := Marshal(geojson.NewFeatureCollection(), IndexNodeSize(1)); err == nil {
+		t.Errorf("should error on index node size 1")
+	}
+}
+
+func TestLevelBounds(t *testing.T) {
+	cases := []struct {
+		items, size int
+		bounds      [][2]int
+		nodes       int
+	}{
+		{items: 1, size: 16, bounds: [][2]int{{1, 2}, {0, 1}}, nodes: 2},
+		{items: 16, size: 16, bounds: [][2]int{{1, 17}, {0, 1}}, nodes: 17},
+		{items: 17, size: 16, bounds: [][2]int{{3, 20}, {1, 3}, {0, 1}}, nodes: 20},
+		{items: 5, size: 2, bounds: [][2]int{{6, 11}, {3, 6}, {1, 3}, {0, 1}}, nodes: 11},
+	}
+
+	for _, tc := range cases {
+		bounds, nodes := levelBounds(tc.items, tc.size)
+		if nodes != tc.nodes || !reflect.DeepEqual(bounds, tc.bounds) {
+			t.Errorf("%d/%d: incorrect bounds: %v %v", tc.items, tc.size, bounds, nodes)
+		}
+	}
+}
+
+func TestHilbert(t *testing.T) {
+	// the curve starts at the origin and ends at the max x
+	if v := hilbert(0, 0); v != 0 {
+		t.Errorf("incorrect value: %v", v)
+	}
+
+	if v := hilbert(hilbertMax, 0); v != 1<<32-1 {
+		t.Errorf("incorrect value: %v", v)
+	}
+
+	// consecutive values are adjacent cells on a 4x4 grid
+	cells := map[uint32][2]int{}
+	for x := uint32(0); x < 4; x++ {
+		for y := uint32(0); y < 4; y++ {
+			cells[hilbert(x<<14, y<<14)>>28] = [2]int{int(x), int(y)}
+		}
+	}
+
+	if len(cells) != 16 {
+		t.Fatalf("cells should have different values: %v", cells)
+	}
+
+	for i := uint32(1); i < 16; i++ {
+		a, b := cells[i-1], cells[i]
+		if d := abs(a[0]-b[0]) + abs(a[1]-b[1]); d != 1 {
+			t.Errorf("cells %d and %d are not adjacent: %v %v", i-1, i, a, b)
+		}
+	}
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+
+	return i
+}
+
+// The testdata files are written with the official flatbuffers builder,
+// see testdata/README.md.
+var fixtureNames = []string{
+	"Lisbon", "Porto", "Madrid", "Seville", "Paris",
+	"Lyon", "Andorra la Vella", "Bern", "Zürich", "Brussels",
+}
+
+func TestFixtures(t *testing.T) {
+	cases := []struct {
+		file string
+		size uint16
+	}{
+		{file: "places.fgb", size: 4},
+		{file: "places_noindex.fgb", size: 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.file, func(t *testing.T) {
+			data, err := ioutil.ReadFile(filepath.Join("testdata", tc.file))
+			if err != nil {
+				t.Fatalf("read error: %v", err)
+			}
+
+			r, err := NewReader(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("new reader error: %v", err)
+			}
+
+			expected := &Header{
+				Name:         "places",
+				Bound:        orb.Bound{Min: orb.Point{-9.1393, 37.3891}, Max: orb.Point{8.5417, 50.8503}},
+				GeometryType: Point,
+				Columns: []Column{
+					{Name: "capital", Type: Bool},
+					{Name: "name", Type: String},
+					{Name: "population", Type: Long, Nullable: true},
+				},
+				FeaturesCount: 10,
+				IndexNodeSize: tc.size,
+				CRS:           &CRS{Org: "EPSG", Code: 4326},
+			}
+			if !reflect.DeepEqual(r.Header, expected) {
+				t.Errorf("incorrect header: %+v", r.Header)
+			}
+
+			// the size prefixed header follows the magic bytes
+			header := r.Header.encode()
+			if !bytes.Equal(header, data[8:8+len(header)]) {
+				t.Errorf("incorrect header encoding")
+				t.Logf("%x", header)
+				t.Logf("%x", data[8:8+len(header)])
+			}
+
+			all, err := r.ReadAll()
+			if err != nil {
+				t.Fatalf("read all error: %v", err)
+			}
+
+			byName := map[string]*geojson.Feature{}
+			for _, f := range all.Features {
+				byName[f.Properties.MustString("name")] = f
+			}
+
+			if f := byName["Zürich"]; !orb.Equal(f.Geometry, orb.Point{8.5417, 47.3769}) ||
+				f.Properties["population"] != int64(421878) || f.Properties["capital"] != false {
+				t.Errorf("incorrect feature: %v %v", f.Geometry, f.Properties)
+			}
+
+			if p := byName["Andorra la Vella"].Properties; p["capital"] != true || p["population"] != nil {
+				t.Errorf("incorrect properties: %v", p)
+			}
+
+			// writing the features in the original order gives the same file
+			fc := geojson.NewFeatureCollection()
+			for _, n := range fixtureNames {
+				fc.Append(byName[n])
+			}
+
+			out, err := Marshal(fc, Name("places"), EPSG(4326), IndexNodeSize(tc.size))
+			if err != nil {
+				t.Fatalf("marshal error: %v", err)
+			}
+
+			if !bytes.Equal(out, data) {
+				t.Errorf("marshal should match the fixture")
+			}
+
+			searches := []struct {
+				bound    orb.Bound
+				expected []string
+			}{
+				{
+					bound:    orb.Bound{Min: orb.Point{-10, 36}, Max: orb.Point{0, 42}},
+					expected: []string{"Lisbon", "Madrid", "Porto", "Seville"},
+				},
+				{
+					bound:    orb.Bound{Min: orb.Point{6, 45}, Max: orb.Point{10, 48}},
+					expected: []string{"Bern", "Zürich"},
+				},
+				{
+					bound:    orb.Bound{Min: orb.Point{1.5218, 42.5063}, Max: orb.Point{1.5218, 42.5063}},
+					expected: []string{"Andorra la Vella"},
+				},
+				{
+					bound: orb.Bound{Min: orb.Point{-30, 0}, Max: orb.Point{-20, 10}},
+				},
+			}
+
+			for _, s := range searches {
+				result, err := r.Search(s.bound)
+				if err != nil {
+					t.Fatalf("search error: %v", err)
+				}
+
+				var names []string
+				for _, f := range result.Features {
+					names = append(names, f.Properties.MustString("name"))
+				}
+				sort.Strings(names)
+
+				if !reflect.DeepEqual(names, s.expected) {
+					t.Errorf("incorrect features for %v: %v", s.bound, names)
+				}
+			}
+		})
+	}
+}
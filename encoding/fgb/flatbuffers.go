@@ -0,0 +1,507 @@
+package fgb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// This file has a minimal implementation of the FlatBuffers binary format,
+// enough to read and write the FlatGeobuf header and feature tables.
+// See https://flatbuffers.dev/md__internals.html
+
+// errInvalidBuffer is returned when the flatbuffer offsets are out of range.
+var errInvalidBuffer = errors.New("fgb: invalid flatbuffer")
+
+// fbNode is a part of a flatbuffer being built: a table, a vector of
+// scalars, a string or a vector of tables.
+type fbNode struct {
+	// fields of a table, indexed by the field id, nil if not set.
+	fields []*fbField
+
+	// data of a vector of scalars or a string.
+	data     []byte
+	count    int
+	elemSize int
+	str      bool
+
+	// tables of a vector of tables.
+	tables []*fbNode
+	isVec  bool
+}
+
+// fbField is a field of a table, either a scalar or a reference to another node.
+type fbField struct {
+	scalar []byte
+	child  *fbNode
+}
+
+func newTable(numFields int) *fbNode {
+	return &fbNode{fields: make([]*fbField, numFields)}
+}
+
+// The scalar setters skip values equal to the schema default,
+// as readers use the default for fields that are not set.
+
+func (n *fbNode) setUint8(id int, v, def uint8) {
+	if v != def {
+		n.fields[id] = &fbField{scalar: []byte{v}}
+	}
+}
+
+func (n *fbNode) setBool(id int, v, def bool) {
+	var b, d uint8
+	if v {
+		b = 1
+	}
+	if def {
+		d = 1
+	}
+	n.setUint8(id, b, d)
+}
+
+func (n *fbNode) setUint16(id int, v, def uint16) {
+	if v != def {
+		b := make([]byte, 2)
+		binary.LittleEndian.PutUint16(b, v)
+		n.fields[id] = &fbField{scalar: b}
+	}
+}
+
+func (n *fbNode) setInt32(id int, v, def int32) {
+	if v != def {
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint32(b, uint32(v))
+		n.fields[id] = &fbField{scalar: b}
+	}
+}
+
+func (n *fbNode) setUint64(id int, v, def uint64) {
+	if v != def {
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, v)
+		n.fields[id] = &fbField{scalar: b}
+	}
+}
+
+func (n *fbNode) setString(id int, s string) {
+	if s != "" {
+		n.fields[id] = &fbField{child: &fbNode{data: []byte(s), count: len(s), elemSize: 1, str: true}}
+	}
+}
+
+func (n *fbNode) setBytes(id int, b []byte) {
+	if len(b) > 0 {
+		n.fields[id] = &fbField{child: &fbNode{data: b, count: len(b), elemSize: 1}}
+	}
+}
+
+func (n *fbNode) setFloat64s(id int, vs []float64) {
+	if len(vs) == 0 {
+		return
+	}
+
+	b := make([]byte, 8*len(vs))
+	for i, v := range vs {
+		binary.LittleEndian.PutUint64(b[8*i:], math.Float64bits(v))
+	}
+	n.fields[id] = &fbField{child: &fbNode{data: b, count: len(vs), elemSize: 8}}
+}
+
+func (n *fbNode) setUint32s(id int, vs []uint32) {
+	if len(vs) == 0 {
+		return
+	}
+
+	b := make([]byte, 4*len(vs))
+	for i, v := range vs {
+		binary.LittleEndian.PutUint32(b[4*i:], v)
+	}
+	n.fields[id] = &fbField{child: &fbNode{data: b, count: len(vs), elemSize: 4}}
+}
+
+func (n *fbNode) setTable(id int, t *fbNode) {
+	if t != nil {
+		n.fields[id] = &fbField{child: t}
+	}
+}
+
+func (n *fbNode) setTables(id int, ts []*fbNode) {
+	if len(ts) > 0 {
+		n.fields[id] = &fbField{child: &fbNode{tables: ts, isVec: true}}
+	}
+}
+
+// fbBuilder writes the nodes back to front, like the flatbuffers libraries:
+// the referenced nodes first, in field order, then the table with its
+// fields ordered as flatc's generated Create functions add them, the
+// largest scalars first and then in reverse field order. Identical vtables
+// are shared. This is the layout of the FlatGeobuf writers using flatc.
+type fbBuilder struct {
+	// buf[head:] is the data written so far.
+	buf  []byte
+	head int
+
+	minAlign int
+
+	// vtables are the offsets of the written vtables.
+	vtables []int
+}
+
+// finishSizePrefixed returns the buffer of the root table
+// prefixed with its size as a uint32.
+func finishSizePrefixed(root *fbNode) []byte {
+	b := &fbBuilder{buf: make([]byte, 256), head: 256, minAlign: 1}
+
+	table := b.write(root)
+	b.prep(b.minAlign, 8)
+	b.prependOffset(table)
+	b.placeUint32(uint32(b.offset()))
+
+	return b.buf[b.head:]
+}
+
+// offset is the position from the end of the buffer, which does not
+// change as the buffer grows. Nodes are referenced by their offset.
+func (b *fbBuilder) offset() int {
+	return len(b.buf) - b.head
+}
+
+// prep pads the buffer so that the size bytes written after
+// the additional bytes are aligned to the size.
+func (b *fbBuilder) prep(size, additional int) {
+	if size > b.minAlign {
+		b.minAlign = size
+	}
+
+	pad := -(b.offset() + additional) & (size - 1)
+	b.grow(pad + size + additional)
+
+	// the new bytes are zero
+	b.head -= pad
+}
+
+// grow makes room for at least n more bytes.
+func (b *fbBuilder) grow(n int) {
+	for b.head < n {
+		buf := make([]byte, 2*len(b.buf))
+		head := len(buf) - b.offset()
+		copy(buf[head:], b.buf[b.head:])
+		b.buf, b.head = buf, head
+	}
+}
+
+func (b *fbBuilder) place(data []byte) {
+	b.head -= len(data)
+	copy(b.buf[b.head:], data)
+}
+
+func (b *fbBuilder) placeUint32(v uint32) {
+	b.head -= 4
+	binary.LittleEndian.PutUint32(b.buf[b.head:], v)
+}
+
+func (b *fbBuilder) placeUint16(v uint16) {
+	b.head -= 2
+	binary.LittleEndian.PutUint16(b.buf[b.head:], v)
+}
+
+// prependOffset writes the uoffset to the node at the offset.
+func (b *fbBuilder) prependOffset(off int) {
+	b.prep(4, 0)
+	b.placeUint32(uint32(b.offset() - off + 4))
+}
+
+func (b *fbBuilder) write(n *fbNode) int {
+	switch {
+	case n.isVec:
+		return b.writeTables(n)
+	case n.fields != nil:
+		return b.writeTable(n)
+	}
+
+	return b.writeVector(n)
+}
+
+func (b *fbBuilder) writeVector(n *fbNode) int {
+	size := len(n.data)
+	if n.str {
+		size++
+	}
+
+	b.prep(4, size)
+	b.prep(n.elemSize, size)
+	if n.str {
+		b.place([]byte{0})
+	}
+
+	b.place(n.data)
+	b.placeUint32(uint32(n.count))
+
+	return b.offset()
+}
+
+func (b *fbBuilder) writeTables(n *fbNode) int {
+	offsets := make([]int, len(n.tables))
+	for i, t := range n.tables {
+		offsets[i] = b.write(t)
+	}
+
+	b.prep(4, 4*len(offsets))
+	for i := len(offsets) - 1; i >= 0; i-- {
+		b.prependOffset(offsets[i])
+	}
+	b.placeUint32(uint32(len(offsets)))
+
+	return b.offset()
+}
+
+func (b *fbBuilder) writeTable(n *fbNode) int {
+	numFields := len(n.fields)
+	for numFields > 0 && n.fields[numFields-1] == nil {
+		numFields--
+	}
+
+	children := make([]int, numFields)
+	for i := 0; i < numFields; i++ {
+		if f := n.fields[i]; f != nil && f.child != nil {
+			children[i] = b.write(f.child)
+		}
+	}
+
+	// the offsets of the fields from the end, 0 if not set
+	start := b.offset()
+	fields := make([]int, numFields)
+	for _, size := range []int{8, 4, 2, 1} {
+		for i := numFields - 1; i >= 0; i-- {
+			f := n.fields[i]
+			if f == nil {
+				continue
+			}
+
+			s := len(f.scalar)
+			if f.child != nil {
+				s = 4
+			}
+
+			if s != size {
+				continue
+			}
+
+			if f.child != nil {
+				b.prependOffset(children[i])
+			} else {
+				b.prep(size, 0)
+				b.place(f.scalar)
+			}
+
+			fields[i] = b.offset()
+		}
+	}
+
+	// the offset to the vtable is set below
+	b.prep(4, 0)
+	b.placeUint32(0)
+	table := b.offset()
+
+	vtable := make([]byte, 4+2*numFields)
+	binary.LittleEndian.PutUint16(vtable, uint16(len(vtable)))
+	binary.LittleEndian.PutUint16(vtable[2:], uint16(table-start))
+	for i, f := range fields {
+		if f != 0 {
+			binary.LittleEndian.PutUint16(vtable[4+2*i:], uint16(table-f))
+		}
+	}
+
+	for _, vt := range b.vtables {
+		p := len(b.buf) - vt
+		if int(binary.LittleEndian.Uint16(b.buf[p:])) == len(vtable) && bytes.Equal(b.buf[p:p+len(vtable)], vtable) {
+			binary.LittleEndian.PutUint32(b.buf[b.head:], uint32(int32(vt-table)))
+			return table
+		}
+	}
+
+	// vtables are 2 byte aligned, after the 4 byte table offset
+	b.grow(len(vtable))
+	b.place(vtable)
+	b.vtables = append(b.vtables, b.offset())
+	binary.LittleEndian.PutUint32(b.buf[len(b.buf)-table:], uint32(b.offset()-table))
+
+	return table
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+// fbTable is a table in a flatbuffer being read.
+// Reads out of range panic with errInvalidBuffer, recovered by readRoot.
+type fbTable struct {
+	buf []byte
+	pos int
+}
+
+// readRoot calls the function with the root table of the buffer,
+// returning errInvalidBuffer if the buffer offsets are invalid.
+func readRoot(buf []byte, f func(t fbTable) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if r != errInvalidBuffer {
+				panic(r)
+			}
+
+			err = errInvalidBuffer
+		}
+	}()
+
+	t := fbTable{buf: buf}
+	t.pos = t.indirect(0)
+	return f(t)
+}
+
+func (t fbTable) check(pos, size int) {
+	if pos < 0 || size < 0 || pos+size > len(t.buf) || pos+size < pos {
+		panic(errInvalidBuffer)
+	}
+}
+
+func (t fbTable) uint16At(pos int) int {
+	t.check(pos, 2)
+	return int(binary.LittleEndian.Uint16(t.buf[pos:]))
+}
+
+func (t fbTable) uint32At(pos int) uint32 {
+	t.check(pos, 4)
+	return binary.LittleEndian.Uint32(t.buf[pos:])
+}
+
+// indirect returns the position referenced by the uoffset at the position.
+func (t fbTable) indirect(pos int) int {
+	return pos + int(t.uint32At(pos))
+}
+
+// field returns the position of the field, -1 if it is not set.
+func (t fbTable) field(id int) int {
+	vt := t.pos - int(int32(t.uint32At(t.pos)))
+	o := 4 + 2*id
+	if o >= t.uint16At(vt) {
+		return -1
+	}
+
+	if off := t.uint16At(vt + o); off != 0 {
+		return t.pos + off
+	}
+
+	return -1
+}
+
+func (t fbTable) uint8(id int, def uint8) uint8 {
+	p := t.field(id)
+	if p < 0 {
+		return def
+	}
+
+	t.check(p, 1)
+	return t.buf[p]
+}
+
+func (t fbTable) bool(id int, def bool) bool {
+	var d uint8
+	if def {
+		d = 1
+	}
+
+	return t.uint8(id, d) != 0
+}
+
+func (t fbTable) uint16(id int, def uint16) uint16 {
+	p := t.field(id)
+	if p < 0 {
+		return def
+	}
+
+	return uint16(t.uint16At(p))
+}
+
+func (t fbTable) int32(id int, def int32) int32 {
+	p := t.field(id)
+	if p < 0 {
+		return def
+	}
+
+	return int32(t.uint32At(p))
+}
+
+func (t fbTable) uint64(id int, def uint64) uint64 {
+	p := t.field(id)
+	if p < 0 {
+		return def
+	}
+
+	t.check(p, 8)
+	return binary.LittleEndian.Uint64(t.buf[p:])
+}
+
+// vector returns the position of the first element and the
+// number of elements, checked to be in range, of the vector field.
+func (t fbTable) vector(id, elemSize int) (int, int) {
+	p := t.field(id)
+	if p < 0 {
+		return 0, 0
+	}
+
+	v := t.indirect(p)
+	n := int(t.uint32At(v))
+	t.check(v+4, n*elemSize)
+
+	return v + 4, n
+}
+
+func (t fbTable) bytes(id int) []byte {
+	p, n := t.vector(id, 1)
+	return t.buf[p : p+n]
+}
+
+func (t fbTable) string(id int) string {
+	return string(t.bytes(id))
+}
+
+func (t fbTable) float64s(id int) []float64 {
+	p, n := t.vector(id, 8)
+	vs := make([]float64, n)
+	for i := range vs {
+		vs[i] = math.Float64frombits(binary.LittleEndian.Uint64(t.buf[p+8*i:]))
+	}
+
+	return vs
+}
+
+func (t fbTable) uint32s(id int) []uint32 {
+	p, n := t.vector(id, 4)
+	vs := make([]uint32, n)
+	for i := range vs {
+		vs[i] = binary.LittleEndian.Uint32(t.buf[p+4*i:])
+	}
+
+	return vs
+}
+
+func (t fbTable) table(id int) (fbTable, bool) {
+	p := t.field(id)
+	if p < 0 {
+		return fbTable{}, false
+	}
+
+	return fbTable{buf: t.buf, pos: t.indirect(p)}, true
+}
+
+func (t fbTable) tables(id int) []fbTable {
+	p, n := t.vector(id, 4)
+	ts := make([]fbTable, n)
+	for i := range ts {
+		ts[i] = fbTable{buf: t.buf, pos: t.indirect(p + 4*i)}
+	}
+
+	return ts
+}
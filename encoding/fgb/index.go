@@ -0,0 +1,286 @@
+package fgb
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/dadadamarine/orb"
+)
+
+// The index is a packed Hilbert R-tree. The features are sorted by the
+// Hilbert value of the center of their bounds, largest first like the
+// reference implementation, and the tree is stored
+// root first, the leaves last. Each node is its bound and an offset,
+// to the feature data for leaves or to the first child node otherwise.
+
+const (
+	nodeSize   = 40
+	hilbertMax = 1<<16 - 1
+)
+
+type node struct {
+	minX, minY, maxX, maxY float64
+	offset                 uint64
+}
+
+// emptyNode has a bound that intersects nothing, used for features without geometry.
+var emptyNode = node{
+	minX: math.Inf(1), minY: math.Inf(1),
+	maxX: math.Inf(-1), maxY: math.Inf(-1),
+}
+
+func boundNode(b orb.Bound) node {
+	return node{minX: b.Min[0], minY: b.Min[1], maxX: b.Max[0], maxY: b.Max[1]}
+}
+
+func (n *node) expand(o node) {
+	n.minX = math.Min(n.minX, o.minX)
+	n.minY = math.Min(n.minY, o.minY)
+	n.maxX = math.Max(n.maxX, o.maxX)
+	n.maxY = math.Max(n.maxY, o.maxY)
+}
+
+func (n node) intersects(b orb.Bound) bool {
+	return n.maxX >= b.Min[0] && n.maxY >= b.Min[1] &&
+		n.minX <= b.Max[0] && n.minY <= b.Max[1]
+}
+
+func (n node) encode(b []byte) {
+	binary.LittleEndian.PutUint64(b[0:], math.Float64bits(n.minX))
+	binary.LittleEndian.PutUint64(b[8:], math.Float64bits(n.minY))
+	binary.LittleEndian.PutUint64(b[16:], math.Float64bits(n.maxX))
+	binary.LittleEndian.PutUint64(b[24:], math.Float64bits(n.maxY))
+	binary.LittleEndian.PutUint64(b[32:], n.offset)
+}
+
+func decodeNode(b []byte) node {
+	return node{
+		minX:   math.Float64frombits(binary.LittleEndian.Uint64(b[0:])),
+		minY:   math.Float64frombits(binary.LittleEndian.Uint64(b[8:])),
+		maxX:   math.Float64frombits(binary.LittleEndian.Uint64(b[16:])),
+		maxY:   math.Float64frombits(binary.LittleEndian.Uint64(b[24:])),
+		offset: binary.LittleEndian.Uint64(b[32:]),
+	}
+}
+
+// levelBounds returns the range of node indexes of each level of the
+// tree, from the leaves to the root, and the total number of nodes.
+func levelBounds(numItems, size int) ([][2]int, int) {
+	if numItems == 0 || size < 2 {
+		return nil, 0
+	}
+
+	// there is always a root above the leaves, even for one item
+	n, numNodes := numItems, numItems
+	levelNumNodes := []int{n}
+	for {
+		n = (n + size - 1) / size
+		numNodes += n
+		levelNumNodes = append(levelNumNodes, n)
+
+		if n == 1 {
+			break
+		}
+	}
+
+	bounds := make([][2]int, 0, len(levelNumNodes))
+	n = numNodes
+	for _, c := range levelNumNodes {
+		bounds = append(bounds, [2]int{n - c, n})
+		n -= c
+	}
+
+	return bounds, numNodes
+}
+
+// indexSize returns the size in bytes of the index.
+func indexSize(numItems uint64, size uint16) int64 {
+	if numItems == 0 || size < 2 {
+		return 0
+	}
+
+	_, numNodes := levelBounds(int(numItems), int(size))
+	return int64(numNodes) * nodeSize
+}
+
+// hilbertSort returns the order of the items by the Hilbert
+// value of their center within the extent, in decreasing order.
+func hilbertSort(items []node, extent node) []int {
+	width := extent.maxX - extent.minX
+	height := extent.maxY - extent.minY
+
+	values := make([]uint32, len(items))
+	for i, n := range items {
+		if n == emptyNode {
+			continue
+		}
+
+		var x, y uint32
+		if width > 0 {
+			x = uint32(math.Floor(hilbertMax * ((n.minX+n.maxX)/2 - extent.minX) / width))
+		}
+
+		if height > 0 {
+			y = uint32(math.Floor(hilbertMax * ((n.minY+n.maxY)/2 - extent.minY) / height))
+		}
+
+		values[i] = hilbert(x, y)
+	}
+
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return values[order[i]] > values[order[j]]
+	})
+
+	return order
+}
+
+// buildIndex returns the encoded tree of the leaf nodes, which must
+// already be sorted and have the offsets of the features set.
+func buildIndex(leaves []node, size int) []byte {
+	bounds, numNodes := levelBounds(len(leaves), size)
+
+	nodes := make([]node, numNodes)
+	copy(nodes[numNodes-len(leaves):], leaves)
+
+	for level := 0; level < len(bounds)-1; level++ {
+		children := bounds[level]
+		parent := bounds[level+1][0]
+
+		for i := children[0]; i < children[1]; i += size {
+			n := emptyNode
+			n.offset = uint64(i)
+
+			end := i + size
+			if end > children[1] {
+				end = children[1]
+			}
+
+			for j := i; j < end; j++ {
+				n.expand(nodes[j])
+			}
+
+			nodes[parent] = n
+			parent++
+		}
+	}
+
+	data := make([]byte, nodeSize*numNodes)
+	for i, n := range nodes {
+		n.encode(data[nodeSize*i:])
+	}
+
+	return data
+}
+
+// searchIndex returns the offsets of the features, relative to the start
+// of the features, whose bounds intersect the bound, in file order.
+// The index nodes are read from r as needed.
+func searchIndex(r io.ReaderAt, offset int64, numItems uint64, size uint16, b orb.Bound) ([]uint64, error) {
+	bounds, numNodes := levelBounds(int(numItems), int(size))
+	if numNodes == 0 {
+		return nil, nil
+	}
+
+	leafStart := numNodes - int(numItems)
+
+	type entry struct {
+		index, level int
+	}
+
+	var results []uint64
+	queue := []entry{{index: 0, level: len(bounds) - 1}}
+	buf := make([]byte, nodeSize*int(size))
+
+	for len(queue) > 0 {
+		e := queue[0]
+		queue = queue[1:]
+
+		end := e.index + int(size)
+		if levelEnd := bounds[e.level][1]; end > levelEnd {
+			end = levelEnd
+		}
+
+		data := buf[:nodeSize*(end-e.index)]
+		if _, err := r.ReadAt(data, offset+int64(e.index)*nodeSize); err != nil {
+			return nil, err
+		}
+
+		for i := e.index; i < end; i++ {
+			n := decodeNode(data[nodeSize*(i-e.index):])
+			if !n.intersects(b) {
+				continue
+			}
+
+			if i >= leafStart {
+				results = append(results, n.offset)
+				continue
+			}
+
+			// the children must be on the level below
+			child := bounds[e.level-1]
+			if n.offset < uint64(child[0]) || n.offset >= uint64(child[1]) {
+				return nil, errInvalidIndex
+			}
+
+			queue = append(queue, entry{index: int(n.offset), level: e.level - 1})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i] < results[j] })
+	return results, nil
+}
+
+// hilbert returns the Hilbert curve value of the 16 bit coordinates.
+// From https://github.com/rawrunprotected/hilbert_curves (public domain).
+func hilbert(x, y uint32) uint32 {
+	a := x ^ y
+	b := 0xFFFF ^ a
+	c := 0xFFFF ^ (x | y)
+	d := x & (y ^ 0xFFFF)
+
+	A := a | (b >> 1)
+	B := (a >> 1) ^ a
+	C := ((c >> 1) ^ (b & (d >> 1))) ^ c
+	D := ((a & (c >> 1)) ^ (d >> 1)) ^ d
+
+	a, b, c, d = A, B, C, D
+	A = (a & (a >> 2)) ^ (b & (b >> 2))
+	B = (a & (b >> 2)) ^ (b & ((a ^ b) >> 2))
+	C ^= (a & (c >> 2)) ^ (b & (d >> 2))
+	D ^= (b & (c >> 2)) ^ ((a ^ b) & (d >> 2))
+
+	a, b, c, d = A, B, C, D
+	A = (a & (a >> 4)) ^ (b & (b >> 4))
+	B = (a & (b >> 4)) ^ (b & ((a ^ b) >> 4))
+	C ^= (a & (c >> 4)) ^ (b & (d >> 4))
+	D ^= (b & (c >> 4)) ^ ((a ^ b) & (d >> 4))
+
+	a, b, c, d = A, B, C, D
+	C ^= (a & (c >> 8)) ^ (b & (d >> 8))
+	D ^= (b & (c >> 8)) ^ ((a ^ b) & (d >> 8))
+
+	a = C ^ (C >> 1)
+	b = D ^ (D >> 1)
+
+	i0 := x ^ y
+	i1 := b | (0xFFFF ^ (i0 | a))
+
+	i0 = (i0 | (i0 << 8)) & 0x00FF00FF
+	i0 = (i0 | (i0 << 4)) & 0x0F0F0F0F
+	i0 = (i0 | (i0 << 2)) & 0x33333333
+	i0 = (i0 | (i0 << 1)) & 0x55555555
+
+	i1 = (i1 | (i1 << 8)) & 0x00FF00FF
+	i1 = (i1 | (i1 << 4)) & 0x0F0F0F0F
+	i1 = (i1 | (i1 << 2)) & 0x33333333
+	i1 = (i1 | (i1 << 1)) & 0x55555555
+
+	return (i1 << 1) | i0
+}
@@ -0,0 +1,188 @@
+package fgb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+)
+
+// maxFeatureSize limits the size of a feature so that bad data
+// can't come in and preallocate tons of memory.
+const maxFeatureSize = 1 << 30
+
+var (
+	// ErrNotFlatGeobuf is returned when the data does not start with the FlatGeobuf magic bytes.
+	ErrNotFlatGeobuf = errors.New("fgb: invalid magic bytes")
+
+	errInvalidIndex = errors.New("fgb: invalid index")
+)
+
+// A Reader reads the features of FlatGeobuf data. Only the parts
+// of the data needed are read so a bounding box search of a large
+// file, or of a file over HTTP with range requests, is efficient.
+type Reader struct {
+	Header *Header
+
+	r              io.ReaderAt
+	indexOffset    int64
+	featuresOffset int64
+}
+
+// NewReader reads the header and returns a reader for the features.
+func NewReader(r io.ReaderAt) (*Reader, error) {
+	var m [12]byte
+	if err := readFull(r, m[:], 0); err != nil {
+		return nil, ErrNotFlatGeobuf
+	}
+
+	// the patch version, the last byte, can be anything
+	if !bytes.Equal(m[:7], magic[:7]) {
+		return nil, ErrNotFlatGeobuf
+	}
+
+	size := int64(binary.LittleEndian.Uint32(m[8:]))
+	if size < 4 || size > maxHeaderSize {
+		return nil, fmt.Errorf("fgb: invalid header size: %d", size)
+	}
+
+	buf := make([]byte, size)
+	if err := readFull(r, buf, 12); err != nil {
+		return nil, fmt.Errorf("fgb: invalid header: %v", err)
+	}
+
+	h, err := decodeHeader(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	indexOffset := 12 + size
+	return &Reader{
+		Header:         h,
+		r:              r,
+		indexOffset:    indexOffset,
+		featuresOffset: indexOffset + indexSize(h.FeaturesCount, h.IndexNodeSize),
+	}, nil
+}
+
+// Unmarshal decodes all the features of the FlatGeobuf data.
+func Unmarshal(data []byte) (*geojson.FeatureCollection, error) {
+	r, err := NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ReadAll()
+}
+
+// ReadAll reads all the features in the order of the file. For files
+// with an index this is the Hilbert order of the writer, not the order
+// of the original collection.
+func (r *Reader) ReadAll() (*geojson.FeatureCollection, error) {
+	fc := geojson.NewFeatureCollection()
+
+	offset := r.featuresOffset
+	for i := uint64(0); r.Header.FeaturesCount == 0 || i < r.Header.FeaturesCount; i++ {
+		f, size, err := r.readFeature(offset)
+		if err == io.EOF && r.Header.FeaturesCount == 0 {
+			// the number of features is not known when streaming
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		fc.Append(f)
+		offset += size
+	}
+
+	return fc, nil
+}
+
+// Search returns the features whose geometry bound intersects the bound,
+// in the order of the file. The index is used if there is one,
+// otherwise all the features are read.
+func (r *Reader) Search(b orb.Bound) (*geojson.FeatureCollection, error) {
+	if r.Header.IndexNodeSize == 0 || r.Header.FeaturesCount == 0 {
+		all, err := r.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+
+		fc := geojson.NewFeatureCollection()
+		for _, f := range all.Features {
+			if f.Geometry != nil && geometryNode(f.Geometry).intersects(b) {
+				fc.Append(f)
+			}
+		}
+
+		return fc, nil
+	}
+
+	offsets, err := searchIndex(r.r, r.indexOffset, r.Header.FeaturesCount, r.Header.IndexNodeSize, b)
+	if err != nil {
+		return nil, err
+	}
+
+	fc := geojson.NewFeatureCollection()
+	for _, o := range offsets {
+		f, _, err := r.readFeature(r.featuresOffset + int64(o))
+		if err != nil {
+			return nil, err
+		}
+
+		fc.Append(f)
+	}
+
+	return fc, nil
+}
+
+// readFeature reads the feature at the offset, returning it and its
+// size including the size prefix. Returns io.EOF if at the end of the data.
+func (r *Reader) readFeature(offset int64) (*geojson.Feature, int64, error) {
+	var s [4]byte
+	if err := readFull(r.r, s[:], offset); err != nil {
+		return nil, 0, err
+	}
+
+	size := int64(binary.LittleEndian.Uint32(s[:]))
+	if size > maxFeatureSize {
+		return nil, 0, fmt.Errorf("fgb: invalid feature size: %d", size)
+	}
+
+	buf := make([]byte, size)
+	if err := readFull(r.r, buf, offset+4); err != nil {
+		return nil, 0, fmt.Errorf("fgb: invalid feature: %v", err)
+	}
+
+	f, err := decodeFeature(buf, r.Header)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return f, 4 + size, nil
+}
+
+// readFull reads len(p) bytes at the offset. Returns io.EOF if no bytes
+// were read and io.ErrUnexpectedEOF if only some.
+func readFull(r io.ReaderAt, p []byte, offset int64) error {
+	n, err := r.ReadAt(p, offset)
+	if n == len(p) {
+		return nil
+	}
+
+	if err == io.EOF && n == 0 {
+		return io.EOF
+	}
+
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
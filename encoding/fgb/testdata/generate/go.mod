@@ -0,0 +1,5 @@
+module github.com/dadadamarine/orb/encoding/fgb/testdata/generate
+
+go 1.15
+
+require github.com/google/flatbuffers v24.3.25+incompatible
@@ -0,0 +1,299 @@
+// Command generate writes the FlatGeobuf test files with the official
+// flatbuffers builder, building the tables like flatc's generated C++ and
+// Rust Create functions: the referenced tables, vectors and strings first
+// in field order, then the fields largest first, in reverse field order.
+// The index is a packed Hilbert R-tree as in the reference implementation.
+//
+//	go run . ..
+package main
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"os"
+	"sort"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+type place struct {
+	name    string
+	x, y    float64
+	pop     int64
+	capital bool
+	hasPop  bool
+}
+
+var places = []place{
+	{"Lisbon", -9.1393, 38.7223, 545923, true, true},
+	{"Porto", -8.6291, 41.1579, 231800, false, true},
+	{"Madrid", -3.7038, 40.4168, 3223334, true, true},
+	{"Seville", -5.9845, 37.3891, 688711, false, true},
+	{"Paris", 2.3522, 48.8566, 2165423, true, true},
+	{"Lyon", 4.8357, 45.764, 516092, false, true},
+	{"Andorra la Vella", 1.5218, 42.5063, 0, true, false},
+	{"Bern", 7.4474, 46.948, 133883, true, true},
+	{"Zürich", 8.5417, 47.3769, 421878, false, true},
+	{"Brussels", 4.3517, 50.8503, 185103, true, true},
+}
+
+// header returns the size prefixed header, the columns are
+// capital Bool(2), name String(11) and population Long(7), nullable.
+func header(indexNodeSize uint16, minX, minY, maxX, maxY float64) []byte {
+	b := flatbuffers.NewBuilder(0)
+
+	// children in field order: name(0), envelope(1), columns(7), crs(10)
+	name := b.CreateString("places")
+
+	b.StartVector(8, 4, 8)
+	for _, v := range []float64{maxY, maxX, minY, minX} {
+		b.PrependFloat64(v)
+	}
+	envelope := b.EndVector(4)
+
+	type col struct {
+		name     string
+		typ      byte
+		nullable bool
+	}
+	var cols []flatbuffers.UOffsetT
+	for _, c := range []col{{"capital", 2, false}, {"name", 11, false}, {"population", 7, true}} {
+		n := b.CreateString(c.name)
+		b.StartObject(11)
+		b.PrependUOffsetTSlot(0, n, 0)
+		b.PrependByteSlot(7, boolByte(c.nullable), 1)
+		b.PrependByteSlot(1, c.typ, 0)
+		cols = append(cols, b.EndObject())
+	}
+	b.StartVector(4, len(cols), 4)
+	for i := len(cols) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(cols[i])
+	}
+	columns := b.EndVector(len(cols))
+
+	org := b.CreateString("EPSG")
+	b.StartObject(6)
+	b.PrependInt32Slot(1, 4326, 0)
+	b.PrependUOffsetTSlot(0, org, 0)
+	crs := b.EndObject()
+
+	b.StartObject(14)
+	b.PrependUint64Slot(8, uint64(len(places)), 0)
+	b.PrependUOffsetTSlot(10, crs, 0)
+	b.PrependUOffsetTSlot(7, columns, 0)
+	b.PrependUOffsetTSlot(1, envelope, 0)
+	b.PrependUOffsetTSlot(0, name, 0)
+	b.PrependUint16Slot(9, indexNodeSize, 16)
+	b.PrependByteSlot(2, 1, 0)
+	h := b.EndObject()
+
+	b.FinishSizePrefixed(h)
+	return b.FinishedBytes()
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	var buf [2]byte
+	binary.LittleEndian.PutUint16(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func boolByte(v bool) byte {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// feature returns the size prefixed feature of the place.
+func feature(p place) []byte {
+	// properties: column index uint16 then the value
+	var props []byte
+	props = appendUint16(props, 0)
+	props = append(props, boolByte(p.capital))
+	props = appendUint16(props, 1)
+	props = appendUint32(props, uint32(len(p.name)))
+	props = append(props, p.name...)
+	if p.hasPop {
+		props = appendUint16(props, 2)
+		props = appendUint64(props, uint64(p.pop))
+	}
+
+	b := flatbuffers.NewBuilder(0)
+
+	// geometry: xy(1), then type(6)
+	b.StartVector(8, 2, 8)
+	b.PrependFloat64(p.y)
+	b.PrependFloat64(p.x)
+	xy := b.EndVector(2)
+
+	b.StartObject(8)
+	b.PrependUOffsetTSlot(1, xy, 0)
+	b.PrependByteSlot(6, 1, 0)
+	g := b.EndObject()
+
+	pv := b.CreateByteVector(props)
+
+	b.StartObject(3)
+	b.PrependUOffsetTSlot(1, pv, 0)
+	b.PrependUOffsetTSlot(0, g, 0)
+	f := b.EndObject()
+
+	b.FinishSizePrefixed(f)
+	return b.FinishedBytes()
+}
+
+// hilbert is the xy to Hilbert index of http://threadlocalmutex.com/?p=126
+func hilbert(x, y uint32) uint32 {
+	a := x ^ y
+	b := 0xFFFF ^ a
+	c := 0xFFFF ^ (x | y)
+	d := x & (y ^ 0xFFFF)
+
+	A := a | (b >> 1)
+	B := (a >> 1) ^ a
+	C := ((c >> 1) ^ (b & (d >> 1))) ^ c
+	D := ((a & (c >> 1)) ^ (d >> 1)) ^ d
+
+	a, b, c, d = A, B, C, D
+	A = (a & (a >> 2)) ^ (b & (b >> 2))
+	B = (a & (b >> 2)) ^ (b & ((a ^ b) >> 2))
+	C ^= (a & (c >> 2)) ^ (b & (d >> 2))
+	D ^= (b & (c >> 2)) ^ ((a ^ b) & (d >> 2))
+
+	a, b, c, d = A, B, C, D
+	A = (a & (a >> 4)) ^ (b & (b >> 4))
+	B = (a & (b >> 4)) ^ (b & ((a ^ b) >> 4))
+	C ^= (a & (c >> 4)) ^ (b & (d >> 4))
+	D ^= (b & (c >> 4)) ^ ((a ^ b) & (d >> 4))
+
+	a, b, c, d = A, B, C, D
+	C ^= (a & (c >> 8)) ^ (b & (d >> 8))
+	D ^= (b & (c >> 8)) ^ ((a ^ b) & (d >> 8))
+
+	a = C ^ (C >> 1)
+	b = D ^ (D >> 1)
+
+	i0 := x ^ y
+	i1 := b | (0xFFFF ^ (i0 | a))
+
+	i0 = (i0 | (i0 << 8)) & 0x00FF00FF
+	i0 = (i0 | (i0 << 4)) & 0x0F0F0F0F
+	i0 = (i0 | (i0 << 2)) & 0x33333333
+	i0 = (i0 | (i0 << 1)) & 0x55555555
+
+	i1 = (i1 | (i1 << 8)) & 0x00FF00FF
+	i1 = (i1 | (i1 << 4)) & 0x0F0F0F0F
+	i1 = (i1 | (i1 << 2)) & 0x33333333
+	i1 = (i1 | (i1 << 1)) & 0x55555555
+
+	return (i1 << 1) | i0
+}
+
+type nodeItem struct {
+	minX, minY, maxX, maxY float64
+	offset                 uint64
+}
+
+// write writes the file, with an index if the node size is not 0.
+func write(path string, indexNodeSize uint16) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range places {
+		minX, minY = math.Min(minX, p.x), math.Min(minY, p.y)
+		maxX, maxY = math.Max(maxX, p.x), math.Max(maxY, p.y)
+	}
+
+	order := make([]int, len(places))
+	for i := range order {
+		order[i] = i
+	}
+
+	var index []byte
+	if indexNodeSize > 0 {
+		const hmax = float64(1<<16 - 1)
+		h := func(p place) uint32 {
+			x := uint32(math.Floor(hmax * (p.x - minX) / (maxX - minX)))
+			y := uint32(math.Floor(hmax * (p.y - minY) / (maxY - minY)))
+			return hilbert(x, y)
+		}
+
+		// descending, as the reference packedrtree hilbertSort
+		sort.Slice(order, func(i, j int) bool { return h(places[order[i]]) > h(places[order[j]]) })
+
+		// level sizes, leaves first
+		n := len(places)
+		sizes := []int{n}
+		total := n
+		for n != 1 {
+			n = (n + int(indexNodeSize) - 1) / int(indexNodeSize)
+			sizes = append(sizes, n)
+			total += n
+		}
+
+		nodes := make([]nodeItem, total)
+		starts := make([]int, len(sizes))
+		end := total
+		for i, s := range sizes {
+			starts[i] = end - s
+			end -= s
+		}
+
+		var offset uint64
+		for i, j := range order {
+			p := places[j]
+			nodes[starts[0]+i] = nodeItem{p.x, p.y, p.x, p.y, offset}
+			offset += uint64(len(feature(p)))
+		}
+
+		for l := 0; l < len(sizes)-1; l++ {
+			pos, end, parent := starts[l], starts[l]+sizes[l], starts[l+1]
+			for pos < end {
+				nd := nodeItem{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1), uint64(pos)}
+				for k := 0; k < int(indexNodeSize) && pos < end; k++ {
+					c := nodes[pos]
+					nd.minX, nd.minY = math.Min(nd.minX, c.minX), math.Min(nd.minY, c.minY)
+					nd.maxX, nd.maxY = math.Max(nd.maxX, c.maxX), math.Max(nd.maxY, c.maxY)
+					pos++
+				}
+				nodes[parent] = nd
+				parent++
+			}
+		}
+
+		for _, nd := range nodes {
+			for _, v := range []float64{nd.minX, nd.minY, nd.maxX, nd.maxY} {
+				index = appendUint64(index, math.Float64bits(v))
+			}
+			index = appendUint64(index, nd.offset)
+		}
+	}
+
+	out := []byte{0x66, 0x67, 0x62, 0x03, 0x66, 0x67, 0x62, 0x00}
+	out = append(out, header(indexNodeSize, minX, minY, maxX, maxY)...)
+	out = append(out, index...)
+	for _, j := range order {
+		out = append(out, feature(places[j])...)
+	}
+
+	if err := ioutil.WriteFile(path, out, 0644); err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	write(os.Args[1]+"/places.fgb", 4)
+	write(os.Args[1]+"/places_noindex.fgb", 0)
+}
@@ -0,0 +1,190 @@
+package fgb
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+)
+
+// Option is a function that configures writing.
+type Option func(*options)
+
+type options struct {
+	name          string
+	description   string
+	crs           *CRS
+	indexNodeSize uint16
+}
+
+// Name sets the name of the dataset in the header.
+func Name(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// Description sets the description of the dataset in the header.
+func Description(desc string) Option {
+	return func(o *options) {
+		o.description = desc
+	}
+}
+
+// EPSG sets the coordinate reference system to the EPSG code.
+func EPSG(code int32) Option {
+	return func(o *options) {
+		if o.crs == nil {
+			o.crs = &CRS{}
+		}
+
+		o.crs.Org = "EPSG"
+		o.crs.Code = code
+	}
+}
+
+// WKT sets the WKT of the coordinate reference system.
+func WKT(wkt string) Option {
+	return func(o *options) {
+		if o.crs == nil {
+			o.crs = &CRS{}
+		}
+
+		o.crs.WKT = wkt
+	}
+}
+
+// IndexNodeSize sets the number of children of each index node,
+// by default 16. Set to 0 to write the features without an index.
+func IndexNodeSize(size uint16) Option {
+	return func(o *options) {
+		o.indexNodeSize = size
+	}
+}
+
+// Marshal encodes the feature collection as FlatGeobuf. With an index,
+// the default, the features are reordered by the Hilbert value of their
+// bounds so the input order is not preserved, see IndexNodeSize(0) to keep it.
+func Marshal(fc *geojson.FeatureCollection, opts ...Option) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := Write(buf, fc, opts...); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Write writes the feature collection as FlatGeobuf. With an index,
+// the default, the features are reordered by the Hilbert value of their
+// bounds so the input order is not preserved, see IndexNodeSize(0) to keep it.
+// Feature ids are not part of the format and are dropped.
+func Write(w io.Writer, fc *geojson.FeatureCollection, opts ...Option) error {
+	o := &options{indexNodeSize: DefaultIndexNodeSize}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.indexNodeSize == 1 {
+		return fmt.Errorf("fgb: invalid index node size: %d", o.indexNodeSize)
+	}
+
+	h := &Header{
+		Name:          o.name,
+		Description:   o.description,
+		GeometryType:  headerGeometryTypeOf(fc),
+		Columns:       propertyColumns(fc),
+		FeaturesCount: uint64(len(fc.Features)),
+		IndexNodeSize: o.indexNodeSize,
+		CRS:           o.crs,
+	}
+
+	features := make([][]byte, 0, len(fc.Features))
+	leaves := make([]node, 0, len(fc.Features))
+	extent := emptyNode
+
+	for _, f := range fc.Features {
+		data, err := encodeFeature(f, h.Columns)
+		if err != nil {
+			return err
+		}
+
+		features = append(features, data)
+
+		n := geometryNode(f.Geometry)
+		leaves = append(leaves, n)
+		extent.expand(n)
+	}
+
+	if extent != emptyNode {
+		h.Bound = orb.Bound{
+			Min: orb.Point{extent.minX, extent.minY},
+			Max: orb.Point{extent.maxX, extent.maxY},
+		}
+	}
+
+	var index []byte
+	if h.IndexNodeSize > 0 && len(features) > 0 {
+		order := hilbertSort(leaves, extent)
+
+		sorted := make([][]byte, len(order))
+		sortedLeaves := make([]node, len(order))
+
+		var offset uint64
+		for i, j := range order {
+			sorted[i] = features[j]
+			sortedLeaves[i] = leaves[j]
+			sortedLeaves[i].offset = offset
+
+			offset += uint64(len(features[j]))
+		}
+
+		features = sorted
+		index = buildIndex(sortedLeaves, int(h.IndexNodeSize))
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.Write(magic[:])
+	bw.Write(h.encode())
+	bw.Write(index)
+	for _, f := range features {
+		bw.Write(f)
+	}
+
+	return bw.Flush()
+}
+
+// headerGeometryTypeOf returns the type of all the geometries, Unknown if mixed.
+func headerGeometryTypeOf(fc *geojson.FeatureCollection) GeometryType {
+	typ := Unknown
+	for _, f := range fc.Features {
+		if f.Geometry == nil {
+			continue
+		}
+
+		t := geometryTypeOf(f.Geometry)
+		if typ == Unknown {
+			typ = t
+		} else if typ != t {
+			return Unknown
+		}
+	}
+
+	return typ
+}
+
+// geometryNode returns the index node of the geometry bound.
+func geometryNode(g orb.Geometry) node {
+	if g == nil {
+		return emptyNode
+	}
+
+	b := g.Bound()
+	if b.IsEmpty() {
+		return emptyNode
+	}
+
+	return boundNode(b)
+}
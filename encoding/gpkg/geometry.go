@@ -0,0 +1,290 @@
+package gpkg
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/encoding/wkb"
+)
+
+var (
+	_ sql.Scanner   = &GeometryScanner{}
+	_ driver.Valuer = value{}
+)
+
+var (
+	// ErrNotGeoPackageBinary is returned when unmarshalling data that
+	// does not start with the GeoPackage binary header.
+	ErrNotGeoPackageBinary = errors.New("gpkg: invalid geometry header")
+
+	// ErrExtendedGeometry is returned when unmarshalling an extended
+	// geometry type, the payload of which is not standard WKB.
+	ErrExtendedGeometry = errors.New("gpkg: extended geometry types not supported")
+)
+
+// Envelope is the type of the envelope in the geometry header.
+type Envelope byte
+
+// The envelope types. The z and m values of the envelope are not
+// kept, only the x and y range is available as Header.Bound.
+const (
+	EnvelopeNone Envelope = 0
+	EnvelopeXY   Envelope = 1
+	EnvelopeXYZ  Envelope = 2
+	EnvelopeXYM  Envelope = 3
+	EnvelopeXYZM Envelope = 4
+)
+
+// size returns the length of the envelope in bytes, -1 if invalid.
+func (e Envelope) size() int {
+	switch e {
+	case EnvelopeNone:
+		return 0
+	case EnvelopeXY:
+		return 32
+	case EnvelopeXYZ, EnvelopeXYM:
+		return 48
+	case EnvelopeXYZM:
+		return 64
+	}
+
+	return -1
+}
+
+const (
+	flagLittleEndian = 0x01
+	flagEnvelope     = 0x0e
+	flagEmpty        = 0x10
+	flagExtended     = 0x20
+)
+
+// Header is the GeoPackage binary header that prefixes the WKB.
+type Header struct {
+	Version  byte
+	SRID     int32
+	Envelope Envelope
+
+	// Bound is the x and y range of the envelope, the zero
+	// bound if the envelope type is EnvelopeNone.
+	Bound orb.Bound
+
+	// Empty is set for empty geometries, e.g. a point with NaN coordinates.
+	Empty bool
+
+	// Extended is set if the geometry is an extension type,
+	// in which case the payload is not standard WKB.
+	Extended bool
+
+	ByteOrder binary.ByteOrder
+}
+
+// ReadHeader reads the GeoPackage binary header and returns it
+// and the WKB that follows.
+func ReadHeader(data []byte) (*Header, []byte, error) {
+	if len(data) < 8 || data[0] != 'G' || data[1] != 'P' {
+		return nil, nil, ErrNotGeoPackageBinary
+	}
+
+	flags := data[3]
+	h := &Header{
+		Version:   data[2],
+		Envelope:  Envelope((flags & flagEnvelope) >> 1),
+		Empty:     flags&flagEmpty != 0,
+		Extended:  flags&flagExtended != 0,
+		ByteOrder: binary.BigEndian,
+	}
+
+	if flags&flagLittleEndian != 0 {
+		h.ByteOrder = binary.LittleEndian
+	}
+
+	h.SRID = int32(h.ByteOrder.Uint32(data[4:8]))
+
+	size := h.Envelope.size()
+	if size < 0 || len(data) < 8+size {
+		return nil, nil, ErrNotGeoPackageBinary
+	}
+
+	if size > 0 {
+		// the envelope is minx, maxx, miny, maxy
+		e := data[8:]
+		h.Bound = orb.Bound{
+			Min: orb.Point{h.float(e[0:]), h.float(e[16:])},
+			Max: orb.Point{h.float(e[8:]), h.float(e[24:])},
+		}
+	}
+
+	return h, data[8+size:], nil
+}
+
+func (h *Header) float(b []byte) float64 {
+	return math.Float64frombits(h.ByteOrder.Uint64(b))
+}
+
+// Unmarshal decodes the GeoPackage binary geometry.
+// Use ReadHeader to also get the SRID and envelope.
+func Unmarshal(data []byte) (orb.Geometry, error) {
+	h, payload, err := ReadHeader(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.Extended {
+		return nil, ErrExtendedGeometry
+	}
+
+	return wkb.Unmarshal(payload)
+}
+
+// MustMarshal will encode the geometry and panic on error.
+// Currently there is no reason to error during geometry marshalling.
+func MustMarshal(g orb.Geometry, srid int32) []byte {
+	data, err := Marshal(g, srid)
+	if err != nil {
+		panic(err)
+	}
+
+	return data
+}
+
+// Marshal encodes the geometry as GeoPackage binary with the SRID.
+// Geometries other than points include an xy envelope.
+// The header and WKB are little endian.
+func Marshal(g orb.Geometry, srid int32) ([]byte, error) {
+	payload, err := wkb.Marshal(g, binary.LittleEndian)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := EnvelopeXY
+	flags := byte(flagLittleEndian)
+	if _, ok := g.(orb.Point); ok {
+		envelope = EnvelopeNone
+	} else if isEmpty(g) {
+		envelope = EnvelopeNone
+		flags |= flagEmpty
+	}
+	flags |= byte(envelope) << 1
+
+	size := envelope.size()
+	data := make([]byte, 8+size, 8+size+len(payload))
+	data[0], data[1], data[2], data[3] = 'G', 'P', 0, flags
+	binary.LittleEndian.PutUint32(data[4:8], uint32(srid))
+
+	if envelope == EnvelopeXY {
+		b := g.Bound()
+		for i, v := range []float64{b.Min[0], b.Max[0], b.Min[1], b.Max[1]} {
+			binary.LittleEndian.PutUint64(data[8+8*i:], math.Float64bits(v))
+		}
+	}
+
+	return append(data, payload...), nil
+}
+
+// isEmpty returns true if the geometry has no points.
+func isEmpty(g orb.Geometry) bool {
+	switch g := g.(type) {
+	case orb.MultiPoint:
+		return len(g) == 0
+	case orb.LineString:
+		return len(g) == 0
+	case orb.MultiLineString:
+		return len(g) == 0
+	case orb.Ring:
+		return len(g) == 0
+	case orb.Polygon:
+		return len(g) == 0
+	case orb.MultiPolygon:
+		return len(g) == 0
+	case orb.Collection:
+		return len(g) == 0
+	}
+
+	return false
+}
+
+// GeometryScanner scans GeoPackage binary geometry columns in query results.
+// It works like the wkb.GeometryScanner, the geometry is decoded into
+// the orb geometry type pointer or, if nil, the Geometry attribute.
+//
+//	var p orb.Point
+//	err := db.QueryRow("SELECT geom FROM places WHERE fid=?", id).Scan(gpkg.Scanner(&p))
+type GeometryScanner struct {
+	g        interface{}
+	SRID     int32
+	Geometry orb.Geometry
+	Valid    bool // Valid is true if the geometry is not NULL
+}
+
+// Scanner returns a GeometryScanner that can scan a GeoPackage geometry
+// into the orb geometry type pointer or, if nil, the scanner.Geometry attribute.
+func Scanner(g interface{}) *GeometryScanner {
+	return &GeometryScanner{g: g}
+}
+
+// Scan will scan the input []byte data into a geometry.
+func (s *GeometryScanner) Scan(d interface{}) error {
+	s.Geometry = nil
+	s.SRID = 0
+	s.Valid = false
+
+	if d == nil {
+		return nil
+	}
+
+	data, ok := d.([]byte)
+	if !ok {
+		return wkb.ErrUnsupportedDataType
+	}
+
+	if data == nil {
+		return nil
+	}
+
+	h, payload, err := ReadHeader(data)
+	if err != nil {
+		return err
+	}
+
+	if h.Extended {
+		return ErrExtendedGeometry
+	}
+
+	ws := wkb.Scanner(s.g)
+	if err := ws.Scan(payload); err != nil {
+		return err
+	}
+
+	s.SRID = h.SRID
+	s.Geometry = ws.Geometry
+	s.Valid = ws.Valid
+	return nil
+}
+
+type value struct {
+	v    orb.Geometry
+	srid int32
+}
+
+// Value returns a driver.Valuer that encodes the geometry as
+// GeoPackage binary with the SRID, nil geometries as NULL.
+func Value(g orb.Geometry, srid int32) driver.Valuer {
+	return value{v: g, srid: srid}
+}
+
+func (v value) Value() (driver.Value, error) {
+	if v.v == nil {
+		return nil, nil
+	}
+
+	data, err := Marshal(v.v, v.srid)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
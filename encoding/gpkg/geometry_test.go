@@ -0,0 +1,236 @@
+package gpkg
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/encoding/wkb"
+)
+
+func TestMarshal(t *testing.T) {
+	cases := []struct {
+		name     string
+		geom     orb.Geometry
+		envelope Envelope
+		empty    bool
+	}{
+		{
+			name:     "point",
+			geom:     orb.Point{1, 2},
+			envelope: EnvelopeNone,
+		},
+		{
+			name:     "line string",
+			geom:     orb.LineString{{1, 2}, {3, -4}},
+			envelope: EnvelopeXY,
+		},
+		{
+			name:     "polygon",
+			geom:     orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+			envelope: EnvelopeXY,
+		},
+		{
+			name:     "multi polygon",
+			geom:     orb.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}, {{{5, 5}, {6, 5}, {6, 6}, {5, 5}}}},
+			envelope: EnvelopeXY,
+		},
+		{
+			name:     "empty line string",
+			geom:     orb.LineString{},
+			envelope: EnvelopeNone,
+			empty:    true,
+		},
+		{
+			name:     "collection",
+			geom:     orb.Collection{orb.Point{1, 2}, orb.LineString{{3, 4}, {5, 6}}},
+			envelope: EnvelopeXY,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := Marshal(tc.geom, 4326)
+			if err != nil {
+				t.Fatalf("marshal error: %v", err)
+			}
+
+			h, payload, err := ReadHeader(data)
+			if err != nil {
+				t.Fatalf("read header error: %v", err)
+			}
+
+			if h.SRID != 4326 {
+				t.Errorf("incorrect srid: %v", h.SRID)
+			}
+
+			if h.Envelope != tc.envelope {
+				t.Errorf("incorrect envelope: %v != %v", h.Envelope, tc.envelope)
+			}
+
+			if h.Empty != tc.empty {
+				t.Errorf("incorrect empty: %v", h.Empty)
+			}
+
+			if h.ByteOrder != binary.LittleEndian {
+				t.Errorf("should be little endian")
+			}
+
+			if tc.envelope == EnvelopeXY && !h.Bound.Equal(tc.geom.Bound()) {
+				t.Errorf("incorrect bound: %v != %v", h.Bound, tc.geom.Bound())
+			}
+
+			if w := wkb.MustMarshal(tc.geom, binary.LittleEndian); string(payload) != string(w) {
+				t.Errorf("payload should be the wkb")
+			}
+
+			g, err := Unmarshal(data)
+			if err != nil {
+				t.Fatalf("unmarshal error: %v", err)
+			}
+
+			if !orb.Equal(g, tc.geom) {
+				t.Errorf("incorrect geometry: %v != %v", g, tc.geom)
+			}
+		})
+	}
+}
+
+func TestReadHeader(t *testing.T) {
+	// big endian with an xyz envelope
+	payload := wkb.MustMarshal(orb.LineString{{1, 2}, {3, 4}}, binary.BigEndian)
+
+	data := []byte{'G', 'P', 0, byte(EnvelopeXYZ) << 1, 0, 0, 0x0f, 0xe6}
+	for _, v := range []float64{1, 3, 2, 4, -10, 10} {
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], math.Float64bits(v))
+		data = append(data, b[:]...)
+	}
+	data = append(data, payload...)
+
+	h, rest, err := ReadHeader(data)
+	if err != nil {
+		t.Fatalf("read header error: %v", err)
+	}
+
+	if h.SRID != 4070 {
+		t.Errorf("incorrect srid: %v", h.SRID)
+	}
+
+	if h.ByteOrder != binary.BigEndian {
+		t.Errorf("should be big endian")
+	}
+
+	expected := orb.Bound{Min: orb.Point{1, 2}, Max: orb.Point{3, 4}}
+	if !h.Bound.Equal(expected) {
+		t.Errorf("incorrect bound: %v", h.Bound)
+	}
+
+	if string(rest) != string(payload) {
+		t.Errorf("incorrect payload")
+	}
+
+	g, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if !orb.Equal(g, orb.LineString{{1, 2}, {3, 4}}) {
+		t.Errorf("incorrect geometry: %v", g)
+	}
+}
+
+func TestReadHeader_errors(t *testing.T) {
+	cases := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{
+			name: "too short",
+			data: []byte{'G', 'P', 0},
+			err:  ErrNotGeoPackageBinary,
+		},
+		{
+			name: "magic",
+			data: []byte{'X', 'P', 0, 1, 0, 0, 0, 0},
+			err:  ErrNotGeoPackageBinary,
+		},
+		{
+			name: "invalid envelope type",
+			data: []byte{'G', 'P', 0, 7 << 1, 0, 0, 0, 0},
+			err:  ErrNotGeoPackageBinary,
+		},
+		{
+			name: "truncated envelope",
+			data: []byte{'G', 'P', 0, 1<<1 | 1, 0, 0, 0, 0, 1, 2, 3},
+			err:  ErrNotGeoPackageBinary,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := ReadHeader(tc.data)
+			if err != tc.err {
+				t.Errorf("incorrect error: %v", err)
+			}
+		})
+	}
+
+	// extended types are not wkb
+	data := MustMarshal(orb.Point{1, 2}, 0)
+	data[3] |= flagExtended
+
+	if _, err := Unmarshal(data); err != ErrExtendedGeometry {
+		t.Errorf("incorrect error: %v", err)
+	}
+}
+
+func TestGeometryScanner(t *testing.T) {
+	data := MustMarshal(orb.Point{1, 2}, 3857)
+
+	var p orb.Point
+	s := Scanner(&p)
+	if err := s.Scan(data); err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+
+	if !s.Valid || s.SRID != 3857 || !p.Equal(orb.Point{1, 2}) {
+		t.Errorf("incorrect scan: %v %v %v", s.Valid, s.SRID, p)
+	}
+
+	s = Scanner(nil)
+	if err := s.Scan(data); err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+
+	if !orb.Equal(s.Geometry, orb.Point{1, 2}) {
+		t.Errorf("incorrect geometry: %v", s.Geometry)
+	}
+
+	if err := s.Scan(nil); err != nil || s.Valid {
+		t.Errorf("null should not be valid: %v", err)
+	}
+
+	var ls orb.LineString
+	if err := Scanner(&ls).Scan(data); err != wkb.ErrIncorrectGeometry {
+		t.Errorf("incorrect error: %v", err)
+	}
+}
+
+func TestValue(t *testing.T) {
+	v, err := Value(orb.Point{1, 2}, 4326).Value()
+	if err != nil {
+		t.Fatalf("value error: %v", err)
+	}
+
+	if string(v.([]byte)) != string(MustMarshal(orb.Point{1, 2}, 4326)) {
+		t.Errorf("incorrect value")
+	}
+
+	v, err = Value(nil, 4326).Value()
+	if err != nil || v != nil {
+		t.Errorf("nil geometry should be null: %v %v", v, err)
+	}
+}
@@ -0,0 +1,210 @@
+// Package gpkg reads and writes OGC GeoPackage feature tables and the
+// GeoPackage binary geometry format, the header wrapping the WKB.
+// The database is a *sql.DB opened with a SQLite driver chosen by the caller,
+// e.g. github.com/mattn/go-sqlite3 or modernc.org/sqlite.
+// Specification at https://www.geopackage.org/spec/
+package gpkg
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+)
+
+// A Table is a feature table listed in gpkg_contents.
+type Table struct {
+	Name        string
+	Identifier  string
+	Description string
+
+	// Bound is the extent in gpkg_contents, it may not be set
+	// or out of date as it is informative only.
+	Bound orb.Bound
+
+	GeometryColumn string
+
+	// GeometryType is the geometry_type_name of the geometry column,
+	// e.g. POINT, LINESTRING, POLYGON or GEOMETRY.
+	GeometryType string
+	SRID         int32
+}
+
+// Tables returns the feature tables in the GeoPackage, sorted by name.
+func Tables(db *sql.DB) ([]*Table, error) {
+	rows, err := db.Query(`SELECT c.table_name, c.identifier, c.description,
+		c.min_x, c.min_y, c.max_x, c.max_y,
+		g.column_name, g.geometry_type_name, g.srs_id
+		FROM gpkg_contents c JOIN gpkg_geometry_columns g ON c.table_name = g.table_name
+		WHERE c.data_type = 'features' ORDER BY c.table_name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []*Table
+	for rows.Next() {
+		var (
+			t                      Table
+			identifier, desc       sql.NullString
+			minX, minY, maxX, maxY sql.NullFloat64
+		)
+
+		err := rows.Scan(&t.Name, &identifier, &desc,
+			&minX, &minY, &maxX, &maxY,
+			&t.GeometryColumn, &t.GeometryType, &t.SRID)
+		if err != nil {
+			return nil, err
+		}
+
+		t.Identifier = identifier.String
+		t.Description = desc.String
+		if minX.Valid && minY.Valid && maxX.Valid && maxY.Valid {
+			t.Bound = orb.Bound{
+				Min: orb.Point{minX.Float64, minY.Float64},
+				Max: orb.Point{maxX.Float64, maxY.Float64},
+			}
+		}
+
+		tables = append(tables, &t)
+	}
+
+	return tables, rows.Err()
+}
+
+// ReadTable reads the features of the feature table. The primary key
+// is the feature id and the other columns are the properties.
+// BOOLEAN columns are read as bools, DATE and DATETIME columns as time.Time,
+// BLOB columns as []byte and TEXT columns as strings.
+func ReadTable(db *sql.DB, name string) (*geojson.FeatureCollection, error) {
+	var geomColumn string
+	err := db.QueryRow(`SELECT column_name FROM gpkg_geometry_columns WHERE table_name = ?`, name).Scan(&geomColumn)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("gpkg: not a feature table: %s", name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	pk, err := primaryKey(db, name)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query("SELECT * FROM " + quote(name))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	fc := geojson.NewFeatureCollection()
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		f := geojson.NewFeature(nil)
+		for i, c := range columns {
+			v := values[i]
+			switch {
+			case strings.EqualFold(c.Name(), geomColumn):
+				if data, ok := v.([]byte); ok && data != nil {
+					g, err := Unmarshal(data)
+					if err != nil {
+						return nil, err
+					}
+
+					f.Geometry = g
+				}
+			case strings.EqualFold(c.Name(), pk):
+				f.ID = v
+			default:
+				f.Properties[c.Name()] = columnValue(c.DatabaseTypeName(), v)
+			}
+		}
+
+		fc.Append(f)
+	}
+
+	return fc, rows.Err()
+}
+
+// primaryKey returns the name of the integer primary key column of the table.
+func primaryKey(db *sql.DB, name string) (string, error) {
+	rows, err := db.Query("PRAGMA table_info(" + quote(name) + ")")
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var pk string
+	for rows.Next() {
+		var (
+			cid, notNull, key int
+			column, typ       string
+			def               interface{}
+		)
+
+		if err := rows.Scan(&cid, &column, &typ, &notNull, &def, &key); err != nil {
+			return "", err
+		}
+
+		if key > 0 {
+			pk = column
+		}
+	}
+
+	return pk, rows.Err()
+}
+
+// columnValue converts the value returned by the driver
+// based on the declared type of the column.
+func columnValue(typ string, v interface{}) interface{} {
+	typ = strings.ToUpper(typ)
+
+	switch v := v.(type) {
+	case []byte:
+		if typ == "BLOB" || v == nil {
+			return v
+		}
+
+		return columnValue(typ, string(v))
+	case int64:
+		if typ == "BOOLEAN" {
+			return v != 0
+		}
+	case string:
+		switch typ {
+		case "DATETIME":
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t
+			}
+		case "DATE":
+			if t, err := time.Parse("2006-01-02", v); err == nil {
+				return t
+			}
+		}
+	}
+
+	return v
+}
+
+// quote returns the name as a quoted SQL identifier.
+func quote(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
@@ -0,0 +1,59 @@
+package gpkg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+)
+
+func TestColumnType(t *testing.T) {
+	cases := []struct {
+		name   string
+		values []interface{}
+		typ    string
+	}{
+		{name: "bool", values: []interface{}{true, nil}, typ: "BOOLEAN"},
+		{name: "integer", values: []interface{}{1, int64(2)}, typ: "INTEGER"},
+		{name: "real", values: []interface{}{1.5, 2.0}, typ: "REAL"},
+		{name: "integer and real", values: []interface{}{1, 2.5}, typ: "REAL"},
+		{name: "datetime", values: []interface{}{time.Now()}, typ: "DATETIME"},
+		{name: "blob", values: []interface{}{[]byte{1}}, typ: "BLOB"},
+		{name: "mixed", values: []interface{}{1, "a"}, typ: "TEXT"},
+		{name: "all null", values: []interface{}{nil}, typ: "TEXT"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if typ := columnType(tc.values); typ != tc.typ {
+				t.Errorf("incorrect type: %v != %v", typ, tc.typ)
+			}
+		})
+	}
+}
+
+func TestGeometryType(t *testing.T) {
+	fc := geojson.NewFeatureCollection()
+	if typ := geometryType(fc); typ != "GEOMETRY" {
+		t.Errorf("incorrect type: %v", typ)
+	}
+
+	fc.Append(geojson.NewFeature(orb.Polygon{}))
+	fc.Append(geojson.NewFeature(orb.Ring{}))
+	fc.Append(geojson.NewFeature(nil))
+	if typ := geometryType(fc); typ != "POLYGON" {
+		t.Errorf("incorrect type: %v", typ)
+	}
+
+	fc.Append(geojson.NewFeature(orb.MultiPolygon{}))
+	if typ := geometryType(fc); typ != "GEOMETRY" {
+		t.Errorf("incorrect type: %v", typ)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	if q := quote(`a "b"`); q != `"a ""b"""` {
+		t.Errorf("incorrect quote: %v", q)
+	}
+}
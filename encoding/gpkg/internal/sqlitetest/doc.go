@@ -0,0 +1,3 @@
+// Package sqlitetest tests the gpkg package against a real SQLite database.
+// It is a separate module so the cgo SQLite driver is not a dependency of orb.
+package sqlitetest
@@ -0,0 +1,10 @@
+module github.com/dadadamarine/orb/encoding/gpkg/internal/sqlitetest
+
+go 1.15
+
+require (
+	github.com/dadadamarine/orb v0.0.0
+	github.com/mattn/go-sqlite3 v1.14.6
+)
+
+replace github.com/dadadamarine/orb => ../../../..
@@ -0,0 +1,263 @@
+package sqlitetest
+
+import (
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/encoding/gpkg"
+	"github.com/dadadamarine/orb/geojson"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// openSQLite opens a new GeoPackage file, skipping the test
+// if the driver is not available, e.g. built without cgo.
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.gpkg"))
+	if err != nil {
+		t.Fatalf("open error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.Ping(); err != nil {
+		t.Skipf("sqlite not available: %v", err)
+	}
+
+	return db
+}
+
+func TestSQLite(t *testing.T) {
+	db := openSQLite(t)
+
+	when := time.Date(2020, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	places := geojson.NewFeatureCollection()
+
+	f := geojson.NewFeature(orb.Point{1, 2})
+	f.ID = 10
+	f.Properties["name"] = "a"
+	f.Properties["count"] = 1
+	f.Properties["score"] = 1
+	f.Properties["ok"] = true
+	f.Properties["when"] = when
+	f.Properties["data"] = []byte{1, 2}
+	places.Append(f)
+
+	f = geojson.NewFeature(orb.Point{-3, 4})
+	f.Properties["name"] = "b"
+	f.Properties["score"] = 2.5
+	f.Properties["ok"] = false
+	f.Properties["tags"] = []interface{}{"x", "y"}
+	places.Append(f)
+
+	places.Append(geojson.NewFeature(nil))
+
+	if err := gpkg.WriteTable(db, "places", places, gpkg.Description("some places")); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	areas := geojson.NewFeatureCollection()
+	areas.Append(geojson.NewFeature(orb.Polygon{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{2, 2}, {2, 3}, {3, 3}, {2, 2}},
+	}))
+	areas.Append(geojson.NewFeature(orb.MultiPolygon{{{{20, 20}, {21, 20}, {21, 21}, {20, 20}}}}))
+
+	err := gpkg.WriteTable(db, "areas", areas, gpkg.SRID(3857, "PROJCS[...]"), gpkg.GeometryColumn("shape"))
+	if err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	var appID, version int
+	if err := db.QueryRow("PRAGMA application_id").Scan(&appID); err != nil {
+		t.Fatalf("query error: %v", err)
+	}
+
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		t.Fatalf("query error: %v", err)
+	}
+
+	if appID != 0x47504B47 || version != 10300 {
+		t.Errorf("incorrect pragmas: %x %v", appID, version)
+	}
+
+	// the declared column types
+	types := map[string]string{}
+	rows, err := db.Query(`PRAGMA table_info("places")`)
+	if err != nil {
+		t.Fatalf("query error: %v", err)
+	}
+
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, typ        string
+			def              interface{}
+		)
+
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &def, &pk); err != nil {
+			t.Fatalf("scan error: %v", err)
+		}
+
+		types[name] = typ
+	}
+	rows.Close()
+
+	expectedTypes := map[string]string{
+		"fid": "INTEGER", "geom": "POINT", "count": "INTEGER", "data": "BLOB", "name": "TEXT",
+		"ok": "BOOLEAN", "score": "REAL", "tags": "TEXT", "when": "DATETIME",
+	}
+	if !reflect.DeepEqual(types, expectedTypes) {
+		t.Errorf("incorrect column types: %v", types)
+	}
+
+	var srsName string
+	if err := db.QueryRow(`SELECT srs_name FROM gpkg_spatial_ref_sys WHERE srs_id = 3857`).Scan(&srsName); err != nil {
+		t.Errorf("srid should be added: %v", err)
+	}
+
+	tables, err := gpkg.Tables(db)
+	if err != nil {
+		t.Fatalf("tables error: %v", err)
+	}
+
+	expectedTables := []*gpkg.Table{
+		{
+			Name:           "areas",
+			Identifier:     "areas",
+			Bound:          orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{21, 21}},
+			GeometryColumn: "shape",
+			GeometryType:   "GEOMETRY",
+			SRID:           3857,
+		},
+		{
+			Name:           "places",
+			Identifier:     "places",
+			Description:    "some places",
+			Bound:          orb.Bound{Min: orb.Point{-3, 2}, Max: orb.Point{1, 4}},
+			GeometryColumn: "geom",
+			GeometryType:   "POINT",
+			SRID:           4326,
+		},
+	}
+	if !reflect.DeepEqual(tables, expectedTables) {
+		t.Errorf("incorrect tables")
+		for _, table := range tables {
+			t.Logf("%+v", table)
+		}
+	}
+
+	result, err := gpkg.ReadTable(db, "places")
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+
+	if len(result.Features) != 3 {
+		t.Fatalf("incorrect number of features: %d", len(result.Features))
+	}
+
+	// the second feature gets the next id
+	for i, id := range []int64{10, 11, 12} {
+		if result.Features[i].ID != id {
+			t.Errorf("%d: incorrect id: %v", i, result.Features[i].ID)
+		}
+	}
+
+	expected := geojson.Properties{
+		"name": "a", "count": int64(1), "score": 1.0, "ok": true,
+		"when": when, "data": []byte{1, 2}, "tags": nil,
+	}
+	if p := result.Features[0].Properties; !reflect.DeepEqual(p, expected) {
+		t.Errorf("incorrect properties: %v", p)
+	}
+
+	expected = geojson.Properties{
+		"name": "b", "count": nil, "score": 2.5, "ok": false,
+		"when": nil, "data": nil, "tags": `["x","y"]`,
+	}
+	if p := result.Features[1].Properties; !reflect.DeepEqual(p, expected) {
+		t.Errorf("incorrect properties: %v", p)
+	}
+
+	for i, f := range places.Features {
+		if g := result.Features[i].Geometry; !orb.Equal(g, f.Geometry) && (g != nil || f.Geometry != nil) {
+			t.Errorf("%d: incorrect geometry: %v", i, g)
+		}
+	}
+
+	result, err = gpkg.ReadTable(db, "areas")
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+
+	for i, f := range areas.Features {
+		if g := result.Features[i].Geometry; !orb.Equal(g, f.Geometry) {
+			t.Errorf("%d: incorrect geometry: %v", i, g)
+		}
+	}
+}
+
+func TestSQLite_rollback(t *testing.T) {
+	db := openSQLite(t)
+
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(orb.Point{1, 2}))
+
+	if err := gpkg.WriteTable(db, "places", fc); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	// the table exists, nothing of the second write should remain
+	fc.Append(geojson.NewFeature(orb.Point{3, 4}))
+	if err := gpkg.WriteTable(db, "places", fc, gpkg.Description("again")); err == nil {
+		t.Fatalf("should error if the table exists")
+	}
+
+	err := gpkg.WriteTable(db, "other", fc, gpkg.SRID(1234, ""))
+	if err == nil || !strings.Contains(err.Error(), "no definition for srid 1234") {
+		t.Fatalf("incorrect error: %v", err)
+	}
+
+	// reserved property names
+	fc.Features[0].Properties["FID"] = 1
+	err = gpkg.WriteTable(db, "other", fc)
+	if err == nil || !strings.Contains(err.Error(), "reserved column: FID") {
+		t.Fatalf("incorrect error: %v", err)
+	}
+	delete(fc.Features[0].Properties, "FID")
+
+	// fails inserting the features, after the table and contents rows are created
+	fc.Features[0].ID, fc.Features[1].ID = 1, 1
+	if err := gpkg.WriteTable(db, "other", fc); err == nil {
+		t.Fatalf("should error on duplicate ids")
+	}
+
+	tables, err := gpkg.Tables(db)
+	if err != nil {
+		t.Fatalf("tables error: %v", err)
+	}
+
+	if len(tables) != 1 || tables[0].Description != "" {
+		t.Errorf("incorrect tables: %v", tables)
+	}
+
+	var count int
+	if err := db.QueryRow(`SELECT count(*) FROM "places"`).Scan(&count); err != nil {
+		t.Fatalf("query error: %v", err)
+	}
+
+	if count != 1 {
+		t.Errorf("incorrect number of rows: %d", count)
+	}
+
+	_, err = gpkg.ReadTable(db, "other")
+	if err == nil || !strings.Contains(err.Error(), "not a feature table") {
+		t.Errorf("incorrect error: %v", err)
+	}
+}
@@ -0,0 +1,442 @@
+package gpkg
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+)
+
+const (
+	// applicationID is "GPKG" as an integer, set with the user version
+	// to mark the SQLite database as a GeoPackage.
+	applicationID = 0x47504B47
+	userVersion   = 10300
+
+	defaultGeometryColumn = "geom"
+	featureIDColumn       = "fid"
+)
+
+// wgs84 is the definition of EPSG:4326 required by the specification.
+const wgs84 = `GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563,AUTHORITY["EPSG","7030"]],AUTHORITY["EPSG","6326"]],PRIMEM["Greenwich",0,AUTHORITY["EPSG","8901"]],UNIT["degree",0.0174532925199433,AUTHORITY["EPSG","9122"]],AUTHORITY["EPSG","4326"]]`
+
+// Option is a function that configures writing.
+type Option func(*options)
+
+type options struct {
+	srid           int32
+	definition     string
+	geometryColumn string
+	description    string
+}
+
+// SRID sets the spatial reference system of the table, by default 4326.
+// The definition is the WKT added to gpkg_spatial_ref_sys, it is not
+// needed for 4326, 0 and -1 or if the SRID is already in the GeoPackage.
+func SRID(srid int32, definition string) Option {
+	return func(o *options) {
+		o.srid = srid
+		o.definition = definition
+	}
+}
+
+// GeometryColumn sets the name of the geometry column, by default "geom".
+func GeometryColumn(name string) Option {
+	return func(o *options) {
+		o.geometryColumn = name
+	}
+}
+
+// Description sets the description of the table in gpkg_contents.
+func Description(desc string) Option {
+	return func(o *options) {
+		o.description = desc
+	}
+}
+
+// WriteTable creates a new feature table with the features. The GeoPackage
+// tables are created if the database is empty. The table has a "fid"
+// primary key, set from integer feature ids, the geometry column and
+// a column for each property. Everything is written in one transaction.
+func WriteTable(db *sql.DB, name string, fc *geojson.FeatureCollection, opts ...Option) error {
+	o := &options{
+		srid:           4326,
+		geometryColumn: defaultGeometryColumn,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	columns, err := propertyColumns(fc, o.geometryColumn)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := writeTable(tx, name, fc, columns, o); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func writeTable(tx *sql.Tx, name string, fc *geojson.FeatureCollection, columns []column, o *options) error {
+	if err := createCoreTables(tx); err != nil {
+		return err
+	}
+
+	if err := addSpatialRefSys(tx, o.srid, o.definition); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(createTableSQL(name, o.geometryColumn, geometryType(fc), columns)); err != nil {
+		return err
+	}
+
+	bound, ok := featuresBound(fc)
+	var minX, minY, maxX, maxY interface{}
+	if ok {
+		minX, minY, maxX, maxY = bound.Min[0], bound.Min[1], bound.Max[0], bound.Max[1]
+	}
+
+	_, err := tx.Exec(`INSERT INTO gpkg_contents
+		(table_name, data_type, identifier, description, min_x, min_y, max_x, max_y, srs_id)
+		VALUES (?, 'features', ?, ?, ?, ?, ?, ?, ?)`,
+		name, name, o.description, minX, minY, maxX, maxY, o.srid)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO gpkg_geometry_columns
+		(table_name, column_name, geometry_type_name, srs_id, z, m)
+		VALUES (?, ?, ?, ?, 0, 0)`,
+		name, o.geometryColumn, geometryType(fc), o.srid)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(insertSQL(name, o.geometryColumn, columns))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	args := make([]interface{}, 2+len(columns))
+	for _, f := range fc.Features {
+		args[0] = featureID(f.ID)
+		args[1] = Value(f.Geometry, o.srid)
+		for i, c := range columns {
+			v, err := c.value(f.Properties[c.name])
+			if err != nil {
+				return err
+			}
+
+			args[2+i] = v
+		}
+
+		if _, err := stmt.Exec(args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var coreTables = []string{
+	`CREATE TABLE IF NOT EXISTS gpkg_spatial_ref_sys (
+		srs_name TEXT NOT NULL,
+		srs_id INTEGER PRIMARY KEY,
+		organization TEXT NOT NULL,
+		organization_coordsys_id INTEGER NOT NULL,
+		definition TEXT NOT NULL,
+		description TEXT)`,
+	`CREATE TABLE IF NOT EXISTS gpkg_contents (
+		table_name TEXT NOT NULL PRIMARY KEY,
+		data_type TEXT NOT NULL,
+		identifier TEXT UNIQUE,
+		description TEXT DEFAULT '',
+		last_change DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now')),
+		min_x DOUBLE,
+		min_y DOUBLE,
+		max_x DOUBLE,
+		max_y DOUBLE,
+		srs_id INTEGER,
+		CONSTRAINT fk_gc_r_srs_id FOREIGN KEY (srs_id) REFERENCES gpkg_spatial_ref_sys(srs_id))`,
+	`CREATE TABLE IF NOT EXISTS gpkg_geometry_columns (
+		table_name TEXT NOT NULL,
+		column_name TEXT NOT NULL,
+		geometry_type_name TEXT NOT NULL,
+		srs_id INTEGER NOT NULL,
+		z TINYINT NOT NULL,
+		m TINYINT NOT NULL,
+		CONSTRAINT pk_geom_cols PRIMARY KEY (table_name, column_name),
+		CONSTRAINT uk_gc_table_name UNIQUE (table_name),
+		CONSTRAINT fk_gc_tn FOREIGN KEY (table_name) REFERENCES gpkg_contents(table_name),
+		CONSTRAINT fk_gc_srs FOREIGN KEY (srs_id) REFERENCES gpkg_spatial_ref_sys (srs_id))`,
+	`INSERT OR IGNORE INTO gpkg_spatial_ref_sys VALUES
+		('Undefined cartesian SRS', -1, 'NONE', -1, 'undefined', 'undefined cartesian coordinate reference system'),
+		('Undefined geographic SRS', 0, 'NONE', 0, 'undefined', 'undefined geographic coordinate reference system'),
+		('WGS 84 geodetic', 4326, 'EPSG', 4326, '` + wgs84 + `', 'longitude/latitude coordinates in decimal degrees on the WGS 84 spheroid')`,
+	`PRAGMA application_id = ` + strconv.Itoa(applicationID),
+	`PRAGMA user_version = ` + strconv.Itoa(userVersion),
+}
+
+func createCoreTables(tx *sql.Tx) error {
+	for _, q := range coreTables {
+		if _, err := tx.Exec(q); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addSpatialRefSys adds the SRID to gpkg_spatial_ref_sys if it is not there.
+func addSpatialRefSys(tx *sql.Tx, srid int32, definition string) error {
+	var count int
+	err := tx.QueryRow(`SELECT count(*) FROM gpkg_spatial_ref_sys WHERE srs_id = ?`, srid).Scan(&count)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	if definition == "" {
+		return fmt.Errorf("gpkg: no definition for srid %d", srid)
+	}
+
+	_, err = tx.Exec(`INSERT INTO gpkg_spatial_ref_sys
+		(srs_name, srs_id, organization, organization_coordsys_id, definition)
+		VALUES (?, ?, 'EPSG', ?, ?)`,
+		"EPSG:"+strconv.Itoa(int(srid)), srid, srid, definition)
+	return err
+}
+
+// column is a property column of the feature table.
+type column struct {
+	name string
+
+	// typ is the GeoPackage data type, one of
+	// BOOLEAN, INTEGER, REAL, TEXT, DATETIME or BLOB.
+	typ string
+}
+
+// propertyColumns returns the columns for the properties, sorted by name.
+func propertyColumns(fc *geojson.FeatureCollection, geomColumn string) ([]column, error) {
+	values := map[string][]interface{}{}
+	for _, f := range fc.Features {
+		for k, v := range f.Properties {
+			values[k] = append(values[k], v)
+		}
+	}
+
+	names := make([]string, 0, len(values))
+	for k := range values {
+		if strings.EqualFold(k, featureIDColumn) || strings.EqualFold(k, geomColumn) {
+			return nil, fmt.Errorf("gpkg: property name is a reserved column: %s", k)
+		}
+
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	columns := make([]column, 0, len(names))
+	for _, n := range names {
+		columns = append(columns, column{name: n, typ: columnType(values[n])})
+	}
+
+	return columns, nil
+}
+
+func columnType(values []interface{}) string {
+	types := map[string]bool{}
+	for _, v := range values {
+		switch v.(type) {
+		case nil:
+		case bool:
+			types["BOOLEAN"] = true
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			types["INTEGER"] = true
+		case float32, float64:
+			types["REAL"] = true
+		case time.Time:
+			types["DATETIME"] = true
+		case []byte:
+			types["BLOB"] = true
+		default:
+			types["TEXT"] = true
+		}
+	}
+
+	if len(types) == 2 && types["INTEGER"] && types["REAL"] {
+		return "REAL"
+	}
+
+	if len(types) == 1 {
+		for t := range types {
+			return t
+		}
+	}
+
+	// mixed types, or all null
+	return "TEXT"
+}
+
+// value converts the property value for the column.
+func (c column) value(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	switch c.typ {
+	case "BOOLEAN", "INTEGER", "REAL", "BLOB":
+		return v, nil
+	case "DATETIME":
+		return v.(time.Time).UTC().Format("2006-01-02T15:04:05.000Z"), nil
+	}
+
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+
+		return string(data), nil
+	case time.Time:
+		return v.UTC().Format("2006-01-02T15:04:05.000Z"), nil
+	}
+
+	return fmt.Sprint(v), nil
+}
+
+func createTableSQL(name, geomColumn, geomType string, columns []column) string {
+	var sb strings.Builder
+	sb.WriteString("CREATE TABLE " + quote(name) + " (")
+	sb.WriteString(quote(featureIDColumn) + " INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL, ")
+	sb.WriteString(quote(geomColumn) + " " + geomType)
+	for _, c := range columns {
+		sb.WriteString(", " + quote(c.name) + " " + c.typ)
+	}
+	sb.WriteString(")")
+
+	return sb.String()
+}
+
+func insertSQL(name, geomColumn string, columns []column) string {
+	names := make([]string, 0, 2+len(columns))
+	names = append(names, quote(featureIDColumn), quote(geomColumn))
+	for _, c := range columns {
+		names = append(names, quote(c.name))
+	}
+
+	return "INSERT INTO " + quote(name) + " (" + strings.Join(names, ", ") +
+		") VALUES (?" + strings.Repeat(", ?", len(names)-1) + ")"
+}
+
+// geometryType returns the geometry_type_name for the features,
+// GEOMETRY if there are different types.
+func geometryType(fc *geojson.FeatureCollection) string {
+	typ := ""
+	for _, f := range fc.Features {
+		if f.Geometry == nil {
+			continue
+		}
+
+		t := geometryTypeName(f.Geometry)
+		if typ == "" {
+			typ = t
+		} else if typ != t {
+			return "GEOMETRY"
+		}
+	}
+
+	if typ == "" {
+		return "GEOMETRY"
+	}
+
+	return typ
+}
+
+func geometryTypeName(g orb.Geometry) string {
+	switch g.(type) {
+	case orb.Point:
+		return "POINT"
+	case orb.MultiPoint:
+		return "MULTIPOINT"
+	case orb.LineString:
+		return "LINESTRING"
+	case orb.MultiLineString:
+		return "MULTILINESTRING"
+	case orb.Ring, orb.Polygon, orb.Bound:
+		return "POLYGON"
+	case orb.MultiPolygon:
+		return "MULTIPOLYGON"
+	case orb.Collection:
+		return "GEOMETRYCOLLECTION"
+	}
+
+	return "GEOMETRY"
+}
+
+func featuresBound(fc *geojson.FeatureCollection) (orb.Bound, bool) {
+	var (
+		bound orb.Bound
+		ok    bool
+	)
+
+	for _, f := range fc.Features {
+		if f.Geometry == nil || isEmpty(f.Geometry) {
+			continue
+		}
+
+		if b := f.Geometry.Bound(); !ok {
+			bound, ok = b, true
+		} else {
+			bound = bound.Union(b)
+		}
+	}
+
+	return bound, ok
+}
+
+// featureID returns the id if it is an integer, nil otherwise
+// so a new one is assigned by the database.
+func featureID(id interface{}) interface{} {
+	switch id := id.(type) {
+	case int:
+		return int64(id)
+	case int32:
+		return int64(id)
+	case int64:
+		return id
+	case uint32:
+		return int64(id)
+	case float64:
+		if id == math.Trunc(id) && math.Abs(id) < 1<<53 {
+			return int64(id)
+		}
+	case string:
+		if i, err := strconv.ParseInt(id, 10, 64); err == nil {
+			return i
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package gpx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dadadamarine/orb"
+)
+
+// Unmarshal decodes the GPX data, version 1.0 or 1.1. Times without
+// a time zone are assumed to be UTC. Unknown elements are ignored.
+func Unmarshal(data []byte) (*GPX, error) {
+	return Decode(bytes.NewReader(data))
+}
+
+// Decode decodes the GPX from the reader, see Unmarshal.
+func Decode(r io.Reader) (*GPX, error) {
+	doc := &gpxXML{}
+	if err := xml.NewDecoder(r).Decode(doc); err != nil {
+		return nil, fmt.Errorf("gpx: %v", err)
+	}
+
+	var err error
+	g := &GPX{
+		Creator:     doc.Creator,
+		Name:        strings.TrimSpace(doc.Name),
+		Description: strings.TrimSpace(doc.Desc),
+		Extensions:  doc.Extensions.extensions(),
+	}
+
+	timeString := doc.Time
+	if m := doc.Metadata; m != nil {
+		g.Name = strings.TrimSpace(m.Name)
+		g.Description = strings.TrimSpace(m.Desc)
+		timeString = m.Time
+	}
+
+	if g.Time, err = parseTime(timeString); err != nil {
+		return nil, err
+	}
+
+	if g.Waypoints, err = decodeWaypoints(doc.Waypoints); err != nil {
+		return nil, err
+	}
+
+	for _, r := range doc.Routes {
+		route := &Route{
+			Name:        strings.TrimSpace(r.Name),
+			Comment:     strings.TrimSpace(r.Cmt),
+			Description: strings.TrimSpace(r.Desc),
+			Type:        strings.TrimSpace(r.Type),
+			Extensions:  r.Extensions.extensions(),
+		}
+
+		if route.Points, err = decodeWaypoints(r.Points); err != nil {
+			return nil, err
+		}
+
+		g.Routes = append(g.Routes, route)
+	}
+
+	for _, t := range doc.Tracks {
+		track := &Track{
+			Name:        strings.TrimSpace(t.Name),
+			Comment:     strings.TrimSpace(t.Cmt),
+			Description: strings.TrimSpace(t.Desc),
+			Type:        strings.TrimSpace(t.Type),
+			Extensions:  t.Extensions.extensions(),
+		}
+
+		for _, s := range t.Segments {
+			segment := &Segment{Extensions: s.Extensions.extensions()}
+			if segment.Points, err = decodeWaypoints(s.Points); err != nil {
+				return nil, err
+			}
+
+			track.Segments = append(track.Segments, segment)
+		}
+
+		g.Tracks = append(g.Tracks, track)
+	}
+
+	return g, nil
+}
+
+func (e *extensionsXML) extensions() Extensions {
+	if e == nil || len(e.Elements) == 0 {
+		return nil
+	}
+
+	e.Elements.clean()
+	return e.Elements
+}
+
+func decodeWaypoints(wpts []*waypointXML) (Waypoints, error) {
+	if len(wpts) == 0 {
+		return nil, nil
+	}
+
+	ws := make(Waypoints, 0, len(wpts))
+	for _, wpt := range wpts {
+		w, err := wpt.waypoint()
+		if err != nil {
+			return nil, err
+		}
+
+		ws = append(ws, w)
+	}
+
+	return ws, nil
+}
+
+func (wpt *waypointXML) waypoint() (Waypoint, error) {
+	lon, err := strconv.ParseFloat(strings.TrimSpace(wpt.Lon), 64)
+	if err != nil {
+		return Waypoint{}, fmt.Errorf("gpx: invalid longitude: %q", wpt.Lon)
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(wpt.Lat), 64)
+	if err != nil {
+		return Waypoint{}, fmt.Errorf("gpx: invalid latitude: %q", wpt.Lat)
+	}
+
+	w := Waypoint{
+		Point:       orb.Point{lon, lat},
+		Name:        strings.TrimSpace(wpt.Name),
+		Comment:     strings.TrimSpace(wpt.Cmt),
+		Description: strings.TrimSpace(wpt.Desc),
+		Symbol:      strings.TrimSpace(wpt.Sym),
+		Type:        strings.TrimSpace(wpt.Type),
+		Extensions:  wpt.Extensions.extensions(),
+	}
+
+	if ele := strings.TrimSpace(wpt.Ele); ele != "" {
+		e, err := strconv.ParseFloat(ele, 64)
+		if err != nil {
+			return Waypoint{}, fmt.Errorf("gpx: invalid elevation: %q", wpt.Ele)
+		}
+
+		w.Elevation = &e
+	}
+
+	if w.Time, err = parseTime(wpt.Time); err != nil {
+		return Waypoint{}, err
+	}
+
+	return w, nil
+}
+
+// timeLayouts are the xsd:dateTime layouts, the time zone is optional.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+}
+
+func parseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("gpx: invalid time: %q", s)
+}
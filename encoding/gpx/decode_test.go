@@ -0,0 +1,202 @@
+package gpx
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dadadamarine/orb"
+)
+
+const testGPX = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="device" xmlns="http://www.topografix.com/GPX/1/1"
+	xmlns:gpxtpx="http://www.garmin.com/xmlschemas/TrackPointExtension/v1">
+	<metadata>
+		<name>Morning Run</name>
+		<time>2020-05-01T06:00:00Z</time>
+	</metadata>
+	<wpt lat="52.5" lon="13.4">
+		<ele>34.5</ele>
+		<name>Start</name>
+		<sym>Flag</sym>
+	</wpt>
+	<rte>
+		<name>Plan</name>
+		<rtept lat="52.5" lon="13.4"></rtept>
+		<rtept lat="52.6" lon="13.5"></rtept>
+	</rte>
+	<trk>
+		<name>Run</name>
+		<type>running</type>
+		<trkseg>
+			<trkpt lat="52.5" lon="13.4">
+				<ele>34</ele>
+				<time>2020-05-01T06:00:00Z</time>
+				<extensions>
+					<gpxtpx:TrackPointExtension>
+						<gpxtpx:hr>120</gpxtpx:hr>
+						<gpxtpx:cad>80</gpxtpx:cad>
+					</gpxtpx:TrackPointExtension>
+				</extensions>
+			</trkpt>
+			<trkpt lat="52.51" lon="13.41">
+				<ele>35</ele>
+				<time>2020-05-01T06:00:10.5Z</time>
+			</trkpt>
+		</trkseg>
+		<trkseg>
+			<trkpt lat="52.6" lon="13.5"><time>2020-05-01T06:10:00</time></trkpt>
+		</trkseg>
+	</trk>
+</gpx>`
+
+func TestUnmarshal(t *testing.T) {
+	g, err := Unmarshal([]byte(testGPX))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	start := time.Date(2020, 5, 1, 6, 0, 0, 0, time.UTC)
+	if g.Creator != "device" || g.Name != "Morning Run" || !g.Time.Equal(start) {
+		t.Errorf("incorrect metadata: %v %v %v", g.Creator, g.Name, g.Time)
+	}
+
+	if len(g.Waypoints) != 1 {
+		t.Fatalf("incorrect waypoints: %v", g.Waypoints)
+	}
+
+	w := g.Waypoints[0]
+	if w.Point != (orb.Point{13.4, 52.5}) || w.Elevation == nil || *w.Elevation != 34.5 {
+		t.Errorf("incorrect waypoint: %v %v", w.Point, w.Elevation)
+	}
+
+	if w.Name != "Start" || w.Symbol != "Flag" || !w.Time.IsZero() {
+		t.Errorf("incorrect waypoint attributes: %v", w)
+	}
+
+	if len(g.Routes) != 1 || g.Routes[0].Name != "Plan" {
+		t.Fatalf("incorrect routes: %v", g.Routes)
+	}
+
+	if ls := g.Routes[0].LineString(); !ls.Equal(orb.LineString{{13.4, 52.5}, {13.5, 52.6}}) {
+		t.Errorf("incorrect route: %v", ls)
+	}
+
+	if len(g.Tracks) != 1 || g.Tracks[0].Name != "Run" || g.Tracks[0].Type != "running" {
+		t.Fatalf("incorrect tracks: %v", g.Tracks)
+	}
+
+	track := g.Tracks[0]
+	expected := orb.MultiLineString{{{13.4, 52.5}, {13.41, 52.51}}, {{13.5, 52.6}}}
+	if mls := track.MultiLineString(); !mls.Equal(expected) {
+		t.Errorf("incorrect track: %v", mls)
+	}
+
+	points := track.Segments[0].Points
+	if !points[1].Time.Equal(start.Add(10500 * time.Millisecond)) {
+		t.Errorf("incorrect time: %v", points[1].Time)
+	}
+
+	if tm := track.Segments[1].Points[0].Time; !tm.Equal(start.Add(10 * time.Minute)) {
+		t.Errorf("time without zone should be utc: %v", tm)
+	}
+
+	if hr, ok := points[0].Extensions.Value("hr"); !ok || hr != "120" {
+		t.Errorf("incorrect heart rate: %v %v", hr, ok)
+	}
+
+	if _, ok := points[1].Extensions.Value("hr"); ok {
+		t.Errorf("should not have heart rate")
+	}
+
+	ext := points[0].Extensions[0]
+	if ext.XMLName.Space != "http://www.garmin.com/xmlschemas/TrackPointExtension/v1" ||
+		ext.XMLName.Local != "TrackPointExtension" || ext.Value != "" || len(ext.Children) != 2 {
+		t.Errorf("incorrect extension: %v", ext)
+	}
+}
+
+func TestUnmarshal_gpx10(t *testing.T) {
+	data := []byte(`<gpx version="1.0" creator="old" xmlns="http://www.topografix.com/GPX/1/0">
+		<name>Old</name>
+		<time>2004-01-02T03:04:05Z</time>
+		<trk><trkseg><trkpt lat="1" lon="2"><ele>3</ele></trkpt></trkseg></trk>
+	</gpx>`)
+
+	g, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if g.Name != "Old" || g.Time.Year() != 2004 {
+		t.Errorf("incorrect header: %v %v", g.Name, g.Time)
+	}
+
+	w := g.Tracks[0].Segments[0].Points[0]
+	if w.Point != (orb.Point{2, 1}) || *w.Elevation != 3 {
+		t.Errorf("incorrect point: %v", w)
+	}
+}
+
+func TestUnmarshal_errors(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "not xml",
+			data: `not xml`,
+		},
+		{
+			name: "invalid latitude",
+			data: `<gpx><wpt lat="a" lon="1"></wpt></gpx>`,
+		},
+		{
+			name: "missing longitude",
+			data: `<gpx><rte><rtept lat="1"></rtept></rte></gpx>`,
+		},
+		{
+			name: "invalid elevation",
+			data: `<gpx><trk><trkseg><trkpt lat="1" lon="1"><ele>high</ele></trkpt></trkseg></trk></gpx>`,
+		},
+		{
+			name: "invalid time",
+			data: `<gpx><trk><trkseg><trkpt lat="1" lon="1"><time>yesterday</time></trkpt></trkseg></trk></gpx>`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Unmarshal([]byte(tc.data)); err == nil {
+				t.Errorf("should return error")
+			}
+		})
+	}
+}
+
+func TestExtensions_Value(t *testing.T) {
+	es := Extensions{
+		{XMLName: xmlName("a"), Value: "1"},
+		{XMLName: xmlName("b"), Children: []Extension{
+			{XMLName: xmlName("c"), Value: "2"},
+		}},
+		{XMLName: xmlName("c"), Value: "3"},
+	}
+
+	cases := []struct {
+		name  string
+		value string
+		ok    bool
+	}{
+		{name: "a", value: "1", ok: true},
+		{name: "c", value: "2", ok: true},
+		{name: "d", value: "", ok: false},
+	}
+
+	for _, tc := range cases {
+		v, ok := es.Value(tc.name)
+		if !reflect.DeepEqual([]interface{}{v, ok}, []interface{}{tc.value, tc.ok}) {
+			t.Errorf("%s: incorrect value: %v %v", tc.name, v, ok)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package gpx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Marshal encodes the GPX as version 1.1. Times are written in UTC.
+func Marshal(g *GPX) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := Encode(buf, g); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Encode writes the GPX to the writer, see Marshal.
+func Encode(w io.Writer, g *GPX) error {
+	doc := &gpxXML{
+		Xmlns:      Namespace,
+		Version:    "1.1",
+		Creator:    g.Creator,
+		Waypoints:  encodeWaypoints(g.Waypoints),
+		Extensions: encodeExtensions(g.Extensions),
+	}
+
+	if doc.Creator == "" {
+		doc.Creator = DefaultCreator
+	}
+
+	if g.Name != "" || g.Description != "" || !g.Time.IsZero() {
+		doc.Metadata = &metadataXML{
+			Name: g.Name,
+			Desc: g.Description,
+			Time: formatTime(g.Time),
+		}
+	}
+
+	for _, r := range g.Routes {
+		doc.Routes = append(doc.Routes, &routeXML{
+			Name:       r.Name,
+			Cmt:        r.Comment,
+			Desc:       r.Description,
+			Type:       r.Type,
+			Extensions: encodeExtensions(r.Extensions),
+			Points:     encodeWaypoints(r.Points),
+		})
+	}
+
+	for _, t := range g.Tracks {
+		track := &trackXML{
+			Name:       t.Name,
+			Cmt:        t.Comment,
+			Desc:       t.Description,
+			Type:       t.Type,
+			Extensions: encodeExtensions(t.Extensions),
+		}
+
+		for _, s := range t.Segments {
+			track.Segments = append(track.Segments, &segmentXML{
+				Points:     encodeWaypoints(s.Points),
+				Extensions: encodeExtensions(s.Extensions),
+			})
+		}
+
+		doc.Tracks = append(doc.Tracks, track)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(doc); err != nil {
+		return fmt.Errorf("gpx: %v", err)
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func encodeWaypoints(ws Waypoints) []*waypointXML {
+	if len(ws) == 0 {
+		return nil
+	}
+
+	result := make([]*waypointXML, 0, len(ws))
+	for _, w := range ws {
+		wpt := &waypointXML{
+			Lat:        formatFloat(w.Point.Lat()),
+			Lon:        formatFloat(w.Point.Lon()),
+			Time:       formatTime(w.Time),
+			Name:       w.Name,
+			Cmt:        w.Comment,
+			Desc:       w.Description,
+			Sym:        w.Symbol,
+			Type:       w.Type,
+			Extensions: encodeExtensions(w.Extensions),
+		}
+
+		if w.Elevation != nil {
+			wpt.Ele = formatFloat(*w.Elevation)
+		}
+
+		result = append(result, wpt)
+	}
+
+	return result
+}
+
+func encodeExtensions(es Extensions) *extensionsXML {
+	if len(es) == 0 {
+		return nil
+	}
+
+	return &extensionsXML{Elements: es}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,80 @@
+package gpx
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestMarshal(t *testing.T) {
+	g, err := Unmarshal([]byte(testGPX))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	data, err := Marshal(g)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	if !strings.Contains(string(data), `<gpx xmlns="`+Namespace+`" version="1.1" creator="device">`) {
+		t.Errorf("incorrect gpx element: %s", data)
+	}
+
+	result, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if !reflect.DeepEqual(result, g) {
+		t.Errorf("round trip should be equal")
+		t.Logf("%+v", result)
+		t.Logf("%+v", g)
+	}
+}
+
+func TestMarshal_format(t *testing.T) {
+	ele := 0.5
+	g := &GPX{
+		Waypoints: Waypoints{{
+			Point:     orb.Point{0.000001, -12.25},
+			Elevation: &ele,
+			Time:      time.Date(2020, 5, 1, 8, 0, 0, 0, time.FixedZone("", 2*3600)),
+		}},
+		Tracks: []*Track{{Segments: []*Segment{{
+			Points: Waypoints{{
+				Extensions: Extensions{{XMLName: xmlName("speed"), Value: "2.5"}},
+			}},
+		}}}},
+	}
+
+	data, err := Marshal(g)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	for _, s := range []string{
+		`creator="orb"`,
+		`<wpt lat="-12.25" lon="0.000001">`,
+		`<ele>0.5</ele>`,
+		`<time>2020-05-01T06:00:00Z</time>`,
+		`<extensions>`,
+		`<speed>2.5</speed>`,
+	} {
+		if !strings.Contains(string(data), s) {
+			t.Errorf("should contain %s: %s", s, data)
+		}
+	}
+
+	if strings.Contains(string(data), "metadata") {
+		t.Errorf("should not have metadata: %s", data)
+	}
+}
+
+func xmlName(local string) xml.Name {
+	return xml.Name{Local: local}
+}
@@ -0,0 +1,247 @@
+// Package gpx decodes and encodes GPX, the GPS Exchange Format, with
+// tracks, routes and waypoints as orb geometries.
+// Specification at https://www.topografix.com/GPX/1/1/
+package gpx
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+
+	"github.com/dadadamarine/orb"
+)
+
+// Namespace is the GPX 1.1 namespace used when encoding.
+const Namespace = "http://www.topografix.com/GPX/1/1"
+
+// DefaultCreator is the creator attribute used when encoding
+// if the GPX does not have one.
+const DefaultCreator = "orb"
+
+// GPX is a GPX document with its waypoints, routes and tracks.
+type GPX struct {
+	Creator string
+
+	// Name, Description and Time are from the metadata element.
+	Name        string
+	Description string
+	Time        time.Time
+
+	Waypoints Waypoints
+	Routes    []*Route
+	Tracks    []*Track
+
+	Extensions Extensions
+}
+
+// A Waypoint is a point of a track segment or route, or a standalone
+// waypoint. Time is zero and Elevation is nil if they are not set.
+type Waypoint struct {
+	Point     orb.Point
+	Elevation *float64
+	Time      time.Time
+
+	Name        string
+	Comment     string
+	Description string
+	Symbol      string
+	Type        string
+
+	Extensions Extensions
+}
+
+// Waypoints are the points of a track segment or route,
+// or the waypoints of the document.
+type Waypoints []Waypoint
+
+// LineString returns the points of the waypoints as a line string.
+func (ws Waypoints) LineString() orb.LineString {
+	ls := make(orb.LineString, 0, len(ws))
+	for _, w := range ws {
+		ls = append(ls, w.Point)
+	}
+
+	return ls
+}
+
+// MultiPoint returns the points of the waypoints as a multi point.
+func (ws Waypoints) MultiPoint() orb.MultiPoint {
+	return orb.MultiPoint(ws.LineString())
+}
+
+// A Route is an ordered list of waypoints leading to a destination.
+type Route struct {
+	Name        string
+	Comment     string
+	Description string
+	Type        string
+
+	Points Waypoints
+
+	Extensions Extensions
+}
+
+// LineString returns the route points as a line string.
+func (r *Route) LineString() orb.LineString {
+	return r.Points.LineString()
+}
+
+// A Track is an ordered list of segments, each a continuous
+// span of recorded points.
+type Track struct {
+	Name        string
+	Comment     string
+	Description string
+	Type        string
+
+	Segments []*Segment
+
+	Extensions Extensions
+}
+
+// MultiLineString returns the track segments as a multi line string.
+func (t *Track) MultiLineString() orb.MultiLineString {
+	mls := make(orb.MultiLineString, 0, len(t.Segments))
+	for _, s := range t.Segments {
+		mls = append(mls, s.LineString())
+	}
+
+	return mls
+}
+
+// A Segment is a continuous span of track points, a new segment
+// is usually started if the GPS receiver lost its fix.
+type Segment struct {
+	Points Waypoints
+
+	Extensions Extensions
+}
+
+// LineString returns the segment points as a line string.
+func (s *Segment) LineString() orb.LineString {
+	return s.Points.LineString()
+}
+
+// An Extension is an element in the extensions of a GPX element,
+// e.g. the heart rate of a Garmin TrackPointExtension. The namespaces
+// of the elements are kept but their prefixes are not.
+type Extension struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr  `xml:",any,attr"`
+	Value    string      `xml:",chardata"`
+	Children []Extension `xml:",any"`
+}
+
+// Extensions are the elements in the extensions of a GPX element.
+type Extensions []Extension
+
+// Value returns the value of the first element with the local name,
+// searching depth first. Returns false if there is no such element.
+func (es Extensions) Value(name string) (string, bool) {
+	for _, e := range es {
+		if e.XMLName.Local == name {
+			return e.Value, true
+		}
+
+		if v, ok := Extensions(e.Children).Value(name); ok {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// clean trims the whitespace between child elements and removes the
+// namespace declarations, which the encoder writes for the element names.
+func (es Extensions) clean() {
+	for i := range es {
+		e := &es[i]
+		e.Value = strings.TrimSpace(e.Value)
+
+		attrs := e.Attrs[:0]
+		for _, a := range e.Attrs {
+			if a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns") {
+				continue
+			}
+
+			attrs = append(attrs, a)
+		}
+
+		e.Attrs = attrs
+		if len(e.Attrs) == 0 {
+			e.Attrs = nil
+		}
+
+		Extensions(e.Children).clean()
+	}
+}
+
+// The xml types below are used for both decoding and encoding. Elements are
+// matched by their local name so both GPX 1.0 and 1.1 can be decoded. Numbers
+// are strings to control their format when encoding, encoding/xml uses
+// exponents for small values.
+
+type gpxXML struct {
+	XMLName  xml.Name     `xml:"gpx"`
+	Xmlns    string       `xml:"xmlns,attr,omitempty"`
+	Version  string       `xml:"version,attr"`
+	Creator  string       `xml:"creator,attr"`
+	Metadata *metadataXML `xml:"metadata"`
+
+	// GPX 1.0 has these at the top level, they are only decoded.
+	Name string `xml:"name,omitempty"`
+	Desc string `xml:"desc,omitempty"`
+	Time string `xml:"time,omitempty"`
+
+	Waypoints  []*waypointXML `xml:"wpt"`
+	Routes     []*routeXML    `xml:"rte"`
+	Tracks     []*trackXML    `xml:"trk"`
+	Extensions *extensionsXML `xml:"extensions"`
+}
+
+type metadataXML struct {
+	Name       string         `xml:"name,omitempty"`
+	Desc       string         `xml:"desc,omitempty"`
+	Time       string         `xml:"time,omitempty"`
+	Extensions *extensionsXML `xml:"extensions"`
+}
+
+type waypointXML struct {
+	Lat        string         `xml:"lat,attr"`
+	Lon        string         `xml:"lon,attr"`
+	Ele        string         `xml:"ele,omitempty"`
+	Time       string         `xml:"time,omitempty"`
+	Name       string         `xml:"name,omitempty"`
+	Cmt        string         `xml:"cmt,omitempty"`
+	Desc       string         `xml:"desc,omitempty"`
+	Sym        string         `xml:"sym,omitempty"`
+	Type       string         `xml:"type,omitempty"`
+	Extensions *extensionsXML `xml:"extensions"`
+}
+
+type routeXML struct {
+	Name       string         `xml:"name,omitempty"`
+	Cmt        string         `xml:"cmt,omitempty"`
+	Desc       string         `xml:"desc,omitempty"`
+	Type       string         `xml:"type,omitempty"`
+	Extensions *extensionsXML `xml:"extensions"`
+	Points     []*waypointXML `xml:"rtept"`
+}
+
+type trackXML struct {
+	Name       string         `xml:"name,omitempty"`
+	Cmt        string         `xml:"cmt,omitempty"`
+	Desc       string         `xml:"desc,omitempty"`
+	Type       string         `xml:"type,omitempty"`
+	Extensions *extensionsXML `xml:"extensions"`
+	Segments   []*segmentXML  `xml:"trkseg"`
+}
+
+type segmentXML struct {
+	Points     []*waypointXML `xml:"trkpt"`
+	Extensions *extensionsXML `xml:"extensions"`
+}
+
+type extensionsXML struct {
+	Elements Extensions `xml:",any"`
+}
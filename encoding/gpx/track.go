@@ -0,0 +1,83 @@
+package gpx
+
+import (
+	"time"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/resample"
+)
+
+// LineStringIndexMapper is implemented by the simplifiers
+// and smoothers of the simplify package.
+type LineStringIndexMapper interface {
+	LineStringIndexMap(ls orb.LineString) (orb.LineString, []int)
+}
+
+// Simplify returns the waypoints kept by the simplifier, e.g.
+// simplify.GeoDouglasPeucker(5). The kept points have the attributes of
+// the original point, for smoothers that of the point each new point
+// was derived from. The waypoints are not modified.
+func (ws Waypoints) Simplify(s LineStringIndexMapper) Waypoints {
+	ls, indexes := s.LineStringIndexMap(ws.LineString())
+
+	result := make(Waypoints, 0, len(indexes))
+	for i, index := range indexes {
+		w := ws[index]
+		w.Point = ls[i]
+		result = append(result, w)
+	}
+
+	return result
+}
+
+// Track returns the waypoints as a resample track. The times are set if
+// all the points have a time and the elevations are the first channel if
+// all the points have an elevation.
+func (ws Waypoints) Track() resample.Track {
+	t := resample.Track{LineString: ws.LineString()}
+
+	times := make([]time.Time, 0, len(ws))
+	elevations := make([]float64, 0, len(ws))
+	for _, w := range ws {
+		if !w.Time.IsZero() {
+			times = append(times, w.Time)
+		}
+
+		if w.Elevation != nil {
+			elevations = append(elevations, *w.Elevation)
+		}
+	}
+
+	if len(ws) > 0 && len(times) == len(ws) {
+		t.Times = times
+	}
+
+	if len(ws) > 0 && len(elevations) == len(ws) {
+		t.Channels = [][]float64{elevations}
+	}
+
+	return t
+}
+
+// WaypointsFromTrack returns the waypoints of a resample track, the
+// first channel, if any, are the elevations. Use with Track to resample
+// waypoints, the other attributes are not kept.
+//
+//	seg.Points = gpx.WaypointsFromTrack(seg.Points.Track().ToTimeInterval(time.Second))
+func WaypointsFromTrack(t resample.Track) Waypoints {
+	ws := make(Waypoints, len(t.LineString))
+	for i, p := range t.LineString {
+		ws[i].Point = p
+
+		if i < len(t.Times) {
+			ws[i].Time = t.Times[i]
+		}
+
+		if len(t.Channels) > 0 && i < len(t.Channels[0]) {
+			e := t.Channels[0][i]
+			ws[i].Elevation = &e
+		}
+	}
+
+	return ws
+}
@@ -0,0 +1,95 @@
+package gpx
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/planar"
+	"github.com/dadadamarine/orb/simplify"
+)
+
+func testWaypoints() Waypoints {
+	start := time.Date(2020, 5, 1, 6, 0, 0, 0, time.UTC)
+
+	ws := Waypoints{}
+	for i, p := range []orb.Point{{0, 0}, {1, 0.1}, {2, 0}, {3, 5}, {4, 6}} {
+		e := float64(10 * i)
+		ws = append(ws, Waypoint{
+			Point:     p,
+			Elevation: &e,
+			Time:      start.Add(time.Duration(i) * time.Second),
+			Name:      string(rune('a' + i)),
+		})
+	}
+
+	return ws
+}
+
+func TestWaypoints_Simplify(t *testing.T) {
+	ws := testWaypoints()
+
+	result := ws.Simplify(simplify.DouglasPeucker(0.5))
+	if !result.LineString().Equal(orb.LineString{{0, 0}, {2, 0}, {3, 5}, {4, 6}}) {
+		t.Errorf("incorrect line string: %v", result.LineString())
+	}
+
+	names := []string{}
+	for _, w := range result {
+		names = append(names, w.Name)
+	}
+
+	if !reflect.DeepEqual(names, []string{"a", "c", "d", "e"}) {
+		t.Errorf("attributes should be kept: %v", names)
+	}
+
+	if *result[1].Elevation != 20 || !result[1].Time.Equal(ws[2].Time) {
+		t.Errorf("incorrect attributes: %v", result[1])
+	}
+
+	if len(ws) != 5 || ws[1].Point != (orb.Point{1, 0.1}) {
+		t.Errorf("should not modify the waypoints: %v", ws)
+	}
+
+	if result := (Waypoints{}).Simplify(simplify.DouglasPeucker(1)); len(result) != 0 {
+		t.Errorf("should be empty: %v", result)
+	}
+}
+
+func TestWaypoints_Track(t *testing.T) {
+	ws := testWaypoints()
+
+	track := ws.Track()
+	if len(track.Times) != 5 || len(track.Channels) != 1 || track.Channels[0][4] != 40 {
+		t.Errorf("incorrect track: %v", track)
+	}
+
+	// resample to every half a second
+	result := WaypointsFromTrack(track.ToTimeInterval(500 * time.Millisecond))
+	if len(result) != 9 {
+		t.Fatalf("incorrect number of points: %d", len(result))
+	}
+
+	if result[1].Point != (orb.Point{0.5, 0.05}) || *result[1].Elevation != 5 {
+		t.Errorf("incorrect point: %v %v", result[1].Point, *result[1].Elevation)
+	}
+
+	if !result[1].Time.Equal(ws[0].Time.Add(500 * time.Millisecond)) {
+		t.Errorf("incorrect time: %v", result[1].Time)
+	}
+
+	// missing values are not part of the track
+	ws[2].Elevation = nil
+	ws[3].Time = time.Time{}
+
+	track = ws.Track()
+	if track.Times != nil || track.Channels != nil {
+		t.Errorf("should only have the line string: %v", track)
+	}
+
+	result = WaypointsFromTrack(track.ToInterval(planar.Distance, 1))
+	if len(result) == 0 || result[0].Elevation != nil || !result[0].Time.IsZero() {
+		t.Errorf("incorrect waypoints: %v", result)
+	}
+}
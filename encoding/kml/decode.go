@@ -0,0 +1,451 @@
+package kml
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+)
+
+// Unmarshal decodes the KML data. If the kml element has one Document
+// it is the returned folder, otherwise the returned folder contains the
+// top level documents, folders and placemarks.
+//
+// Placemarks are decoded as features with the name, description and
+// styleUrl as properties along with the ExtendedData. Data values are
+// strings, SchemaData values are typed by the SimpleField types of the
+// Schema: int, uint, short and ushort as int64, float and double as
+// float64 and bool as bool. The id attribute is the feature id.
+// Altitudes are dropped.
+func Unmarshal(data []byte) (*Folder, error) {
+	return Decode(bytes.NewReader(data))
+}
+
+// Decode decodes the KML from the reader, see Unmarshal.
+func Decode(r io.Reader) (*Folder, error) {
+	root := &kmlRoot{}
+	if err := xml.NewDecoder(r).Decode(root); err != nil {
+		return nil, fmt.Errorf("kml: %v", err)
+	}
+
+	d := &decoder{schemas: map[string]map[string]string{}}
+
+	top := &container{containerFeatures: root.containerFeatures}
+	if subs := root.containers(); len(subs) == 1 && subs[0].Kind == "Document" && len(root.Placemarks) == 0 {
+		top = subs[0].container
+	}
+
+	d.collectSchemas(top)
+	return d.folder(top)
+}
+
+// UnmarshalKMZ decodes the KMZ data, see ReadKMZ.
+func UnmarshalKMZ(data []byte) (*Folder, error) {
+	return ReadKMZ(bytes.NewReader(data), int64(len(data)))
+}
+
+// ReadKMZ decodes a KMZ, a zip archive of a KML file and its resources.
+// The KML is the doc.kml file, or if there is none the first .kml file
+// in the root of the archive.
+func ReadKMZ(r io.ReaderAt, size int64) (*Folder, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("kml: invalid kmz: %v", err)
+	}
+
+	var doc *zip.File
+	for _, f := range zr.File {
+		if !strings.EqualFold(path.Ext(f.Name), ".kml") || strings.Contains(f.Name, "/") {
+			continue
+		}
+
+		if strings.EqualFold(f.Name, "doc.kml") {
+			doc = f
+			break
+		}
+
+		if doc == nil {
+			doc = f
+		}
+	}
+
+	if doc == nil {
+		return nil, fmt.Errorf("kml: no kml file in kmz")
+	}
+
+	rc, err := doc.Open()
+	if err != nil {
+		return nil, fmt.Errorf("kml: invalid kmz: %v", err)
+	}
+	defer rc.Close()
+
+	data, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("kml: invalid kmz: %v", err)
+	}
+
+	return Unmarshal(data)
+}
+
+type decoder struct {
+	// schemas are the SimpleField types of each schema by id.
+	schemas map[string]map[string]string
+}
+
+func (d *decoder) collectSchemas(c *container) {
+	for _, s := range c.Schemas {
+		types := map[string]string{}
+		for _, f := range s.SimpleFields {
+			types[f.Name] = strings.ToLower(f.Type)
+		}
+
+		if s.ID != "" {
+			d.schemas[s.ID] = types
+		}
+
+		if s.Name != "" {
+			d.schemas[s.Name] = types
+		}
+	}
+
+	for _, sub := range c.containers() {
+		d.collectSchemas(sub.container)
+	}
+}
+
+func (d *decoder) folder(c *container) (*Folder, error) {
+	f := &Folder{
+		Name:        strings.TrimSpace(c.Name),
+		Description: strings.TrimSpace(c.Description),
+		Features:    geojson.NewFeatureCollection(),
+	}
+
+	for _, s := range c.Styles {
+		f.Styles = append(f.Styles, s.style())
+	}
+
+	for _, p := range c.Placemarks {
+		feature, err := d.feature(p)
+		if err != nil {
+			return nil, err
+		}
+
+		f.Features.Append(feature)
+	}
+
+	// documents nested in a document are treated as folders
+	for _, sub := range c.containers() {
+		folder, err := d.folder(sub.container)
+		if err != nil {
+			return nil, err
+		}
+
+		f.Folders = append(f.Folders, folder)
+	}
+
+	return f, nil
+}
+
+func (s *xmlStyle) style() Style {
+	style := Style{ID: s.ID}
+	if s.IconStyle != nil {
+		style.IconHref = strings.TrimSpace(s.IconStyle.Href)
+	}
+
+	if s.LineStyle != nil {
+		style.LineColor = strings.TrimSpace(s.LineStyle.Color)
+		style.LineWidth = s.LineStyle.Width
+	}
+
+	if s.PolyStyle != nil {
+		style.PolyColor = strings.TrimSpace(s.PolyStyle.Color)
+	}
+
+	return style
+}
+
+func (d *decoder) feature(p *placemark) (*geojson.Feature, error) {
+	g, err := geometry(p.Geometries)
+	if err != nil {
+		return nil, err
+	}
+
+	f := geojson.NewFeature(g)
+	if p.ID != "" {
+		f.ID = p.ID
+	}
+
+	if name := strings.TrimSpace(p.Name); name != "" {
+		f.Properties["name"] = name
+	}
+
+	if desc := strings.TrimSpace(p.Description); desc != "" {
+		f.Properties["description"] = desc
+	}
+
+	if url := strings.TrimSpace(p.StyleURL); url != "" {
+		f.Properties["styleUrl"] = url
+	}
+
+	if p.ExtendedData == nil {
+		return f, nil
+	}
+
+	for _, data := range p.ExtendedData.Data {
+		f.Properties[data.Name] = data.Value
+	}
+
+	for _, sd := range p.ExtendedData.SchemaData {
+		types := d.schemas[strings.TrimPrefix(sd.SchemaURL, "#")]
+		for _, v := range sd.SimpleData {
+			f.Properties[v.Name] = simpleValue(types[v.Name], v.Value)
+		}
+	}
+
+	return f, nil
+}
+
+// simpleValue converts the value to the SimpleField type,
+// returning the string if it is not valid.
+func simpleValue(typ, s string) interface{} {
+	v := strings.TrimSpace(s)
+
+	switch typ {
+	case "int", "uint", "short", "ushort":
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i
+		}
+	case "float", "double":
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	case "bool":
+		switch strings.ToLower(v) {
+		case "1", "true":
+			return true
+		case "0", "false":
+			return false
+		}
+	}
+
+	return s
+}
+
+// geometry returns the geometry of the elements, nil if there are none,
+// the geometry if there is one and a collection, in order, if there are more.
+func geometry(elements []*geometryElement) (orb.Geometry, error) {
+	geoms, err := geometries(elements)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(geoms) {
+	case 0:
+		return nil, nil
+	case 1:
+		return geoms[0], nil
+	}
+
+	return geoms, nil
+}
+
+// geometries returns the geometries of the elements in order.
+func geometries(elements []*geometryElement) (orb.Collection, error) {
+	geoms := orb.Collection{}
+	for _, e := range elements {
+		g, err := e.geometry()
+		if err != nil {
+			return nil, err
+		}
+
+		if g != nil {
+			geoms = append(geoms, g)
+		}
+	}
+
+	return geoms, nil
+}
+
+// geometry returns the geometry of the element, nil for
+// points without coordinates and skipped elements.
+func (g *geometryElement) geometry() (orb.Geometry, error) {
+	switch {
+	case g.Point != nil:
+		ps, err := coordinates(g.Point.Coordinates)
+		if err != nil || len(ps) == 0 {
+			return nil, err
+		}
+
+		return ps[0], nil
+	case g.LineString != nil:
+		ps, err := coordinates(g.LineString.Coordinates)
+		if err != nil {
+			return nil, err
+		}
+
+		return orb.LineString(ps), nil
+	case g.LinearRing != nil:
+		ps, err := coordinates(g.LinearRing.Coordinates)
+		if err != nil {
+			return nil, err
+		}
+
+		return orb.Polygon{orb.Ring(ps)}, nil
+	case g.Polygon != nil:
+		return g.Polygon.polygon()
+	case g.Track != nil:
+		return g.Track.lineString()
+	case g.MultiGeometry != nil:
+		return g.MultiGeometry.geometry()
+	}
+
+	return nil, nil
+}
+
+// geometry returns the geometry of the MultiGeometry element,
+// a multi point, line string or polygon if all the children are of the
+// same type, otherwise a collection in the order of the children.
+func (m *multiGeometry) geometry() (orb.Geometry, error) {
+	c, err := geometries(m.Geometries)
+	if err != nil || len(c) == 0 {
+		return c, err
+	}
+
+	switch c[0].(type) {
+	case orb.Point:
+		mp := make(orb.MultiPoint, 0, len(c))
+		for _, g := range c {
+			p, ok := g.(orb.Point)
+			if !ok {
+				return c, nil
+			}
+			mp = append(mp, p)
+		}
+
+		return mp, nil
+	case orb.LineString:
+		mls := make(orb.MultiLineString, 0, len(c))
+		for _, g := range c {
+			ls, ok := g.(orb.LineString)
+			if !ok {
+				return c, nil
+			}
+			mls = append(mls, ls)
+		}
+
+		return mls, nil
+	case orb.Polygon:
+		mp := make(orb.MultiPolygon, 0, len(c))
+		for _, g := range c {
+			p, ok := g.(orb.Polygon)
+			if !ok {
+				return c, nil
+			}
+			mp = append(mp, p)
+		}
+
+		return mp, nil
+	}
+
+	return c, nil
+}
+
+func (p *polygon) polygon() (orb.Polygon, error) {
+	var polygon orb.Polygon
+
+	if p.Outer != nil {
+		for _, r := range p.Outer.LinearRings {
+			ps, err := coordinates(r.Coordinates)
+			if err != nil {
+				return nil, err
+			}
+
+			polygon = append(polygon, orb.Ring(ps))
+		}
+	}
+
+	if len(polygon) == 0 {
+		return orb.Polygon{}, nil
+	}
+
+	for _, b := range p.Inner {
+		for _, r := range b.LinearRings {
+			ps, err := coordinates(r.Coordinates)
+			if err != nil {
+				return nil, err
+			}
+
+			polygon = append(polygon, orb.Ring(ps))
+		}
+	}
+
+	return polygon, nil
+}
+
+func (t *track) lineString() (orb.LineString, error) {
+	ls := make(orb.LineString, 0, len(t.Coords))
+	for _, c := range t.Coords {
+		fields := strings.Fields(c)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("kml: invalid track coordinate: %q", c)
+		}
+
+		p, err := parsePoint(fields[0], fields[1])
+		if err != nil {
+			return nil, err
+		}
+
+		ls = append(ls, p)
+	}
+
+	return ls, nil
+}
+
+// commaSpace matches the spaces around commas, some files have
+// coordinates like "1, 2" which are not valid but are accepted.
+var commaSpace = regexp.MustCompile(`\s*,\s*`)
+
+// coordinates parses the space separated lon,lat[,alt] tuples.
+func coordinates(s string) ([]orb.Point, error) {
+	fields := strings.Fields(commaSpace.ReplaceAllString(s, ","))
+
+	ps := make([]orb.Point, 0, len(fields))
+	for _, f := range fields {
+		values := strings.Split(f, ",")
+		if len(values) < 2 {
+			return nil, fmt.Errorf("kml: invalid coordinates: %q", f)
+		}
+
+		p, err := parsePoint(values[0], values[1])
+		if err != nil {
+			return nil, err
+		}
+
+		ps = append(ps, p)
+	}
+
+	return ps, nil
+}
+
+func parsePoint(lon, lat string) (orb.Point, error) {
+	x, err := strconv.ParseFloat(lon, 64)
+	if err != nil {
+		return orb.Point{}, fmt.Errorf("kml: invalid coordinate: %q", lon)
+	}
+
+	y, err := strconv.ParseFloat(lat, 64)
+	if err != nil {
+		return orb.Point{}, fmt.Errorf("kml: invalid coordinate: %q", lat)
+	}
+
+	return orb.Point{x, y}, nil
+}
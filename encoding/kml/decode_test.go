@@ -0,0 +1,291 @@
+package kml
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+)
+
+const testKML = `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2" xmlns:gx="http://www.google.com/kml/ext/2.2">
+<Document>
+	<name>Test</name>
+	<Style id="red">
+		<LineStyle><color>ff0000ff</color><width>2.5</width></LineStyle>
+		<PolyStyle><color>7f0000ff</color></PolyStyle>
+	</Style>
+	<Schema name="trail" id="trailSchema">
+		<SimpleField name="length" type="double"/>
+		<SimpleField name="lanes" type="int"/>
+		<SimpleField name="paved" type="bool"/>
+		<SimpleField name="surface" type="string"/>
+	</Schema>
+	<Placemark id="p1">
+		<name> Point </name>
+		<styleUrl>#red</styleUrl>
+		<ExtendedData>
+			<Data name="height"><value>12</value></Data>
+		</ExtendedData>
+		<Point><coordinates>1.5,2.5,100</coordinates></Point>
+	</Placemark>
+	<Placemark>
+		<name>Trail</name>
+		<ExtendedData>
+			<SchemaData schemaUrl="#trailSchema">
+				<SimpleData name="length">12.5</SimpleData>
+				<SimpleData name="lanes">2</SimpleData>
+				<SimpleData name="paved">1</SimpleData>
+				<SimpleData name="surface">gravel</SimpleData>
+				<SimpleData name="other">x</SimpleData>
+			</SchemaData>
+		</ExtendedData>
+		<LineString><coordinates>
+			1,2 3,4
+			5, 6
+		</coordinates></LineString>
+	</Placemark>
+	<Folder>
+		<name>Areas</name>
+		<Placemark>
+			<Polygon>
+				<outerBoundaryIs><LinearRing><coordinates>0,0 10,0 10,10 0,10 0,0</coordinates></LinearRing></outerBoundaryIs>
+				<innerBoundaryIs><LinearRing><coordinates>1,1 2,1 2,2 1,1</coordinates></LinearRing></innerBoundaryIs>
+				<innerBoundaryIs><LinearRing><coordinates>5,5 6,5 6,6 5,5</coordinates></LinearRing></innerBoundaryIs>
+			</Polygon>
+		</Placemark>
+		<Folder>
+			<name>Nested</name>
+			<Placemark>
+				<MultiGeometry>
+					<Point><coordinates>1,1</coordinates></Point>
+					<Point><coordinates>2,2</coordinates></Point>
+				</MultiGeometry>
+			</Placemark>
+			<Placemark>
+				<MultiGeometry>
+					<Point><coordinates>1,1</coordinates></Point>
+					<LineString><coordinates>1,1 2,2</coordinates></LineString>
+				</MultiGeometry>
+			</Placemark>
+			<Placemark>
+				<gx:Track>
+					<when>2020-01-01T00:00:00Z</when>
+					<gx:coord>1 2 3</gx:coord>
+					<gx:coord>4 5 6</gx:coord>
+				</gx:Track>
+			</Placemark>
+		</Folder>
+	</Folder>
+</Document>
+</kml>`
+
+func TestUnmarshal(t *testing.T) {
+	f, err := Unmarshal([]byte(testKML))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if f.Name != "Test" {
+		t.Errorf("incorrect name: %v", f.Name)
+	}
+
+	expectedStyles := []Style{{ID: "red", LineColor: "ff0000ff", LineWidth: 2.5, PolyColor: "7f0000ff"}}
+	if !reflect.DeepEqual(f.Styles, expectedStyles) {
+		t.Errorf("incorrect styles: %v", f.Styles)
+	}
+
+	if len(f.Features.Features) != 2 {
+		t.Fatalf("incorrect number of features: %d", len(f.Features.Features))
+	}
+
+	point := f.Features.Features[0]
+	if point.ID != "p1" {
+		t.Errorf("incorrect id: %v", point.ID)
+	}
+
+	if !orb.Equal(point.Geometry, orb.Point{1.5, 2.5}) {
+		t.Errorf("incorrect geometry: %v", point.Geometry)
+	}
+
+	expected := geojson.Properties{"name": "Point", "styleUrl": "#red", "height": "12"}
+	if !reflect.DeepEqual(point.Properties, expected) {
+		t.Errorf("incorrect properties: %v", point.Properties)
+	}
+
+	trail := f.Features.Features[1]
+	if !orb.Equal(trail.Geometry, orb.LineString{{1, 2}, {3, 4}, {5, 6}}) {
+		t.Errorf("incorrect geometry: %v", trail.Geometry)
+	}
+
+	expected = geojson.Properties{
+		"name":    "Trail",
+		"length":  12.5,
+		"lanes":   int64(2),
+		"paved":   true,
+		"surface": "gravel",
+		"other":   "x",
+	}
+	if !reflect.DeepEqual(trail.Properties, expected) {
+		t.Errorf("incorrect properties: %v", trail.Properties)
+	}
+
+	if len(f.Folders) != 1 || f.Folders[0].Name != "Areas" {
+		t.Fatalf("incorrect folders: %v", f.Folders)
+	}
+
+	areas := f.Folders[0]
+	expectedPolygon := orb.Polygon{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{1, 1}, {2, 1}, {2, 2}, {1, 1}},
+		{{5, 5}, {6, 5}, {6, 6}, {5, 5}},
+	}
+	if g := areas.Features.Features[0].Geometry; !orb.Equal(g, expectedPolygon) {
+		t.Errorf("incorrect polygon: %v", g)
+	}
+
+	if len(areas.Folders) != 1 || areas.Folders[0].Name != "Nested" {
+		t.Fatalf("incorrect nested folders: %v", areas.Folders)
+	}
+
+	nested := areas.Folders[0].Features.Features
+	if g := nested[0].Geometry; !orb.Equal(g, orb.MultiPoint{{1, 1}, {2, 2}}) {
+		t.Errorf("incorrect multi point: %v", g)
+	}
+
+	if g := nested[1].Geometry; !orb.Equal(g, orb.Collection{orb.Point{1, 1}, orb.LineString{{1, 1}, {2, 2}}}) {
+		t.Errorf("incorrect collection: %v", g)
+	}
+
+	if g := nested[2].Geometry; !orb.Equal(g, orb.LineString{{1, 2}, {4, 5}}) {
+		t.Errorf("incorrect track: %v", g)
+	}
+
+	if l := len(f.All().Features); l != 6 {
+		t.Errorf("incorrect number of all features: %d", l)
+	}
+}
+
+func TestUnmarshal_noDocument(t *testing.T) {
+	data := []byte(`<kml><Placemark><Point><coordinates>1,2</coordinates></Point></Placemark>
+		<Folder><name>a</name></Folder></kml>`)
+
+	f, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if len(f.Features.Features) != 1 || len(f.Folders) != 1 || f.Folders[0].Name != "a" {
+		t.Errorf("incorrect folder: %v", f)
+	}
+}
+
+func TestUnmarshal_order(t *testing.T) {
+	data := []byte(`<kml><Document>
+		<Folder><name>a</name></Folder>
+		<Document><name>b</name></Document>
+		<open>1</open>
+		<Folder><name>c</name></Folder>
+		<Placemark>
+			<MultiGeometry>
+				<LineString><coordinates>1,2 3,4</coordinates></LineString>
+				<Point><coordinates>5,6</coordinates></Point>
+				<MultiGeometry>
+					<Point><coordinates>7,8</coordinates></Point>
+					<Polygon><outerBoundaryIs><LinearRing><coordinates>0,0 1,0 1,1 0,0</coordinates></LinearRing></outerBoundaryIs></Polygon>
+				</MultiGeometry>
+			</MultiGeometry>
+		</Placemark>
+	</Document></kml>`)
+
+	f, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	var names []string
+	for _, sub := range f.Folders {
+		names = append(names, sub.Name)
+	}
+
+	if !reflect.DeepEqual(names, []string{"a", "b", "c"}) {
+		t.Errorf("incorrect folder order: %v", names)
+	}
+
+	expected := orb.Collection{
+		orb.LineString{{1, 2}, {3, 4}},
+		orb.Point{5, 6},
+		orb.Collection{
+			orb.Point{7, 8},
+			orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+		},
+	}
+	if g := f.Features.Features[0].Geometry; !orb.Equal(g, expected) {
+		t.Errorf("incorrect geometry: %v", g)
+	}
+}
+
+func TestUnmarshal_errors(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "not xml",
+			data: `not xml`,
+		},
+		{
+			name: "invalid coordinate",
+			data: `<kml><Placemark><Point><coordinates>a,2</coordinates></Point></Placemark></kml>`,
+		},
+		{
+			name: "missing latitude",
+			data: `<kml><Placemark><LineString><coordinates>1,2 3</coordinates></LineString></Placemark></kml>`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Unmarshal([]byte(tc.data)); err == nil {
+				t.Errorf("should return error")
+			}
+		})
+	}
+}
+
+func TestUnmarshalKMZ(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+
+	for _, name := range []string{"files/other.kml", "a.kml", "doc.kml"} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("create error: %v", err)
+		}
+
+		kml := `<kml><Document><name>` + name + `</name></Document></kml>`
+		if _, err := w.Write([]byte(kml)); err != nil {
+			t.Fatalf("write error: %v", err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close error: %v", err)
+	}
+
+	f, err := UnmarshalKMZ(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if f.Name != "doc.kml" {
+		t.Errorf("should read doc.kml: %v", f.Name)
+	}
+
+	if _, err := UnmarshalKMZ([]byte(testKML)); err == nil {
+		t.Errorf("should error on kml data")
+	}
+}
@@ -0,0 +1,341 @@
+package kml
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+)
+
+// Option is a function that configures encoding.
+type Option func(*options)
+
+type options struct {
+	name   string
+	styles []Style
+}
+
+// Name sets the name of the document when encoding a feature collection.
+func Name(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// Styles adds shared styles to the document, referenced by features
+// with a "styleUrl" property, e.g. "#red" for the style with id "red".
+// Without styles the viewer's default style is used.
+func Styles(styles ...Style) Option {
+	return func(o *options) {
+		o.styles = append(o.styles, styles...)
+	}
+}
+
+// Marshal encodes the feature collection as a KML document of placemarks.
+// The "name", "description" and "styleUrl" properties are encoded as
+// the placemark elements and the other properties as ExtendedData.
+// String feature ids are the placemark id attribute.
+func Marshal(fc *geojson.FeatureCollection, opts ...Option) ([]byte, error) {
+	return MarshalFolder(&Folder{Features: fc}, opts...)
+}
+
+// MarshalFolder encodes the folder as a KML document
+// with its sub folders as KML folders.
+func MarshalFolder(f *Folder, opts ...Option) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := Encode(buf, f, opts...); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Encode writes the folder as a KML document, see Marshal.
+func Encode(w io.Writer, f *Folder, opts ...Option) error {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	doc, err := encodeFolder(f)
+	if err != nil {
+		return err
+	}
+
+	if o.name != "" {
+		doc.Name = o.name
+	}
+
+	for _, s := range o.styles {
+		doc.Styles = append(doc.Styles, encodeStyle(s))
+	}
+
+	root := &kmlRoot{
+		Xmlns: Namespace,
+		containerFeatures: containerFeatures{
+			Containers: []*nestedContainer{{Kind: "Document", container: doc}},
+		},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(root); err != nil {
+		return fmt.Errorf("kml: %v", err)
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// MarshalKMZ encodes the feature collection as a KMZ, a zip archive with
+// the KML as doc.kml.
+func MarshalKMZ(fc *geojson.FeatureCollection, opts ...Option) ([]byte, error) {
+	data, err := Marshal(fc, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+
+	w, err := zw.Create("doc.kml")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func encodeFolder(f *Folder) (*container, error) {
+	c := &container{
+		Name:        f.Name,
+		Description: f.Description,
+	}
+
+	for _, s := range f.Styles {
+		c.Styles = append(c.Styles, encodeStyle(s))
+	}
+
+	if f.Features != nil {
+		for _, feature := range f.Features.Features {
+			p, err := encodePlacemark(feature)
+			if err != nil {
+				return nil, err
+			}
+
+			c.Placemarks = append(c.Placemarks, p)
+		}
+	}
+
+	for _, sub := range f.Folders {
+		folder, err := encodeFolder(sub)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Containers = append(c.Containers, &nestedContainer{Kind: "Folder", container: folder})
+	}
+
+	return c, nil
+}
+
+func encodeStyle(s Style) *xmlStyle {
+	style := &xmlStyle{ID: s.ID}
+	if s.IconHref != "" {
+		style.IconStyle = &iconStyle{Href: s.IconHref}
+	}
+
+	if s.LineColor != "" || s.LineWidth != 0 {
+		style.LineStyle = &lineStyle{Color: s.LineColor, Width: s.LineWidth}
+	}
+
+	if s.PolyColor != "" {
+		style.PolyStyle = &polyStyle{Color: s.PolyColor}
+	}
+
+	return style
+}
+
+func encodePlacemark(f *geojson.Feature) (*placemark, error) {
+	p := &placemark{}
+	if id, ok := f.ID.(string); ok {
+		p.ID = id
+	}
+
+	keys := make([]string, 0, len(f.Properties))
+	for k, v := range f.Properties {
+		s, isString := v.(string)
+
+		switch {
+		case k == "name" && isString:
+			p.Name = s
+		case k == "description" && isString:
+			p.Description = s
+		case k == "styleUrl" && isString:
+			p.StyleURL = s
+		default:
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	if len(keys) > 0 {
+		p.ExtendedData = &extendedData{}
+		for _, k := range keys {
+			v, err := formatValue(f.Properties[k])
+			if err != nil {
+				return nil, err
+			}
+
+			p.ExtendedData.Data = append(p.ExtendedData.Data, &data{Name: k, Value: v})
+		}
+	}
+
+	if f.Geometry != nil {
+		g, err := encodeGeometry(f.Geometry)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Geometries = []*geometryElement{g}
+	}
+
+	return p, nil
+}
+
+// formatValue returns the value as a string for an ExtendedData value.
+func formatValue(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("kml: %v", err)
+		}
+
+		return string(data), nil
+	}
+
+	return fmt.Sprint(v), nil
+}
+
+func encodeGeometry(g orb.Geometry) (*geometryElement, error) {
+	switch g := g.(type) {
+	case orb.Point:
+		return &geometryElement{Point: &point{Coordinates: formatCoordinates([]orb.Point{g})}}, nil
+	case orb.LineString:
+		return &geometryElement{LineString: &lineString{Coordinates: formatCoordinates(g)}}, nil
+	case orb.Ring:
+		return &geometryElement{Polygon: encodePolygon(orb.Polygon{g})}, nil
+	case orb.Polygon:
+		return &geometryElement{Polygon: encodePolygon(g)}, nil
+	case orb.Bound:
+		return &geometryElement{Polygon: encodePolygon(g.ToPolygon())}, nil
+	case orb.MultiPoint:
+		m := &multiGeometry{}
+		for _, p := range g {
+			m.Geometries = append(m.Geometries, &geometryElement{
+				Point: &point{Coordinates: formatCoordinates([]orb.Point{p})},
+			})
+		}
+
+		return &geometryElement{MultiGeometry: m}, nil
+	case orb.MultiLineString:
+		m := &multiGeometry{}
+		for _, ls := range g {
+			m.Geometries = append(m.Geometries, &geometryElement{
+				LineString: &lineString{Coordinates: formatCoordinates(ls)},
+			})
+		}
+
+		return &geometryElement{MultiGeometry: m}, nil
+	case orb.MultiPolygon:
+		m := &multiGeometry{}
+		for _, p := range g {
+			m.Geometries = append(m.Geometries, &geometryElement{Polygon: encodePolygon(p)})
+		}
+
+		return &geometryElement{MultiGeometry: m}, nil
+	case orb.Collection:
+		m := &multiGeometry{}
+		for _, c := range g {
+			e, err := encodeGeometry(c)
+			if err != nil {
+				return nil, err
+			}
+
+			m.Geometries = append(m.Geometries, e)
+		}
+
+		return &geometryElement{MultiGeometry: m}, nil
+	}
+
+	return nil, fmt.Errorf("kml: geometry type not supported: %T", g)
+}
+
+// encodePolygon encodes the polygon, KML rings must be closed.
+func encodePolygon(p orb.Polygon) *polygon {
+	if len(p) == 0 {
+		return &polygon{}
+	}
+
+	result := &polygon{
+		Outer: &boundary{LinearRings: []*linearRing{{Coordinates: formatCoordinates(closed(p[0]))}}},
+	}
+
+	for _, r := range p[1:] {
+		result.Inner = append(result.Inner, &boundary{
+			LinearRings: []*linearRing{{Coordinates: formatCoordinates(closed(r))}},
+		})
+	}
+
+	return result
+}
+
+func closed(r orb.Ring) orb.Ring {
+	if len(r) > 0 && !r.Closed() {
+		return append(r.Clone(), r[0])
+	}
+
+	return r
+}
+
+func formatCoordinates(ps []orb.Point) string {
+	tuples := make([]string, 0, len(ps))
+	for _, p := range ps {
+		tuples = append(tuples,
+			strconv.FormatFloat(p[0], 'f', -1, 64)+","+strconv.FormatFloat(p[1], 'f', -1, 64))
+	}
+
+	return strings.Join(tuples, " ")
+}
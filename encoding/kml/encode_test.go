@@ -0,0 +1,186 @@
+package kml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+)
+
+func TestMarshal(t *testing.T) {
+	fc := geojson.NewFeatureCollection()
+
+	f := geojson.NewFeature(orb.Point{1.5, 2.5})
+	f.ID = "p1"
+	f.Properties["name"] = "Point"
+	f.Properties["styleUrl"] = "#red"
+	f.Properties["height"] = 12.0
+	f.Properties["tags"] = []interface{}{"a", "b"}
+	f.Properties["when"] = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	fc.Append(f)
+
+	fc.Append(geojson.NewFeature(orb.LineString{{1, 2}, {3, 4}}))
+	fc.Append(geojson.NewFeature(orb.Polygon{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{1, 1}, {2, 1}, {2, 2}, {1, 1}},
+	}))
+	fc.Append(geojson.NewFeature(orb.MultiPoint{{1, 1}, {2, 2}}))
+	fc.Append(geojson.NewFeature(orb.MultiLineString{{{1, 1}, {2, 2}}, {{3, 3}, {4, 4}}}))
+	fc.Append(geojson.NewFeature(orb.MultiPolygon{
+		{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+		{{{5, 5}, {6, 5}, {6, 6}, {5, 5}}},
+	}))
+	fc.Append(geojson.NewFeature(orb.Collection{orb.Point{1, 1}, orb.LineString{{1, 1}, {2, 2}}}))
+	fc.Append(geojson.NewFeature(nil))
+
+	style := Style{ID: "red", LineColor: "ff0000ff", LineWidth: 2}
+	data, err := Marshal(fc, Name("Test"), Styles(style))
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	if !strings.HasPrefix(string(data), `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+`<kml xmlns="`+Namespace+`">`) {
+		t.Errorf("incorrect start: %s", data[:80])
+	}
+
+	result, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if result.Name != "Test" || !reflect.DeepEqual(result.Styles, []Style{style}) {
+		t.Errorf("incorrect document: %v %v", result.Name, result.Styles)
+	}
+
+	if len(result.Features.Features) != len(fc.Features) {
+		t.Fatalf("incorrect number of features: %d", len(result.Features.Features))
+	}
+
+	for i, f := range fc.Features {
+		g := result.Features.Features[i].Geometry
+		if f.Geometry == nil {
+			if g != nil {
+				t.Errorf("%d: geometry should be nil: %v", i, g)
+			}
+			continue
+		}
+
+		if !orb.Equal(g, f.Geometry) {
+			t.Errorf("%d: incorrect geometry: %v != %v", i, g, f.Geometry)
+		}
+	}
+
+	p := result.Features.Features[0]
+	if p.ID != "p1" {
+		t.Errorf("incorrect id: %v", p.ID)
+	}
+
+	expected := geojson.Properties{
+		"name":     "Point",
+		"styleUrl": "#red",
+		"height":   "12",
+		"tags":     `["a","b"]`,
+		"when":     "2020-01-02T03:04:05Z",
+	}
+	if !reflect.DeepEqual(p.Properties, expected) {
+		t.Errorf("incorrect properties: %v", p.Properties)
+	}
+}
+
+func TestMarshal_closesRings(t *testing.T) {
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(orb.Ring{{0, 0}, {1, 0}, {1, 1}}))
+	fc.Append(geojson.NewFeature(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}}))
+
+	data, err := Marshal(fc)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	if !strings.Contains(string(data), "<coordinates>0,0 1,0 1,1 0,0</coordinates>") {
+		t.Errorf("ring should be closed: %s", data)
+	}
+
+	result, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	expected := orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}
+	if g := result.Features.Features[1].Geometry; !orb.Equal(g, expected) {
+		t.Errorf("incorrect bound polygon: %v", g)
+	}
+}
+
+func TestMarshal_collection(t *testing.T) {
+	collection := orb.Collection{
+		orb.LineString{{1, 2}, {3, 4}},
+		orb.Point{5, 6},
+		orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+		orb.Point{7, 8},
+		orb.MultiLineString{{{1, 1}, {2, 2}}, {{3, 3}, {4, 4}}},
+		orb.Collection{orb.Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 0}}}, orb.Point{9, 9}},
+	}
+
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(collection))
+
+	data, err := Marshal(fc)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	result, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if g := result.Features.Features[0].Geometry; !orb.Equal(g, collection) {
+		t.Errorf("incorrect geometry: %v", g)
+	}
+}
+
+func TestMarshalFolder(t *testing.T) {
+	sub := &Folder{Name: "sub", Features: geojson.NewFeatureCollection()}
+	sub.Features.Append(geojson.NewFeature(orb.Point{1, 2}))
+
+	data, err := MarshalFolder(&Folder{Name: "root", Folders: []*Folder{sub}})
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	result, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if result.Name != "root" || len(result.Folders) != 1 || result.Folders[0].Name != "sub" {
+		t.Fatalf("incorrect folders: %v", result)
+	}
+
+	if l := len(result.All().Features); l != 1 {
+		t.Errorf("incorrect number of features: %d", l)
+	}
+}
+
+func TestMarshalKMZ(t *testing.T) {
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(orb.Point{1, 2}))
+
+	data, err := MarshalKMZ(fc, Name("zipped"))
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	result, err := UnmarshalKMZ(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if result.Name != "zipped" || !orb.Equal(result.Features.Features[0].Geometry, orb.Point{1, 2}) {
+		t.Errorf("incorrect result: %v", result)
+	}
+}
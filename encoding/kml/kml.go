@@ -0,0 +1,313 @@
+// Package kml decodes and encodes KML, and zipped KMZ, placemarks
+// as GeoJSON features with orb geometries.
+// Specification at https://www.ogc.org/standard/kml/
+package kml
+
+import (
+	"encoding/xml"
+
+	"github.com/dadadamarine/orb/geojson"
+)
+
+// Namespace is the KML 2.2 namespace used when encoding.
+const Namespace = "http://www.opengis.net/kml/2.2"
+
+// A Folder is a KML Document or Folder with its placemarks and sub folders.
+type Folder struct {
+	Name        string
+	Description string
+
+	// Features are the placemarks directly in the folder.
+	Features *geojson.FeatureCollection
+
+	Folders []*Folder
+
+	// Styles are the shared styles defined in the folder.
+	Styles []Style
+}
+
+// All returns the features of the folder and all its sub folders, depth first.
+func (f *Folder) All() *geojson.FeatureCollection {
+	fc := geojson.NewFeatureCollection()
+	f.walk(func(folder *Folder) {
+		if folder.Features != nil {
+			fc.Features = append(fc.Features, folder.Features.Features...)
+		}
+	})
+
+	return fc
+}
+
+func (f *Folder) walk(fn func(*Folder)) {
+	fn(f)
+	for _, sub := range f.Folders {
+		sub.walk(fn)
+	}
+}
+
+// A Style is a shared style referenced by placemarks with
+// a styleUrl of "#" and the id. Colors are in the KML aabbggrr format,
+// e.g. "ff0000ff" for opaque red.
+type Style struct {
+	ID string
+
+	// IconHref is the url of the icon of points.
+	IconHref string
+
+	LineColor string
+	LineWidth float64
+
+	PolyColor string
+}
+
+// The xml types below are used for both decoding and encoding. Elements
+// are matched by their local name so the namespace version does not matter.
+
+type kmlRoot struct {
+	XMLName xml.Name `xml:"kml"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	containerFeatures
+}
+
+// containerFeatures are the features of a document or folder.
+type containerFeatures struct {
+	// Containers are the nested documents and folders in document order.
+	Containers []*nestedContainer `xml:",any"`
+	Placemarks []*placemark       `xml:"Placemark"`
+}
+
+// containers returns the nested documents and folders, without the skipped elements.
+func (c containerFeatures) containers() []*nestedContainer {
+	var result []*nestedContainer
+	for _, n := range c.Containers {
+		if n.container != nil {
+			result = append(result, n)
+		}
+	}
+
+	return result
+}
+
+// nestedContainer is a Document or Folder element. The other elements
+// matched by the ",any" field are skipped and have no container.
+type nestedContainer struct {
+	// Kind is the element name, Document or Folder.
+	Kind string
+	*container
+}
+
+func (n *nestedContainer) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	switch start.Name.Local {
+	case "Document", "Folder":
+		n.Kind = start.Name.Local
+		n.container = &container{}
+		return d.DecodeElement(n.container, &start)
+	}
+
+	return d.Skip()
+}
+
+func (n *nestedContainer) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(n.container, xml.StartElement{Name: xml.Name{Local: n.Kind}})
+}
+
+type container struct {
+	ID          string      `xml:"id,attr,omitempty"`
+	Name        string      `xml:"name,omitempty"`
+	Description string      `xml:"description,omitempty"`
+	Styles      []*xmlStyle `xml:"Style"`
+	Schemas     []*schema   `xml:"Schema"`
+	containerFeatures
+}
+
+type placemark struct {
+	ID           string        `xml:"id,attr,omitempty"`
+	Name         string        `xml:"name,omitempty"`
+	Description  string        `xml:"description,omitempty"`
+	StyleURL     string        `xml:"styleUrl,omitempty"`
+	ExtendedData *extendedData `xml:"ExtendedData"`
+
+	// Geometries are the geometry elements, there should be only one.
+	Geometries []*geometryElement `xml:",any"`
+}
+
+type extendedData struct {
+	Data       []*data       `xml:"Data"`
+	SchemaData []*schemaData `xml:"SchemaData"`
+}
+
+type data struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type schemaData struct {
+	SchemaURL  string        `xml:"schemaUrl,attr"`
+	SimpleData []*simpleData `xml:"SimpleData"`
+}
+
+type simpleData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+type schema struct {
+	ID           string         `xml:"id,attr"`
+	Name         string         `xml:"name,attr"`
+	SimpleFields []*simpleField `xml:"SimpleField"`
+}
+
+type simpleField struct {
+	Name string `xml:"name,attr"`
+	Type string `xml:"type,attr"`
+}
+
+// geometryElement is a geometry element, the field of its type is set.
+// The other elements matched by ",any" fields are skipped and have none set.
+type geometryElement struct {
+	Point         *point
+	LineString    *lineString
+	LinearRing    *linearRing
+	Polygon       *polygon
+	Track         *track
+	MultiGeometry *multiGeometry
+}
+
+func (g *geometryElement) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v interface{}
+	switch start.Name.Local {
+	case "Point":
+		g.Point = &point{}
+		v = g.Point
+	case "LineString":
+		g.LineString = &lineString{}
+		v = g.LineString
+	case "LinearRing":
+		g.LinearRing = &linearRing{}
+		v = g.LinearRing
+	case "Polygon":
+		g.Polygon = &polygon{}
+		v = g.Polygon
+	case "Track":
+		g.Track = &track{}
+		v = g.Track
+	case "MultiGeometry":
+		g.MultiGeometry = &multiGeometry{}
+		v = g.MultiGeometry
+	default:
+		return d.Skip()
+	}
+
+	return d.DecodeElement(v, &start)
+}
+
+// MarshalXML encodes the element, tracks are only decoded.
+func (g *geometryElement) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	var (
+		name string
+		v    interface{}
+	)
+
+	switch {
+	case g.Point != nil:
+		name, v = "Point", g.Point
+	case g.LineString != nil:
+		name, v = "LineString", g.LineString
+	case g.LinearRing != nil:
+		name, v = "LinearRing", g.LinearRing
+	case g.Polygon != nil:
+		name, v = "Polygon", g.Polygon
+	case g.MultiGeometry != nil:
+		name, v = "MultiGeometry", g.MultiGeometry
+	default:
+		return nil
+	}
+
+	return e.EncodeElement(v, xml.StartElement{Name: xml.Name{Local: name}})
+}
+
+// multiGeometry is a MultiGeometry element with its children in document order.
+type multiGeometry struct {
+	Geometries []*geometryElement
+}
+
+func (m *multiGeometry) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			g := &geometryElement{}
+			if err := g.UnmarshalXML(d, t); err != nil {
+				return err
+			}
+
+			m.Geometries = append(m.Geometries, g)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func (m *multiGeometry) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, g := range m.Geometries {
+		if err := g.MarshalXML(e, start); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+type point struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type lineString struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type linearRing struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type polygon struct {
+	Outer *boundary   `xml:"outerBoundaryIs"`
+	Inner []*boundary `xml:"innerBoundaryIs,omitempty"`
+}
+
+type boundary struct {
+	LinearRings []*linearRing `xml:"LinearRing"`
+}
+
+// track is a gx:Track, the coordinates are space separated.
+type track struct {
+	Coords []string `xml:"coord"`
+}
+
+type xmlStyle struct {
+	ID        string     `xml:"id,attr,omitempty"`
+	IconStyle *iconStyle `xml:"IconStyle"`
+	LineStyle *lineStyle `xml:"LineStyle"`
+	PolyStyle *polyStyle `xml:"PolyStyle"`
+}
+
+type iconStyle struct {
+	Href string `xml:"Icon>href"`
+}
+
+type lineStyle struct {
+	Color string  `xml:"color,omitempty"`
+	Width float64 `xml:"width,omitempty"`
+}
+
+type polyStyle struct {
+	Color string `xml:"color,omitempty"`
+}
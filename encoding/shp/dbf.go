@@ -0,0 +1,448 @@
+package shp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dbfVersion      = 0x03
+	dbfHeaderEnd    = 0x0d
+	dbfEndOfFile    = 0x1a
+	dbfDeleted      = '*'
+	dbfFieldLength  = 32
+	dbfMaxCharacter = 254
+)
+
+// dbfReader reads the records of a .dbf file.
+type dbfReader struct {
+	r       *bufio.Reader
+	fields  []Field
+	count   int
+	length  int
+	decode  func([]byte) string
+	record  []byte
+	current int
+}
+
+func newDBFReader(r io.Reader, codePage string) (*dbfReader, error) {
+	br := bufio.NewReader(r)
+
+	var h [32]byte
+	if _, err := io.ReadFull(br, h[:]); err != nil {
+		return nil, fmt.Errorf("shp: invalid dbf header: %v", err)
+	}
+
+	d := &dbfReader{
+		r:      br,
+		count:  int(binary.LittleEndian.Uint32(h[4:8])),
+		length: int(binary.LittleEndian.Uint16(h[10:12])),
+	}
+
+	headerLen := int(binary.LittleEndian.Uint16(h[8:10]))
+	if headerLen < 33 || d.length < 1 {
+		return nil, fmt.Errorf("shp: invalid dbf header")
+	}
+
+	rest := make([]byte, headerLen-32)
+	if _, err := io.ReadFull(br, rest); err != nil {
+		return nil, fmt.Errorf("shp: invalid dbf header: %v", err)
+	}
+
+	decode, err := decoder(codePage)
+	if err != nil {
+		return nil, err
+	}
+	d.decode = decode
+
+	offset := 1
+	for i := 0; i+dbfFieldLength <= len(rest) && rest[i] != dbfHeaderEnd; i += dbfFieldLength {
+		f := rest[i : i+dbfFieldLength]
+
+		name := f[:11]
+		if n := bytes.IndexByte(name, 0); n >= 0 {
+			name = name[:n]
+		}
+
+		field := Field{
+			Name:     d.decode(bytes.TrimSpace(name)),
+			Type:     f[11],
+			Length:   int(f[16]),
+			Decimals: int(f[17]),
+		}
+
+		offset += field.Length
+		d.fields = append(d.fields, field)
+	}
+
+	if offset > d.length {
+		return nil, fmt.Errorf("shp: dbf fields longer than the record")
+	}
+
+	d.record = make([]byte, d.length)
+	return d, nil
+}
+
+// next returns the values of the next record, false if it was deleted.
+func (d *dbfReader) next() (map[string]interface{}, bool, error) {
+	if d.current >= d.count {
+		return nil, false, io.EOF
+	}
+	d.current++
+
+	if _, err := io.ReadFull(d.r, d.record); err != nil {
+		return nil, false, fmt.Errorf("shp: invalid dbf record: %v", err)
+	}
+
+	values := make(map[string]interface{}, len(d.fields))
+
+	offset := 1
+	for _, f := range d.fields {
+		v, err := d.value(f, d.record[offset:offset+f.Length])
+		if err != nil {
+			return nil, false, err
+		}
+
+		values[f.Name] = v
+		offset += f.Length
+	}
+
+	return values, d.record[0] != dbfDeleted, nil
+}
+
+func (d *dbfReader) value(f Field, data []byte) (interface{}, error) {
+	switch f.Type {
+	case 'C':
+		return d.decode(bytes.TrimRight(data, " \x00")), nil
+	case 'N', 'F':
+		s := strings.TrimSpace(string(bytes.Trim(data, "\x00")))
+		if s == "" || strings.Trim(s, "*") == "" {
+			return nil, nil
+		}
+
+		if f.Decimals == 0 {
+			if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return i, nil
+			}
+		}
+
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("shp: invalid number in field %s: %q", f.Name, s)
+		}
+
+		return v, nil
+	case 'L':
+		switch strings.TrimSpace(string(data)) {
+		case "Y", "y", "T", "t":
+			return true, nil
+		case "N", "n", "F", "f":
+			return false, nil
+		}
+
+		return nil, nil
+	case 'D':
+		s := strings.TrimSpace(string(data))
+		if s == "" || strings.Trim(s, "0") == "" {
+			return nil, nil
+		}
+
+		t, err := time.Parse("20060102", s)
+		if err != nil {
+			return nil, fmt.Errorf("shp: invalid date in field %s: %q", f.Name, s)
+		}
+
+		return t, nil
+	}
+
+	// other types, such as memo references, as strings
+	return d.decode(bytes.TrimSpace(data)), nil
+}
+
+// decoder returns a function to decode strings in the code page.
+func decoder(codePage string) (func([]byte) string, error) {
+	cp := strings.ToUpper(strings.TrimSpace(codePage))
+	cp = strings.NewReplacer("-", "", "_", "", " ", "").Replace(cp)
+
+	switch cp {
+	case "":
+		return func(b []byte) string {
+			if utf8.Valid(b) {
+				return string(b)
+			}
+
+			return decodeLatin1(b)
+		}, nil
+	case "UTF8", "65001":
+		return func(b []byte) string { return string(b) }, nil
+	case "ISO88591", "LATIN1", "88591":
+		return decodeLatin1, nil
+	case "1252", "CP1252", "WINDOWS1252", "ANSI1252":
+		return decodeWindows1252, nil
+	case "ASCII", "USASCII":
+		return func(b []byte) string { return string(b) }, nil
+	}
+
+	return nil, fmt.Errorf("shp: unsupported code page: %s", codePage)
+}
+
+func decodeLatin1(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+
+	return string(runes)
+}
+
+// windows1252 are the characters from 0x80 to 0x9f that differ from Latin-1.
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8d, 'Ž', 0x8f,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9d, 'ž', 'Ÿ',
+}
+
+func decodeWindows1252(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		if 0x80 <= c && c <= 0x9f {
+			runes[i] = windows1252[c-0x80]
+		} else {
+			runes[i] = rune(c)
+		}
+	}
+
+	return string(runes)
+}
+
+// dbfFields returns the fields for the properties, sorted by name.
+// Names are truncated to the 10 characters allowed by the format.
+func dbfFields(props []map[string]interface{}) ([]Field, []string) {
+	columns := map[string][]interface{}{}
+	for _, p := range props {
+		for k, v := range p {
+			columns[k] = append(columns[k], v)
+		}
+	}
+
+	keys := make([]string, 0, len(columns))
+	for k := range columns {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fields := make([]Field, 0, len(keys))
+	used := map[string]bool{}
+	for _, k := range keys {
+		f := dbfField(columns[k])
+		f.Name = fieldName(k, used)
+		fields = append(fields, f)
+	}
+
+	return fields, keys
+}
+
+func dbfField(values []interface{}) Field {
+	var (
+		types    = map[byte]bool{}
+		length   = 1
+		integral = true
+	)
+
+	for _, v := range values {
+		switch v := v.(type) {
+		case nil:
+		case bool:
+			types['L'] = true
+		case time.Time:
+			types['D'] = true
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			types['N'] = true
+			length = maxInt(length, len(fmt.Sprint(v)))
+		case float32, float64:
+			types['N'] = true
+
+			f := toFloat(v)
+			if f != math.Trunc(f) || math.Abs(f) >= 1e15 {
+				integral = false
+			} else {
+				length = maxInt(length, len(strconv.FormatFloat(f, 'f', 0, 64)))
+			}
+		default:
+			types['C'] = true
+		}
+	}
+
+	switch {
+	case len(types) == 1 && types['L']:
+		return Field{Type: 'L', Length: 1}
+	case len(types) == 1 && types['D']:
+		return Field{Type: 'D', Length: 8}
+	case len(types) == 1 && types['N'] && integral:
+		return Field{Type: 'N', Length: length}
+	case len(types) == 1 && types['N']:
+		return Field{Type: 'N', Length: 24, Decimals: 15}
+	}
+
+	// everything else is a string
+	length = 1
+	for _, v := range values {
+		if v != nil {
+			length = maxInt(length, len(formatString(v)))
+		}
+	}
+
+	return Field{Type: 'C', Length: minInt(length, dbfMaxCharacter)}
+}
+
+// fieldName truncates the name to 10 bytes, adding a number if needed to be unique.
+func fieldName(name string, used map[string]bool) string {
+	n := truncate(name, 10)
+	for i := 1; used[strings.ToUpper(n)]; i++ {
+		suffix := "_" + strconv.Itoa(i)
+		n = truncate(name, 10-len(suffix)) + suffix
+	}
+
+	used[strings.ToUpper(n)] = true
+	return n
+}
+
+func writeDBF(w io.Writer, fields []Field, keys []string, props []map[string]interface{}) error {
+	recordLength := 1
+	for _, f := range fields {
+		recordLength += f.Length
+	}
+
+	headerLength := 32 + dbfFieldLength*len(fields) + 1
+	if headerLength > math.MaxUint16 || recordLength > math.MaxUint16 {
+		return fmt.Errorf("shp: too many fields")
+	}
+
+	bw := bufio.NewWriter(w)
+
+	now := time.Now()
+	h := make([]byte, 32)
+	h[0] = dbfVersion
+	h[1], h[2], h[3] = byte(now.Year()-1900), byte(now.Month()), byte(now.Day())
+	binary.LittleEndian.PutUint32(h[4:8], uint32(len(props)))
+	binary.LittleEndian.PutUint16(h[8:10], uint16(headerLength))
+	binary.LittleEndian.PutUint16(h[10:12], uint16(recordLength))
+	bw.Write(h)
+
+	for _, f := range fields {
+		d := make([]byte, dbfFieldLength)
+		copy(d[:10], f.Name)
+		d[11] = f.Type
+		d[16] = byte(f.Length)
+		d[17] = byte(f.Decimals)
+		bw.Write(d)
+	}
+	bw.WriteByte(dbfHeaderEnd)
+
+	record := make([]byte, recordLength)
+	for _, p := range props {
+		record[0] = ' '
+
+		offset := 1
+		for i, f := range fields {
+			formatValue(record[offset:offset+f.Length], f, p[keys[i]])
+			offset += f.Length
+		}
+
+		bw.Write(record)
+	}
+
+	bw.WriteByte(dbfEndOfFile)
+	return bw.Flush()
+}
+
+// formatValue writes the value into the fixed width field.
+func formatValue(dst []byte, f Field, v interface{}) {
+	for i := range dst {
+		dst[i] = ' '
+	}
+
+	if v == nil {
+		return
+	}
+
+	var s string
+	switch f.Type {
+	case 'L':
+		s = "F"
+		if v.(bool) {
+			s = "T"
+		}
+	case 'D':
+		s = v.(time.Time).Format("20060102")
+	case 'N':
+		if f.Decimals == 0 {
+			if fv, ok := v.(float32); ok {
+				s = strconv.FormatFloat(float64(fv), 'f', 0, 64)
+			} else if fv, ok := v.(float64); ok {
+				s = strconv.FormatFloat(fv, 'f', 0, 64)
+			} else {
+				s = fmt.Sprint(v)
+			}
+		} else {
+			f64 := toFloat(v)
+			for d := f.Decimals; d >= 0; d-- {
+				s = strconv.FormatFloat(f64, 'f', d, 64)
+				if len(s) <= f.Length {
+					break
+				}
+			}
+		}
+
+		// numbers are right aligned
+		if len(s) <= len(dst) {
+			copy(dst[len(dst)-len(s):], s)
+		}
+		return
+	default:
+		s = truncate(formatString(v), len(dst))
+	}
+
+	copy(dst, s)
+}
+
+func formatString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	return fmt.Sprint(v)
+}
+
+func toFloat(v interface{}) float64 {
+	switch v := v.(type) {
+	case float32:
+		return float64(v)
+	case float64:
+		return v
+	}
+
+	f, _ := strconv.ParseFloat(fmt.Sprint(v), 64)
+	return f
+}
+
+// truncate truncates the string to n bytes without splitting a character.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n]
+}
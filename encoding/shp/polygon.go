@@ -0,0 +1,117 @@
+package shp
+
+import (
+	"math"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/planar"
+)
+
+// polygon builds a polygon or multi polygon from the parts of a shape.
+// In shapefiles outer rings are clockwise and holes counter clockwise,
+// each hole is added to the smallest outer ring that contains it.
+// The result follows the GeoJSON orientation, counter clockwise outer rings.
+func polygon(parts [][]orb.Point) orb.Geometry {
+	var outers, holes []orb.Ring
+	for _, p := range parts {
+		r := orb.Ring(p)
+		if len(r) == 0 {
+			continue
+		}
+
+		if r.Orientation() == orb.CCW {
+			holes = append(holes, r)
+		} else {
+			outers = append(outers, r)
+		}
+	}
+
+	// all rings the wrong way around, written by a non conforming tool
+	if len(outers) == 0 {
+		outers, holes = holes, nil
+	}
+
+	mp := make(orb.MultiPolygon, 0, len(outers))
+	areas := make([]float64, 0, len(outers))
+	for _, r := range outers {
+		mp = append(mp, orb.Polygon{r})
+		areas = append(areas, math.Abs(planar.Area(r)))
+	}
+
+	for _, h := range holes {
+		best := -1
+		for i, p := range mp {
+			if !p[0].Bound().Contains(h.Bound().Min) || !p[0].Bound().Contains(h.Bound().Max) {
+				continue
+			}
+
+			if !ringContainsRing(p[0], h) {
+				continue
+			}
+
+			if best == -1 || areas[i] < areas[best] {
+				best = i
+			}
+		}
+
+		if best == -1 {
+			// a hole without an outer ring is taken to be an outer ring
+			mp = append(mp, orb.Polygon{h})
+			areas = append(areas, math.Abs(planar.Area(h)))
+			continue
+		}
+
+		mp[best] = append(mp[best], h)
+	}
+
+	for _, p := range mp {
+		orient(p, orb.CCW)
+	}
+
+	if len(mp) == 1 {
+		return mp[0]
+	}
+
+	return mp
+}
+
+// ringContainsRing returns true if a vertex of the inner ring, not on the
+// boundary of the outer, is inside the outer ring.
+func ringContainsRing(outer, inner orb.Ring) bool {
+	for _, p := range inner {
+		if !planar.RingContains(outer, p) {
+			return false
+		}
+
+		if !onRing(outer, p) {
+			return true
+		}
+	}
+
+	// all the vertices are on the outer ring
+	return true
+}
+
+func onRing(r orb.Ring, p orb.Point) bool {
+	for i := 1; i < len(r); i++ {
+		if planar.DistanceFromSegmentSquared(r[i-1], r[i], p) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// orient winds the outer ring in the direction and the holes the other way.
+func orient(p orb.Polygon, outer orb.Orientation) {
+	for i, r := range p {
+		o := outer
+		if i > 0 {
+			o = -outer
+		}
+
+		if r.Orientation() == -o {
+			r.Reverse()
+		}
+	}
+}
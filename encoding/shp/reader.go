@@ -0,0 +1,413 @@
+package shp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+)
+
+const (
+	// limits so that bad data can't come in and preallocate tons of memory.
+	maxPointsAlloc = 10000
+	maxPartsAlloc  = 1000
+)
+
+// A Reader reads the features of a shapefile one at a time.
+type Reader struct {
+	ShapeType ShapeType
+	Bound     orb.Bound
+
+	// Fields are the columns of the .dbf file, nil if there is none.
+	Fields []Field
+
+	// Projection is the WKT of the .prj file, empty if there is none.
+	// It can be parsed with project.ParseWKT.
+	Projection string
+
+	shp *bufio.Reader
+	dbf *dbfReader
+
+	// seeker and offsets, from the .shx file, are used
+	// to seek to the records if there are gaps.
+	seeker   io.ReadSeeker
+	offsets  []int64
+	position int64
+	index    int
+
+	closers []io.Closer
+}
+
+// Open opens the shapefile with the given .shp path, or the path without
+// the extension. The .dbf, .shx, .cpg and .prj files are used if they exist.
+// The reader must be closed when done.
+func Open(path string, opts ...Option) (*Reader, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	base := basePath(path)
+
+	shp, err := os.Open(sidecar(base, ".shp"))
+	if err != nil {
+		return nil, err
+	}
+
+	closers := []io.Closer{shp}
+	closeAll := func() {
+		for _, c := range closers {
+			c.Close()
+		}
+	}
+
+	if o.codePage == "" {
+		if data, err := ioutil.ReadFile(sidecar(base, ".cpg")); err == nil {
+			o.codePage = strings.TrimSpace(string(data))
+		}
+	}
+
+	var dbf io.Reader
+	if p := sidecar(base, ".dbf"); fileExists(p) {
+		f, err := os.Open(p)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+
+		closers = append(closers, f)
+		dbf = f
+	}
+
+	r, err := newReader(shp, dbf, o)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+	r.closers = closers
+
+	if p := sidecar(base, ".shx"); fileExists(p) {
+		offsets, err := readIndex(p)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+
+		r.seeker = shp
+		r.offsets = offsets
+	}
+
+	if data, err := ioutil.ReadFile(sidecar(base, ".prj")); err == nil {
+		r.Projection = strings.TrimSpace(string(data))
+	}
+
+	return r, nil
+}
+
+// NewReader creates a reader for the .shp and .dbf data. The dbf reader
+// may be nil in which case the features will have no properties.
+func NewReader(shp, dbf io.Reader, opts ...Option) (*Reader, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return newReader(shp, dbf, o)
+}
+
+func newReader(shp, dbf io.Reader, o *options) (*Reader, error) {
+	r := &Reader{
+		shp: bufio.NewReader(shp),
+	}
+
+	h, err := readHeader(r.shp)
+	if err != nil {
+		return nil, err
+	}
+
+	r.ShapeType = h.ShapeType
+	r.Bound = h.Bound
+	r.position = headerLength
+
+	if dbf != nil {
+		r.dbf, err = newDBFReader(dbf, o.codePage)
+		if err != nil {
+			return nil, err
+		}
+
+		r.Fields = r.dbf.fields
+	}
+
+	return r, nil
+}
+
+// ReadFile reads all the features of the shapefile into a feature collection.
+func ReadFile(path string, opts ...Option) (*geojson.FeatureCollection, error) {
+	r, err := Open(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return r.ReadAll()
+}
+
+// ReadAll reads the remaining features into a feature collection.
+func (r *Reader) ReadAll() (*geojson.FeatureCollection, error) {
+	fc := geojson.NewFeatureCollection()
+	for {
+		f, err := r.Next()
+		if err == io.EOF {
+			return fc, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		fc.Append(f)
+	}
+}
+
+// Next returns the next feature, or io.EOF when there are no more.
+// Records marked as deleted in the .dbf file are skipped.
+func (r *Reader) Next() (*geojson.Feature, error) {
+	for {
+		g, err := r.nextGeometry()
+		if err != nil {
+			return nil, err
+		}
+
+		f := geojson.NewFeature(g)
+		if r.dbf == nil {
+			return f, nil
+		}
+
+		props, ok, err := r.dbf.next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("shp: dbf has fewer records than the shp")
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			f.Properties = props
+			return f, nil
+		}
+	}
+}
+
+// Close closes the files opened by Open.
+func (r *Reader) Close() error {
+	var err error
+	for _, c := range r.closers {
+		if e := c.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	r.closers = nil
+	return err
+}
+
+func (r *Reader) nextGeometry() (orb.Geometry, error) {
+	if r.offsets != nil {
+		if r.index >= len(r.offsets) {
+			return nil, io.EOF
+		}
+
+		if offset := r.offsets[r.index]; offset != r.position {
+			if _, err := r.seeker.Seek(offset, io.SeekStart); err != nil {
+				return nil, err
+			}
+
+			r.shp.Reset(r.seeker)
+			r.position = offset
+		}
+	}
+	r.index++
+
+	var rh [8]byte
+	n, err := io.ReadFull(r.shp, rh[:])
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("shp: invalid record header: %v", err)
+	}
+
+	length := int64(binary.BigEndian.Uint32(rh[4:8])) * 2
+	if length < 4 {
+		return nil, fmt.Errorf("shp: invalid record length: %d", length)
+	}
+
+	content := make([]byte, 0, minInt64(length, 16*maxPointsAlloc))
+	buf := make([]byte, 4096)
+	for int64(len(content)) < length {
+		m := minInt64(length-int64(len(content)), int64(len(buf)))
+		if _, err := io.ReadFull(r.shp, buf[:m]); err != nil {
+			return nil, fmt.Errorf("shp: invalid record: %v", err)
+		}
+
+		content = append(content, buf[:m]...)
+	}
+
+	r.position += int64(n) + length
+	return decodeShape(content)
+}
+
+func readHeader(r io.Reader) (header, error) {
+	var b [headerLength]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return header{}, fmt.Errorf("shp: invalid header: %v", err)
+	}
+
+	if binary.BigEndian.Uint32(b[0:4]) != fileCode {
+		return header{}, fmt.Errorf("shp: invalid file code")
+	}
+
+	if binary.LittleEndian.Uint32(b[28:32]) != version {
+		return header{}, fmt.Errorf("shp: unsupported version: %d", binary.LittleEndian.Uint32(b[28:32]))
+	}
+
+	return header{
+		ShapeType: ShapeType(binary.LittleEndian.Uint32(b[32:36])),
+		Bound:     readBound(b[36:68]),
+		Length:    int32(binary.BigEndian.Uint32(b[24:28])),
+	}, nil
+}
+
+// readIndex reads the record offsets, in bytes, from the .shx file.
+func readIndex(path string) ([]int64, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := readHeader(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	records := data[headerLength:]
+	offsets := make([]int64, 0, len(records)/8)
+	for i := 0; i+8 <= len(records); i += 8 {
+		offsets = append(offsets, int64(binary.BigEndian.Uint32(records[i:i+4]))*2)
+	}
+
+	return offsets, nil
+}
+
+func readBound(b []byte) orb.Bound {
+	return orb.Bound{
+		Min: orb.Point{readFloat(b[0:8]), readFloat(b[8:16])},
+		Max: orb.Point{readFloat(b[16:24]), readFloat(b[24:32])},
+	}
+}
+
+func readFloat(b []byte) float64 {
+	return math.Float64frombits(binary.LittleEndian.Uint64(b))
+}
+
+// decodeShape decodes the record content into a geometry.
+func decodeShape(b []byte) (orb.Geometry, error) {
+	t := ShapeType(binary.LittleEndian.Uint32(b[0:4]))
+
+	switch t.base() {
+	case Null:
+		return nil, nil
+	case Point:
+		if len(b) < 20 {
+			return nil, fmt.Errorf("shp: invalid point")
+		}
+
+		return orb.Point{readFloat(b[4:12]), readFloat(b[12:20])}, nil
+	case MultiPoint:
+		if len(b) < 40 {
+			return nil, fmt.Errorf("shp: invalid multipoint")
+		}
+
+		n := int(binary.LittleEndian.Uint32(b[36:40]))
+		points, err := readPoints(b[40:], n)
+		if err != nil {
+			return nil, err
+		}
+
+		return orb.MultiPoint(points), nil
+	case PolyLine, Polygon:
+		if len(b) < 44 {
+			return nil, fmt.Errorf("shp: invalid %s", t)
+		}
+
+		numParts := int(binary.LittleEndian.Uint32(b[36:40]))
+		numPoints := int(binary.LittleEndian.Uint32(b[40:44]))
+		if numParts < 0 || len(b) < 44+4*numParts {
+			return nil, fmt.Errorf("shp: invalid %s", t)
+		}
+
+		points, err := readPoints(b[44+4*numParts:], numPoints)
+		if err != nil {
+			return nil, err
+		}
+
+		parts := make([][]orb.Point, 0, minInt(numParts, maxPartsAlloc))
+		for i := 0; i < numParts; i++ {
+			start := int(int32(binary.LittleEndian.Uint32(b[44+4*i:])))
+
+			end := numPoints
+			if i < numParts-1 {
+				end = int(int32(binary.LittleEndian.Uint32(b[48+4*i:])))
+			}
+
+			if start < 0 || end < start || end > numPoints {
+				return nil, fmt.Errorf("shp: invalid %s part", t)
+			}
+
+			parts = append(parts, points[start:end])
+		}
+
+		if t.base() == PolyLine {
+			return polyLine(parts), nil
+		}
+
+		return polygon(parts), nil
+	}
+
+	return nil, fmt.Errorf("shp: unsupported shape type: %s", t)
+}
+
+func readPoints(b []byte, n int) ([]orb.Point, error) {
+	if n < 0 || len(b) < 16*n {
+		return nil, fmt.Errorf("shp: invalid number of points: %d", n)
+	}
+
+	points := make([]orb.Point, n)
+	for i := range points {
+		points[i] = orb.Point{readFloat(b[16*i:]), readFloat(b[16*i+8:])}
+	}
+
+	return points, nil
+}
+
+func polyLine(parts [][]orb.Point) orb.Geometry {
+	if len(parts) == 1 {
+		return orb.LineString(parts[0])
+	}
+
+	mls := make(orb.MultiLineString, 0, len(parts))
+	for _, p := range parts {
+		mls = append(mls, orb.LineString(p))
+	}
+
+	return mls
+}
@@ -0,0 +1,191 @@
+// Package shp reads and writes ESRI Shapefiles, the .shp geometry, .shx index
+// and .dbf attribute files along with the .cpg code page and .prj projection.
+// Specification at https://www.esri.com/content/dam/esrisites/sitecore-archive/Files/Pdfs/library/whitepapers/pdfs/shapefile.pdf
+package shp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dadadamarine/orb"
+)
+
+// ShapeType is the type of the shapes in a shapefile.
+type ShapeType int32
+
+// The shape types. The Z and M types are read as 2d,
+// the z and measure values are dropped.
+const (
+	Null        ShapeType = 0
+	Point       ShapeType = 1
+	PolyLine    ShapeType = 3
+	Polygon     ShapeType = 5
+	MultiPoint  ShapeType = 8
+	PointZ      ShapeType = 11
+	PolyLineZ   ShapeType = 13
+	PolygonZ    ShapeType = 15
+	MultiPointZ ShapeType = 18
+	PointM      ShapeType = 21
+	PolyLineM   ShapeType = 23
+	PolygonM    ShapeType = 25
+	MultiPointM ShapeType = 28
+	MultiPatch  ShapeType = 31
+)
+
+// base returns the 2d shape type of a Z or M type.
+func (t ShapeType) base() ShapeType {
+	switch t {
+	case PointZ, PointM:
+		return Point
+	case PolyLineZ, PolyLineM:
+		return PolyLine
+	case PolygonZ, PolygonM:
+		return Polygon
+	case MultiPointZ, MultiPointM:
+		return MultiPoint
+	}
+
+	return t
+}
+
+func (t ShapeType) String() string {
+	switch t {
+	case Null:
+		return "Null"
+	case Point:
+		return "Point"
+	case PolyLine:
+		return "PolyLine"
+	case Polygon:
+		return "Polygon"
+	case MultiPoint:
+		return "MultiPoint"
+	case PointZ:
+		return "PointZ"
+	case PolyLineZ:
+		return "PolyLineZ"
+	case PolygonZ:
+		return "PolygonZ"
+	case MultiPointZ:
+		return "MultiPointZ"
+	case PointM:
+		return "PointM"
+	case PolyLineM:
+		return "PolyLineM"
+	case PolygonM:
+		return "PolygonM"
+	case MultiPointM:
+		return "MultiPointM"
+	case MultiPatch:
+		return "MultiPatch"
+	}
+
+	return fmt.Sprintf("ShapeType(%d)", int32(t))
+}
+
+// A Field describes a column of the .dbf attribute file.
+type Field struct {
+	Name string
+
+	// Type is the dBase field type: 'C' for character, 'N' or 'F'
+	// for numbers, 'L' for logical and 'D' for dates.
+	Type byte
+
+	Length   int
+	Decimals int
+}
+
+const (
+	fileCode = 9994
+	version  = 1000
+
+	headerLength = 100
+)
+
+// header is the header of the .shp and .shx files.
+type header struct {
+	ShapeType ShapeType
+	Bound     orb.Bound
+
+	// Length is the file length in 16 bit words.
+	Length int32
+}
+
+// Option is a function that configures reading or writing.
+type Option func(*options)
+
+type options struct {
+	codePage   string
+	projection string
+}
+
+// CodePage sets the encoding of the strings in the .dbf file when reading,
+// e.g. "UTF-8", "ISO-8859-1" or "1252". Open uses the .cpg file if there
+// is one. If not set, strings are read as UTF-8 if valid, otherwise Latin-1.
+// Files are always written as UTF-8.
+func CodePage(cpg string) Option {
+	return func(o *options) {
+		o.codePage = cpg
+	}
+}
+
+// Projection sets the WKT to write to the .prj file when writing.
+// The .prj read by Open is available as Reader.Projection.
+func Projection(wkt string) Option {
+	return func(o *options) {
+		o.projection = wkt
+	}
+}
+
+// basePath returns the path without the .shp extension.
+func basePath(path string) string {
+	if ext := filepath.Ext(path); strings.EqualFold(ext, ".shp") {
+		return strings.TrimSuffix(path, ext)
+	}
+
+	return path
+}
+
+// sidecar returns the path of the file with the extension, trying
+// the upper case extension if the lower case does not exist.
+func sidecar(base, ext string) string {
+	p := base + ext
+	if _, err := os.Stat(p); err != nil {
+		if upper := base + strings.ToUpper(ext); fileExists(upper) {
+			return upper
+		}
+	}
+
+	return p
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+
+	return b
+}
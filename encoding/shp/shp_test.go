@@ -0,0 +1,625 @@
+package shp
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+	"github.com/dadadamarine/orb/planar"
+)
+
+func TestWriteRead(t *testing.T) {
+	cases := []struct {
+		name     string
+		geoms    []orb.Geometry
+		expected []orb.Geometry
+		typ      ShapeType
+	}{
+		{
+			name:  "points",
+			geoms: []orb.Geometry{orb.Point{1, 2}, orb.Point{3, 4}, nil},
+			typ:   Point,
+		},
+		{
+			name:  "multi points",
+			geoms: []orb.Geometry{orb.MultiPoint{{1, 2}, {3, 4}}, orb.Point{5, 6}},
+			expected: []orb.Geometry{
+				orb.MultiPoint{{1, 2}, {3, 4}},
+				orb.MultiPoint{{5, 6}},
+			},
+			typ: MultiPoint,
+		},
+		{
+			name: "line strings",
+			geoms: []orb.Geometry{
+				orb.LineString{{1, 2}, {3, 4}},
+				orb.MultiLineString{{{1, 2}, {3, 4}}, {{5, 6}, {7, 8}, {9, 9}}},
+			},
+			typ: PolyLine,
+		},
+		{
+			name: "polygons",
+			geoms: []orb.Geometry{
+				orb.Polygon{
+					{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+					{{2, 2}, {2, 4}, {4, 4}, {4, 2}, {2, 2}},
+				},
+				orb.MultiPolygon{
+					{
+						{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+						{{2, 2}, {2, 4}, {4, 4}, {4, 2}, {2, 2}},
+						{{6, 6}, {6, 8}, {8, 8}, {8, 6}, {6, 6}},
+					},
+					{{{20, 20}, {30, 20}, {30, 30}, {20, 30}, {20, 20}}},
+					// an island in the hole
+					{{{2.5, 2.5}, {3.5, 2.5}, {3.5, 3.5}, {2.5, 3.5}, {2.5, 2.5}}},
+				},
+			},
+			typ: Polygon,
+		},
+		{
+			name: "polygon orientation and closing",
+			geoms: []orb.Geometry{
+				orb.Polygon{
+					{{0, 0}, {0, 10}, {10, 10}, {10, 0}},
+				},
+			},
+			expected: []orb.Geometry{
+				orb.Polygon{
+					{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+				},
+			},
+			typ: Polygon,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fc := geojson.NewFeatureCollection()
+			for _, g := range tc.geoms {
+				fc.Append(geojson.NewFeature(g))
+			}
+
+			shp, shx, dbf := &bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}
+			if err := Write(shp, shx, dbf, fc); err != nil {
+				t.Fatalf("write error: %v", err)
+			}
+
+			if shx.Len() != headerLength+8*len(tc.geoms) {
+				t.Errorf("incorrect shx length: %d", shx.Len())
+			}
+
+			r, err := NewReader(shp, dbf)
+			if err != nil {
+				t.Fatalf("reader error: %v", err)
+			}
+
+			if r.ShapeType != tc.typ {
+				t.Errorf("incorrect shape type: %v != %v", r.ShapeType, tc.typ)
+			}
+
+			result, err := r.ReadAll()
+			if err != nil {
+				t.Fatalf("read error: %v", err)
+			}
+
+			expected := tc.expected
+			if expected == nil {
+				expected = tc.geoms
+			}
+
+			if len(result.Features) != len(expected) {
+				t.Fatalf("incorrect number of features: %d", len(result.Features))
+			}
+
+			for i, f := range result.Features {
+				if !orb.Equal(f.Geometry, expected[i]) && !(f.Geometry == nil && expected[i] == nil) {
+					t.Errorf("incorrect geometry %d", i)
+					t.Logf("%v", f.Geometry)
+					t.Logf("%v", expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteRead_properties(t *testing.T) {
+	date := time.Date(2020, 5, 17, 0, 0, 0, 0, time.UTC)
+
+	fc := geojson.NewFeatureCollection()
+
+	f := geojson.NewFeature(orb.Point{1, 2})
+	f.Properties = geojson.Properties{
+		"name":             "Zürich",
+		"count":            3,
+		"population":       float64(415367),
+		"ratio":            0.125,
+		"open":             true,
+		"founded":          date,
+		"a_very_long_name": "truncated",
+		"a_very_long_nam2": "also truncated",
+		"mixed":            "text",
+	}
+	fc.Append(f)
+
+	f = geojson.NewFeature(orb.Point{3, 4})
+	f.Properties = geojson.Properties{
+		"name":  "Genève",
+		"count": -12,
+		"ratio": -1e-6,
+		"open":  false,
+		"mixed": 5,
+	}
+	fc.Append(f)
+
+	shp, shx, dbf := &bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}
+	if err := Write(shp, shx, dbf, fc); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	r, err := NewReader(shp, dbf)
+	if err != nil {
+		t.Fatalf("reader error: %v", err)
+	}
+
+	names := []string{}
+	for _, f := range r.Fields {
+		names = append(names, f.Name)
+	}
+
+	expectedNames := []string{"a_very_lon", "a_very_l_1", "count", "founded", "mixed", "name", "open", "population", "ratio"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("incorrect fields: %v", names)
+	}
+
+	result, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+
+	expected := []map[string]interface{}{
+		{
+			"name":       "Zürich",
+			"count":      int64(3),
+			"population": int64(415367),
+			"ratio":      0.125,
+			"open":       true,
+			"founded":    date,
+			"a_very_lon": "also truncated",
+			"a_very_l_1": "truncated",
+			"mixed":      "text",
+		},
+		{
+			"name":       "Genève",
+			"count":      int64(-12),
+			"population": nil,
+			"ratio":      -1e-6,
+			"open":       false,
+			"founded":    nil,
+			"a_very_lon": "",
+			"a_very_l_1": "",
+			"mixed":      "5",
+		},
+	}
+
+	for i, f := range result.Features {
+		if !reflect.DeepEqual(map[string]interface{}(f.Properties), expected[i]) {
+			t.Errorf("incorrect properties %d", i)
+			t.Logf("%v", f.Properties)
+			t.Logf("%v", expected[i])
+		}
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "shp")
+	if err != nil {
+		t.Fatalf("temp dir error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	fc := geojson.NewFeatureCollection()
+	for i := 0; i < 3; i++ {
+		f := geojson.NewFeature(orb.LineString{{0, float64(i)}, {1, float64(i)}})
+		f.Properties["id"] = i
+		fc.Append(f)
+	}
+
+	wkt := `GEOGCS["GCS_WGS_1984",DATUM["D_WGS_1984",SPHEROID["WGS_1984",6378137.0,298.257223563]],PRIMEM["Greenwich",0.0],UNIT["Degree",0.0174532925199433]]`
+
+	path := filepath.Join(dir, "lines.shp")
+	if err := WriteFile(path, fc, Projection(wkt)); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	for _, ext := range []string{".shp", ".shx", ".dbf", ".cpg", ".prj"} {
+		if !fileExists(filepath.Join(dir, "lines"+ext)) {
+			t.Errorf("%s file not written", ext)
+		}
+	}
+
+	r, err := Open(filepath.Join(dir, "lines"))
+	if err != nil {
+		t.Fatalf("open error: %v", err)
+	}
+	defer r.Close()
+
+	if r.Projection != wkt {
+		t.Errorf("incorrect projection: %v", r.Projection)
+	}
+
+	expectedBound := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 2}}
+	if !r.Bound.Equal(expectedBound) {
+		t.Errorf("incorrect bound: %v", r.Bound)
+	}
+
+	for i := 0; i < 3; i++ {
+		f, err := r.Next()
+		if err != nil {
+			t.Fatalf("read error: %v", err)
+		}
+
+		if v := f.Properties["id"]; v != int64(i) {
+			t.Errorf("incorrect id: %v", v)
+		}
+	}
+
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("should be EOF: %v", err)
+	}
+}
+
+func TestWrite_errors(t *testing.T) {
+	cases := []struct {
+		name  string
+		geoms []orb.Geometry
+	}{
+		{
+			name:  "mixed",
+			geoms: []orb.Geometry{orb.Point{1, 2}, orb.LineString{{1, 2}, {3, 4}}},
+		},
+		{
+			name:  "collection",
+			geoms: []orb.Geometry{orb.Collection{orb.Point{1, 2}}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fc := geojson.NewFeatureCollection()
+			for _, g := range tc.geoms {
+				fc.Append(geojson.NewFeature(g))
+			}
+
+			if err := Write(&bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}, fc); err == nil {
+				t.Errorf("should return error")
+			}
+		})
+	}
+}
+
+func TestNewReader_errors(t *testing.T) {
+	if _, err := NewReader(bytes.NewReader(nil), nil); err == nil {
+		t.Errorf("should return error for empty file")
+	}
+
+	if _, err := NewReader(bytes.NewReader(make([]byte, 100)), nil); err == nil {
+		t.Errorf("should return error for invalid file code")
+	}
+
+	// truncated record
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(orb.LineString{{1, 2}, {3, 4}}))
+
+	shp := &bytes.Buffer{}
+	if err := Write(shp, &bytes.Buffer{}, &bytes.Buffer{}, fc); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	r, err := NewReader(bytes.NewReader(shp.Bytes()[:shp.Len()-4]), nil)
+	if err != nil {
+		t.Fatalf("reader error: %v", err)
+	}
+
+	if _, err := r.Next(); err == nil || err == io.EOF {
+		t.Errorf("should return error: %v", err)
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	cases := []struct {
+		codePage string
+		input    []byte
+		expected string
+	}{
+		{codePage: "", input: []byte("Zürich"), expected: "Zürich"},
+		{codePage: "", input: []byte{'Z', 0xfc, 'r', 'i', 'c', 'h'}, expected: "Zürich"},
+		{codePage: "UTF-8", input: []byte("Zürich"), expected: "Zürich"},
+		{codePage: "ISO-8859-1", input: []byte{'Z', 0xfc, 'r', 'i', 'c', 'h'}, expected: "Zürich"},
+		{codePage: "1252", input: []byte{0x80, ' ', 0x93, 'a', 0x94}, expected: "€ “a”"},
+	}
+
+	for _, tc := range cases {
+		d, err := decoder(tc.codePage)
+		if err != nil {
+			t.Fatalf("decoder error: %v", err)
+		}
+
+		if v := d(tc.input); v != tc.expected {
+			t.Errorf("%q: incorrect string: %q != %q", tc.codePage, v, tc.expected)
+		}
+	}
+
+	if _, err := decoder("EBCDIC"); err == nil {
+		t.Errorf("should return error for unsupported code page")
+	}
+}
+
+func TestReader_deleted(t *testing.T) {
+	fc := geojson.NewFeatureCollection()
+	for i := 0; i < 3; i++ {
+		f := geojson.NewFeature(orb.Point{float64(i), 0})
+		f.Properties["id"] = i
+		fc.Append(f)
+	}
+
+	shp, dbf := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := Write(shp, &bytes.Buffer{}, dbf, fc); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	// mark the second record as deleted
+	data := dbf.Bytes()
+	headerLen := int(data[8]) | int(data[9])<<8
+	recordLen := int(data[10]) | int(data[11])<<8
+	data[headerLen+recordLen] = '*'
+
+	r, err := NewReader(shp, bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("reader error: %v", err)
+	}
+
+	result, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+
+	if len(result.Features) != 2 {
+		t.Fatalf("incorrect number of features: %d", len(result.Features))
+	}
+
+	if v := result.Features[1].Geometry; !orb.Equal(v, orb.Point{2, 0}) {
+		t.Errorf("incorrect geometry: %v", v)
+	}
+}
+
+func TestReadFile_buildings(t *testing.T) {
+	r, err := Open("testdata/buildings.shp")
+	if err != nil {
+		t.Fatalf("open error: %v", err)
+	}
+	defer r.Close()
+
+	if r.ShapeType != Polygon {
+		t.Errorf("incorrect shape type: %v", r.ShapeType)
+	}
+
+	expectedBound := orb.Bound{Min: orb.Point{1.4920903, 42.465178}, Max: orb.Point{1.7321076, 42.5438298}}
+	if !r.Bound.Equal(expectedBound) {
+		t.Errorf("incorrect bound: %v", r.Bound)
+	}
+
+	expectedFields := []Field{
+		{Name: "osm_id", Type: 'C', Length: 10},
+		{Name: "code", Type: 'N', Length: 4},
+		{Name: "fclass", Type: 'C', Length: 28},
+		{Name: "name", Type: 'C', Length: 100},
+		{Name: "type", Type: 'C', Length: 20},
+	}
+	if !reflect.DeepEqual(r.Fields, expectedFields) {
+		t.Errorf("incorrect fields: %v", r.Fields)
+	}
+
+	result, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+
+	cases := []struct {
+		properties geojson.Properties
+		rings      []int
+	}{
+		{
+			properties: geojson.Properties{
+				"osm_id": "30772650", "code": int64(1500), "fclass": "building",
+				"name": "Policia D'Andorra", "type": "public",
+			},
+			rings: []int{5},
+		},
+		{
+			properties: geojson.Properties{
+				"osm_id": "54858984", "code": int64(1500), "fclass": "building",
+				"name": "Pyrénées Hyper-Centre", "type": "",
+			},
+			rings: []int{7},
+		},
+		{
+			properties: geojson.Properties{
+				"osm_id": "4193667", "code": int64(1500), "fclass": "building",
+				"name": "", "type": "",
+			},
+			rings: []int{11, 5, 5},
+		},
+		{
+			properties: geojson.Properties{
+				"osm_id": "7630873", "code": int64(1500), "fclass": "building",
+				"name": "centre esportiu de Sant Julia de Loria", "type": "",
+			},
+			rings: []int{5, 5},
+		},
+	}
+
+	if len(result.Features) != len(cases) {
+		t.Fatalf("incorrect number of features: %d", len(result.Features))
+	}
+
+	for i, tc := range cases {
+		f := result.Features[i]
+		if !reflect.DeepEqual(f.Properties, tc.properties) {
+			t.Errorf("%d: incorrect properties: %v", i, f.Properties)
+		}
+
+		// the clockwise shell and the counter clockwise holes are one polygon
+		p, ok := f.Geometry.(orb.Polygon)
+		if !ok {
+			t.Fatalf("%d: incorrect geometry: %T", i, f.Geometry)
+		}
+
+		if len(p) != len(tc.rings) {
+			t.Fatalf("%d: incorrect number of rings: %d", i, len(p))
+		}
+
+		for j, r := range p {
+			if len(r) != tc.rings[j] {
+				t.Errorf("%d: ring %d: incorrect number of points: %d", i, j, len(r))
+			}
+
+			o := orb.CW
+			if j == 0 {
+				o = orb.CCW
+			}
+
+			if r.Orientation() != o {
+				t.Errorf("%d: ring %d: incorrect orientation", i, j)
+			}
+
+			if c, _ := planar.CentroidArea(r); j > 0 && !planar.RingContains(p[0], c) {
+				t.Errorf("%d: hole %d should be inside the shell", i, j)
+			}
+		}
+	}
+}
+
+func TestReadFile_typed(t *testing.T) {
+	fc, err := ReadFile("testdata/typed.shp")
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+
+	expected := []geojson.Properties{
+		{
+			"NAME": "Café", "COUNT": int64(42), "AREA": 123.456, "RATIO": 0.5,
+			"BUILT": time.Date(2014, 3, 5, 0, 0, 0, 0, time.UTC), "OPEN": true,
+		},
+		{
+			"NAME": "", "COUNT": int64(-7), "AREA": nil, "RATIO": 12.5,
+			"BUILT": time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC), "OPEN": false,
+		},
+		{
+			"NAME": "Niño", "COUNT": nil, "AREA": 0.0, "RATIO": nil,
+			"BUILT": nil, "OPEN": nil,
+		},
+	}
+
+	if len(fc.Features) != len(expected) {
+		t.Fatalf("incorrect number of features: %d", len(fc.Features))
+	}
+
+	for i, f := range fc.Features {
+		if !reflect.DeepEqual(f.Properties, expected[i]) {
+			t.Errorf("%d: incorrect properties: %v", i, f.Properties)
+		}
+	}
+
+	if g := fc.Features[2].Geometry; !orb.Equal(g, orb.Point{0, 10}) {
+		t.Errorf("incorrect geometry: %v", g)
+	}
+
+	// the code page of the .cpg file can be overridden
+	fc, err = ReadFile("testdata/typed.shp", CodePage("ISO-8859-1"))
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+
+	if v := fc.Features[0].Properties["NAME"]; v != "Café" {
+		t.Errorf("incorrect name: %v", v)
+	}
+}
+
+func TestReadFile_measures(t *testing.T) {
+	cases := []struct {
+		name     string
+		typ      ShapeType
+		expected []orb.Geometry
+	}{
+		{
+			name: "pointz",
+			typ:  PointZ,
+			expected: []orb.Geometry{
+				orb.Point{10, 10}, orb.Point{5, 5}, orb.Point{0, 10},
+			},
+		},
+		{
+			name: "polylinem",
+			typ:  PolyLineM,
+			expected: []orb.Geometry{
+				orb.LineString{{0, 0}, {5, 5}, {10, 10}},
+				orb.LineString{{15, 15}, {20, 20}, {25, 25}},
+			},
+		},
+		{
+			name: "polygonz",
+			typ:  PolygonZ,
+			expected: []orb.Geometry{
+				orb.Polygon{{{0, 0}, {5, 0}, {5, 5}, {0, 5}, {0, 0}}},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := Open(filepath.Join("testdata", tc.name))
+			if err != nil {
+				t.Fatalf("open error: %v", err)
+			}
+			defer r.Close()
+
+			if r.ShapeType != tc.typ {
+				t.Errorf("incorrect shape type: %v", r.ShapeType)
+			}
+
+			fc, err := r.ReadAll()
+			if err != nil {
+				t.Fatalf("read error: %v", err)
+			}
+
+			if len(fc.Features) != len(tc.expected) {
+				t.Fatalf("incorrect number of features: %d", len(fc.Features))
+			}
+
+			for i, f := range fc.Features {
+				if !orb.Equal(f.Geometry, tc.expected[i]) {
+					t.Errorf("%d: incorrect geometry: %v", i, f.Geometry)
+				}
+
+				// the numeric values are blank
+				if len(f.Properties) != 1 {
+					t.Errorf("%d: incorrect properties: %v", i, f.Properties)
+				}
+
+				for _, v := range f.Properties {
+					if v != nil {
+						t.Errorf("%d: blank value should be nil: %v", i, v)
+					}
+				}
+			}
+		})
+	}
+}
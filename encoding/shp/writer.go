@@ -0,0 +1,332 @@
+package shp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+)
+
+// WriteFile writes the feature collection to a shapefile with the given
+// .shp path, or the path without the extension. The .shx, .dbf and .cpg
+// files are written next to it and the .prj if the Projection option is set.
+// All the features must be points, line strings or polygons, the single
+// and multi types can be mixed. Properties are written as dbf columns
+// with names truncated to 10 characters, strings to 254 bytes.
+func WriteFile(path string, fc *geojson.FeatureCollection, opts ...Option) error {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	base := basePath(path)
+
+	files := make([]*os.File, 0, 3)
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	for _, ext := range []string{".shp", ".shx", ".dbf"} {
+		f, err := os.Create(base + ext)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, f)
+	}
+
+	if err := Write(files[0], files[1], files[2], fc); err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	files = nil
+
+	if err := ioutil.WriteFile(base+".cpg", []byte("UTF-8"), 0666); err != nil {
+		return err
+	}
+
+	if o.projection != "" {
+		return ioutil.WriteFile(base+".prj", []byte(o.projection), 0666)
+	}
+
+	return nil
+}
+
+// Write writes the feature collection as the .shp, .shx and .dbf data.
+// Strings in the .dbf are UTF-8 encoded.
+func Write(shp, shx, dbf io.Writer, fc *geojson.FeatureCollection) error {
+	shapeType := Null
+	for _, f := range fc.Features {
+		t, err := geometryShapeType(f.Geometry)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case t == Null || t == shapeType:
+		case shapeType == Null:
+			shapeType = t
+		case t == Point && shapeType == MultiPoint:
+		case t == MultiPoint && shapeType == Point:
+			shapeType = MultiPoint
+		default:
+			return fmt.Errorf("shp: mixed geometry types: %s and %s", shapeType, t)
+		}
+	}
+
+	records := make([][]byte, 0, len(fc.Features))
+	props := make([]map[string]interface{}, 0, len(fc.Features))
+
+	var (
+		bound    orb.Bound
+		hasBound bool
+	)
+
+	for _, f := range fc.Features {
+		records = append(records, encodeShape(shapeType, f.Geometry))
+		props = append(props, f.Properties)
+
+		if f.Geometry == nil || isEmpty(f.Geometry) {
+			continue
+		}
+
+		if b := f.Geometry.Bound(); !hasBound {
+			bound, hasBound = b, true
+		} else {
+			bound = bound.Union(b)
+		}
+	}
+
+	length := int64(headerLength)
+	for _, r := range records {
+		length += 8 + int64(len(r))
+	}
+
+	if length/2 > math.MaxInt32 {
+		return fmt.Errorf("shp: file too large")
+	}
+
+	h := header{ShapeType: shapeType, Bound: bound}
+
+	// the shp file
+	bw := bufio.NewWriter(shp)
+	h.Length = int32(length / 2)
+	bw.Write(h.encode())
+
+	for i, r := range records {
+		var rh [8]byte
+		binary.BigEndian.PutUint32(rh[0:4], uint32(i+1))
+		binary.BigEndian.PutUint32(rh[4:8], uint32(len(r)/2))
+		bw.Write(rh[:])
+		bw.Write(r)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	// the shx index
+	bw = bufio.NewWriter(shx)
+	h.Length = int32((headerLength + 8*len(records)) / 2)
+	bw.Write(h.encode())
+
+	offset := int64(headerLength)
+	for _, r := range records {
+		var rh [8]byte
+		binary.BigEndian.PutUint32(rh[0:4], uint32(offset/2))
+		binary.BigEndian.PutUint32(rh[4:8], uint32(len(r)/2))
+		bw.Write(rh[:])
+
+		offset += 8 + int64(len(r))
+	}
+
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	fields, keys := dbfFields(props)
+	return writeDBF(dbf, fields, keys, props)
+}
+
+func (h header) encode() []byte {
+	b := make([]byte, headerLength)
+	binary.BigEndian.PutUint32(b[0:4], fileCode)
+	binary.BigEndian.PutUint32(b[24:28], uint32(h.Length))
+	binary.LittleEndian.PutUint32(b[28:32], version)
+	binary.LittleEndian.PutUint32(b[32:36], uint32(h.ShapeType))
+	putBound(b[36:68], h.Bound)
+
+	return b
+}
+
+func geometryShapeType(g orb.Geometry) (ShapeType, error) {
+	if g == nil || isEmpty(g) {
+		return Null, nil
+	}
+
+	switch g.(type) {
+	case orb.Point:
+		return Point, nil
+	case orb.MultiPoint:
+		return MultiPoint, nil
+	case orb.LineString, orb.MultiLineString:
+		return PolyLine, nil
+	case orb.Ring, orb.Polygon, orb.MultiPolygon:
+		return Polygon, nil
+	}
+
+	return Null, fmt.Errorf("shp: geometry type not supported: %T", g)
+}
+
+func isEmpty(g orb.Geometry) bool {
+	switch g := g.(type) {
+	case orb.MultiPoint:
+		return len(g) == 0
+	case orb.LineString:
+		return len(g) == 0
+	case orb.MultiLineString:
+		return len(g) == 0
+	case orb.Ring:
+		return len(g) == 0
+	case orb.Polygon:
+		return len(g) == 0
+	case orb.MultiPolygon:
+		return len(g) == 0
+	}
+
+	return false
+}
+
+// encodeShape encodes the geometry as the record content of the shape type.
+func encodeShape(t ShapeType, g orb.Geometry) []byte {
+	if g == nil || isEmpty(g) {
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint32(b, uint32(Null))
+		return b
+	}
+
+	var parts [][]orb.Point
+	switch g := g.(type) {
+	case orb.Point:
+		if t == Point {
+			b := make([]byte, 20)
+			binary.LittleEndian.PutUint32(b, uint32(Point))
+			putPoint(b[4:], g)
+			return b
+		}
+
+		parts = [][]orb.Point{{g}}
+	case orb.MultiPoint:
+		parts = [][]orb.Point{g}
+	case orb.LineString:
+		parts = [][]orb.Point{g}
+	case orb.MultiLineString:
+		for _, ls := range g {
+			parts = append(parts, ls)
+		}
+	case orb.Ring:
+		parts = ringParts(orb.Polygon{g})
+	case orb.Polygon:
+		parts = ringParts(g)
+	case orb.MultiPolygon:
+		for _, p := range g {
+			parts = append(parts, ringParts(p)...)
+		}
+	}
+
+	var (
+		numPoints int
+		bound     orb.Bound
+	)
+
+	for i, p := range parts {
+		numPoints += len(p)
+
+		b := orb.MultiPoint(p).Bound()
+		if i == 0 {
+			bound = b
+		} else {
+			bound = bound.Union(b)
+		}
+	}
+
+	if t == MultiPoint {
+		b := make([]byte, 40+16*numPoints)
+		binary.LittleEndian.PutUint32(b, uint32(MultiPoint))
+		putBound(b[4:36], bound)
+		binary.LittleEndian.PutUint32(b[36:40], uint32(numPoints))
+
+		offset := 40
+		for _, p := range parts {
+			for _, pt := range p {
+				putPoint(b[offset:], pt)
+				offset += 16
+			}
+		}
+
+		return b
+	}
+
+	b := make([]byte, 44+4*len(parts)+16*numPoints)
+	binary.LittleEndian.PutUint32(b, uint32(t))
+	putBound(b[4:36], bound)
+	binary.LittleEndian.PutUint32(b[36:40], uint32(len(parts)))
+	binary.LittleEndian.PutUint32(b[40:44], uint32(numPoints))
+
+	offset, start := 44+4*len(parts), 0
+	for i, p := range parts {
+		binary.LittleEndian.PutUint32(b[44+4*i:], uint32(start))
+		start += len(p)
+
+		for _, pt := range p {
+			putPoint(b[offset:], pt)
+			offset += 16
+		}
+	}
+
+	return b
+}
+
+// ringParts returns the closed rings of the polygon, the outer
+// ring clockwise and the holes counter clockwise.
+func ringParts(p orb.Polygon) [][]orb.Point {
+	p = p.Clone()
+	for i, r := range p {
+		if len(r) > 0 && !r.Closed() {
+			p[i] = append(r, r[0])
+		}
+	}
+	orient(p, orb.CW)
+
+	parts := make([][]orb.Point, 0, len(p))
+	for _, r := range p {
+		if len(r) > 0 {
+			parts = append(parts, r)
+		}
+	}
+
+	return parts
+}
+
+func putPoint(b []byte, p orb.Point) {
+	binary.LittleEndian.PutUint64(b[0:8], math.Float64bits(p[0]))
+	binary.LittleEndian.PutUint64(b[8:16], math.Float64bits(p[1]))
+}
+
+func putBound(b []byte, bound orb.Bound) {
+	putPoint(b[0:16], bound.Min)
+	putPoint(b[16:32], bound.Max)
+}
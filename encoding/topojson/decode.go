@@ -0,0 +1,152 @@
+package topojson
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+	"github.com/dadadamarine/orb/internal/topology"
+)
+
+// ToFeatureCollections converts the objects of the topology into
+// GeoJSON feature collections keyed by the object name.
+// GeometryCollection objects will have a feature for every child
+// geometry, other objects will be a collection of one feature.
+func (t *Topology) ToFeatureCollections() (map[string]*geojson.FeatureCollection, error) {
+	arcs := t.decodeArcs()
+
+	result := make(map[string]*geojson.FeatureCollection, len(t.Objects))
+	for name, obj := range t.Objects {
+		fc, err := t.featureCollection(arcs, obj)
+		if err != nil {
+			return nil, err
+		}
+
+		result[name] = fc
+	}
+
+	return result, nil
+}
+
+// ToFeatureCollection converts the named object of the topology
+// into a GeoJSON feature collection.
+func (t *Topology) ToFeatureCollection(name string) (*geojson.FeatureCollection, error) {
+	obj, ok := t.Objects[name]
+	if !ok {
+		return nil, fmt.Errorf("topojson: object not found: %s", name)
+	}
+
+	return t.featureCollection(t.decodeArcs(), obj)
+}
+
+// ObjectNames returns the sorted names of the objects in the topology.
+func (t *Topology) ObjectNames() []string {
+	names := make([]string, 0, len(t.Objects))
+	for name := range t.Objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (t *Topology) featureCollection(arcs []orb.LineString, obj *Geometry) (*geojson.FeatureCollection, error) {
+	fc := geojson.NewFeatureCollection()
+
+	geometries := []*Geometry{obj}
+	if obj.Type == "GeometryCollection" {
+		geometries = obj.Geometries
+	}
+
+	for _, g := range geometries {
+		geom, err := t.geometry(arcs, g)
+		if err != nil {
+			return nil, err
+		}
+
+		f := geojson.NewFeature(geom)
+		f.ID = g.ID
+		if g.Properties != nil {
+			f.Properties = g.Properties
+		}
+
+		fc.Append(f)
+	}
+
+	return fc, nil
+}
+
+// geometry converts the topojson geometry into an orb geometry.
+func (t *Topology) geometry(arcs []orb.LineString, g *Geometry) (orb.Geometry, error) {
+	switch g.Type {
+	case "":
+		return nil, nil
+	case "Point":
+		return t.position(g.Point), nil
+	case "MultiPoint":
+		mp := make(orb.MultiPoint, 0, len(g.MultiPoint))
+		for _, pos := range g.MultiPoint {
+			mp = append(mp, t.position(pos))
+		}
+		return mp, nil
+	case "LineString":
+		return stitch(arcs, g.LineString)
+	case "MultiLineString":
+		mls := make(orb.MultiLineString, 0, len(g.MultiLineString))
+		for _, indexes := range g.MultiLineString {
+			ls, err := stitch(arcs, indexes)
+			if err != nil {
+				return nil, err
+			}
+			mls = append(mls, ls)
+		}
+		return mls, nil
+	case "Polygon":
+		return polygon(arcs, g.Polygon)
+	case "MultiPolygon":
+		mp := make(orb.MultiPolygon, 0, len(g.MultiPolygon))
+		for _, rings := range g.MultiPolygon {
+			p, err := polygon(arcs, rings)
+			if err != nil {
+				return nil, err
+			}
+			mp = append(mp, p)
+		}
+		return mp, nil
+	case "GeometryCollection":
+		c := make(orb.Collection, 0, len(g.Geometries))
+		for _, child := range g.Geometries {
+			geom, err := t.geometry(arcs, child)
+			if err != nil {
+				return nil, err
+			}
+			c = append(c, geom)
+		}
+		return c, nil
+	}
+
+	return nil, ErrInvalidGeometry
+}
+
+func polygon(arcs []orb.LineString, rings [][]int) (orb.Polygon, error) {
+	p := make(orb.Polygon, 0, len(rings))
+	for _, indexes := range rings {
+		ls, err := stitch(arcs, indexes)
+		if err != nil {
+			return nil, err
+		}
+		p = append(p, orb.Ring(ls))
+	}
+
+	return p, nil
+}
+
+func stitch(arcs []orb.LineString, indexes []int) (orb.LineString, error) {
+	ls, ok := topology.Stitch(arcs, indexes)
+	if !ok {
+		return nil, fmt.Errorf("topojson: arc index out of range: %v", indexes)
+	}
+
+	return ls, nil
+}
@@ -0,0 +1,128 @@
+package topojson
+
+import (
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+// the example from the TopoJSON specification.
+const specExample = `{
+  "type": "Topology",
+  "transform": {
+    "scale": [0.0005000500050005, 0.00010001000100010001],
+    "translate": [100, 0]
+  },
+  "objects": {
+    "example": {
+      "type": "GeometryCollection",
+      "geometries": [
+        {
+          "type": "Point",
+          "properties": {"prop0": "value0"},
+          "coordinates": [4000, 5000]
+        },
+        {
+          "type": "LineString",
+          "properties": {"prop0": "value0", "prop1": 0},
+          "arcs": [0]
+        },
+        {
+          "type": "Polygon",
+          "properties": {"prop0": "value0", "prop1": {"this": "that"}},
+          "arcs": [[-2]]
+        }
+      ]
+    }
+  },
+  "arcs": [
+    [[4000, 0], [1999, 9999], [2000, -9999], [2000, 9999]],
+    [[0, 0], [0, 9999], [2000, 0], [0, -9999], [-2000, 0]]
+  ]
+}`
+
+func TestTopology_ToFeatureCollection(t *testing.T) {
+	topo, err := Unmarshal([]byte(specExample))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	fc, err := topo.ToFeatureCollection("example")
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	if l := len(fc.Features); l != 3 {
+		t.Fatalf("incorrect number of features: %d", l)
+	}
+
+	expected := []orb.Geometry{
+		orb.Point{102, 0.5},
+		orb.LineString{{102, 0}, {103, 1}, {104, 0}, {105, 1}},
+		orb.Polygon{{{100, 0}, {101, 0}, {101, 1}, {100, 1}, {100, 0}}},
+	}
+
+	for i, f := range fc.Features {
+		g := orb.Round(f.Geometry, 1e3)
+		if !orb.Equal(g, expected[i]) {
+			t.Errorf("incorrect geometry %d: %v", i, g)
+		}
+
+		if f.Properties.MustString("prop0") != "value0" {
+			t.Errorf("incorrect properties: %v", f.Properties)
+		}
+	}
+
+	_, err = topo.ToFeatureCollection("missing")
+	if err == nil {
+		t.Errorf("should return error for missing object")
+	}
+}
+
+func TestTopology_ToFeatureCollections(t *testing.T) {
+	data := `{
+	  "type": "Topology",
+	  "objects": {
+	    "line": {"type": "LineString", "arcs": [0, 1], "id": "a"},
+	    "empty": {"type": null}
+	  },
+	  "arcs": [[[0, 0], [1, 1]], [[1, 1], [2, 0]]]
+	}`
+
+	topo, err := Unmarshal([]byte(data))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	fcs, err := topo.ToFeatureCollections()
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	f := fcs["line"].Features[0]
+	if !orb.Equal(f.Geometry, orb.LineString{{0, 0}, {1, 1}, {2, 0}}) {
+		t.Errorf("incorrect stitched line: %v", f.Geometry)
+	}
+
+	if f.ID != "a" {
+		t.Errorf("incorrect id: %v", f.ID)
+	}
+
+	if g := fcs["empty"].Features[0].Geometry; g != nil {
+		t.Errorf("expected nil geometry: %v", g)
+	}
+}
+
+func TestTopology_ToFeatureCollections_badArc(t *testing.T) {
+	topo := &Topology{
+		Objects: map[string]*Geometry{
+			"line": {Type: "LineString", LineString: []int{3}},
+		},
+		Arcs: [][][]float64{{{0, 0}, {1, 1}}},
+	}
+
+	_, err := topo.ToFeatureCollections()
+	if err == nil {
+		t.Errorf("should return error for out of range arc")
+	}
+}
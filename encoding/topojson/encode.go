@@ -0,0 +1,274 @@
+package topojson
+
+import (
+	"math"
+	"sort"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+	"github.com/dadadamarine/orb/internal/topology"
+)
+
+type options struct {
+	quantize float64
+}
+
+// An Option is a possible parameter when building a topology.
+type Option func(*options)
+
+// Quantize is an option to quantize the topology. The value is the number
+// of distinguishable positions in each dimension, e.g. 1e4 or 1e6.
+// The arcs will be delta-encoded and a Transform added to the topology.
+// Values less than 2 disable quantization, the default.
+func Quantize(n float64) Option {
+	return func(o *options) {
+		o.quantize = n
+	}
+}
+
+// New builds a topology from the feature collections, keyed by object name.
+// Each feature collection becomes a GeometryCollection object with a child
+// geometry for each feature. Shared line segments of the line strings
+// and polygon rings are detected and stored only once as arcs.
+func New(collections map[string]*geojson.FeatureCollection, opts ...Option) *Topology {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	// sort the names so arcs come out in a consistent order.
+	names := make([]string, 0, len(collections))
+	for name := range collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b := &builder{
+		topo:  topology.New(),
+		bound: orb.Bound{Min: orb.Point{math.Inf(1), math.Inf(1)}, Max: orb.Point{math.Inf(-1), math.Inf(-1)}},
+	}
+
+	for _, name := range names {
+		for _, f := range collections[name].Features {
+			b.collect(f.Geometry)
+		}
+	}
+
+	b.topo.Build()
+
+	t := &Topology{
+		Type:    topologyType,
+		Objects: make(map[string]*Geometry, len(collections)),
+	}
+
+	for _, name := range names {
+		fc := collections[name]
+
+		obj := &Geometry{
+			Type:       "GeometryCollection",
+			Geometries: make([]*Geometry, 0, len(fc.Features)),
+		}
+
+		for _, f := range fc.Features {
+			g := b.geometry(f.Geometry)
+			g.ID = f.ID
+			g.Properties = f.Properties
+			obj.Geometries = append(obj.Geometries, g)
+		}
+
+		t.Objects[name] = obj
+	}
+
+	if !b.bound.IsEmpty() {
+		t.BBox = []float64{b.bound.Min[0], b.bound.Min[1], b.bound.Max[0], b.bound.Max[1]}
+	}
+
+	if o.quantize >= 2 && t.BBox != nil {
+		t.quantize(b.topo.Arcs(), o.quantize, b.bound)
+	} else {
+		arcs := b.topo.Arcs()
+		t.Arcs = make([][][]float64, len(arcs))
+		for i, arc := range arcs {
+			t.Arcs[i] = make([][]float64, len(arc))
+			for j, p := range arc {
+				t.Arcs[i][j] = []float64{p[0], p[1]}
+			}
+		}
+	}
+
+	return t
+}
+
+// builder holds the state needed to compute the shared arcs.
+type builder struct {
+	topo  *topology.Topology
+	next  int // next part to use when building the geometries
+	bound orb.Bound
+}
+
+// collect walks the geometry and records the line strings and rings.
+func (b *builder) collect(geom orb.Geometry) {
+	if geom == nil {
+		return
+	}
+
+	b.bound = b.bound.Union(geom.Bound())
+
+	switch g := geom.(type) {
+	case orb.LineString:
+		b.topo.AddLineString(g)
+	case orb.MultiLineString:
+		for _, ls := range g {
+			b.topo.AddLineString(ls)
+		}
+	case orb.Ring:
+		b.topo.AddRing(g)
+	case orb.Polygon:
+		for _, r := range g {
+			b.topo.AddRing(r)
+		}
+	case orb.MultiPolygon:
+		for _, p := range g {
+			for _, r := range p {
+				b.topo.AddRing(r)
+			}
+		}
+	case orb.Collection:
+		for _, c := range g {
+			b.collect(c)
+		}
+	case orb.Bound:
+		b.topo.AddRing(g.ToRing())
+	}
+}
+
+// nextArcs returns the arcs of the next part. Geometries must be visited
+// in the same order as they were collected.
+func (b *builder) nextArcs() []int {
+	arcs := b.topo.Part(b.next)
+	b.next++
+
+	if arcs == nil {
+		return []int{}
+	}
+
+	return arcs
+}
+
+// geometry converts the orb geometry into a topojson geometry
+// referencing the computed arcs.
+func (b *builder) geometry(geom orb.Geometry) *Geometry {
+	switch g := geom.(type) {
+	case orb.Point:
+		return &Geometry{Type: "Point", Point: []float64{g[0], g[1]}}
+	case orb.MultiPoint:
+		mp := make([][]float64, 0, len(g))
+		for _, p := range g {
+			mp = append(mp, []float64{p[0], p[1]})
+		}
+		return &Geometry{Type: "MultiPoint", MultiPoint: mp}
+	case orb.LineString:
+		return &Geometry{Type: "LineString", LineString: b.nextArcs()}
+	case orb.MultiLineString:
+		mls := make([][]int, 0, len(g))
+		for range g {
+			mls = append(mls, b.nextArcs())
+		}
+		return &Geometry{Type: "MultiLineString", MultiLineString: mls}
+	case orb.Ring:
+		return &Geometry{Type: "Polygon", Polygon: [][]int{b.nextArcs()}}
+	case orb.Polygon:
+		return &Geometry{Type: "Polygon", Polygon: b.polygon(g)}
+	case orb.MultiPolygon:
+		mp := make([][][]int, 0, len(g))
+		for _, p := range g {
+			mp = append(mp, b.polygon(p))
+		}
+		return &Geometry{Type: "MultiPolygon", MultiPolygon: mp}
+	case orb.Collection:
+		c := make([]*Geometry, 0, len(g))
+		for _, child := range g {
+			c = append(c, b.geometry(child))
+		}
+		return &Geometry{Type: "GeometryCollection", Geometries: c}
+	case orb.Bound:
+		return &Geometry{Type: "Polygon", Polygon: [][]int{b.nextArcs()}}
+	}
+
+	// nil geometry
+	return &Geometry{}
+}
+
+func (b *builder) polygon(p orb.Polygon) [][]int {
+	result := make([][]int, 0, len(p))
+	for range p {
+		result = append(result, b.nextArcs())
+	}
+
+	return result
+}
+
+// quantize sets the quantized and delta-encoded arcs and
+// quantizes the positions of all the point geometries.
+func (t *Topology) quantize(arcs []orb.LineString, n float64, bound orb.Bound) {
+	tr := &Transform{
+		Scale:     [2]float64{(bound.Max[0] - bound.Min[0]) / (n - 1), (bound.Max[1] - bound.Min[1]) / (n - 1)},
+		Translate: [2]float64{bound.Min[0], bound.Min[1]},
+	}
+
+	if tr.Scale[0] == 0 {
+		tr.Scale[0] = 1
+	}
+
+	if tr.Scale[1] == 0 {
+		tr.Scale[1] = 1
+	}
+
+	t.Transform = tr
+	t.Arcs = make([][][]float64, len(arcs))
+	for i, arc := range arcs {
+		result := make([][]float64, 0, len(arc))
+
+		var px, py float64
+		for j, p := range arc {
+			x, y := tr.invert(p)
+			if j > 0 && x == px && y == py {
+				continue
+			}
+
+			result = append(result, []float64{x - px, y - py})
+			px, py = x, y
+		}
+
+		// an arc must have at least two positions
+		if len(result) == 1 {
+			result = append(result, []float64{0, 0})
+		}
+
+		t.Arcs[i] = result
+	}
+
+	for _, obj := range t.Objects {
+		tr.quantizePoints(obj)
+	}
+}
+
+func (tr *Transform) quantizePoints(g *Geometry) {
+	switch g.Type {
+	case "Point":
+		g.Point[0], g.Point[1] = tr.invert(orb.Point{g.Point[0], g.Point[1]})
+	case "MultiPoint":
+		for _, p := range g.MultiPoint {
+			p[0], p[1] = tr.invert(orb.Point{p[0], p[1]})
+		}
+	case "GeometryCollection":
+		for _, c := range g.Geometries {
+			tr.quantizePoints(c)
+		}
+	}
+}
+
+func (tr *Transform) invert(p orb.Point) (float64, float64) {
+	return math.Round((p[0] - tr.Translate[0]) / tr.Scale[0]),
+		math.Round((p[1] - tr.Translate[1]) / tr.Scale[1])
+}
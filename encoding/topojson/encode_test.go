@@ -0,0 +1,202 @@
+package topojson
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+)
+
+func TestNew_sharedArcs(t *testing.T) {
+	// two squares that share the x=1 edge
+	left := orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}
+	right := orb.Polygon{{{1, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 0}}}
+
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(left))
+	fc.Append(geojson.NewFeature(right))
+
+	topo := New(map[string]*geojson.FeatureCollection{"squares": fc})
+
+	if l := len(topo.Arcs); l != 3 {
+		t.Fatalf("incorrect number of arcs: %d", l)
+	}
+
+	obj := topo.Objects["squares"]
+	if l := len(obj.Geometries); l != 2 {
+		t.Fatalf("incorrect number of geometries: %d", l)
+	}
+
+	// the shared arc should be referenced by both, once reversed.
+	shared := map[int]int{}
+	for _, g := range obj.Geometries {
+		for _, i := range g.Polygon[0] {
+			if i < 0 {
+				i = ^i
+			}
+			shared[i]++
+		}
+	}
+
+	count := 0
+	for _, c := range shared {
+		if c == 2 {
+			count++
+		}
+	}
+
+	if count != 1 {
+		t.Errorf("should have one shared arc: %v", shared)
+	}
+
+	expected := [][]float64{{1, 0}, {1, 1}}
+	for _, arc := range topo.Arcs {
+		if reflect.DeepEqual(arc, expected) {
+			return
+		}
+	}
+
+	t.Errorf("shared arc not found: %v", topo.Arcs)
+}
+
+func TestNew_sharedRing(t *testing.T) {
+	// the hole of the outer polygon is exactly the inner polygon
+	hole := orb.Ring{{1, 1}, {1, 2}, {2, 2}, {2, 1}, {1, 1}}
+	outer := orb.Polygon{
+		{{0, 0}, {3, 0}, {3, 3}, {0, 3}, {0, 0}},
+		hole,
+	}
+
+	inner := orb.Polygon{{{2, 2}, {1, 2}, {1, 1}, {2, 1}, {2, 2}}}
+
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(outer))
+	fc.Append(geojson.NewFeature(inner))
+
+	topo := New(map[string]*geojson.FeatureCollection{"rings": fc})
+	if l := len(topo.Arcs); l != 2 {
+		t.Fatalf("incorrect number of arcs: %d", l)
+	}
+
+	h := topo.Objects["rings"].Geometries[0].Polygon[1]
+	i := topo.Objects["rings"].Geometries[1].Polygon[0]
+	if h[0] != ^i[0] {
+		t.Errorf("inner ring should be the reverse of the hole: %v %v", h, i)
+	}
+}
+
+func TestNew_lines(t *testing.T) {
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(orb.LineString{{0, 0}, {1, 0}, {2, 0}, {3, 0}}))
+	fc.Append(geojson.NewFeature(orb.LineString{{1, 1}, {1, 0}, {2, 0}, {2, 1}}))
+	fc.Append(geojson.NewFeature(orb.Point{5, 5}))
+
+	topo := New(map[string]*geojson.FeatureCollection{"lines": fc})
+
+	geoms := topo.Objects["lines"].Geometries
+	if !reflect.DeepEqual(geoms[0].LineString, []int{0, 1, 2}) {
+		t.Errorf("incorrect first line: %v", geoms[0].LineString)
+	}
+
+	if !reflect.DeepEqual(geoms[1].LineString, []int{3, 1, 4}) {
+		t.Errorf("incorrect second line: %v", geoms[1].LineString)
+	}
+
+	if !reflect.DeepEqual(geoms[2].Point, []float64{5, 5}) {
+		t.Errorf("incorrect point: %v", geoms[2].Point)
+	}
+
+	if !reflect.DeepEqual(topo.BBox, []float64{0, 0, 5, 5}) {
+		t.Errorf("incorrect bbox: %v", topo.BBox)
+	}
+}
+
+func TestNew_quantize(t *testing.T) {
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(orb.LineString{{0, 0}, {5, 5}, {10, 10}}))
+	fc.Append(geojson.NewFeature(orb.Point{2.5, 7.5}))
+
+	topo := New(map[string]*geojson.FeatureCollection{"q": fc}, Quantize(11))
+
+	expected := &Transform{
+		Scale:     [2]float64{1, 1},
+		Translate: [2]float64{0, 0},
+	}
+	if !reflect.DeepEqual(topo.Transform, expected) {
+		t.Errorf("incorrect transform: %v", topo.Transform)
+	}
+
+	if !reflect.DeepEqual(topo.Arcs[0], [][]float64{{0, 0}, {5, 5}, {5, 5}}) {
+		t.Errorf("arc not delta encoded: %v", topo.Arcs[0])
+	}
+
+	// positions are not delta encoded
+	if p := topo.Objects["q"].Geometries[1].Point; !reflect.DeepEqual(p, []float64{3, 8}) {
+		t.Errorf("incorrect point: %v", p)
+	}
+}
+
+func TestNew_quantizeCollapse(t *testing.T) {
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(orb.LineString{{0, 0}, {0.01, 0.01}, {0.02, 0.02}, {10, 10}}))
+
+	topo := New(map[string]*geojson.FeatureCollection{"q": fc}, Quantize(11))
+	if !reflect.DeepEqual(topo.Arcs[0], [][]float64{{0, 0}, {10, 10}}) {
+		t.Errorf("duplicate positions should be removed: %v", topo.Arcs[0])
+	}
+}
+
+func TestNew_roundTrip(t *testing.T) {
+	geometries := []orb.Geometry{
+		orb.Point{1, 2},
+		orb.MultiPoint{{1, 2}, {3, 4}},
+		orb.LineString{{0, 0}, {1, 1}, {2, 0}},
+		orb.MultiLineString{{{0, 0}, {1, 1}}, {{2, 2}, {1, 1}, {0, 3}}},
+		orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+		orb.MultiPolygon{
+			{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+			{{{1, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 0}}},
+		},
+		orb.Collection{orb.Point{1, 2}, orb.LineString{{0, 0}, {1, 0}}},
+	}
+
+	for _, quantize := range []float64{0, 1e6} {
+		fc := geojson.NewFeatureCollection()
+		for i, g := range geometries {
+			f := geojson.NewFeature(g)
+			f.Properties["index"] = float64(i)
+			fc.Append(f)
+		}
+
+		topo := New(map[string]*geojson.FeatureCollection{"all": fc}, Quantize(quantize))
+		data, err := topo.MarshalJSON()
+		if err != nil {
+			t.Fatalf("marshal error: %v", err)
+		}
+
+		topo, err = Unmarshal(data)
+		if err != nil {
+			t.Fatalf("unmarshal error: %v", err)
+		}
+
+		result, err := topo.ToFeatureCollection("all")
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+
+		for i, f := range result.Features {
+			if v := f.Properties.MustFloat64("index"); v != float64(i) {
+				t.Errorf("incorrect properties: %v", f.Properties)
+			}
+
+			// quantized result will have some small differences
+			g := orb.Round(f.Geometry, 1e4)
+			if !orb.Equal(g, geometries[i]) {
+				t.Errorf("%v: geometry not equal for %v", quantize, geometries[i].GeoJSONType())
+				t.Logf("%v", g)
+				t.Logf("%v", geometries[i])
+			}
+		}
+	}
+}
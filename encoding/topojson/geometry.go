@@ -0,0 +1,140 @@
+package topojson
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/dadadamarine/orb/geojson"
+)
+
+// ErrInvalidGeometry will be returned if a the json of the geometry is invalid.
+var ErrInvalidGeometry = errors.New("topojson: invalid geometry")
+
+// A Geometry corresponds to a TopoJSON geometry object. Only the attribute
+// matching the Type is set. Line based geometries reference the arcs
+// of the topology by index, a negative index is the one's complement
+// of the index and means the arc should be reversed.
+type Geometry struct {
+	ID         interface{}        `json:"id,omitempty"`
+	Type       string             `json:"type"`
+	BBox       []float64          `json:"bbox,omitempty"`
+	Properties geojson.Properties `json:"properties,omitempty"`
+
+	Point           []float64
+	MultiPoint      [][]float64
+	LineString      []int
+	MultiLineString [][]int
+	Polygon         [][]int
+	MultiPolygon    [][][]int
+	Geometries      []*Geometry
+}
+
+// MarshalJSON converts the geometry object into the proper JSON.
+// The geometry specific attribute is encoded as "coordinates",
+// "arcs" or "geometries" depending on the type.
+func (g Geometry) MarshalJSON() ([]byte, error) {
+	jg := &jsonGeometry{
+		ID:         g.ID,
+		BBox:       g.BBox,
+		Properties: g.Properties,
+	}
+
+	if g.Type != "" {
+		jg.Type = &g.Type
+	}
+
+	if len(jg.Properties) == 0 {
+		jg.Properties = nil
+	}
+
+	var err error
+	switch g.Type {
+	case "Point":
+		jg.Coordinates, err = json.Marshal(g.Point)
+	case "MultiPoint":
+		jg.Coordinates, err = json.Marshal(g.MultiPoint)
+	case "LineString":
+		jg.Arcs, err = json.Marshal(g.LineString)
+	case "MultiLineString":
+		jg.Arcs, err = json.Marshal(g.MultiLineString)
+	case "Polygon":
+		jg.Arcs, err = json.Marshal(g.Polygon)
+	case "MultiPolygon":
+		jg.Arcs, err = json.Marshal(g.MultiPolygon)
+	case "GeometryCollection":
+		geometries := g.Geometries
+		if geometries == nil {
+			geometries = []*Geometry{}
+		}
+		jg.Geometries, err = json.Marshal(geometries)
+	case "":
+		// a null geometry, type will be encoded as null
+	default:
+		return nil, ErrInvalidGeometry
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(jg)
+}
+
+// UnmarshalJSON decodes the data into a TopoJSON geometry.
+func (g *Geometry) UnmarshalJSON(data []byte) error {
+	jg := &jsonGeometry{}
+	err := json.Unmarshal(data, jg)
+	if err != nil {
+		return err
+	}
+
+	*g = Geometry{
+		ID:         jg.ID,
+		BBox:       jg.BBox,
+		Properties: jg.Properties,
+	}
+
+	if jg.Type == nil {
+		return nil
+	}
+	g.Type = *jg.Type
+
+	switch g.Type {
+	case "Point":
+		err = unmarshalMember(jg.Coordinates, &g.Point)
+	case "MultiPoint":
+		err = unmarshalMember(jg.Coordinates, &g.MultiPoint)
+	case "LineString":
+		err = unmarshalMember(jg.Arcs, &g.LineString)
+	case "MultiLineString":
+		err = unmarshalMember(jg.Arcs, &g.MultiLineString)
+	case "Polygon":
+		err = unmarshalMember(jg.Arcs, &g.Polygon)
+	case "MultiPolygon":
+		err = unmarshalMember(jg.Arcs, &g.MultiPolygon)
+	case "GeometryCollection":
+		err = unmarshalMember(jg.Geometries, &g.Geometries)
+	default:
+		return ErrInvalidGeometry
+	}
+
+	return err
+}
+
+func unmarshalMember(data json.RawMessage, v interface{}) error {
+	if len(data) == 0 {
+		return ErrInvalidGeometry
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+type jsonGeometry struct {
+	ID          interface{}        `json:"id,omitempty"`
+	Type        *string            `json:"type"`
+	BBox        []float64          `json:"bbox,omitempty"`
+	Properties  geojson.Properties `json:"properties,omitempty"`
+	Coordinates json.RawMessage    `json:"coordinates,omitempty"`
+	Arcs        json.RawMessage    `json:"arcs,omitempty"`
+	Geometries  json.RawMessage    `json:"geometries,omitempty"`
+}
@@ -0,0 +1,132 @@
+// Package topojson implements encoding and decoding of TopoJSON, an extension
+// of GeoJSON that encodes topology. Shared boundaries between geometries
+// are stored once as arcs and referenced by index from the geometries.
+package topojson
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dadadamarine/orb"
+)
+
+const topologyType = "Topology"
+
+// A Topology corresponds to a TopoJSON topology object.
+type Topology struct {
+	Type      string               `json:"type"`
+	BBox      []float64            `json:"bbox,omitempty"`
+	Transform *Transform           `json:"transform,omitempty"`
+	Objects   map[string]*Geometry `json:"objects"`
+
+	// Arcs are stored as they appear in the TopoJSON. If the topology
+	// is quantized the positions are delta-encoded integers and
+	// the Transform must be applied to get the real coordinates.
+	Arcs [][][]float64 `json:"arcs"`
+}
+
+// A Transform defines the quantization of a topology. Positions
+// are computed as the quantized position multiplied by the scale
+// plus the translate.
+type Transform struct {
+	Scale     [2]float64 `json:"scale"`
+	Translate [2]float64 `json:"translate"`
+}
+
+// Unmarshal decodes the data into a TopoJSON topology.
+// Alternately one can call json.Unmarshal(t) directly for the same result.
+func Unmarshal(data []byte) (*Topology, error) {
+	t := &Topology{}
+	err := json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// MarshalJSON converts the topology object into the proper JSON.
+// Alternately one can call json.Marshal(t) directly for the same result.
+func (t Topology) MarshalJSON() ([]byte, error) {
+	jt := jsonTopology(t)
+	jt.Type = topologyType
+
+	if jt.Objects == nil {
+		jt.Objects = map[string]*Geometry{}
+	}
+
+	if jt.Arcs == nil {
+		jt.Arcs = [][][]float64{}
+	}
+
+	return json.Marshal(jt)
+}
+
+// UnmarshalJSON decodes the data into a TopoJSON topology.
+func (t *Topology) UnmarshalJSON(data []byte) error {
+	jt := jsonTopology{}
+	err := json.Unmarshal(data, &jt)
+	if err != nil {
+		return err
+	}
+
+	if jt.Type != topologyType {
+		return fmt.Errorf("topojson: not a topology: type=%s", jt.Type)
+	}
+
+	*t = Topology(jt)
+	return nil
+}
+
+// jsonTopology is used to avoid recursion in the json marshal methods.
+type jsonTopology Topology
+
+// decodeArcs converts the raw arcs into line strings, removing
+// the delta-encoding and the transform if quantized.
+func (t *Topology) decodeArcs() []orb.LineString {
+	result := make([]orb.LineString, len(t.Arcs))
+	for i, arc := range t.Arcs {
+		ls := make(orb.LineString, 0, len(arc))
+
+		x, y := 0.0, 0.0
+		for _, pos := range arc {
+			if len(pos) < 2 {
+				continue
+			}
+
+			if t.Transform == nil {
+				ls = append(ls, orb.Point{pos[0], pos[1]})
+				continue
+			}
+
+			x += pos[0]
+			y += pos[1]
+			ls = append(ls, t.Transform.apply(x, y))
+		}
+
+		result[i] = ls
+	}
+
+	return result
+}
+
+// position converts an object position into a point.
+// Positions of point geometries are quantized but not delta-encoded.
+func (t *Topology) position(pos []float64) orb.Point {
+	if len(pos) < 2 {
+		return orb.Point{}
+	}
+
+	if t.Transform == nil {
+		return orb.Point{pos[0], pos[1]}
+	}
+
+	return t.Transform.apply(pos[0], pos[1])
+}
+
+func (tr *Transform) apply(x, y float64) orb.Point {
+	return orb.Point{
+		x*tr.Scale[0] + tr.Translate[0],
+		y*tr.Scale[1] + tr.Translate[1],
+	}
+}
@@ -0,0 +1,113 @@
+package topojson
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTopology_MarshalJSON(t *testing.T) {
+	topo := &Topology{
+		Objects: map[string]*Geometry{
+			"point": {Type: "Point", Point: []float64{1, 2}},
+		},
+	}
+
+	data, err := json.Marshal(topo)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	expected := `{"type":"Topology","objects":{"point":{"type":"Point","coordinates":[1,2]}},"arcs":[]}`
+	if string(data) != expected {
+		t.Errorf("incorrect json: %v", string(data))
+	}
+}
+
+func TestTopology_UnmarshalJSON(t *testing.T) {
+	_, err := Unmarshal([]byte(`{"type":"FeatureCollection"}`))
+	if err == nil || !strings.Contains(err.Error(), "not a topology") {
+		t.Errorf("should error on non topology: %v", err)
+	}
+}
+
+func TestGeometry_MarshalJSON(t *testing.T) {
+	cases := []struct {
+		name     string
+		geometry *Geometry
+		json     string
+	}{
+		{
+			name:     "null",
+			geometry: &Geometry{},
+			json:     `{"type":null}`,
+		},
+		{
+			name:     "multi point",
+			geometry: &Geometry{Type: "MultiPoint", MultiPoint: [][]float64{{1, 2}}},
+			json:     `{"type":"MultiPoint","coordinates":[[1,2]]}`,
+		},
+		{
+			name:     "line string",
+			geometry: &Geometry{Type: "LineString", LineString: []int{0, -2}},
+			json:     `{"type":"LineString","arcs":[0,-2]}`,
+		},
+		{
+			name:     "multi polygon",
+			geometry: &Geometry{ID: 1, Type: "MultiPolygon", MultiPolygon: [][][]int{{{0}}, {{1}}}},
+			json:     `{"id":1,"type":"MultiPolygon","arcs":[[[0]],[[1]]]}`,
+		},
+		{
+			name: "collection",
+			geometry: &Geometry{
+				Type:       "GeometryCollection",
+				Geometries: []*Geometry{{Type: "Polygon", Polygon: [][]int{{0}}, Properties: map[string]interface{}{"a": "b"}}},
+			},
+			json: `{"type":"GeometryCollection","geometries":[{"type":"Polygon","properties":{"a":"b"},"arcs":[[0]]}]}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.geometry)
+			if err != nil {
+				t.Fatalf("marshal error: %v", err)
+			}
+
+			if string(data) != tc.json {
+				t.Errorf("incorrect json: %v", string(data))
+			}
+
+			g := &Geometry{}
+			err = json.Unmarshal(data, g)
+			if err != nil {
+				t.Fatalf("unmarshal error: %v", err)
+			}
+
+			if tc.geometry.ID != nil {
+				// numbers will be float64 after unmarshalling
+				g.ID = tc.geometry.ID
+			}
+
+			if !reflect.DeepEqual(g, tc.geometry) {
+				t.Errorf("incorrect round trip: %v", g)
+			}
+		})
+	}
+}
+
+func TestGeometry_UnmarshalJSON_errors(t *testing.T) {
+	cases := []string{
+		`{"type":"Polygon"}`,
+		`{"type":"Circle","arcs":[0]}`,
+	}
+
+	for _, c := range cases {
+		g := &Geometry{}
+		err := json.Unmarshal([]byte(c), g)
+		if err != ErrInvalidGeometry {
+			t.Errorf("expected invalid geometry error for %s: %v", c, err)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package geo
+
+import (
+	"math"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/clip"
+)
+
+// SplitAntimeridian splits line strings and polygons that cross the
+// antimeridian, ±180 degrees, into parts that do not, so they render
+// correctly on flat maps. A segment crosses if its longitudes are more
+// than 180 degrees apart, so the shortest way is taken, or if it extends
+// past ±180. Line strings are returned as an orb.MultiLineString and
+// polygons as an orb.MultiPolygon, other types are returned unchanged.
+// Where a segment crosses the latitude is interpolated linearly, so long
+// segments should first be densified, see Densify. Rings around a pole
+// are closed along the pole on the side of their mean latitude.
+func SplitAntimeridian(g orb.Geometry) orb.Geometry {
+	switch g := g.(type) {
+	case orb.LineString:
+		return splitLineString(g)
+	case orb.MultiLineString:
+		result := orb.MultiLineString{}
+		for _, ls := range g {
+			result = append(result, splitLineString(ls)...)
+		}
+		return result
+	case orb.Polygon:
+		return splitPolygon(g)
+	case orb.MultiPolygon:
+		result := orb.MultiPolygon{}
+		for _, p := range g {
+			result = append(result, splitPolygon(p)...)
+		}
+		return result
+	case orb.Collection:
+		result := make(orb.Collection, 0, len(g))
+		for _, c := range g {
+			result = append(result, SplitAntimeridian(c))
+		}
+		return result
+	}
+
+	return g
+}
+
+func splitLineString(ls orb.LineString) orb.MultiLineString {
+	if len(ls) < 2 {
+		return orb.MultiLineString{ls.Clone()}
+	}
+
+	u := unwrap(ls)
+
+	var (
+		result  orb.MultiLineString
+		current orb.LineString
+		k       int
+	)
+
+	for i := 1; i < len(u); i++ {
+		a, b := u[i-1], u[i]
+
+		mid := window((a[0] + b[0]) / 2)
+		ka, kb := mid, mid
+
+		// a boundary, at an odd multiple of 180, strictly between the points
+		boundary, crosses := 0.0, false
+		if lo, hi := math.Min(a[0], b[0]), math.Max(a[0], b[0]); window(lo) != window(hi) {
+			boundary = 360*float64(window(hi)) - 180
+			if lo < boundary && boundary < hi {
+				crosses = true
+				ka, kb = window((a[0]+boundary)/2), window((boundary+b[0])/2)
+			}
+		}
+
+		if current == nil || ka != k {
+			if current != nil {
+				result = append(result, current)
+			}
+
+			current = orb.LineString{shift(a, ka)}
+			k = ka
+		}
+
+		if crosses {
+			t := (boundary - a[0]) / (b[0] - a[0])
+			lat := a[1] + t*(b[1]-a[1])
+
+			current = append(current, shift(orb.Point{boundary, lat}, ka))
+			result = append(result, current)
+
+			current = orb.LineString{shift(orb.Point{boundary, lat}, kb)}
+			k = kb
+		}
+
+		current = append(current, shift(b, kb))
+	}
+
+	return append(result, current)
+}
+
+func splitPolygon(p orb.Polygon) orb.MultiPolygon {
+	if len(p) == 0 || len(p[0]) == 0 {
+		return orb.MultiPolygon{}
+	}
+
+	outer := orb.Ring(unwrap(orb.LineString(p[0])))
+
+	// a ring around a pole ends 360 degrees from where it starts
+	first, last := outer[0], outer[len(outer)-1]
+	if math.Abs(last[0]-first[0]) > 180 {
+		mean := 0.0
+		for _, pt := range outer {
+			mean += pt[1]
+		}
+
+		pole := 90.0
+		if mean < 0 {
+			pole = -90
+		}
+
+		outer = append(outer, orb.Point{last[0], pole}, orb.Point{first[0], pole}, first)
+	}
+
+	b := outer.Bound()
+	polygon := orb.Polygon{outer}
+	for _, r := range p[1:] {
+		if len(r) == 0 {
+			continue
+		}
+
+		// move the inner rings next to the outer ring
+		inner := orb.Ring(unwrap(orb.LineString(r)))
+		offset := 360 * math.Round((b.Center()[0]-inner[0][0])/360)
+		for i := range inner {
+			inner[i][0] += offset
+		}
+
+		polygon = append(polygon, inner)
+	}
+
+	// a boundary at the east edge of the bound belongs to the west window
+	kmin := window(b.Min[0])
+	kmax := int(math.Ceil((b.Max[0] - 180) / 360))
+	if kmax < kmin {
+		kmax = kmin
+	}
+
+	if kmin == kmax {
+		for _, r := range polygon {
+			for i := range r {
+				r[i] = shift(r[i], kmin)
+			}
+		}
+
+		return orb.MultiPolygon{polygon}
+	}
+
+	result := orb.MultiPolygon{}
+	for k := kmin; k <= kmax; k++ {
+		w := orb.Bound{
+			Min: orb.Point{360*float64(k) - 180, -90},
+			Max: orb.Point{360*float64(k) + 180, 90},
+		}
+
+		part := clip.Polygon(w, polygon.Clone())
+		if len(part) == 0 || len(part[0]) < 4 {
+			continue
+		}
+
+		for _, r := range part {
+			for i := range r {
+				r[i] = shift(r[i], k)
+			}
+		}
+
+		result = append(result, part)
+	}
+
+	return result
+}
+
+// unwrap returns a copy of the points with 360 added or subtracted from
+// the longitudes so consecutive points are never more than 180 degrees apart.
+func unwrap(ls orb.LineString) orb.LineString {
+	result := ls.Clone()
+	for i := 1; i < len(result); i++ {
+		d := result[i][0] - result[i-1][0]
+		result[i][0] -= 360 * math.Round(d/360)
+	}
+
+	return result
+}
+
+// window returns the 360 degree band, centered on a multiple of 360,
+// containing the longitude.
+func window(lon float64) int {
+	return int(math.Floor((lon + 180) / 360))
+}
+
+// shift moves the point from window k into -180 to 180.
+func shift(p orb.Point, k int) orb.Point {
+	return orb.Point{p[0] - 360*float64(k), p[1]}
+}
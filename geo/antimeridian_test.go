@@ -0,0 +1,187 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/planar"
+)
+
+func TestSplitAntimeridian_lineString(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    orb.LineString
+		expected orb.MultiLineString
+	}{
+		{
+			name:     "does not cross",
+			input:    orb.LineString{{10, 10}, {20, 20}, {30, 10}},
+			expected: orb.MultiLineString{{{10, 10}, {20, 20}, {30, 10}}},
+		},
+		{
+			name:  "crosses east",
+			input: orb.LineString{{170, 10}, {-170, 20}},
+			expected: orb.MultiLineString{
+				{{170, 10}, {180, 15}},
+				{{-180, 15}, {-170, 20}},
+			},
+		},
+		{
+			name:  "crosses west and back",
+			input: orb.LineString{{-170, 0}, {170, 10}, {160, 10}, {-175, 20}},
+			expected: orb.MultiLineString{
+				{{-170, 0}, {-180, 5}},
+				{{180, 5}, {170, 10}, {160, 10}, {180, 18}},
+				{{-180, 18}, {-175, 20}},
+			},
+		},
+		{
+			name:  "extends past 180",
+			input: orb.LineString{{170, 0}, {190, 10}},
+			expected: orb.MultiLineString{
+				{{170, 0}, {180, 5}},
+				{{-180, 5}, {-170, 10}},
+			},
+		},
+		{
+			name:     "ends on the antimeridian",
+			input:    orb.LineString{{170, 0}, {180, 10}},
+			expected: orb.MultiLineString{{{170, 0}, {180, 10}}},
+		},
+		{
+			name:     "single point",
+			input:    orb.LineString{{170, 0}},
+			expected: orb.MultiLineString{{{170, 0}}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := SplitAntimeridian(tc.input).(orb.MultiLineString)
+			if !result.Equal(tc.expected) {
+				t.Errorf("incorrect result")
+				t.Logf("%v", result)
+				t.Logf("%v", tc.expected)
+			}
+		})
+	}
+}
+
+func TestSplitAntimeridian_densified(t *testing.T) {
+	// Tokyo to San Francisco
+	ls := Densify(orb.LineString{{139.6917, 35.6895}, {-122.4194, 37.7749}}, 50000)
+
+	result := SplitAntimeridian(ls).(orb.MultiLineString)
+	if len(result) != 2 {
+		t.Fatalf("should split in 2: %d", len(result))
+	}
+
+	if v := result[0][len(result[0])-1]; v[0] != 180 {
+		t.Errorf("should end at the antimeridian: %v", v)
+	}
+
+	if v := result[1][0]; v[0] != -180 {
+		t.Errorf("should start at the antimeridian: %v", v)
+	}
+
+	for _, ls := range result {
+		for i := 1; i < len(ls); i++ {
+			if math.Abs(ls[i][0]-ls[i-1][0]) > 180 {
+				t.Errorf("should not jump: %v %v", ls[i-1], ls[i])
+			}
+		}
+	}
+}
+
+func TestSplitAntimeridian_polygon(t *testing.T) {
+	cases := []struct {
+		name  string
+		input orb.Polygon
+		parts int
+		area  float64
+	}{
+		{
+			name:  "does not cross",
+			input: orb.Polygon{{{10, 10}, {20, 10}, {20, 20}, {10, 20}, {10, 10}}},
+			parts: 1,
+			area:  100,
+		},
+		{
+			name:  "crosses",
+			input: orb.Polygon{{{170, 10}, {-170, 10}, {-170, 20}, {170, 20}, {170, 10}}},
+			parts: 2,
+			area:  200,
+		},
+		{
+			name: "crosses with hole",
+			input: orb.Polygon{
+				{{170, 10}, {-170, 10}, {-170, 20}, {170, 20}, {170, 10}},
+				{{178, 12}, {178, 18}, {-178, 18}, {-178, 12}, {178, 12}},
+			},
+			parts: 2,
+			area:  200 - 24,
+		},
+		{
+			name:  "extends past 180",
+			input: orb.Polygon{{{170, 10}, {190, 10}, {190, 20}, {170, 20}, {170, 10}}},
+			parts: 2,
+			area:  200,
+		},
+		{
+			name:  "around the south pole",
+			input: orb.Polygon{{{-180, -80}, {-60, -70}, {60, -75}, {180, -80}, {-180, -80}}},
+			parts: 1,
+		},
+		{
+			name:  "around the north pole",
+			input: orb.Polygon{{{0, 80}, {-120, 80}, {120, 80}, {0, 80}}},
+			parts: 2,
+			area:  360 * 10,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := SplitAntimeridian(tc.input).(orb.MultiPolygon)
+			if len(result) != tc.parts {
+				t.Fatalf("incorrect number of parts: %d != %d: %v", len(result), tc.parts, result)
+			}
+
+			area := 0.0
+			for _, p := range result {
+				if !p[0].Closed() {
+					t.Errorf("ring should be closed: %v", p[0])
+				}
+
+				b := p.Bound()
+				if b.Min[0] < -180 || b.Max[0] > 180 {
+					t.Errorf("should be within -180 to 180: %v", b)
+				}
+
+				area += planar.Area(p)
+			}
+
+			if tc.area != 0 && math.Abs(area-tc.area) > 1e-6 {
+				t.Errorf("incorrect area: %v != %v", area, tc.area)
+			}
+		})
+	}
+}
+
+func TestSplitAntimeridian_types(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		// should not panic with any type
+		SplitAntimeridian(g)
+	}
+
+	p := orb.Point{190, 10}
+	if v := SplitAntimeridian(p); v != p {
+		t.Errorf("should not change points: %v", v)
+	}
+
+	mls := orb.MultiLineString{{{170, 0}, {-170, 0}}, {{0, 0}, {1, 1}}}
+	if v := SplitAntimeridian(mls).(orb.MultiLineString); len(v) != 3 {
+		t.Errorf("incorrect number of lines: %v", v)
+	}
+}
@@ -1,11 +1,136 @@
 package geo
 
 import (
+	"fmt"
 	"math"
+	"sort"
 
 	"github.com/dadadamarine/orb"
 )
 
+// Bound returns the bound of the geometry's points on the sphere. If the
+// geometry crosses the antimeridian the bound goes the shorter way around and
+// Min[0] will be greater than Max[0], same as NewBoundAroundPoint. Polygons
+// around a pole extend to it and across all longitudes. Like orb.Bound this is
+// the bound of the points, the edges are not taken to be great circle paths.
+func Bound(g orb.Geometry) orb.Bound {
+	bb := &boundBuilder{
+		minLat: math.Inf(1),
+		maxLat: math.Inf(-1),
+	}
+	bb.add(g)
+
+	if len(bb.lons) == 0 {
+		return orb.Bound{}
+	}
+
+	b := orb.Bound{
+		Min: orb.Point{-180, bb.minLat},
+		Max: orb.Point{180, bb.maxLat},
+	}
+
+	if bb.north {
+		b.Max[1] = 90
+	}
+
+	if bb.south {
+		b.Min[1] = -90
+	}
+
+	if bb.north || bb.south {
+		return b
+	}
+
+	// the bound is everything but the largest gap between longitudes
+	lons := bb.lons
+	sort.Float64s(lons)
+
+	gap := lons[0] + 360 - lons[len(lons)-1]
+	b.Min[0], b.Max[0] = lons[0], lons[len(lons)-1]
+	for i := 1; i < len(lons); i++ {
+		if d := lons[i] - lons[i-1]; d > gap {
+			gap = d
+			b.Min[0], b.Max[0] = lons[i], lons[i-1]
+		}
+	}
+
+	return b
+}
+
+type boundBuilder struct {
+	lons           []float64
+	minLat, maxLat float64
+
+	// north and south are true if a polygon contains the pole.
+	north, south bool
+}
+
+func (bb *boundBuilder) add(g orb.Geometry) {
+	if g == nil {
+		return
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		bb.addPoint(g)
+	case orb.MultiPoint:
+		for _, p := range g {
+			bb.addPoint(p)
+		}
+	case orb.LineString:
+		for _, p := range g {
+			bb.addPoint(p)
+		}
+	case orb.MultiLineString:
+		for _, ls := range g {
+			bb.add(ls)
+		}
+	case orb.Ring:
+		bb.addPolygon(orb.Polygon{g})
+	case orb.Polygon:
+		bb.addPolygon(g)
+	case orb.MultiPolygon:
+		for _, p := range g {
+			bb.addPolygon(p)
+		}
+	case orb.Collection:
+		for _, g := range g {
+			bb.add(g)
+		}
+	case orb.Bound:
+		bb.addPoint(g.Min)
+		bb.addPoint(g.Max)
+	default:
+		panic(fmt.Sprintf("geometry type not supported: %T", g))
+	}
+}
+
+func (bb *boundBuilder) addPoint(p orb.Point) {
+	lon := p[0]
+	if lon < -180 || lon > 180 {
+		lon -= 360 * math.Floor((lon+180)/360)
+	}
+
+	bb.lons = append(bb.lons, lon)
+	bb.minLat = math.Min(bb.minLat, p[1])
+	bb.maxLat = math.Max(bb.maxLat, p[1])
+}
+
+func (bb *boundBuilder) addPolygon(p orb.Polygon) {
+	if len(p) == 0 {
+		return
+	}
+
+	for _, r := range p {
+		for _, pt := range r {
+			bb.addPoint(pt)
+		}
+	}
+
+	bb.north = bb.north || PolygonContains(p, orb.Point{0, 90})
+	bb.south = bb.south || PolygonContains(p, orb.Point{0, -90})
+}
+
 // NewBoundAroundPoint creates a new bound given a center point,
 // and a distance from the center point in meters.
 func NewBoundAroundPoint(center orb.Point, distance float64) orb.Bound {
@@ -85,3 +85,60 @@ func TestBoundPad(t *testing.T) {
 		t.Errorf("should be extend bound around fill earth: %v", b2)
 	}
 }
+
+func TestBound(t *testing.T) {
+	cases := []struct {
+		name     string
+		geom     orb.Geometry
+		expected orb.Bound
+	}{
+		{
+			name:     "line string",
+			geom:     orb.LineString{{10, 10}, {20, 5}, {30, 20}},
+			expected: orb.Bound{Min: orb.Point{10, 5}, Max: orb.Point{30, 20}},
+		},
+		{
+			name:     "across the antimeridian",
+			geom:     orb.LineString{{170, 10}, {-170, 20}, {175, 5}},
+			expected: orb.Bound{Min: orb.Point{170, 5}, Max: orb.Point{-170, 20}},
+		},
+		{
+			name: "polygon across the antimeridian",
+			geom: orb.Polygon{
+				{{170, -10}, {-170, -10}, {-170, 10}, {170, 10}, {170, -10}},
+			},
+			expected: orb.Bound{Min: orb.Point{170, -10}, Max: orb.Point{-170, 10}},
+		},
+		{
+			name:     "past 180",
+			geom:     orb.MultiPoint{{170, 0}, {190, 1}},
+			expected: orb.Bound{Min: orb.Point{170, 0}, Max: orb.Point{-170, 1}},
+		},
+		{
+			name:     "around the north pole",
+			geom:     orb.Polygon{{{0, 80}, {120, 80}, {-120, 85}, {0, 80}}},
+			expected: orb.Bound{Min: orb.Point{-180, 80}, Max: orb.Point{180, 90}},
+		},
+		{
+			name: "collection",
+			geom: orb.Collection{
+				orb.Point{-175, -5},
+				orb.LineString{{160, 0}, {170, 10}},
+			},
+			expected: orb.Bound{Min: orb.Point{160, -5}, Max: orb.Point{-175, 10}},
+		},
+		{
+			name:     "empty",
+			geom:     orb.MultiPoint{},
+			expected: orb.Bound{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if b := Bound(tc.geom); !b.Equal(tc.expected) {
+				t.Errorf("incorrect bound: %v != %v", b, tc.expected)
+			}
+		})
+	}
+}
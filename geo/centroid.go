@@ -0,0 +1,126 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/dadadamarine/orb"
+)
+
+// Centroid returns the centroid of the geometry on the sphere. Polygons are
+// weighted by area, lines by length and points equally. Only the highest
+// dimension present in a collection is used, e.g. points are ignored if there
+// are polygons. As with RingContains the edges are great circle paths and
+// rings enclose the smaller side, so the result is correct for geometry
+// across the antimeridian or around a pole. If the centroid is undefined,
+// for example for two antipodal points, the zero point is returned.
+func Centroid(g orb.Geometry) orb.Point {
+	c := &centroid{}
+	c.add(g)
+
+	for _, sum := range []vector{c.area, c.length, c.points} {
+		l := math.Sqrt(sum.dot(sum))
+		if l > 1e-15 {
+			return fromVector(sum)
+		}
+	}
+
+	return orb.Point{}
+}
+
+// centroid sums the unit vectors by dimension, weighted by
+// area, length or count.
+type centroid struct {
+	points, length, area vector
+}
+
+func (c *centroid) add(g orb.Geometry) {
+	if g == nil {
+		return
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		c.addPoint(g)
+	case orb.MultiPoint:
+		for _, p := range g {
+			c.addPoint(p)
+		}
+	case orb.LineString:
+		c.addLineString(g)
+	case orb.MultiLineString:
+		for _, ls := range g {
+			c.addLineString(ls)
+		}
+	case orb.Ring:
+		c.addPolygon(orb.Polygon{g})
+	case orb.Polygon:
+		c.addPolygon(g)
+	case orb.MultiPolygon:
+		for _, p := range g {
+			c.addPolygon(p)
+		}
+	case orb.Collection:
+		for _, g := range g {
+			c.add(g)
+		}
+	case orb.Bound:
+		c.addPolygon(orb.Polygon{g.ToRing()})
+	default:
+		panic(fmt.Sprintf("geometry type not supported: %T", g))
+	}
+}
+
+func (c *centroid) addPoint(p orb.Point) {
+	c.points = c.points.add(toVector(p), 1)
+}
+
+func (c *centroid) addLineString(ls orb.LineString) {
+	var sum vector
+	for i := 1; i < len(ls); i++ {
+		a, b := toVector(ls[i-1]), toVector(ls[i])
+
+		// the integral of the position along the path is
+		// the sum of the end points times tan(length/2)
+		n := a.cross(b)
+		s := math.Tan(math.Atan2(math.Sqrt(n.dot(n)), a.dot(b)) / 2)
+		if s > 1e15 {
+			// antipodal points, the path is undefined
+			continue
+		}
+
+		sum = sum.add(a, s).add(b, s)
+	}
+
+	// a line of zero length is a point
+	if sum == (vector{}) {
+		if len(ls) > 0 {
+			c.addPoint(ls[0])
+		}
+
+		return
+	}
+
+	c.length = c.length.add(sum, 1)
+}
+
+func (c *centroid) addPolygon(p orb.Polygon) {
+	for i, r := range p {
+		sr := newSphericalRing(r)
+		if sr == nil {
+			if i == 0 {
+				// a polygon without area is its outline
+				c.addLineString(orb.LineString(r))
+				return
+			}
+
+			continue
+		}
+
+		if i == 0 {
+			c.area = c.area.add(sr.centroid(), 1)
+		} else {
+			c.area = c.area.add(sr.centroid(), -1)
+		}
+	}
+}
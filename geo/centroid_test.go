@@ -0,0 +1,128 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/planar"
+)
+
+func TestCentroid(t *testing.T) {
+	cases := []struct {
+		name     string
+		geom     orb.Geometry
+		expected orb.Point
+	}{
+		{
+			name:     "point",
+			geom:     orb.Point{10, 20},
+			expected: orb.Point{10, 20},
+		},
+		{
+			name:     "multi point across the antimeridian",
+			geom:     orb.MultiPoint{{170, 0}, {-170, 0}},
+			expected: orb.Point{180, 0},
+		},
+		{
+			name:     "line string",
+			geom:     orb.LineString{{0, 0}, {10, 0}, {30, 0}},
+			expected: orb.Point{15, 0},
+		},
+		{
+			name:     "line string of one point",
+			geom:     orb.LineString{{5, 5}, {5, 5}},
+			expected: orb.Point{5, 5},
+		},
+		{
+			name:     "polygon across the antimeridian",
+			geom:     orb.Polygon{{{170, -10}, {-170, -10}, {-170, 10}, {170, 10}, {170, -10}}},
+			expected: orb.Point{180, 0},
+		},
+		{
+			name:     "clockwise polygon",
+			geom:     orb.Polygon{{{-10, -10}, {-10, 10}, {10, 10}, {10, -10}, {-10, -10}}},
+			expected: orb.Point{0, 0},
+		},
+		{
+			name:     "around the north pole",
+			geom:     orb.Polygon{{{0, 80}, {120, 80}, {-120, 80}, {0, 80}}},
+			expected: orb.Point{0, 90},
+		},
+		{
+			name: "polygon with hole",
+			geom: orb.Polygon{
+				{{-10, -10}, {10, -10}, {10, 10}, {-10, 10}, {-10, -10}},
+				{{-2, -2}, {2, -2}, {2, 2}, {-2, 2}, {-2, -2}},
+			},
+			expected: orb.Point{0, 0},
+		},
+		{
+			name: "polygons over points",
+			geom: orb.Collection{
+				orb.Point{100, 50},
+				orb.Polygon{{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}, {-1, -1}}},
+			},
+			expected: orb.Point{0, 0},
+		},
+		{
+			name:     "antipodal points",
+			geom:     orb.MultiPoint{{0, 0}, {180, 0}},
+			expected: orb.Point{0, 0},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := Centroid(tc.geom)
+
+			// longitudes at the poles and antimeridian are arbitrary
+			if math.Abs(c[1]-tc.expected[1]) > epsilon {
+				t.Errorf("incorrect centroid: %v != %v", c, tc.expected)
+			}
+
+			if math.Abs(c[1]) != 90 && math.Abs(math.Mod(c[0]-tc.expected[0]+540, 360)-180) > epsilon {
+				t.Errorf("incorrect centroid: %v != %v", c, tc.expected)
+			}
+		})
+	}
+}
+
+func TestCentroid_hole(t *testing.T) {
+	poly := orb.Polygon{
+		{{-10, -10}, {10, -10}, {10, 10}, {-10, 10}, {-10, -10}},
+		{{0, -5}, {5, -5}, {5, 5}, {0, 5}, {0, -5}},
+	}
+
+	// the hole moves the centroid west, about the same as planar
+	expected, _ := planar.CentroidArea(poly)
+	if c := Centroid(poly); math.Abs(c[0]-expected[0]) > 0.01 || math.Abs(c[1]) > epsilon {
+		t.Errorf("incorrect centroid: %v", c)
+	}
+}
+
+func TestCentroid_small(t *testing.T) {
+	// close to planar for small polygons
+	poly := orb.Polygon{
+		{
+			{-122.4163816, 37.7792782},
+			{-122.4162786, 37.7787626},
+			{-122.4151027, 37.7789118},
+			{-122.4152143, 37.7794274},
+			{-122.4163816, 37.7792782},
+		},
+	}
+
+	c := Centroid(poly)
+	expected, _ := planar.CentroidArea(poly)
+	if math.Abs(c[0]-expected[0]) > 1e-5 || math.Abs(c[1]-expected[1]) > 1e-5 {
+		t.Errorf("incorrect centroid: %v != %v", c, expected)
+	}
+}
+
+func TestCentroid_types(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		// should not panic with any supported type
+		Centroid(g)
+	}
+}
@@ -0,0 +1,208 @@
+package geo
+
+import (
+	"math"
+
+	"github.com/dadadamarine/orb"
+)
+
+// RingContains returns true if the point is inside the ring on the sphere.
+// The edges are great circle paths and the inside is the smaller of the two
+// areas the ring divides the sphere into, the winding order is ignored.
+// Unlike planar.RingContains this works for rings across the antimeridian
+// or around a pole. Points on the boundary are considered in.
+func RingContains(r orb.Ring, point orb.Point) bool {
+	sr := newSphericalRing(r)
+	if sr == nil {
+		return false
+	}
+
+	return sr.contains(toVector(point))
+}
+
+// PolygonContains checks if the point is within the polygon on the sphere.
+// Points on the boundary are considered in.
+func PolygonContains(p orb.Polygon, point orb.Point) bool {
+	if len(p) == 0 || !RingContains(p[0], point) {
+		return false
+	}
+
+	v := toVector(point)
+	for i := 1; i < len(p); i++ {
+		sr := newSphericalRing(p[i])
+		if sr != nil && sr.contains(v) && !sr.onBoundary(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MultiPolygonContains checks if the point is within the multi-polygon
+// on the sphere. Points on the boundary are considered in.
+func MultiPolygonContains(mp orb.MultiPolygon, point orb.Point) bool {
+	for _, p := range mp {
+		if PolygonContains(p, point) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sphericalRing is a ring of unit vectors, without the closing point,
+// wound so the enclosed area is on the left.
+type sphericalRing struct {
+	points []vector
+}
+
+// newSphericalRing returns nil if the ring has less than 3 distinct points.
+func newSphericalRing(r orb.Ring) *sphericalRing {
+	points := make([]vector, 0, len(r))
+	for i, p := range r {
+		if i > 0 && p == r[i-1] {
+			continue
+		}
+
+		points = append(points, toVector(p))
+	}
+
+	if len(points) > 1 && r[0] == r[len(r)-1] {
+		points = points[:len(points)-1]
+	}
+
+	if len(points) < 3 {
+		return nil
+	}
+
+	// the area on the left, as a fan of triangles from the first point
+	area := 0.0
+	for i := 1; i < len(points)-1; i++ {
+		area += triangleArea(points[0], points[i], points[i+1])
+	}
+
+	area = math.Mod(area, 4*math.Pi)
+	if area < 0 {
+		area += 4 * math.Pi
+	}
+
+	if area > 2*math.Pi {
+		for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+			points[i], points[j] = points[j], points[i]
+		}
+
+	}
+
+	return &sphericalRing{points: points}
+}
+
+// triangleArea returns the signed area of the spherical triangle,
+// positive if counter clockwise.
+func triangleArea(a, b, c vector) float64 {
+	return 2 * math.Atan2(a.dot(b.cross(c)), 1+a.dot(b)+b.dot(c)+c.dot(a))
+}
+
+// edge returns the points of the ith edge.
+func (r *sphericalRing) edge(i int) (vector, vector) {
+	return r.points[i], r.points[(i+1)%len(r.points)]
+}
+
+// centroid returns the integral of the position over the enclosed area,
+// half the sum of the edge lengths times their normals. It points to the
+// centroid of the area.
+func (r *sphericalRing) centroid() vector {
+	p0 := r.points[0]
+
+	var sum vector
+	for i := range r.points {
+		a, b := r.edge(i)
+
+		// the area of the flat polygon through the points, relative to the
+		// first point so small polygons do not lose precision
+		sum = sum.add(a.add(p0, -1).cross(b.add(p0, -1)), 0.5)
+
+		// plus the difference for the curve of the edges
+		n := a.cross(b)
+		l := math.Sqrt(n.dot(n))
+		if l == 0 {
+			continue
+		}
+
+		sum = sum.add(n, (math.Atan2(l, a.dot(b))/l-1)/2)
+	}
+
+	return sum
+}
+
+func (r *sphericalRing) onBoundary(p vector) bool {
+	for i := range r.points {
+		a, b := r.edge(i)
+
+		n := a.cross(b)
+		if math.Abs(n.dot(p)) <= 1e-12*math.Sqrt(n.dot(n)) && onArc(a, b, n, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// contains counts the edges crossed by the great circle path from
+// a point just inside the first edge to the point.
+func (r *sphericalRing) contains(p vector) bool {
+	if r.onBoundary(p) {
+		return true
+	}
+
+	var o vector
+	for i := range r.points {
+		a, b := r.edge(i)
+
+		n := a.cross(b)
+		l := math.Sqrt(n.dot(n))
+		if l == 0 {
+			continue
+		}
+
+		// the middle of the edge, moved a little to the left
+		m := a.add(b, 1)
+		o = vector{}.add(m, 1/math.Sqrt(m.dot(m))).add(n, 1e-9/l)
+		break
+	}
+
+	inside := true
+	for i := range r.points {
+		a, b := r.edge(i)
+		if crosses(a, b, o, p) {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// crosses returns true if the great circle path a-b crosses c-d.
+// A vertex on c-d is taken to be on its left so a path through it
+// is counted once.
+func crosses(a, b, c, d vector) bool {
+	ab := a.cross(b)
+	acb := -ab.dot(c)
+	bda := ab.dot(d)
+	if acb*bda <= 0 {
+		return false
+	}
+
+	cd := c.cross(d)
+	cbd := -halfOpenSign(cd.dot(b))
+	dac := halfOpenSign(cd.dot(a))
+
+	return acb*cbd > 0 && acb*dac > 0
+}
+
+func halfOpenSign(v float64) float64 {
+	if v < 0 {
+		return -1
+	}
+
+	return 1
+}
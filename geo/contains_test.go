@@ -0,0 +1,154 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestRingContains(t *testing.T) {
+	cases := []struct {
+		name   string
+		ring   orb.Ring
+		point  orb.Point
+		result bool
+	}{
+		{
+			name:   "inside",
+			ring:   orb.Ring{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+			point:  orb.Point{5, 5},
+			result: true,
+		},
+		{
+			name:   "outside",
+			ring:   orb.Ring{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+			point:  orb.Point{15, 5},
+			result: false,
+		},
+		{
+			name:   "clockwise",
+			ring:   orb.Ring{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}},
+			point:  orb.Point{5, 5},
+			result: true,
+		},
+		{
+			name:   "not closed",
+			ring:   orb.Ring{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+			point:  orb.Point{5, 5},
+			result: true,
+		},
+		{
+			name:   "on a vertex",
+			ring:   orb.Ring{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+			point:  orb.Point{10, 10},
+			result: true,
+		},
+		{
+			name:   "on an edge",
+			ring:   orb.Ring{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+			point:  orb.Point{10, 5},
+			result: true,
+		},
+		{
+			name:   "in line with a vertex",
+			ring:   orb.Ring{{0, 0}, {10, 5}, {0, 10}, {5, 5}, {0, 0}},
+			point:  orb.Point{7, 5},
+			result: true,
+		},
+		{
+			name:   "in line with a vertex outside",
+			ring:   orb.Ring{{0, 0}, {10, 5}, {0, 10}, {5, 5}, {0, 0}},
+			point:  orb.Point{3, 5},
+			result: false,
+		},
+		{
+			name:   "across the antimeridian",
+			ring:   orb.Ring{{170, -10}, {-170, -10}, {-170, 10}, {170, 10}, {170, -10}},
+			point:  orb.Point{179, 0},
+			result: true,
+		},
+		{
+			name:   "across the antimeridian other side",
+			ring:   orb.Ring{{170, -10}, {-170, -10}, {-170, 10}, {170, 10}, {170, -10}},
+			point:  orb.Point{-175, 5},
+			result: true,
+		},
+		{
+			name:   "across the antimeridian outside",
+			ring:   orb.Ring{{170, -10}, {-170, -10}, {-170, 10}, {170, 10}, {170, -10}},
+			point:  orb.Point{0, 0},
+			result: false,
+		},
+		{
+			name:   "around the north pole",
+			ring:   orb.Ring{{0, 80}, {120, 80}, {-120, 80}, {0, 80}},
+			point:  orb.Point{45, 89},
+			result: true,
+		},
+		{
+			name:   "around the north pole outside",
+			ring:   orb.Ring{{0, 80}, {120, 80}, {-120, 80}, {0, 80}},
+			point:  orb.Point{45, 60},
+			result: false,
+		},
+		{
+			name:   "degenerate",
+			ring:   orb.Ring{{0, 0}, {10, 0}, {0, 0}},
+			point:  orb.Point{5, 0},
+			result: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if v := RingContains(tc.ring, tc.point); v != tc.result {
+				t.Errorf("incorrect result: %v != %v", v, tc.result)
+			}
+		})
+	}
+}
+
+func TestPolygonContains(t *testing.T) {
+	p := orb.Polygon{
+		{{170, -10}, {-170, -10}, {-170, 10}, {170, 10}, {170, -10}},
+		{{178, -2}, {178, 2}, {-178, 2}, {-178, -2}, {178, -2}},
+	}
+
+	cases := []struct {
+		name   string
+		point  orb.Point
+		result bool
+	}{
+		{name: "in outer ring", point: orb.Point{175, 0}, result: true},
+		{name: "in the hole", point: orb.Point{180, 0}, result: false},
+		{name: "on the hole", point: orb.Point{178, 0}, result: true},
+		{name: "outside", point: orb.Point{160, 0}, result: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if v := PolygonContains(p, tc.point); v != tc.result {
+				t.Errorf("incorrect result: %v != %v", v, tc.result)
+			}
+		})
+	}
+
+	if PolygonContains(orb.Polygon{}, orb.Point{}) {
+		t.Errorf("empty polygon should not contain")
+	}
+}
+
+func TestMultiPolygonContains(t *testing.T) {
+	mp := orb.MultiPolygon{
+		{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+		{{{179, 0}, {-179, 0}, {-179, 1}, {179, 1}, {179, 0}}},
+	}
+
+	if !MultiPolygonContains(mp, orb.Point{180, 0.5}) {
+		t.Errorf("should contain point")
+	}
+
+	if MultiPolygonContains(mp, orb.Point{2, 0.5}) {
+		t.Errorf("should not contain point")
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"fmt"
 
 	"github.com/dadadamarine/orb"
-	"github.com/paulmach/orb/geo"
+	"github.com/dadadamarine/orb/geo"
 )
 
 func ExampleArea() {
@@ -0,0 +1,193 @@
+package geo
+
+import (
+	"math"
+
+	"github.com/dadadamarine/orb"
+)
+
+// Interpolate returns the point at the fraction, from 0 to 1, of the way
+// along the great circle path between the two points. If the points are the
+// same, or within a few millimeters, p1 is returned. If the points are antipodal
+// the great circle path is not unique, the path heading north from p1 along
+// its meridian, through the north pole, is used.
+func Interpolate(p1, p2 orb.Point, fraction float64) orb.Point {
+	// below this the angular distance is too small for a stable result,
+	// about 6mm on the surface of the earth.
+	const epsilon = 1e-9
+
+	if fraction == 1 {
+		return p2
+	}
+
+	if fraction == 0 {
+		return p1
+	}
+
+	delta := DistanceHaversine(p1, p2) / orb.EarthRadius
+	if delta < epsilon {
+		return p1
+	}
+
+	v1 := toVector(p1)
+	if math.Pi-delta < epsilon {
+		// unit vector pointing north from p1, or towards the
+		// opposite meridian if p1 is the north pole.
+		lon, lat := deg2rad(p1[0]), deg2rad(p1[1])
+		north := vector{
+			-math.Sin(lat) * math.Cos(lon),
+			-math.Sin(lat) * math.Sin(lon),
+			math.Cos(lat),
+		}
+
+		angle := fraction * math.Pi
+		return fromVector(vector{
+			math.Cos(angle)*v1[0] + math.Sin(angle)*north[0],
+			math.Cos(angle)*v1[1] + math.Sin(angle)*north[1],
+			math.Cos(angle)*v1[2] + math.Sin(angle)*north[2],
+		})
+	}
+
+	a := math.Sin((1-fraction)*delta) / math.Sin(delta)
+	b := math.Sin(fraction*delta) / math.Sin(delta)
+
+	v2 := toVector(p2)
+	return fromVector(vector{
+		a*v1[0] + b*v2[0],
+		a*v1[1] + b*v2[1],
+		a*v1[2] + b*v2[2],
+	})
+}
+
+// Densify returns a new line string with points added along the great
+// circle paths between the points so no segment is longer than the max
+// distance in meters. The result is suitable for drawing flight paths
+// but may cross the antimeridian, see SplitAntimeridian. Segments between
+// antipodal points go north through the pole, see Interpolate.
+func Densify(ls orb.LineString, maxDistance float64) orb.LineString {
+	if len(ls) == 0 {
+		return orb.LineString{}
+	}
+
+	result := make(orb.LineString, 0, len(ls))
+	result = append(result, ls[0])
+	for i := 1; i < len(ls); i++ {
+		d := DistanceHaversine(ls[i-1], ls[i])
+
+		n := 1
+		if maxDistance > 0 {
+			n = int(math.Ceil(d / maxDistance))
+		}
+
+		for j := 1; j < n; j++ {
+			result = append(result, Interpolate(ls[i-1], ls[i], float64(j)/float64(n)))
+		}
+
+		result = append(result, ls[i])
+	}
+
+	return result
+}
+
+// Intersection returns the point where the great circle segments a1-a2 and
+// b1-b2 cross. The bool is false if the segments do not cross or are on the
+// same great circle.
+func Intersection(a1, a2, b1, b2 orb.Point) (orb.Point, bool) {
+	va1, va2 := toVector(a1), toVector(a2)
+	vb1, vb2 := toVector(b1), toVector(b2)
+
+	// normals of the great circles
+	na, nb := va1.cross(va2), vb1.cross(vb2)
+
+	i := na.cross(nb)
+	l := math.Sqrt(i.dot(i))
+	if l < 1e-15 {
+		return orb.Point{}, false
+	}
+
+	// the great circles cross at two antipodal points
+	for _, c := range []vector{i, {-i[0], -i[1], -i[2]}} {
+		if onArc(va1, va2, na, c) && onArc(vb1, vb2, nb, c) {
+			return fromVector(c), true
+		}
+	}
+
+	return orb.Point{}, false
+}
+
+// onArc returns true if the point is on the minor arc from a to b,
+// n is the normal of the arc's great circle.
+func onArc(a, b, n, p vector) bool {
+	const epsilon = 1e-12
+
+	l := math.Sqrt(n.dot(n))
+	return a.cross(p).dot(n) >= -epsilon*l && p.cross(b).dot(n) >= -epsilon*l
+}
+
+// CrossTrackDistance returns the distance in meters from the point to the
+// great circle path through start and end. It is negative if the point is
+// to the left of the path and positive to the right.
+func CrossTrackDistance(start, end, p orb.Point) float64 {
+	d13 := DistanceHaversine(start, p) / orb.EarthRadius
+	b13 := deg2rad(Bearing(start, p))
+	b12 := deg2rad(Bearing(start, end))
+
+	return math.Asin(math.Sin(d13)*math.Sin(b13-b12)) * orb.EarthRadius
+}
+
+// AlongTrackDistance returns the distance in meters from start to the
+// point on the great circle path through start and end closest to the point.
+// It is negative if the closest point is behind the start.
+func AlongTrackDistance(start, end, p orb.Point) float64 {
+	d13 := DistanceHaversine(start, p) / orb.EarthRadius
+	b13 := deg2rad(Bearing(start, p))
+	b12 := deg2rad(Bearing(start, end))
+
+	dxt := math.Asin(math.Sin(d13) * math.Sin(b13-b12))
+
+	c := math.Cos(d13) / math.Cos(dxt)
+	c = math.Max(-1, math.Min(1, c))
+
+	d := math.Acos(c) * orb.EarthRadius
+	if math.Cos(b12-b13) < 0 {
+		return -d
+	}
+
+	return d
+}
+
+// vector is a point on the unit sphere.
+type vector [3]float64
+
+func toVector(p orb.Point) vector {
+	lon, lat := deg2rad(p[0]), deg2rad(p[1])
+	return vector{
+		math.Cos(lat) * math.Cos(lon),
+		math.Cos(lat) * math.Sin(lon),
+		math.Sin(lat),
+	}
+}
+
+func fromVector(v vector) orb.Point {
+	return orb.Point{
+		rad2deg(math.Atan2(v[1], v[0])),
+		rad2deg(math.Atan2(v[2], math.Sqrt(v[0]*v[0]+v[1]*v[1]))),
+	}
+}
+
+func (v vector) cross(w vector) vector {
+	return vector{
+		v[1]*w[2] - v[2]*w[1],
+		v[2]*w[0] - v[0]*w[2],
+		v[0]*w[1] - v[1]*w[0],
+	}
+}
+
+// add returns v plus s times w.
+func (v vector) add(w vector, s float64) vector {
+	return vector{v[0] + s*w[0], v[1] + s*w[1], v[2] + s*w[2]}
+}
+
+func (v vector) dot(w vector) float64 {
+	return v[0]*w[0] + v[1]*w[1] + v[2]*w[2]
+}
@@ -0,0 +1,225 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+// the examples from movable-type.co.uk use a 6371 km earth radius
+const veness = orb.EarthRadius / 6371e3
+
+func TestInterpolate(t *testing.T) {
+	p1 := orb.Point{-1.8444, 53.1506}
+	p2 := orb.Point{0.1406, 52.2047}
+
+	if p := Interpolate(p1, p2, 0); !p.Equal(p1) {
+		t.Errorf("incorrect start: %v", p)
+	}
+
+	if p := Interpolate(p1, p2, 1); math.Abs(p[0]-p2[0]) > epsilon || math.Abs(p[1]-p2[1]) > epsilon {
+		t.Errorf("incorrect end: %v", p)
+	}
+
+	mid := Midpoint(p1, p2)
+	if p := Interpolate(p1, p2, 0.5); math.Abs(p[0]-mid[0]) > epsilon || math.Abs(p[1]-mid[1]) > epsilon {
+		t.Errorf("incorrect midpoint: %v != %v", p, mid)
+	}
+
+	if p := Interpolate(p1, p1, 0.5); !p.Equal(p1) {
+		t.Errorf("same points should be the point: %v", p)
+	}
+
+	// distance should be proportional to the fraction
+	d := DistanceHaversine(p1, p2)
+	if v := DistanceHaversine(p1, Interpolate(p1, p2, 0.25)); math.Abs(v-d/4) > 1e-3 {
+		t.Errorf("incorrect distance: %v != %v", v, d/4)
+	}
+}
+
+func TestInterpolate_degenerate(t *testing.T) {
+	cases := []struct {
+		name     string
+		p1, p2   orb.Point
+		fraction float64
+		expected orb.Point
+	}{
+		{
+			name:     "almost same points",
+			p1:       orb.Point{10, 20},
+			p2:       orb.Point{10 + 1e-12, 20},
+			fraction: 0.5,
+			expected: orb.Point{10, 20},
+		},
+		{
+			name:     "antipodal midpoint is the north pole",
+			p1:       orb.Point{10, 0},
+			p2:       orb.Point{-170, 0},
+			fraction: 0.5,
+			expected: orb.Point{0, 90},
+		},
+		{
+			name:     "antipodal goes north along the meridian",
+			p1:       orb.Point{10, -20},
+			p2:       orb.Point{-170, 20},
+			fraction: 0.25,
+			expected: orb.Point{10, 25},
+		},
+		{
+			name:     "antipodal past the pole",
+			p1:       orb.Point{10, 20},
+			p2:       orb.Point{-170, -20},
+			fraction: 0.75,
+			expected: orb.Point{-170, 25},
+		},
+		{
+			name:     "antipodal from the north pole",
+			p1:       orb.Point{0, 90},
+			p2:       orb.Point{0, -90},
+			fraction: 0.5,
+			expected: orb.Point{180, 0},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := Interpolate(tc.p1, tc.p2, tc.fraction)
+			if math.IsNaN(p[0]) || math.IsNaN(p[1]) {
+				t.Fatalf("should not be NaN: %v", p)
+			}
+
+			if d := DistanceHaversine(p, tc.expected); d > 1e-3 {
+				t.Errorf("incorrect point: %v != %v", p, tc.expected)
+			}
+		})
+	}
+}
+
+func TestDensify(t *testing.T) {
+	// San Francisco to Tokyo
+	ls := orb.LineString{{-122.4194, 37.7749}, {139.6917, 35.6895}}
+
+	result := Densify(ls, 100000)
+	if len(result) < 80 {
+		t.Fatalf("should add points: %d", len(result))
+	}
+
+	if !result[0].Equal(ls[0]) || !result[len(result)-1].Equal(ls[1]) {
+		t.Errorf("should keep the end points")
+	}
+
+	for i := 1; i < len(result); i++ {
+		if d := DistanceHaversine(result[i-1], result[i]); d > 100000+1e-6 {
+			t.Errorf("segment %d too long: %v", i, d)
+		}
+	}
+
+	// goes north over the Pacific
+	if result[len(result)/2][1] < 45 {
+		t.Errorf("should follow the great circle: %v", result[len(result)/2])
+	}
+
+	if v := Densify(nil, 1000); len(v) != 0 {
+		t.Errorf("should be empty: %v", v)
+	}
+}
+
+func TestDensify_antipodal(t *testing.T) {
+	ls := orb.LineString{{10, 20}, {-170, -20}}
+
+	result := Densify(ls, 1000000)
+	for i, p := range result {
+		if math.IsNaN(p[0]) || math.IsNaN(p[1]) {
+			t.Fatalf("point %d is NaN: %v", i, p)
+		}
+	}
+
+	for i := 1; i < len(result); i++ {
+		if d := DistanceHaversine(result[i-1], result[i]); d > 1000000+1e-6 {
+			t.Errorf("segment %d too long: %v", i, d)
+		}
+	}
+}
+
+func TestIntersection(t *testing.T) {
+	p1 := orb.Point{0.2545, 51.8853}
+	p2 := orb.Point{2.5735, 49.0034}
+
+	a := PointAtBearingAndDistance(p1, 108.547, 500000)
+	b := PointAtBearingAndDistance(p2, 32.435, 500000)
+
+	p, ok := Intersection(p1, a, p2, b)
+	if !ok {
+		t.Fatalf("should intersect")
+	}
+
+	expected := orb.Point{4.5084, 50.9078}
+	if math.Abs(p[0]-expected[0]) > 1e-3 || math.Abs(p[1]-expected[1]) > 1e-3 {
+		t.Errorf("incorrect intersection: %v != %v", p, expected)
+	}
+
+	// the segments are too short to cross
+	a = PointAtBearingAndDistance(p1, 108.547, 100000)
+	if _, ok := Intersection(p1, a, p2, b); ok {
+		t.Errorf("should not intersect")
+	}
+
+	// same great circle
+	if _, ok := Intersection(orb.Point{0, 0}, orb.Point{10, 0}, orb.Point{5, 0}, orb.Point{20, 0}); ok {
+		t.Errorf("should not intersect")
+	}
+
+	// across the antimeridian
+	p, ok = Intersection(orb.Point{170, 10}, orb.Point{-170, -10}, orb.Point{170, -10}, orb.Point{-170, 10})
+	if !ok {
+		t.Fatalf("should intersect")
+	}
+
+	if math.Abs(math.Abs(p[0])-180) > epsilon || math.Abs(p[1]) > epsilon {
+		t.Errorf("incorrect intersection: %v", p)
+	}
+}
+
+func TestCrossTrackDistance(t *testing.T) {
+	start := orb.Point{-1.7297, 53.3206}
+	end := orb.Point{0.1334, 53.1887}
+	p := orb.Point{-0.7972, 53.2611}
+
+	if d := CrossTrackDistance(start, end, p); math.Abs(d-(-307.5*veness)) > 0.1 {
+		t.Errorf("incorrect distance: %v", d)
+	}
+
+	// to the right of the path
+	p = orb.Point{-0.7972, 53.2}
+	if d := CrossTrackDistance(start, end, p); d <= 0 {
+		t.Errorf("should be positive: %v", d)
+	}
+
+	// on the path
+	if d := CrossTrackDistance(start, end, Interpolate(start, end, 0.3)); math.Abs(d) > 1e-6 {
+		t.Errorf("should be zero: %v", d)
+	}
+}
+
+func TestAlongTrackDistance(t *testing.T) {
+	start := orb.Point{-1.7297, 53.3206}
+	end := orb.Point{0.1334, 53.1887}
+	p := orb.Point{-0.7972, 53.2611}
+
+	if d := AlongTrackDistance(start, end, p); math.Abs(d-62331*veness) > 1 {
+		t.Errorf("incorrect distance: %v", d)
+	}
+
+	// on the path
+	mid := Interpolate(start, end, 0.3)
+	expected := DistanceHaversine(start, mid)
+	if d := AlongTrackDistance(start, end, mid); math.Abs(d-expected) > 1e-3 {
+		t.Errorf("incorrect distance: %v != %v", d, expected)
+	}
+
+	// behind the start
+	if d := AlongTrackDistance(start, end, orb.Point{-2.5, 53.4}); d >= 0 {
+		t.Errorf("should be negative: %v", d)
+	}
+}
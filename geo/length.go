@@ -2,7 +2,7 @@ package geo
 
 import (
 	"github.com/dadadamarine/orb"
-	"github.com/paulmach/orb/internal/length"
+	"github.com/dadadamarine/orb/internal/length"
 )
 
 // Length returns the length of the boundary of the geometry
@@ -0,0 +1,78 @@
+package geo
+
+import (
+	"math"
+
+	"github.com/dadadamarine/orb"
+)
+
+// RhumbDistance returns the distance in meters between two points along
+// a rhumb line, a path of constant bearing.
+func RhumbDistance(p1, p2 orb.Point) float64 {
+	lat1, lat2 := deg2rad(p1[1]), deg2rad(p2[1])
+	dLat := lat2 - lat1
+	dLon := wrapRadians(deg2rad(p2[0] - p1[0]))
+
+	q := rhumbQ(lat1, lat2)
+	return math.Sqrt(dLat*dLat+q*q*dLon*dLon) * orb.EarthRadius
+}
+
+// RhumbBearing returns the constant bearing, from -180 to 180, of the
+// rhumb line from one point to the other.
+func RhumbBearing(from, to orb.Point) float64 {
+	dLon := wrapRadians(deg2rad(to[0] - from[0]))
+	dPsi := stretchedLatitude(deg2rad(to[1])) - stretchedLatitude(deg2rad(from[1]))
+
+	return rad2deg(math.Atan2(dLon, dPsi))
+}
+
+// RhumbPointAtBearingAndDistance returns the point at the distance in
+// meters along the rhumb line with the given bearing from the point.
+func RhumbPointAtBearingAndDistance(p orb.Point, bearing, distance float64) orb.Point {
+	delta := distance / orb.EarthRadius
+	theta := deg2rad(bearing)
+
+	lat1 := deg2rad(p[1])
+	lat2 := lat1 + delta*math.Cos(theta)
+
+	// past a pole
+	if lat2 > math.Pi/2 {
+		lat2 = math.Pi - lat2
+	} else if lat2 < -math.Pi/2 {
+		lat2 = -math.Pi - lat2
+	}
+
+	dLon := delta * math.Sin(theta) / rhumbQ(lat1, lat2)
+	lon := wrapRadians(deg2rad(p[0]) + dLon)
+
+	return orb.Point{rad2deg(lon), rad2deg(lat2)}
+}
+
+// stretchedLatitude is the Mercator projected latitude.
+func stretchedLatitude(lat float64) float64 {
+	return math.Log(math.Tan(math.Pi/4 + lat/2))
+}
+
+// rhumbQ is the ratio of the latitude change to the stretched latitude change,
+// the cosine of the latitude for east-west lines.
+func rhumbQ(lat1, lat2 float64) float64 {
+	dPsi := stretchedLatitude(lat2) - stretchedLatitude(lat1)
+	if math.Abs(dPsi) > 1e-12 {
+		return (lat2 - lat1) / dPsi
+	}
+
+	return math.Cos(lat1)
+}
+
+// wrapRadians wraps the longitude to -pi to pi.
+func wrapRadians(lon float64) float64 {
+	if lon > math.Pi {
+		return lon - 2*math.Pi*math.Floor((lon+math.Pi)/(2*math.Pi))
+	}
+
+	if lon < -math.Pi {
+		return lon + 2*math.Pi*math.Floor((math.Pi-lon)/(2*math.Pi))
+	}
+
+	return lon
+}
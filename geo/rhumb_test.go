@@ -0,0 +1,71 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func dms(d, m, s float64) float64 {
+	return d + m/60 + s/3600
+}
+
+func TestRhumbDistance(t *testing.T) {
+	p1 := orb.Point{-dms(4, 8, 2), dms(50, 21, 59)}
+	p2 := orb.Point{-dms(71, 2, 27), dms(42, 21, 4)}
+
+	if d := RhumbDistance(p1, p2); math.Abs(d-5198e3*veness) > 1e3 {
+		t.Errorf("incorrect distance: %v", d)
+	}
+
+	// along the equator
+	p1, p2 = orb.Point{0, 0}, orb.Point{1, 0}
+	if d, e := RhumbDistance(p1, p2), DistanceHaversine(p1, p2); math.Abs(d-e) > epsilon {
+		t.Errorf("incorrect distance: %v != %v", d, e)
+	}
+
+	// across the antimeridian
+	p1, p2 = orb.Point{179.5, 30}, orb.Point{-179.5, 30}
+	if d, e := RhumbDistance(p1, p2), RhumbDistance(orb.Point{0.5, 30}, orb.Point{-0.5, 30}); math.Abs(d-e) > epsilon {
+		t.Errorf("incorrect distance: %v != %v", d, e)
+	}
+}
+
+func TestRhumbBearing(t *testing.T) {
+	p1 := orb.Point{-dms(4, 8, 2), dms(50, 21, 59)}
+	p2 := orb.Point{-dms(71, 2, 27), dms(42, 21, 4)}
+
+	if b := RhumbBearing(p1, p2); math.Abs(b-(dms(260, 7, 38)-360)) > 1e-3 {
+		t.Errorf("incorrect bearing: %v", b)
+	}
+
+	if b := RhumbBearing(orb.Point{170, 10}, orb.Point{-170, 10}); math.Abs(b-90) > epsilon {
+		t.Errorf("incorrect bearing: %v", b)
+	}
+}
+
+func TestRhumbPointAtBearingAndDistance(t *testing.T) {
+	p := orb.Point{dms(1, 20, 17), dms(51, 7, 32)}
+	expected := orb.Point{dms(1, 51, 9), dms(50, 57, 48)}
+
+	r := RhumbPointAtBearingAndDistance(p, dms(116, 38, 10), 40230*veness)
+	if math.Abs(r[0]-expected[0]) > 1e-3 || math.Abs(r[1]-expected[1]) > 1e-3 {
+		t.Errorf("incorrect point: %v != %v", r, expected)
+	}
+
+	// round trip
+	p1 := orb.Point{-122.4194, 37.7749}
+	p2 := orb.Point{139.6917, 35.6895}
+
+	r = RhumbPointAtBearingAndDistance(p1, RhumbBearing(p1, p2), RhumbDistance(p1, p2))
+	if math.Abs(r[0]-p2[0]) > epsilon || math.Abs(r[1]-p2[1]) > epsilon {
+		t.Errorf("incorrect point: %v != %v", r, p2)
+	}
+
+	// east along a parallel
+	r = RhumbPointAtBearingAndDistance(orb.Point{179, 60}, 90, RhumbDistance(orb.Point{0, 60}, orb.Point{2, 60}))
+	if math.Abs(r[0]-(-179)) > epsilon || math.Abs(r[1]-60) > epsilon {
+		t.Errorf("incorrect point: %v", r)
+	}
+}
@@ -0,0 +1,304 @@
+package geohash
+
+import (
+	"fmt"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/planar"
+)
+
+// Geometry returns the covering set of geohashes, of the given precision,
+// for the given geometry.
+func Geometry(g orb.Geometry, precision int) Set {
+	if g == nil {
+		return nil
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		return Point(g, precision)
+	case orb.MultiPoint:
+		return MultiPoint(g, precision)
+	case orb.LineString:
+		return LineString(g, precision)
+	case orb.MultiLineString:
+		return MultiLineString(g, precision)
+	case orb.Ring:
+		return Ring(g, precision)
+	case orb.Polygon:
+		return Polygon(g, precision)
+	case orb.MultiPolygon:
+		return MultiPolygon(g, precision)
+	case orb.Collection:
+		return Collection(g, precision)
+	case orb.Bound:
+		return Bound(g, precision)
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+// Point creates a geohash cover for the point, i.e. just the geohash
+// containing the point.
+func Point(p orb.Point, precision int) Set {
+	return Set{
+		Encode(p, precision): true,
+	}
+}
+
+// MultiPoint creates a geohash cover for the set of points.
+func MultiPoint(mp orb.MultiPoint, precision int) Set {
+	set := make(Set)
+	for _, p := range mp {
+		set[Encode(p, precision)] = true
+	}
+
+	return set
+}
+
+// Bound creates a geohash cover for the bound, i.e. all the geohashes
+// whose interior overlaps the bound. If the bound has no width or height
+// the geohashes containing it, as defined by Encode, are used.
+func Bound(b orb.Bound, precision int) Set {
+	set := make(Set)
+	coverBound(set, "", world, b, precision)
+	return set
+}
+
+func coverBound(set Set, hash string, cell, b orb.Bound, precision int) {
+	if !overlaps(cell.Min[0], cell.Max[0], b.Min[0], b.Max[0], world.Max[0]) ||
+		!overlaps(cell.Min[1], cell.Max[1], b.Min[1], b.Max[1], world.Max[1]) {
+		return
+	}
+
+	if len(hash) >= precision {
+		set[hash] = true
+		return
+	}
+
+	for i := 0; i < len(base32); i++ {
+		coverBound(set, hash+base32[i:i+1], refine(cell, len(hash), i), b, precision)
+	}
+}
+
+// overlaps returns true if the cell range overlaps the bound range in
+// one dimension. Zero length ranges use the half open cell like Encode.
+func overlaps(cellMin, cellMax, min, max, edge float64) bool {
+	if min == max {
+		return cellMin <= min && (min < cellMax || min == edge)
+	}
+
+	return cellMin < max && min < cellMax
+}
+
+// LineString creates a geohash cover for the line string,
+// i.e. the geohashes the line intersects or touches.
+func LineString(ls orb.LineString, precision int) Set {
+	set := make(Set)
+	if len(ls) == 0 {
+		return set
+	}
+
+	if len(ls) == 1 {
+		return Point(ls[0], precision)
+	}
+
+	coverSegments(set, "", world, appendSegments(nil, ls), precision)
+	return set
+}
+
+// MultiLineString creates a geohash cover for the set of line strings.
+func MultiLineString(mls orb.MultiLineString, precision int) Set {
+	set := make(Set)
+
+	var segs []segment
+	for _, ls := range mls {
+		if len(ls) == 1 {
+			set[Encode(ls[0], precision)] = true
+			continue
+		}
+		segs = appendSegments(segs, ls)
+	}
+
+	if len(segs) > 0 {
+		coverSegments(set, "", world, segs, precision)
+	}
+
+	return set
+}
+
+func coverSegments(set Set, hash string, cell orb.Bound, segs []segment, precision int) {
+	var in []segment
+	for _, s := range segs {
+		if _, _, ok := clipSegment(s, cell); ok {
+			in = append(in, s)
+		}
+	}
+
+	if len(in) == 0 {
+		return
+	}
+
+	if len(hash) >= precision {
+		set[hash] = true
+		return
+	}
+
+	for i := 0; i < len(base32); i++ {
+		coverSegments(set, hash+base32[i:i+1], refine(cell, len(hash), i), in, precision)
+	}
+}
+
+// Ring creates a geohash cover for the ring.
+func Ring(r orb.Ring, precision int) Set {
+	return Polygon(orb.Polygon{r}, precision)
+}
+
+// Polygon creates a geohash cover for the polygon, i.e. the geohashes
+// whose interior intersects the interior of the polygon.
+func Polygon(p orb.Polygon, precision int) Set {
+	set := make(Set)
+	if len(p) == 0 || len(p[0]) == 0 {
+		return set
+	}
+
+	var segs []segment
+	for _, r := range p {
+		segs = appendSegments(segs, orb.LineString(r))
+		if len(r) > 0 && r[0] != r[len(r)-1] {
+			segs = append(segs, segment{r[len(r)-1], r[0]})
+		}
+	}
+
+	coverPolygon(set, "", world, segs, p, precision)
+	return set
+}
+
+func coverPolygon(set Set, hash string, cell orb.Bound, segs []segment, p orb.Polygon, precision int) {
+	var in []segment
+	for _, s := range segs {
+		if segmentCrosses(s, cell) {
+			in = append(in, s)
+		}
+	}
+
+	if len(in) == 0 {
+		// the boundary does not pass through the cell so it's
+		// either completely inside or completely outside.
+		if planar.PolygonContains(p, cell.Center()) {
+			fill(set, hash, precision)
+		}
+		return
+	}
+
+	if len(hash) >= precision {
+		set[hash] = true
+		return
+	}
+
+	for i := 0; i < len(base32); i++ {
+		coverPolygon(set, hash+base32[i:i+1], refine(cell, len(hash), i), in, p, precision)
+	}
+}
+
+// fill adds all the descendants of the hash, at the given precision, to the set.
+func fill(set Set, hash string, precision int) {
+	if len(hash) >= precision {
+		set[hash] = true
+		return
+	}
+
+	for i := 0; i < len(base32); i++ {
+		fill(set, hash+base32[i:i+1], precision)
+	}
+}
+
+// MultiPolygon creates a geohash cover for the multi-polygon.
+func MultiPolygon(mp orb.MultiPolygon, precision int) Set {
+	set := make(Set)
+	for _, p := range mp {
+		set.Merge(Polygon(p, precision))
+	}
+
+	return set
+}
+
+// Collection returns the covering set of geohashes for the
+// geometry collection.
+func Collection(c orb.Collection, precision int) Set {
+	set := make(Set)
+	for _, g := range c {
+		set.Merge(Geometry(g, precision))
+	}
+
+	return set
+}
+
+type segment [2]orb.Point
+
+func appendSegments(segs []segment, ls orb.LineString) []segment {
+	for i := 1; i < len(ls); i++ {
+		segs = append(segs, segment{ls[i-1], ls[i]})
+	}
+
+	return segs
+}
+
+// clipSegment returns the parametric range of the segment
+// that is within the closed bound.
+func clipSegment(s segment, b orb.Bound) (float64, float64, bool) {
+	dx := s[1][0] - s[0][0]
+	dy := s[1][1] - s[0][1]
+
+	t0, t1 := 0.0, 1.0
+	clip := func(p, q float64) bool {
+		if p == 0 {
+			return q >= 0
+		}
+
+		r := q / p
+		if p < 0 {
+			if r > t1 {
+				return false
+			}
+			if r > t0 {
+				t0 = r
+			}
+		} else {
+			if r < t0 {
+				return false
+			}
+			if r < t1 {
+				t1 = r
+			}
+		}
+
+		return true
+	}
+
+	if clip(-dx, s[0][0]-b.Min[0]) &&
+		clip(dx, b.Max[0]-s[0][0]) &&
+		clip(-dy, s[0][1]-b.Min[1]) &&
+		clip(dy, b.Max[1]-s[0][1]) {
+		return t0, t1, true
+	}
+
+	return 0, 0, false
+}
+
+// segmentCrosses returns true if the segment passes through the interior
+// of the bound, segments along the edge do not count.
+func segmentCrosses(s segment, b orb.Bound) bool {
+	t0, t1, ok := clipSegment(s, b)
+	if !ok {
+		return false
+	}
+
+	// the clipped part is a chord of the box, its midpoint is strictly
+	// inside unless the chord lies along the edge.
+	t := (t0 + t1) / 2
+	x := s[0][0] + t*(s[1][0]-s[0][0])
+	y := s[0][1] + t*(s[1][1]-s[0][1])
+
+	return b.Min[0] < x && x < b.Max[0] && b.Min[1] < y && y < b.Max[1]
+}
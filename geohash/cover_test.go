@@ -0,0 +1,177 @@
+package geohash
+
+import (
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/planar"
+)
+
+func TestGeometry(t *testing.T) {
+	cell, _ := Decode("ezs4")
+
+	cases := []struct {
+		name   string
+		geom   orb.Geometry
+		count  int
+		merged Set
+	}{
+		{
+			name:   "point",
+			geom:   orb.Point{-5.6, 42.6},
+			count:  1,
+			merged: Set{"ezs42": true},
+		},
+		{
+			name:   "aligned bound",
+			geom:   cell,
+			count:  32,
+			merged: Set{"ezs4": true},
+		},
+		{
+			name:   "aligned polygon",
+			geom:   cell.ToPolygon(),
+			count:  32,
+			merged: Set{"ezs4": true},
+		},
+		{
+			name:   "aligned ring",
+			geom:   cell.ToRing(),
+			count:  32,
+			merged: Set{"ezs4": true},
+		},
+		{
+			name:   "single point line",
+			geom:   orb.LineString{{-5.6, 42.6}},
+			count:  1,
+			merged: Set{"ezs42": true},
+		},
+		{
+			name:  "collection",
+			geom:  orb.Collection{cell, orb.Point{10.40744, 57.64911}},
+			count: 33,
+			merged: Set{
+				"ezs4":  true,
+				"u4pru": true,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			set := Geometry(tc.geom, 5)
+			if len(set) != tc.count {
+				t.Errorf("incorrect count: %v != %v", len(set), tc.count)
+			}
+
+			merged := MergeUp(set, 0)
+			if len(merged) != len(tc.merged) {
+				t.Fatalf("incorrect merged: %v != %v", merged, tc.merged)
+			}
+
+			for h := range tc.merged {
+				if !merged[h] {
+					t.Errorf("missing %v: %v", h, merged)
+				}
+			}
+
+			if len(set) != tc.count {
+				t.Errorf("merge up should not modify input")
+			}
+		})
+	}
+}
+
+func TestLineString(t *testing.T) {
+	ls := orb.LineString{{-5.61, 42.59}, {-5.3, 42.7}, {-5.2, 42.4}}
+	set := LineString(ls, 5)
+
+	// every vertex is covered
+	for _, p := range ls {
+		if !set[Encode(p, 5)] {
+			t.Errorf("point %v not covered", p)
+		}
+	}
+
+	// every cell touches the line
+	for h := range set {
+		b, _ := Decode(h)
+		touches := false
+		for i := 1; i < len(ls); i++ {
+			if _, _, ok := clipSegment(segment{ls[i-1], ls[i]}, b); ok {
+				touches = true
+			}
+		}
+
+		if !touches {
+			t.Errorf("cell %v does not touch line", h)
+		}
+	}
+
+	// a horizontal line through the middle of a row of cells
+	ls = orb.LineString{{-5.62, 42.605}, {-5.45, 42.605}}
+	set = LineString(ls, 5)
+	if len(set) != 4 {
+		t.Errorf("incorrect count: %v", set)
+	}
+
+	mls := MultiLineString(orb.MultiLineString{ls, {{10.40744, 57.64911}}}, 5)
+	if len(mls) != 5 {
+		t.Errorf("incorrect multi line count: %v", mls)
+	}
+}
+
+func TestPolygon(t *testing.T) {
+	p := orb.Polygon{
+		{{-5.7, 42.5}, {-5.3, 42.5}, {-5.3, 42.8}, {-5.7, 42.8}, {-5.7, 42.5}},
+		{{-5.55, 42.6}, {-5.55, 42.7}, {-5.45, 42.7}, {-5.45, 42.6}, {-5.55, 42.6}},
+	}
+
+	set := Polygon(p, 5)
+
+	area := 0.0
+	for h := range set {
+		b, _ := Decode(h)
+		area += (b.Max[0] - b.Min[0]) * (b.Max[1] - b.Min[1])
+
+		// should overlap the polygon
+		inside := planar.PolygonContains(p, b.Center())
+		for _, r := range p {
+			for i := 1; i < len(r); i++ {
+				if segmentCrosses(segment{r[i-1], r[i]}, b) {
+					inside = true
+				}
+			}
+		}
+
+		if !inside {
+			t.Errorf("cell %v does not overlap polygon", h)
+		}
+	}
+
+	if a := planar.Area(p); area < a {
+		t.Errorf("cover area less than polygon area: %v < %v", area, a)
+	}
+
+	// cell fully in the hole
+	if h := Encode(orb.Point{-5.5, 42.65}, 6); Polygon(p, 6)[h] {
+		t.Errorf("cell %v in hole should not be covered", h)
+	}
+
+	mp := MultiPolygon(orb.MultiPolygon{p}, 5)
+	if len(mp) != len(set) {
+		t.Errorf("multi polygon should match: %v != %v", len(mp), len(set))
+	}
+}
+
+func TestBound(t *testing.T) {
+	set := Bound(orb.Bound{Min: orb.Point{-180, -90}, Max: orb.Point{180, 90}}, 2)
+	if len(set) != 32*32 {
+		t.Errorf("world should be all cells: %v", len(set))
+	}
+
+	set = Bound(orb.Bound{Min: orb.Point{10, 10}, Max: orb.Point{10, 10}}, 3)
+	if len(set) != 1 || !set[Encode(orb.Point{10, 10}, 3)] {
+		t.Errorf("point bound should be one cell: %v", set)
+	}
+}
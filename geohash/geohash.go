@@ -0,0 +1,250 @@
+// Package geohash encodes and decodes geohashes and computes the covering
+// set of geohashes for an orb.Geometry.
+package geohash
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/dadadamarine/orb"
+)
+
+// ErrInvalidHash is returned when decoding a string that contains
+// characters outside of the geohash base32 alphabet.
+var ErrInvalidHash = errors.New("geohash: invalid hash")
+
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+var decodeMap [256]int8
+
+func init() {
+	for i := range decodeMap {
+		decodeMap[i] = -1
+	}
+
+	for i := 0; i < len(base32); i++ {
+		decodeMap[base32[i]] = int8(i)
+		decodeMap[strings.ToUpper(base32[i : i+1])[0]] = int8(i)
+	}
+}
+
+// world is the bound of the zero length geohash.
+var world = orb.Bound{Min: orb.Point{-180, -90}, Max: orb.Point{180, 90}}
+
+// A Direction is used to find the neighbor of a geohash.
+type Direction int
+
+// The eight directions, in clockwise order starting at north.
+// This is also the order of the result of the Neighbors function.
+const (
+	North Direction = iota
+	NorthEast
+	East
+	SouthEast
+	South
+	SouthWest
+	West
+	NorthWest
+)
+
+// Encode returns the geohash of the given precision, i.e. number of
+// characters, containing the point. Points on the boundary between cells
+// belong to the cell to the north and/or east.
+func Encode(p orb.Point, precision int) string {
+	if precision <= 0 {
+		return ""
+	}
+
+	b := world
+	hash := make([]byte, precision)
+	for i := range hash {
+		even := i%2 == 0
+
+		c := 0
+		for bit := 4; bit >= 0; bit-- {
+			var mid float64
+			if even {
+				mid = (b.Min[0] + b.Max[0]) / 2
+				if p[0] >= mid {
+					c |= 1 << uint(bit)
+					b.Min[0] = mid
+				} else {
+					b.Max[0] = mid
+				}
+			} else {
+				mid = (b.Min[1] + b.Max[1]) / 2
+				if p[1] >= mid {
+					c |= 1 << uint(bit)
+					b.Min[1] = mid
+				} else {
+					b.Max[1] = mid
+				}
+			}
+
+			even = !even
+		}
+
+		hash[i] = base32[c]
+	}
+
+	return string(hash)
+}
+
+// Decode returns the bound of the cell represented by the geohash.
+// Decoding is case insensitive. The empty hash decodes to the whole world.
+func Decode(hash string) (orb.Bound, error) {
+	b := world
+	for i := 0; i < len(hash); i++ {
+		c := decodeMap[hash[i]]
+		if c < 0 {
+			return orb.Bound{}, ErrInvalidHash
+		}
+
+		b = refine(b, i, int(c))
+	}
+
+	return b, nil
+}
+
+// Center returns the center point of the cell represented by the geohash.
+func Center(hash string) (orb.Point, error) {
+	b, err := Decode(hash)
+	if err != nil {
+		return orb.Point{}, err
+	}
+
+	return b.Center(), nil
+}
+
+// Valid returns true if the hash only contains geohash characters.
+func Valid(hash string) bool {
+	for i := 0; i < len(hash); i++ {
+		if decodeMap[hash[i]] < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Parent returns the geohash one character shorter containing the given hash.
+// The parent of the empty hash is the empty hash.
+func Parent(hash string) string {
+	if hash == "" {
+		return ""
+	}
+
+	return hash[:len(hash)-1]
+}
+
+// Children returns the 32 geohashes one character longer that are
+// contained by the given hash.
+func Children(hash string) []string {
+	result := make([]string, len(base32))
+	for i := range result {
+		result[i] = hash + base32[i:i+1]
+	}
+
+	return result
+}
+
+// Neighbor returns the geohash of the same precision adjacent to the given
+// hash in the given direction. Neighbors wrap around the antimeridian.
+// The empty string is returned if there is no neighbor,
+// i.e. north or south of a cell at the pole.
+func Neighbor(hash string, d Direction) (string, error) {
+	b, err := Decode(hash)
+	if err != nil {
+		return "", err
+	}
+
+	return neighbor(b, len(hash), d), nil
+}
+
+// Neighbors returns the eight geohashes of the same precision surrounding
+// the given hash, in the order of the Direction constants. Entries are the
+// empty string if there is no neighbor in that direction.
+func Neighbors(hash string) ([8]string, error) {
+	var result [8]string
+
+	b, err := Decode(hash)
+	if err != nil {
+		return result, err
+	}
+
+	for d := North; d <= NorthWest; d++ {
+		result[d] = neighbor(b, len(hash), d)
+	}
+
+	return result, nil
+}
+
+func neighbor(b orb.Bound, precision int, d Direction) string {
+	if precision == 0 {
+		return ""
+	}
+
+	dx, dy := 0.0, 0.0
+	switch d {
+	case North:
+		dy = 1
+	case NorthEast:
+		dx, dy = 1, 1
+	case East:
+		dx = 1
+	case SouthEast:
+		dx, dy = 1, -1
+	case South:
+		dy = -1
+	case SouthWest:
+		dx, dy = -1, -1
+	case West:
+		dx = -1
+	case NorthWest:
+		dx, dy = -1, 1
+	}
+
+	c := b.Center()
+	c[0] += dx * (b.Max[0] - b.Min[0])
+	c[1] += dy * (b.Max[1] - b.Min[1])
+
+	if c[1] > 90 || c[1] < -90 {
+		return ""
+	}
+
+	if c[0] > 180 {
+		c[0] -= 360
+	} else if c[0] < -180 {
+		c[0] += 360
+	}
+
+	return Encode(c, precision)
+}
+
+// refine returns the bound of the child cell of b given by the base32
+// value c of the character at index i in the hash.
+func refine(b orb.Bound, i, c int) orb.Bound {
+	even := (i*5)%2 == 0
+	for bit := 4; bit >= 0; bit-- {
+		on := c&(1<<uint(bit)) != 0
+		if even {
+			mid := (b.Min[0] + b.Max[0]) / 2
+			if on {
+				b.Min[0] = mid
+			} else {
+				b.Max[0] = mid
+			}
+		} else {
+			mid := (b.Min[1] + b.Max[1]) / 2
+			if on {
+				b.Min[1] = mid
+			} else {
+				b.Max[1] = mid
+			}
+		}
+
+		even = !even
+	}
+
+	return b
+}
@@ -0,0 +1,245 @@
+package geohash
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestEncode(t *testing.T) {
+	cases := []struct {
+		name      string
+		point     orb.Point
+		precision int
+		result    string
+	}{
+		{
+			name:      "spain",
+			point:     orb.Point{-5.6, 42.6},
+			precision: 5,
+			result:    "ezs42",
+		},
+		{
+			name:      "denmark",
+			point:     orb.Point{10.40744, 57.64911},
+			precision: 11,
+			result:    "u4pruydqqvj",
+		},
+		{
+			name:      "origin",
+			point:     orb.Point{0, 0},
+			precision: 3,
+			result:    "s00",
+		},
+		{
+			name:      "top right corner",
+			point:     orb.Point{180, 90},
+			precision: 4,
+			result:    "zzzz",
+		},
+		{
+			name:      "bottom left corner",
+			point:     orb.Point{-180, -90},
+			precision: 4,
+			result:    "0000",
+		},
+		{
+			name:      "zero precision",
+			point:     orb.Point{1, 2},
+			precision: 0,
+			result:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := Encode(tc.point, tc.precision)
+			if h != tc.result {
+				t.Errorf("incorrect hash: %v != %v", h, tc.result)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	b, err := Decode("ezs42")
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	expected := orb.Bound{
+		Min: orb.Point{-5.625, 42.5830078125},
+		Max: orb.Point{-5.5810546875, 42.626953125},
+	}
+	if !b.Equal(expected) {
+		t.Errorf("incorrect bound: %v != %v", b, expected)
+	}
+
+	upper, err := Decode("EZS42")
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	if !upper.Equal(b) {
+		t.Errorf("should be case insensitive: %v != %v", upper, b)
+	}
+
+	b, err = Decode("")
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	if !b.Equal(world) {
+		t.Errorf("empty hash should be the world: %v", b)
+	}
+
+	for _, h := range []string{"ezs4a", "ilo", "u4p-"} {
+		_, err := Decode(h)
+		if err != ErrInvalidHash {
+			t.Errorf("%s: incorrect error: %v", h, err)
+		}
+
+		if Valid(h) {
+			t.Errorf("%s: should not be valid", h)
+		}
+	}
+}
+
+func TestDecode_roundTrip(t *testing.T) {
+	points := []orb.Point{
+		{-122.4194, 37.7749},
+		{151.2093, -33.8688},
+		{-0.1278, 51.5074},
+		{179.9999, -89.9999},
+	}
+
+	for _, p := range points {
+		for precision := 1; precision <= 12; precision++ {
+			h := Encode(p, precision)
+			b, err := Decode(h)
+			if err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+
+			if !b.Contains(p) {
+				t.Errorf("%v %d: bound does not contain point: %v", p, precision, b)
+			}
+
+			c, _ := Center(h)
+			if v := Encode(c, precision); v != h {
+				t.Errorf("%v %d: center should encode to same hash: %v != %v", p, precision, v, h)
+			}
+		}
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	n, err := Neighbors("ezs42")
+	if err != nil {
+		t.Fatalf("neighbors error: %v", err)
+	}
+
+	expected := [8]string{"ezs48", "ezs49", "ezs43", "ezs41", "ezs40", "ezefp", "ezefr", "ezefx"}
+	if n != expected {
+		t.Errorf("incorrect neighbors: %v != %v", n, expected)
+	}
+
+	// every neighbor should be adjacent
+	b, _ := Decode("ezs42")
+	for _, h := range n {
+		nb, _ := Decode(h)
+		if !nb.Intersects(b) {
+			t.Errorf("%s should touch ezs42", h)
+		}
+	}
+}
+
+func TestNeighbor(t *testing.T) {
+	cases := []struct {
+		name   string
+		hash   string
+		dir    Direction
+		result string
+	}{
+		{
+			name:   "antimeridian east",
+			hash:   "zz",
+			dir:    East,
+			result: "bp",
+		},
+		{
+			name:   "antimeridian west",
+			hash:   "bp",
+			dir:    West,
+			result: "zz",
+		},
+		{
+			name:   "north pole",
+			hash:   "zz",
+			dir:    North,
+			result: "",
+		},
+		{
+			name:   "south pole",
+			hash:   "00",
+			dir:    SouthWest,
+			result: "",
+		},
+		{
+			name:   "empty hash",
+			hash:   "",
+			dir:    East,
+			result: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := Neighbor(tc.hash, tc.dir)
+			if err != nil {
+				t.Fatalf("neighbor error: %v", err)
+			}
+
+			if h != tc.result {
+				t.Errorf("incorrect neighbor: %v != %v", h, tc.result)
+			}
+		})
+	}
+
+	_, err := Neighbor("a", North)
+	if err != ErrInvalidHash {
+		t.Errorf("incorrect error: %v", err)
+	}
+}
+
+func TestChildren(t *testing.T) {
+	parent, _ := Decode("u4p")
+	area := 0.0
+
+	for _, c := range Children("u4p") {
+		if p := Parent(c); p != "u4p" {
+			t.Errorf("incorrect parent: %v", p)
+		}
+
+		b, err := Decode(c)
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+
+		if !parent.Contains(b.Min) || !parent.Contains(b.Max) {
+			t.Errorf("child %v not within parent", c)
+		}
+
+		area += (b.Max[0] - b.Min[0]) * (b.Max[1] - b.Min[1])
+	}
+
+	pa := (parent.Max[0] - parent.Min[0]) * (parent.Max[1] - parent.Min[1])
+	if math.Abs(area-pa) > 1e-12 {
+		t.Errorf("children should tile the parent: %v != %v", area, pa)
+	}
+
+	if p := Parent(""); p != "" {
+		t.Errorf("parent of empty should be empty: %v", p)
+	}
+}
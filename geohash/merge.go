@@ -0,0 +1,40 @@
+package geohash
+
+// MergeUp will merge up the geohashes in a given set up to the given
+// min precision. Geohashes are merged into their parent only if all 32
+// children are in the set. The geohashes in the input set are expected
+// to all be of the same precision, e.g. outputs of the Geometry function.
+// The input set is not modified.
+func MergeUp(set Set, min int) Set {
+	merged := make(Set)
+
+	current := make(Set, len(set))
+	precision := 0
+	for h, v := range set {
+		if v {
+			current[h] = true
+			precision = len(h)
+		}
+	}
+
+	for ; precision > min && len(current) >= len(base32); precision-- {
+		counts := make(map[string]int, len(current)/len(base32))
+		for h := range current {
+			counts[Parent(h)]++
+		}
+
+		parents := make(Set)
+		for h := range current {
+			if p := Parent(h); counts[p] == len(base32) {
+				parents[p] = true
+			} else {
+				merged[h] = true
+			}
+		}
+
+		current = parents
+	}
+
+	merged.Merge(current)
+	return merged
+}
@@ -0,0 +1,46 @@
+package geohash
+
+import (
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestMergeUp(t *testing.T) {
+	set := Bound(mustDecode(t, "u4"), 4)
+	if len(set) != 32*32 {
+		t.Fatalf("incorrect cover: %v", len(set))
+	}
+
+	merged := MergeUp(set, 2)
+	if len(merged) != 1 || !merged["u4"] {
+		t.Errorf("should merge to single hash: %v", merged)
+	}
+
+	merged = MergeUp(set, 3)
+	if len(merged) != 32 {
+		t.Errorf("should stop at min precision: %v", len(merged))
+	}
+
+	partial := make(Set)
+	for _, h := range Children("u4p")[:31] {
+		partial[h] = true
+	}
+	partial["u4pz"] = false
+
+	merged = MergeUp(partial, 0)
+	if len(merged) != 31 {
+		t.Errorf("incomplete set should not merge: %v", len(merged))
+	}
+}
+
+func mustDecode(t testing.TB, h string) orb.Bound {
+	t.Helper()
+
+	b, err := Decode(h)
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	return b
+}
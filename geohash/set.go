@@ -0,0 +1,41 @@
+package geohash
+
+import (
+	"github.com/dadadamarine/orb/geojson"
+)
+
+// Set is a map/hash of geohashes.
+type Set map[string]bool
+
+// ToFeatureCollection converts a set of geohashes into a feature collection.
+// Each feature has a "geohash" property. Invalid hashes are skipped.
+// This method is mostly useful for debugging output.
+func (s Set) ToFeatureCollection() *geojson.FeatureCollection {
+	fc := geojson.NewFeatureCollection()
+	fc.Features = make([]*geojson.Feature, 0, len(s))
+	for h, v := range s {
+		if !v {
+			continue
+		}
+
+		b, err := Decode(h)
+		if err != nil {
+			continue
+		}
+
+		f := geojson.NewFeature(b.ToPolygon())
+		f.Properties["geohash"] = h
+		fc.Append(f)
+	}
+
+	return fc
+}
+
+// Merge will merge the given set into the existing set.
+func (s Set) Merge(set Set) {
+	for h, v := range set {
+		if v {
+			s[h] = true
+		}
+	}
+}
@@ -4,6 +4,5 @@ go 1.15
 
 require (
 	github.com/gogo/protobuf v1.3.2
-	github.com/paulmach/orb v0.4.0
 	github.com/paulmach/protoscan v0.2.1-0.20210522164731-4e53c6875432
 )
@@ -0,0 +1,50 @@
+// Portions of this file are ported from H3, https://github.com/uber/h3,
+// and modified.
+//
+// Copyright 2016-2021 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package h3
+
+// baseCell describes one of the 122 resolution 0 cells.
+type baseCell struct {
+	homeFijk     faceIJK
+	pentagon     bool
+	cwOffsetPent [2]int
+}
+
+// baseCellRotation is the base cell at a resolution 0 coordinate of a face
+// and the rotation into the base cell's home coordinate system.
+type baseCellRotation struct {
+	baseCell int
+	ccwRot60 int
+}
+
+func isBaseCellPentagon(bc int) bool {
+	return baseCells[bc].pentagon
+}
+
+// baseCellIsCwOffset returns if the face is one where the pentagon
+// base cell requires a clockwise, instead of counter-clockwise, offset.
+func baseCellIsCwOffset(bc, face int) bool {
+	return baseCells[bc].cwOffsetPent[0] == face || baseCells[bc].cwOffsetPent[1] == face
+}
+
+func faceIJKToBaseCell(f faceIJK) int {
+	return faceIJKBaseCells[f.face][f.coord.i][f.coord.j][f.coord.k].baseCell
+}
+
+func faceIJKToBaseCellCCWrot60(f faceIJK) int {
+	return faceIJKBaseCells[f.face][f.coord.i][f.coord.j][f.coord.k].ccwRot60
+}
@@ -0,0 +1,342 @@
+// Portions of this file are ported from H3, https://github.com/uber/h3,
+// and modified.
+//
+// Copyright 2016-2021 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package h3
+
+import (
+	"math"
+)
+
+const (
+	sqrt3over2 = 0.8660254037844386467637231707529361834714
+	sqrt7      = 2.6457513110645905905016157536392604257102
+)
+
+// coordIJK is a coordinate on a hexagon grid with three axes,
+// i, j and k, 120 degrees apart.
+type coordIJK struct {
+	i, j, k int
+}
+
+// vec2d is a 2d cartesian coordinate.
+type vec2d struct {
+	x, y float64
+}
+
+// digit is an index digit, the direction of a child cell from
+// the center child of its parent.
+type digit int
+
+const (
+	centerDigit digit = iota
+	kAxesDigit
+	jAxesDigit
+	jkAxesDigit
+	iAxesDigit
+	ikAxesDigit
+	ijAxesDigit
+	invalidDigit
+)
+
+// unitVecs are the coordinates of the unit vectors in each digit direction.
+var unitVecs = [7]coordIJK{
+	{0, 0, 0}, // center
+	{0, 0, 1}, // k
+	{0, 1, 0}, // j
+	{0, 1, 1}, // jk
+	{1, 0, 0}, // i
+	{1, 0, 1}, // ik
+	{1, 1, 0}, // ij
+}
+
+func (c coordIJK) add(o coordIJK) coordIJK {
+	return coordIJK{c.i + o.i, c.j + o.j, c.k + o.k}
+}
+
+func (c coordIJK) sub(o coordIJK) coordIJK {
+	return coordIJK{c.i - o.i, c.j - o.j, c.k - o.k}
+}
+
+func (c coordIJK) scale(f int) coordIJK {
+	return coordIJK{c.i * f, c.j * f, c.k * f}
+}
+
+// normalize returns the coordinate with all components non-negative
+// and at least one of them zero.
+func (c coordIJK) normalize() coordIJK {
+	if c.i < 0 {
+		c.j -= c.i
+		c.k -= c.i
+		c.i = 0
+	}
+
+	if c.j < 0 {
+		c.i -= c.j
+		c.k -= c.j
+		c.j = 0
+	}
+
+	if c.k < 0 {
+		c.i -= c.k
+		c.j -= c.k
+		c.k = 0
+	}
+
+	min := c.i
+	if c.j < min {
+		min = c.j
+	}
+	if c.k < min {
+		min = c.k
+	}
+
+	if min > 0 {
+		c.i -= min
+		c.j -= min
+		c.k -= min
+	}
+
+	return c
+}
+
+// toDigit returns the digit of a unit vector, or invalidDigit.
+func (c coordIJK) toDigit() digit {
+	c = c.normalize()
+	for d, v := range unitVecs {
+		if c == v {
+			return digit(d)
+		}
+	}
+
+	return invalidDigit
+}
+
+// neighbor returns the coordinate of the neighbor in the digit direction.
+func (c coordIJK) neighbor(d digit) coordIJK {
+	if d > centerDigit && d < invalidDigit {
+		c = c.add(unitVecs[d]).normalize()
+	}
+
+	return c
+}
+
+// combine returns i*iVec + j*jVec + k*kVec, normalized.
+func (c coordIJK) combine(iVec, jVec, kVec coordIJK) coordIJK {
+	return iVec.scale(c.i).add(jVec.scale(c.j)).add(kVec.scale(c.k)).normalize()
+}
+
+func (c coordIJK) rotate60ccw() coordIJK {
+	return c.combine(coordIJK{1, 1, 0}, coordIJK{0, 1, 1}, coordIJK{1, 0, 1})
+}
+
+func (c coordIJK) rotate60cw() coordIJK {
+	return c.combine(coordIJK{1, 0, 1}, coordIJK{1, 1, 0}, coordIJK{0, 1, 1})
+}
+
+// upAp7 returns the containing cell in the next coarser aperture 7
+// counter-clockwise grid.
+func (c coordIJK) upAp7() coordIJK {
+	i := c.i - c.k
+	j := c.j - c.k
+
+	return coordIJK{
+		i: int(math.Round(float64(3*i-j) / 7)),
+		j: int(math.Round(float64(i+2*j) / 7)),
+	}.normalize()
+}
+
+// upAp7r returns the containing cell in the next coarser aperture 7
+// clockwise grid.
+func (c coordIJK) upAp7r() coordIJK {
+	i := c.i - c.k
+	j := c.j - c.k
+
+	return coordIJK{
+		i: int(math.Round(float64(2*i+j) / 7)),
+		j: int(math.Round(float64(3*j-i) / 7)),
+	}.normalize()
+}
+
+// downAp7 returns the center cell in the next finer aperture 7
+// counter-clockwise grid.
+func (c coordIJK) downAp7() coordIJK {
+	return c.combine(coordIJK{3, 0, 1}, coordIJK{1, 3, 0}, coordIJK{0, 1, 3})
+}
+
+// downAp7r returns the center cell in the next finer aperture 7
+// clockwise grid.
+func (c coordIJK) downAp7r() coordIJK {
+	return c.combine(coordIJK{3, 1, 0}, coordIJK{0, 3, 1}, coordIJK{1, 0, 3})
+}
+
+// downAp3 returns the center cell in the next finer aperture 3
+// counter-clockwise grid.
+func (c coordIJK) downAp3() coordIJK {
+	return c.combine(coordIJK{2, 0, 1}, coordIJK{1, 2, 0}, coordIJK{0, 1, 2})
+}
+
+// downAp3r returns the center cell in the next finer aperture 3
+// clockwise grid.
+func (c coordIJK) downAp3r() coordIJK {
+	return c.combine(coordIJK{2, 1, 0}, coordIJK{0, 2, 1}, coordIJK{1, 0, 2})
+}
+
+// toHex2d returns the center of the cell in 2d cartesian coordinates.
+func (c coordIJK) toHex2d() vec2d {
+	i := float64(c.i - c.k)
+	j := float64(c.j - c.k)
+
+	return vec2d{i - 0.5*j, j * sqrt3over2}
+}
+
+// hex2dToCoordIJK returns the coordinates of the cell containing the point.
+func hex2dToCoordIJK(v vec2d) coordIJK {
+	var h coordIJK
+
+	a1 := math.Abs(v.x)
+	a2 := math.Abs(v.y)
+
+	// first do a reverse conversion
+	x2 := a2 / sqrt3over2
+	x1 := a1 + x2/2
+
+	// check if we have the center of a hex
+	m1 := int(x1)
+	m2 := int(x2)
+
+	// otherwise round correctly
+	r1 := x1 - float64(m1)
+	r2 := x2 - float64(m2)
+
+	if r1 < 0.5 {
+		if r1 < 1.0/3.0 {
+			h.i = m1
+			if r2 < (1+r1)/2 {
+				h.j = m2
+			} else {
+				h.j = m2 + 1
+			}
+		} else {
+			if r2 < 1-r1 {
+				h.j = m2
+			} else {
+				h.j = m2 + 1
+			}
+
+			if 1-r1 <= r2 && r2 < 2*r1 {
+				h.i = m1 + 1
+			} else {
+				h.i = m1
+			}
+		}
+	} else {
+		if r1 < 2.0/3.0 {
+			if r2 < 1-r1 {
+				h.j = m2
+			} else {
+				h.j = m2 + 1
+			}
+
+			if 2*r1-1 < r2 && r2 < 1-r1 {
+				h.i = m1
+			} else {
+				h.i = m1 + 1
+			}
+		} else {
+			h.i = m1 + 1
+			if r2 < r1/2 {
+				h.j = m2
+			} else {
+				h.j = m2 + 1
+			}
+		}
+	}
+
+	// now fold across the axes if necessary
+	if v.x < 0 {
+		if h.j%2 == 0 {
+			axisi := h.j / 2
+			diff := h.i - axisi
+			h.i = h.i - 2*diff
+		} else {
+			axisi := (h.j + 1) / 2
+			diff := h.i - axisi
+			h.i = h.i - (2*diff + 1)
+		}
+	}
+
+	if v.y < 0 {
+		h.i = h.i - (2*h.j+1)/2
+		h.j = -h.j
+	}
+
+	return h.normalize()
+}
+
+// rotate60ccw rotates the digit 60 degrees counter-clockwise.
+func (d digit) rotate60ccw() digit {
+	switch d {
+	case kAxesDigit:
+		return ikAxesDigit
+	case ikAxesDigit:
+		return iAxesDigit
+	case iAxesDigit:
+		return ijAxesDigit
+	case ijAxesDigit:
+		return jAxesDigit
+	case jAxesDigit:
+		return jkAxesDigit
+	case jkAxesDigit:
+		return kAxesDigit
+	}
+
+	return d
+}
+
+// rotate60cw rotates the digit 60 degrees clockwise.
+func (d digit) rotate60cw() digit {
+	switch d {
+	case kAxesDigit:
+		return jkAxesDigit
+	case jkAxesDigit:
+		return jAxesDigit
+	case jAxesDigit:
+		return ijAxesDigit
+	case ijAxesDigit:
+		return iAxesDigit
+	case iAxesDigit:
+		return ikAxesDigit
+	case ikAxesDigit:
+		return kAxesDigit
+	}
+
+	return d
+}
+
+// intersect returns the intersection of the lines through p0, p1 and p2, p3.
+func intersect(p0, p1, p2, p3 vec2d) vec2d {
+	s1 := vec2d{p1.x - p0.x, p1.y - p0.y}
+	s2 := vec2d{p3.x - p2.x, p3.y - p2.y}
+
+	t := (s2.x*(p0.y-p2.y) - s2.y*(p0.x-p2.x)) / (-s2.x*s1.y + s1.x*s2.y)
+	return vec2d{p0.x + t*s1.x, p0.y + t*s1.y}
+}
+
+func (v vec2d) almostEqual(o vec2d) bool {
+	const epsilon = 1.1920929e-07 // float32 epsilon
+	return math.Abs(v.x-o.x) < epsilon && math.Abs(v.y-o.y) < epsilon
+}
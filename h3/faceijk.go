@@ -0,0 +1,565 @@
+// Portions of this file are ported from H3, https://github.com/uber/h3,
+// and modified.
+//
+// Copyright 2016-2021 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package h3
+
+import (
+	"math"
+)
+
+const (
+	numIcosaFaces = 20
+	numBaseCells  = 122
+
+	epsilon = 0.0000000000000001
+
+	// res0UGnomonic is the scaling factor from the resolution 0 unit
+	// length (the distance between adjacent cell centers) to the
+	// gnomonic unit length.
+	res0UGnomonic = 0.38196601125010500003
+
+	// ap7RotRads is the rotation angle between the Class II
+	// and Class III resolution axes, asin(sqrt(3.0 / 28.0)).
+	ap7RotRads = 0.333473172251832115336090755351601070065900389
+)
+
+// latLng is a point in radians.
+type latLng struct {
+	lat, lng float64
+}
+
+// faceIJK is a coordinate on a given face of the icosahedron.
+type faceIJK struct {
+	face  int
+	coord coordIJK
+}
+
+// faceOrientIJK describes how to move from one face to an adjacent face.
+type faceOrientIJK struct {
+	face      int
+	translate coordIJK
+	ccwRot60  int
+}
+
+// quadrants of a face, used to find adjacent faces.
+const (
+	quadIJ = 1
+	quadKI = 2
+	quadJK = 3
+)
+
+// overage types when translating coordinates between faces.
+type overage int
+
+const (
+	noOverage overage = iota
+	faceEdge
+	newFace
+)
+
+// faceCenterGeo are the icosahedron face centers in lat/lng radians.
+var faceCenterGeo = [numIcosaFaces]latLng{
+	{0.803582649718989942, 1.248397419617396099},
+	{1.307747883455638156, 2.536945009877921159},
+	{1.054751253523952054, -1.347517358900396623},
+	{0.600191595538186799, -0.450603909469755746},
+	{0.491715428198773866, 0.401988202911306943},
+	{0.172745327415618701, 1.678146885280433686},
+	{0.605929321571350690, 2.953923329812411617},
+	{0.427370518328979641, -1.888876200336285401},
+	{-0.079066118549212831, -0.733429513380867741},
+	{-0.230961644455383637, 0.506495587332349035},
+	{0.079066118549212831, 2.408163140208925497},
+	{0.230961644455383637, -2.635097066257444203},
+	{-0.172745327415618701, -1.463445768309359553},
+	{-0.605929321571350690, -0.187669323777381622},
+	{-0.427370518328979641, 1.252716453253507838},
+	{-0.600191595538186799, 2.690988744120037492},
+	{-0.491715428198773866, -2.739604450678486295},
+	{-0.803582649718989942, -1.893195233972397139},
+	{-1.307747883455638156, -0.604647643711872080},
+	{-1.054751253523952054, 1.794075294689396615},
+}
+
+// faceCenterPoint are the icosahedron face centers as unit vectors.
+var faceCenterPoint [numIcosaFaces][3]float64
+
+// faceAxesAzRadsCII are the azimuths, from the face center, of the
+// Class II i, j and k axes of each face.
+var faceAxesAzRadsCII = [numIcosaFaces][3]float64{
+	{5.619958268523939882, 3.525563166130744542, 1.431168063737548730},
+	{5.760339081714187279, 3.665943979320991689, 1.571548876927796127},
+	{0.780213654393430055, 4.969003859179821079, 2.874608756786625655},
+	{0.430469363979999913, 4.619259568766391033, 2.524864466373195467},
+	{6.130269123335111400, 4.035874020941915804, 1.941478918548720291},
+	{2.692877706530642877, 0.598482604137447119, 4.787272808923838195},
+	{2.982963003477243874, 0.888567901084048369, 5.077358105870439581},
+	{3.532912002790141181, 1.438516900396945656, 5.627307105183336758},
+	{3.494305004259568154, 1.399909901866372864, 5.588700106652763840},
+	{3.003214169499538391, 0.908819067106342928, 5.097609271892733906},
+	{5.930472956509811562, 3.836077854116615875, 1.741682751723420374},
+	{0.138378484090254847, 4.327168688876645809, 2.232773586483450311},
+	{0.448714947059150361, 4.637505151845541521, 2.543110049452346120},
+	{0.158629650112549365, 4.347419854898940135, 2.253024752505744869},
+	{5.891865957979238535, 3.797470855586042958, 1.703075753192847583},
+	{2.711123289609793325, 0.616728187216597771, 4.805518392002988683},
+	{3.294508837434268316, 1.200113735041072948, 5.388903939827463911},
+	{3.804819692245439833, 1.710424589852244509, 5.899214794638635174},
+	{3.664438879055192436, 1.570043776661997111, 5.758833981448388027},
+	{2.361378999196363184, 0.266983896803167583, 4.455774101589558636},
+}
+
+// faceNeighbors are the neighboring faces, and how to translate into
+// their coordinate systems, for each face in the IJ, KI and JK quadrants.
+var faceNeighbors = [numIcosaFaces][4]faceOrientIJK{
+	{{0, coordIJK{0, 0, 0}, 0}, {4, coordIJK{2, 0, 2}, 1}, {1, coordIJK{2, 2, 0}, 5}, {5, coordIJK{0, 2, 2}, 3}},
+	{{1, coordIJK{0, 0, 0}, 0}, {0, coordIJK{2, 0, 2}, 1}, {2, coordIJK{2, 2, 0}, 5}, {6, coordIJK{0, 2, 2}, 3}},
+	{{2, coordIJK{0, 0, 0}, 0}, {1, coordIJK{2, 0, 2}, 1}, {3, coordIJK{2, 2, 0}, 5}, {7, coordIJK{0, 2, 2}, 3}},
+	{{3, coordIJK{0, 0, 0}, 0}, {2, coordIJK{2, 0, 2}, 1}, {4, coordIJK{2, 2, 0}, 5}, {8, coordIJK{0, 2, 2}, 3}},
+	{{4, coordIJK{0, 0, 0}, 0}, {3, coordIJK{2, 0, 2}, 1}, {0, coordIJK{2, 2, 0}, 5}, {9, coordIJK{0, 2, 2}, 3}},
+	{{5, coordIJK{0, 0, 0}, 0}, {10, coordIJK{2, 2, 0}, 3}, {14, coordIJK{2, 0, 2}, 3}, {0, coordIJK{0, 2, 2}, 3}},
+	{{6, coordIJK{0, 0, 0}, 0}, {11, coordIJK{2, 2, 0}, 3}, {10, coordIJK{2, 0, 2}, 3}, {1, coordIJK{0, 2, 2}, 3}},
+	{{7, coordIJK{0, 0, 0}, 0}, {12, coordIJK{2, 2, 0}, 3}, {11, coordIJK{2, 0, 2}, 3}, {2, coordIJK{0, 2, 2}, 3}},
+	{{8, coordIJK{0, 0, 0}, 0}, {13, coordIJK{2, 2, 0}, 3}, {12, coordIJK{2, 0, 2}, 3}, {3, coordIJK{0, 2, 2}, 3}},
+	{{9, coordIJK{0, 0, 0}, 0}, {14, coordIJK{2, 2, 0}, 3}, {13, coordIJK{2, 0, 2}, 3}, {4, coordIJK{0, 2, 2}, 3}},
+	{{10, coordIJK{0, 0, 0}, 0}, {5, coordIJK{2, 2, 0}, 3}, {6, coordIJK{2, 0, 2}, 3}, {15, coordIJK{0, 2, 2}, 3}},
+	{{11, coordIJK{0, 0, 0}, 0}, {6, coordIJK{2, 2, 0}, 3}, {7, coordIJK{2, 0, 2}, 3}, {16, coordIJK{0, 2, 2}, 3}},
+	{{12, coordIJK{0, 0, 0}, 0}, {7, coordIJK{2, 2, 0}, 3}, {8, coordIJK{2, 0, 2}, 3}, {17, coordIJK{0, 2, 2}, 3}},
+	{{13, coordIJK{0, 0, 0}, 0}, {8, coordIJK{2, 2, 0}, 3}, {9, coordIJK{2, 0, 2}, 3}, {18, coordIJK{0, 2, 2}, 3}},
+	{{14, coordIJK{0, 0, 0}, 0}, {9, coordIJK{2, 2, 0}, 3}, {5, coordIJK{2, 0, 2}, 3}, {19, coordIJK{0, 2, 2}, 3}},
+	{{15, coordIJK{0, 0, 0}, 0}, {16, coordIJK{2, 0, 2}, 1}, {19, coordIJK{2, 2, 0}, 5}, {10, coordIJK{0, 2, 2}, 3}},
+	{{16, coordIJK{0, 0, 0}, 0}, {17, coordIJK{2, 0, 2}, 1}, {15, coordIJK{2, 2, 0}, 5}, {11, coordIJK{0, 2, 2}, 3}},
+	{{17, coordIJK{0, 0, 0}, 0}, {18, coordIJK{2, 0, 2}, 1}, {16, coordIJK{2, 2, 0}, 5}, {12, coordIJK{0, 2, 2}, 3}},
+	{{18, coordIJK{0, 0, 0}, 0}, {19, coordIJK{2, 0, 2}, 1}, {17, coordIJK{2, 2, 0}, 5}, {13, coordIJK{0, 2, 2}, 3}},
+	{{19, coordIJK{0, 0, 0}, 0}, {15, coordIJK{2, 0, 2}, 1}, {18, coordIJK{2, 2, 0}, 5}, {14, coordIJK{0, 2, 2}, 3}},
+}
+
+// adjacentFaceDir is the quadrant of the second face relative to the
+// first, 0 if they are the same face and -1 if they are not adjacent.
+var adjacentFaceDir [numIcosaFaces][numIcosaFaces]int
+
+// maxDimByCIIres is the maximum coordinate value of a face
+// at each Class II resolution, in the aperture 7 grid.
+var maxDimByCIIres = [MaxResolution + 2]int{
+	2, -1, 14, -1, 98, -1, 686, -1, 4802, -1, 33614, -1, 235298, -1, 1647086, -1, 11529602,
+}
+
+// unitScaleByCIIres is the unit scale distance at each Class II resolution.
+var unitScaleByCIIres = [MaxResolution + 2]int{
+	1, -1, 7, -1, 49, -1, 343, -1, 2401, -1, 16807, -1, 117649, -1, 823543, -1, 5764801,
+}
+
+func init() {
+	for f := range faceCenterGeo {
+		faceCenterPoint[f] = toPoint3d(faceCenterGeo[f])
+
+		for g := range adjacentFaceDir[f] {
+			adjacentFaceDir[f][g] = -1
+		}
+
+		adjacentFaceDir[f][f] = 0
+		for q := quadIJ; q <= quadJK; q++ {
+			adjacentFaceDir[f][faceNeighbors[f][q].face] = q
+		}
+	}
+}
+
+func isClassIII(res int) bool {
+	return res%2 == 1
+}
+
+func toPoint3d(g latLng) [3]float64 {
+	r := math.Cos(g.lat)
+	return [3]float64{math.Cos(g.lng) * r, math.Sin(g.lng) * r, math.Sin(g.lat)}
+}
+
+func posAngle(a float64) float64 {
+	t := math.Mod(a, 2*math.Pi)
+	if t < 0 {
+		t += 2 * math.Pi
+	}
+
+	return t
+}
+
+func constrainLng(lng float64) float64 {
+	for lng > math.Pi {
+		lng -= 2 * math.Pi
+	}
+
+	for lng < -math.Pi {
+		lng += 2 * math.Pi
+	}
+
+	return lng
+}
+
+// azimuth returns the azimuth from p1 to p2 in radians.
+func azimuth(p1, p2 latLng) float64 {
+	return math.Atan2(
+		math.Cos(p2.lat)*math.Sin(p2.lng-p1.lng),
+		math.Cos(p1.lat)*math.Sin(p2.lat)-math.Sin(p1.lat)*math.Cos(p2.lat)*math.Cos(p2.lng-p1.lng),
+	)
+}
+
+// azDistance returns the point at the given azimuth and
+// great circle distance, both in radians, from p1.
+func azDistance(p1 latLng, az, distance float64) latLng {
+	if distance < epsilon {
+		return p1
+	}
+
+	var p2 latLng
+
+	az = posAngle(az)
+	if az < epsilon || math.Abs(az-math.Pi) < epsilon {
+		// due north or south
+		if az < epsilon {
+			p2.lat = p1.lat + distance
+		} else {
+			p2.lat = p1.lat - distance
+		}
+
+		if math.Abs(p2.lat-math.Pi/2) < epsilon {
+			return latLng{math.Pi / 2, 0}
+		} else if math.Abs(p2.lat+math.Pi/2) < epsilon {
+			return latLng{-math.Pi / 2, 0}
+		}
+
+		p2.lng = constrainLng(p1.lng)
+		return p2
+	}
+
+	sinlat := math.Sin(p1.lat)*math.Cos(distance) + math.Cos(p1.lat)*math.Sin(distance)*math.Cos(az)
+	sinlat = math.Max(-1, math.Min(1, sinlat))
+	p2.lat = math.Asin(sinlat)
+
+	if math.Abs(p2.lat-math.Pi/2) < epsilon {
+		return latLng{math.Pi / 2, 0}
+	} else if math.Abs(p2.lat+math.Pi/2) < epsilon {
+		return latLng{-math.Pi / 2, 0}
+	}
+
+	invcosp2lat := 1 / math.Cos(p2.lat)
+	sinlng := math.Sin(az) * math.Sin(distance) * invcosp2lat
+	coslng := (math.Cos(distance) - math.Sin(p1.lat)*math.Sin(p2.lat)) / math.Cos(p1.lat) * invcosp2lat
+	sinlng = math.Max(-1, math.Min(1, sinlng))
+	coslng = math.Max(-1, math.Min(1, coslng))
+
+	p2.lng = constrainLng(p1.lng + math.Atan2(sinlng, coslng))
+	return p2
+}
+
+// geoToHex2d returns the closest face to the point and the point's
+// 2d coordinates on that face at the given resolution.
+func geoToHex2d(g latLng, res int) (int, vec2d) {
+	v3 := toPoint3d(g)
+
+	face := 0
+	sqd := 5.0
+	for f, c := range faceCenterPoint {
+		dx, dy, dz := v3[0]-c[0], v3[1]-c[1], v3[2]-c[2]
+		if d := dx*dx + dy*dy + dz*dz; d < sqd {
+			face = f
+			sqd = d
+		}
+	}
+
+	// cos(r) = 1 - 2 * sin^2(r/2) = 1 - 2 * (sqd / 4) = 1 - sqd/2
+	r := math.Acos(1 - sqd/2)
+	if r < epsilon {
+		return face, vec2d{}
+	}
+
+	// now have face and r, now find CCW theta from CII i-axis
+	theta := posAngle(faceAxesAzRadsCII[face][0] - posAngle(azimuth(faceCenterGeo[face], g)))
+
+	// adjust theta for Class III (odd resolutions)
+	if isClassIII(res) {
+		theta = posAngle(theta - ap7RotRads)
+	}
+
+	// perform gnomonic scaling of r
+	r = math.Tan(r) / res0UGnomonic
+	for i := 0; i < res; i++ {
+		r *= sqrt7
+	}
+
+	return face, vec2d{r * math.Cos(theta), r * math.Sin(theta)}
+}
+
+// hex2dToGeo returns the point of the 2d coordinate on the face.
+// Substrate coordinates are on the grid used for cell vertices.
+func hex2dToGeo(v vec2d, face, res int, substrate bool) latLng {
+	r := math.Hypot(v.x, v.y)
+	if r < epsilon {
+		return faceCenterGeo[face]
+	}
+
+	theta := math.Atan2(v.y, v.x)
+
+	// scale for current resolution length u
+	for i := 0; i < res; i++ {
+		r /= sqrt7
+	}
+
+	// scale accordingly if this is a substrate grid
+	if substrate {
+		r /= 3
+		if isClassIII(res) {
+			r /= sqrt7
+		}
+	}
+
+	r = math.Atan(r * res0UGnomonic)
+
+	// adjust theta for Class III, substrate grid is already adjusted
+	if !substrate && isClassIII(res) {
+		theta = posAngle(theta + ap7RotRads)
+	}
+
+	// find theta as an azimuth
+	theta = posAngle(faceAxesAzRadsCII[face][0] - theta)
+
+	return azDistance(faceCenterGeo[face], theta, r)
+}
+
+func geoToFaceIJK(g latLng, res int) faceIJK {
+	face, v := geoToHex2d(g, res)
+	return faceIJK{face: face, coord: hex2dToCoordIJK(v)}
+}
+
+func (f faceIJK) toGeo(res int) latLng {
+	return hex2dToGeo(f.coord.toHex2d(), f.face, res, false)
+}
+
+// adjustOverageClassII adjusts the coordinate if it is on an adjacent face.
+// Coordinates must be Class II.
+func (f *faceIJK) adjustOverageClassII(res int, pentLeading4, substrate bool) overage {
+	result := noOverage
+
+	maxDim := maxDimByCIIres[res]
+	if substrate {
+		maxDim *= 3
+	}
+
+	sum := f.coord.i + f.coord.j + f.coord.k
+	if substrate && sum == maxDim {
+		return faceEdge
+	}
+
+	if sum > maxDim {
+		result = newFace
+
+		var orient faceOrientIJK
+		if f.coord.k > 0 {
+			if f.coord.j > 0 {
+				orient = faceNeighbors[f.face][quadJK]
+			} else {
+				orient = faceNeighbors[f.face][quadKI]
+
+				// adjust for the pentagonal missing sequence
+				if pentLeading4 {
+					// translate origin to center of pentagon
+					origin := coordIJK{maxDim, 0, 0}
+					tmp := f.coord.sub(origin).rotate60cw()
+					f.coord = tmp.add(origin)
+				}
+			}
+		} else {
+			orient = faceNeighbors[f.face][quadIJ]
+		}
+
+		f.face = orient.face
+
+		// rotate and translate for adjacent face
+		for i := 0; i < orient.ccwRot60; i++ {
+			f.coord = f.coord.rotate60ccw()
+		}
+
+		unitScale := unitScaleByCIIres[res]
+		if substrate {
+			unitScale *= 3
+		}
+
+		f.coord = f.coord.add(orient.translate.scale(unitScale)).normalize()
+
+		// overage points on pentagon boundaries can end up on edges
+		if substrate && f.coord.i+f.coord.j+f.coord.k == maxDim {
+			result = faceEdge
+		}
+	}
+
+	return result
+}
+
+// adjustPentVertOverage adjusts a pentagon vertex until it is on its face.
+func (f *faceIJK) adjustPentVertOverage(res int) {
+	for f.adjustOverageClassII(res, false, true) == newFace {
+	}
+}
+
+var (
+	vertsCII = [6]coordIJK{
+		{2, 1, 0}, {1, 2, 0}, {0, 2, 1}, {0, 1, 2}, {1, 0, 2}, {2, 0, 1},
+	}
+	vertsCIII = [6]coordIJK{
+		{5, 4, 0}, {1, 5, 0}, {0, 5, 4}, {0, 1, 5}, {4, 0, 5}, {5, 0, 1},
+	}
+)
+
+// vertices returns the vertices of the cell, on the substrate grid,
+// and the adjusted resolution.
+func (f faceIJK) vertices(res, count int) ([]faceIJK, int) {
+	verts := vertsCII
+	if isClassIII(res) {
+		verts = vertsCIII
+	}
+
+	// adjust the center point to be in an aperture 33r substrate grid
+	center := f.coord.downAp3().downAp3r()
+
+	// if res is Class III we need to add a cw aperture 7 to get to
+	// icosahedral Class II
+	if isClassIII(res) {
+		center = center.downAp7r()
+		res++
+	}
+
+	result := make([]faceIJK, count)
+	for v := range result {
+		result[v] = faceIJK{face: f.face, coord: center.add(verts[v]).normalize()}
+	}
+
+	return result, res
+}
+
+// edgeVertices returns the substrate coordinates of the icosahedron face
+// edge between the given faces.
+func edgeVertices(dir, adjRes int) (vec2d, vec2d) {
+	maxDim := float64(maxDimByCIIres[adjRes])
+	v0 := vec2d{3 * maxDim, 0}
+	v1 := vec2d{-1.5 * maxDim, 3 * sqrt3over2 * maxDim}
+	v2 := vec2d{-1.5 * maxDim, -3 * sqrt3over2 * maxDim}
+
+	switch dir {
+	case quadIJ:
+		return v0, v1
+	case quadJK:
+		return v1, v2
+	}
+
+	return v2, v0
+}
+
+// boundary returns the vertices of a hexagon cell. Class III cells that
+// cross an icosahedron edge have an extra vertex where they cross.
+func (f faceIJK) boundary(res int) []latLng {
+	verts, adjRes := f.vertices(res, 6)
+
+	var result []latLng
+	lastFace := -1
+	lastOverage := noOverage
+
+	// the extra iteration checks for an intersection on the last edge
+	for vert := 0; vert < 7; vert++ {
+		v := vert % 6
+		fijk := verts[v]
+
+		ov := fijk.adjustOverageClassII(adjRes, false, true)
+
+		// Check for edge-crossing. Each face of the underlying icosahedron
+		// is a different projection plane. So if an edge of the hexagon
+		// crosses an icosahedron edge, an additional vertex must be
+		// introduced at that intersection point.
+		if isClassIII(res) && vert > 0 && fijk.face != lastFace && lastOverage != faceEdge {
+			// find hex2d of the two vertexes on original face
+			lastV := (v + 5) % 6
+			orig0 := verts[lastV].coord.toHex2d()
+			orig1 := verts[v].coord.toHex2d()
+
+			face2 := lastFace
+			if lastFace == f.face {
+				face2 = fijk.face
+			}
+
+			e0, e1 := edgeVertices(adjacentFaceDir[f.face][face2], adjRes)
+			inter := intersect(orig0, orig1, e0, e1)
+
+			// If a point of intersection occurs at a hexagon vertex, then
+			// each adjacent hexagon edge will lie completely on a single
+			// icosahedron face, and no additional vertex is required.
+			if !orig0.almostEqual(inter) && !orig1.almostEqual(inter) {
+				result = append(result, hex2dToGeo(inter, f.face, adjRes, true))
+			}
+		}
+
+		if vert < 6 {
+			result = append(result, hex2dToGeo(fijk.coord.toHex2d(), fijk.face, adjRes, true))
+		}
+
+		lastFace = fijk.face
+		lastOverage = ov
+	}
+
+	return result
+}
+
+// pentagonBoundary returns the vertices of a pentagon cell. All Class III
+// pentagon edges cross icosahedron edges.
+func (f faceIJK) pentagonBoundary(res int) []latLng {
+	verts, adjRes := f.vertices(res, 5)
+
+	var result []latLng
+	var last faceIJK
+
+	for vert := 0; vert < 6; vert++ {
+		v := vert % 5
+		fijk := verts[v]
+		fijk.adjustPentVertOverage(adjRes)
+
+		if isClassIII(res) && vert > 0 {
+			// find hex2d of the two vertexes on the last face
+			orig0 := last.coord.toHex2d()
+
+			tmp := fijk
+			orient := faceNeighbors[tmp.face][adjacentFaceDir[tmp.face][last.face]]
+			tmp.face = orient.face
+
+			// rotate and translate for adjacent face
+			for i := 0; i < orient.ccwRot60; i++ {
+				tmp.coord = tmp.coord.rotate60ccw()
+			}
+
+			trans := orient.translate.scale(unitScaleByCIIres[adjRes] * 3)
+			tmp.coord = tmp.coord.add(trans).normalize()
+			orig1 := tmp.coord.toHex2d()
+
+			e0, e1 := edgeVertices(adjacentFaceDir[tmp.face][fijk.face], adjRes)
+			inter := intersect(orig0, orig1, e0, e1)
+			result = append(result, hex2dToGeo(inter, tmp.face, adjRes, true))
+		}
+
+		if vert < 5 {
+			result = append(result, hex2dToGeo(fijk.coord.toHex2d(), fijk.face, adjRes, true))
+		}
+
+		last = fijk
+	}
+
+	return result
+}
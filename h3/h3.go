@@ -0,0 +1,440 @@
+// Portions of this file are ported from H3, https://github.com/uber/h3,
+// and modified.
+//
+// Copyright 2016-2021 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package h3 implements the H3 hexagonal hierarchical grid. Cell ids
+// are compatible with the reference implementation, https://h3geo.org.
+package h3
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/dadadamarine/orb"
+)
+
+// ErrInvalidCell is returned when parsing a string that is not a valid cell id.
+var ErrInvalidCell = errors.New("h3: invalid cell")
+
+// MaxResolution is the finest resolution of the grid.
+const MaxResolution = 15
+
+// A Cell is an H3 cell index. The zero value is not a valid cell.
+type Cell uint64
+
+const (
+	cellMode = 1
+
+	modeOffset     = 59
+	resOffset      = 52
+	baseCellOffset = 45
+	digitBits      = 3
+
+	modeMask     = uint64(15) << modeOffset
+	resMask      = uint64(15) << resOffset
+	baseCellMask = uint64(127) << baseCellOffset
+	reservedMask = uint64(7) << 56
+	highBitMask  = uint64(1) << 63
+	digitMask    = uint64(7)
+
+	// initCell is a resolution 0 cell with all digits unused.
+	initCell = Cell(uint64(cellMode)<<modeOffset | 0x1fffffffffff)
+)
+
+// At returns the cell, at the given resolution, containing the point.
+// Returns 0, an invalid cell, for resolutions outside [0, MaxResolution].
+func At(p orb.Point, res int) Cell {
+	if res < 0 || res > MaxResolution || math.IsNaN(p[0]) || math.IsNaN(p[1]) {
+		return 0
+	}
+
+	g := latLng{lat: deg2rad(p[1]), lng: deg2rad(p[0])}
+	return faceIJKToCell(geoToFaceIJK(g, res), res)
+}
+
+// Parse parses the hexadecimal string representation of a cell.
+func Parse(s string) (Cell, error) {
+	v, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, ErrInvalidCell
+	}
+
+	c := Cell(v)
+	if !c.Valid() {
+		return 0, ErrInvalidCell
+	}
+
+	return c, nil
+}
+
+// String returns the hexadecimal representation of the cell.
+func (c Cell) String() string {
+	return strconv.FormatUint(uint64(c), 16)
+}
+
+// Resolution returns the resolution of the cell.
+func (c Cell) Resolution() int {
+	return int((uint64(c) & resMask) >> resOffset)
+}
+
+// BaseCell returns the resolution 0 cell number, [0, 121], of the cell.
+func (c Cell) BaseCell() int {
+	return int((uint64(c) & baseCellMask) >> baseCellOffset)
+}
+
+// Valid returns if the cell is a valid H3 cell index.
+func (c Cell) Valid() bool {
+	if uint64(c)&highBitMask != 0 ||
+		(uint64(c)&modeMask)>>modeOffset != cellMode ||
+		uint64(c)&reservedMask != 0 {
+		return false
+	}
+
+	bc := c.BaseCell()
+	if bc >= numBaseCells {
+		return false
+	}
+
+	res := c.Resolution()
+	foundFirst := false
+	for r := 1; r <= MaxResolution; r++ {
+		d := c.digit(r)
+		if r > res {
+			if d != invalidDigit {
+				return false
+			}
+			continue
+		}
+
+		if d == invalidDigit {
+			return false
+		}
+
+		if !foundFirst && d != centerDigit {
+			foundFirst = true
+			if isBaseCellPentagon(bc) && d == kAxesDigit {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Pentagon returns true if the cell is one of the 12 pentagons
+// at each resolution.
+func (c Cell) Pentagon() bool {
+	return isBaseCellPentagon(c.BaseCell()) && c.leadingNonZeroDigit() == centerDigit
+}
+
+// Center returns the center of the cell.
+func (c Cell) Center() orb.Point {
+	return c.faceIJK().toGeo(c.Resolution()).point()
+}
+
+// Boundary returns the boundary of the cell as a closed, counter-clockwise
+// polygon. Cells crossing an icosahedron edge at Class III (odd)
+// resolutions have extra vertices where the edge distorts them.
+// Cells crossing the antimeridian are not split and will have
+// longitudes on both sides.
+func (c Cell) Boundary() orb.Polygon {
+	res := c.Resolution()
+	f := c.faceIJK()
+
+	var verts []latLng
+	if c.Pentagon() {
+		verts = f.pentagonBoundary(res)
+	} else {
+		verts = f.boundary(res)
+	}
+
+	ring := make(orb.Ring, 0, len(verts)+1)
+	for _, v := range verts {
+		ring = append(ring, v.point())
+	}
+	ring = append(ring, ring[0])
+
+	return orb.Polygon{ring}
+}
+
+// Parent returns the containing cell at the given, coarser, resolution.
+// Returns 0, an invalid cell, if the resolution is finer than the cell's.
+func (c Cell) Parent(res int) Cell {
+	cur := c.Resolution()
+	if res < 0 || res > cur {
+		return 0
+	}
+
+	p := c.setResolution(res)
+	for r := res + 1; r <= cur; r++ {
+		p = p.setDigit(r, invalidDigit)
+	}
+
+	return p
+}
+
+// Children returns the cells, at the given finer resolution, whose
+// parent is this cell. Returns nil if the resolution is not finer.
+func (c Cell) Children(res int) []Cell {
+	cur := c.Resolution()
+	if res <= cur || res > MaxResolution {
+		return nil
+	}
+
+	n := 1
+	for r := cur; r < res; r++ {
+		n *= 7
+	}
+
+	result := make([]Cell, 0, n)
+	return c.appendChildren(result, res)
+}
+
+func (c Cell) appendChildren(result []Cell, res int) []Cell {
+	cur := c.Resolution()
+	if cur == res {
+		return append(result, c)
+	}
+
+	pentagon := c.Pentagon()
+
+	child := c.setResolution(cur + 1)
+	for d := centerDigit; d < invalidDigit; d++ {
+		// pentagons are missing the k axes sub-sequence
+		if pentagon && d == kAxesDigit {
+			continue
+		}
+
+		result = child.setDigit(cur+1, d).appendChildren(result, res)
+	}
+
+	return result
+}
+
+func (c Cell) setResolution(res int) Cell {
+	return Cell(uint64(c)&^resMask | uint64(res)<<resOffset)
+}
+
+func (c Cell) setBaseCell(bc int) Cell {
+	return Cell(uint64(c)&^baseCellMask | uint64(bc)<<baseCellOffset)
+}
+
+func (c Cell) digit(r int) digit {
+	return digit((uint64(c) >> ((MaxResolution - r) * digitBits)) & digitMask)
+}
+
+func (c Cell) setDigit(r int, d digit) Cell {
+	shift := uint((MaxResolution - r) * digitBits)
+	return Cell(uint64(c)&^(digitMask<<shift) | uint64(d)<<shift)
+}
+
+// leadingNonZeroDigit returns the first non-zero digit, or centerDigit.
+func (c Cell) leadingNonZeroDigit() digit {
+	res := c.Resolution()
+	for r := 1; r <= res; r++ {
+		if d := c.digit(r); d != centerDigit {
+			return d
+		}
+	}
+
+	return centerDigit
+}
+
+func (c Cell) rotate60ccw() Cell {
+	res := c.Resolution()
+	for r := 1; r <= res; r++ {
+		c = c.setDigit(r, c.digit(r).rotate60ccw())
+	}
+
+	return c
+}
+
+func (c Cell) rotate60cw() Cell {
+	res := c.Resolution()
+	for r := 1; r <= res; r++ {
+		c = c.setDigit(r, c.digit(r).rotate60cw())
+	}
+
+	return c
+}
+
+// rotatePent60ccw rotates a pentagon cell, skipping over
+// the deleted k axes sub-sequence.
+func (c Cell) rotatePent60ccw() Cell {
+	res := c.Resolution()
+	foundFirst := false
+	for r := 1; r <= res; r++ {
+		c = c.setDigit(r, c.digit(r).rotate60ccw())
+
+		if !foundFirst && c.digit(r) != centerDigit {
+			foundFirst = true
+
+			// adjust for deleted k-axes sequence
+			if c.leadingNonZeroDigit() == kAxesDigit {
+				c = c.rotate60ccw()
+			}
+		}
+	}
+
+	return c
+}
+
+// faceIJKToCell returns the cell of the face coordinates at the given
+// resolution. Returns 0 if the coordinates are not near the face.
+func faceIJKToCell(f faceIJK, res int) Cell {
+	c := initCell.setResolution(res)
+
+	if res == 0 {
+		if f.coord.i > 2 || f.coord.j > 2 || f.coord.k > 2 {
+			return 0
+		}
+
+		return c.setBaseCell(faceIJKToBaseCell(f))
+	}
+
+	// build the index from finest resolution up
+	for r := res - 1; r >= 0; r-- {
+		last := f.coord
+
+		var lastCenter coordIJK
+		if isClassIII(r + 1) {
+			f.coord = f.coord.upAp7()
+			lastCenter = f.coord.downAp7()
+		} else {
+			f.coord = f.coord.upAp7r()
+			lastCenter = f.coord.downAp7r()
+		}
+
+		c = c.setDigit(r+1, last.sub(lastCenter).normalize().toDigit())
+	}
+
+	// f is now the base cell coordinate
+	if f.coord.i > 2 || f.coord.j > 2 || f.coord.k > 2 {
+		return 0
+	}
+
+	bc := faceIJKToBaseCell(f)
+	c = c.setBaseCell(bc)
+
+	// rotate into the base cell's home coordinate system
+	rots := faceIJKToBaseCellCCWrot60(f)
+	if isBaseCellPentagon(bc) {
+		// force rotation out of missing k-axes sub-sequence
+		if c.leadingNonZeroDigit() == kAxesDigit {
+			if baseCellIsCwOffset(bc, f.face) {
+				c = c.rotate60cw()
+			} else {
+				c = c.rotate60ccw()
+			}
+		}
+
+		for i := 0; i < rots; i++ {
+			c = c.rotatePent60ccw()
+		}
+	} else {
+		for i := 0; i < rots; i++ {
+			c = c.rotate60ccw()
+		}
+	}
+
+	return c
+}
+
+// faceIJK returns the coordinates of the cell on its home face,
+// or on the face it overflows onto.
+func (c Cell) faceIJK() faceIJK {
+	bc := c.BaseCell()
+
+	// adjust for the pentagonal missing sequence, all of sub-sequence
+	// 5 needs to be rotated into sub-sequence 6.
+	if isBaseCellPentagon(bc) && c.leadingNonZeroDigit() == ikAxesDigit {
+		c = c.rotate60cw()
+	}
+
+	f := baseCells[bc].homeFijk
+	if !c.toFaceIJKFrom(&f) {
+		// no overage is possible
+		return f
+	}
+
+	// if we're here we have the potential for an "overage", i.e. it is
+	// possible that the cell lies on an adjacent face.
+	orig := f.coord
+
+	res := c.Resolution()
+	if isClassIII(res) {
+		// the overage algorithm requires Class II
+		f.coord = f.coord.downAp7r()
+		res++
+	}
+
+	// adjust for overage if needed, a pentagon base cell with a leading
+	// 4 digit requires special handling.
+	pentLeading4 := isBaseCellPentagon(bc) && c.leadingNonZeroDigit() == iAxesDigit
+	if f.adjustOverageClassII(res, pentLeading4, false) != noOverage {
+		// if the base cell is a pentagon we have the potential for
+		// secondary overages
+		if isBaseCellPentagon(bc) {
+			for f.adjustOverageClassII(res, false, false) != noOverage {
+			}
+		}
+
+		if res != c.Resolution() {
+			f.coord = f.coord.upAp7r()
+		}
+	} else if res != c.Resolution() {
+		f.coord = orig
+	}
+
+	return f
+}
+
+// toFaceIJKFrom walks the digits of the cell from the base cell coordinates
+// in f. Returns false if the cell can not be on an adjacent face.
+func (c Cell) toFaceIJKFrom(f *faceIJK) bool {
+	res := c.Resolution()
+
+	// center base cell hierarchy is entirely on this face
+	possibleOverage := true
+	if !isBaseCellPentagon(c.BaseCell()) && (res == 0 || f.coord == coordIJK{}) {
+		possibleOverage = false
+	}
+
+	for r := 1; r <= res; r++ {
+		if isClassIII(r) {
+			f.coord = f.coord.downAp7()
+		} else {
+			f.coord = f.coord.downAp7r()
+		}
+
+		f.coord = f.coord.neighbor(c.digit(r))
+	}
+
+	return possibleOverage
+}
+
+func (g latLng) point() orb.Point {
+	return orb.Point{rad2deg(g.lng), rad2deg(g.lat)}
+}
+
+func deg2rad(d float64) float64 {
+	return d * math.Pi / 180
+}
+
+func rad2deg(r float64) float64 {
+	return r * 180 / math.Pi
+}
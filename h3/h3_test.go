@@ -0,0 +1,270 @@
+package h3
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/planar"
+)
+
+func TestAt(t *testing.T) {
+	cases := []struct {
+		name   string
+		point  orb.Point
+		res    int
+		result string
+	}{
+		{
+			name:   "sunnyvale",
+			point:  orb.Point{-122.0553238, 37.3615593},
+			res:    7,
+			result: "87283472bffffff",
+		},
+		{
+			name:   "sunnyvale res 5",
+			point:  orb.Point{-122.0553238, 37.3615593},
+			res:    5,
+			result: "85283473fffffff",
+		},
+		{
+			name:   "san francisco",
+			point:  orb.Point{-122.41795063018799, 37.775938728915946},
+			res:    9,
+			result: "8928308280fffff",
+		},
+		{
+			name:   "statue of liberty",
+			point:  orb.Point{-74.044444, 40.689167},
+			res:    10,
+			result: "8a2a1072b59ffff",
+		},
+		{
+			name:   "origin",
+			point:  orb.Point{0, 0},
+			res:    0,
+			result: "8075fffffffffff",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := At(tc.point, tc.res)
+			if v := c.String(); v != tc.result {
+				t.Errorf("incorrect cell: %v != %v", v, tc.result)
+			}
+
+			if !c.Valid() {
+				t.Errorf("should be valid")
+			}
+
+			if r := c.Resolution(); r != tc.res {
+				t.Errorf("incorrect resolution: %v != %v", r, tc.res)
+			}
+		})
+	}
+
+	if c := At(orb.Point{1, 2}, MaxResolution+1); c != 0 {
+		t.Errorf("invalid resolution should be zero: %v", c)
+	}
+}
+
+func TestParse(t *testing.T) {
+	c, err := Parse("8928308280fffff")
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	if c != 0x8928308280fffff {
+		t.Errorf("incorrect cell: %x", uint64(c))
+	}
+
+	if c.BaseCell() != 20 {
+		t.Errorf("incorrect base cell: %v", c.BaseCell())
+	}
+
+	invalid := []string{
+		"",
+		"not hex",
+		"0",
+		"8928308280fff0f",  // digit beyond resolution set
+		"8f28308280fffff",  // unused digit for resolution 15
+		"80f5fffffffffff",  // base cell 122
+		"81087ffffffffff",  // deleted pentagon k sub-sequence
+		"18928308280fffff", // high bit
+	}
+
+	for _, s := range invalid {
+		if _, err := Parse(s); err != ErrInvalidCell {
+			t.Errorf("%q should be invalid: %v", s, err)
+		}
+	}
+}
+
+func TestCell_Center(t *testing.T) {
+	c := mustParse(t, "85283473fffffff")
+
+	center := c.Center()
+	expected := orb.Point{-121.97637597255124, 37.34579337536848}
+	if !pointEqual(center, expected) {
+		t.Errorf("incorrect center: %v != %v", center, expected)
+	}
+}
+
+func TestCell_Boundary(t *testing.T) {
+	c := mustParse(t, "85283473fffffff")
+
+	ring := c.Boundary()[0]
+	if len(ring) != 7 {
+		t.Fatalf("incorrect number of points: %v", len(ring))
+	}
+
+	expected := orb.Point{-121.91508032705622, 37.271355866731895}
+	if !pointEqual(ring[0], expected) {
+		t.Errorf("incorrect first vertex: %v != %v", ring[0], expected)
+	}
+
+	if ring[0] != ring[len(ring)-1] {
+		t.Errorf("ring should be closed")
+	}
+
+	if o := ring.Orientation(); o != orb.CCW {
+		t.Errorf("ring should be counter-clockwise: %v", o)
+	}
+
+	if !planar.RingContains(ring, c.Center()) {
+		t.Errorf("boundary should contain center")
+	}
+
+	// pentagons crossing icosahedron edges
+	p := mustParse(t, "81083ffffffffff").Parent(0)
+	if l := len(p.Boundary()[0]); l != 6 {
+		t.Errorf("class II pentagon should have 5 vertices: %v", l-1)
+	}
+
+	p = p.Children(1)[0]
+	if l := len(p.Boundary()[0]); l != 11 {
+		t.Errorf("class III pentagon should have 10 vertices: %v", l-1)
+	}
+}
+
+func TestCell_Pentagon(t *testing.T) {
+	for res := 0; res <= 2; res++ {
+		count := 0
+		for _, c := range allCells(res) {
+			if c.Pentagon() {
+				count++
+			}
+		}
+
+		if count != 12 {
+			t.Errorf("res %d: incorrect number of pentagons: %v", res, count)
+		}
+	}
+}
+
+func TestCell_Parent(t *testing.T) {
+	c := At(orb.Point{-122.0553238, 37.3615593}, 7)
+
+	if p := c.Parent(5); p.String() != "85283473fffffff" {
+		t.Errorf("incorrect parent: %v", p)
+	}
+
+	if p := c.Parent(7); p != c {
+		t.Errorf("parent at same resolution should be the cell: %v", p)
+	}
+
+	if p := c.Parent(8); p != 0 {
+		t.Errorf("finer resolution should be invalid: %v", p)
+	}
+}
+
+func TestCell_Children(t *testing.T) {
+	c := mustParse(t, "85283473fffffff")
+
+	children := c.Children(7)
+	if len(children) != 49 {
+		t.Errorf("incorrect number of children: %v", len(children))
+	}
+
+	for _, child := range children {
+		if !child.Valid() {
+			t.Errorf("invalid child: %v", child)
+		}
+
+		if p := child.Parent(5); p != c {
+			t.Errorf("incorrect parent: %v != %v", p, c)
+		}
+	}
+
+	// the center child is at the same position as its parent
+	if !pointEqual(children[0].Center(), c.Center()) {
+		t.Errorf("center child should be at the parent center: %v", children[0])
+	}
+
+	pent := initCell.setBaseCell(4)
+	if l := len(pent.Children(2)); l != 1+5+5*7 {
+		t.Errorf("incorrect number of pentagon children: %v", l)
+	}
+
+	if l := len(c.Children(5)); l != 0 {
+		t.Errorf("same resolution should have no children: %v", l)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for res := 0; res <= 2; res++ {
+		for _, c := range allCells(res) {
+			if !c.Valid() {
+				t.Fatalf("invalid cell: %v", c)
+			}
+
+			if v := At(c.Center(), res); v != c {
+				t.Errorf("center in wrong cell: %v != %v", v, c)
+			}
+		}
+	}
+}
+
+func TestSet_ToFeatureCollection(t *testing.T) {
+	c := At(orb.Point{-122.41795063018799, 37.775938728915946}, 9)
+	set := Set{c: true, c.Parent(8): false}
+
+	fc := set.ToFeatureCollection()
+	if len(fc.Features) != 1 {
+		t.Fatalf("incorrect number of features: %v", len(fc.Features))
+	}
+
+	if v := fc.Features[0].Properties["h3"]; v != c.String() {
+		t.Errorf("incorrect property: %v", v)
+	}
+}
+
+func allCells(res int) []Cell {
+	var result []Cell
+	for bc := 0; bc < numBaseCells; bc++ {
+		c := initCell.setBaseCell(bc)
+		if res == 0 {
+			result = append(result, c)
+		} else {
+			result = append(result, c.Children(res)...)
+		}
+	}
+
+	return result
+}
+
+func mustParse(t testing.TB, s string) Cell {
+	t.Helper()
+
+	c, err := Parse(s)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	return c
+}
+
+func pointEqual(p1, p2 orb.Point) bool {
+	return math.Abs(p1[0]-p2[0]) < 1e-9 && math.Abs(p1[1]-p2[1]) < 1e-9
+}
@@ -0,0 +1,62 @@
+package h3
+
+// Neighbors returns the cells sharing an edge with the cell,
+// 6 for hexagons and 5 for pentagons.
+func (c Cell) Neighbors() []Cell {
+	res := c.Resolution()
+	f := c.faceIJK()
+
+	// The neighbors are found by locating the center of the adjacent
+	// cell in each direction on the face's grid. Near pentagons two
+	// directions can find the same cell.
+	result := make([]Cell, 0, 6)
+	for d := kAxesDigit; d < invalidDigit; d++ {
+		v := f.coord.add(unitVecs[d]).toHex2d()
+		n := At(hex2dToGeo(v, f.face, res, false).point(), res)
+
+		if n == c || n == 0 || containsCell(result, n) {
+			continue
+		}
+
+		result = append(result, n)
+	}
+
+	return result
+}
+
+// KRing returns the cells within k steps of the cell, ordered by distance.
+// The first cell is the cell itself.
+func (c Cell) KRing(k int) []Cell {
+	result := []Cell{c}
+	seen := map[Cell]bool{c: true}
+
+	ring := result
+	for i := 0; i < k; i++ {
+		var next []Cell
+		for _, r := range ring {
+			for _, n := range r.Neighbors() {
+				if seen[n] {
+					continue
+				}
+
+				seen[n] = true
+				next = append(next, n)
+			}
+		}
+
+		result = append(result, next...)
+		ring = next
+	}
+
+	return result
+}
+
+func containsCell(cells []Cell, c Cell) bool {
+	for _, v := range cells {
+		if v == c {
+			return true
+		}
+	}
+
+	return false
+}
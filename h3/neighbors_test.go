@@ -0,0 +1,61 @@
+package h3
+
+import (
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestCell_Neighbors(t *testing.T) {
+	for res := 0; res <= 2; res++ {
+		for _, c := range allCells(res) {
+			ns := c.Neighbors()
+
+			expected := 6
+			if c.Pentagon() {
+				expected = 5
+			}
+
+			if len(ns) != expected {
+				t.Errorf("%v: incorrect number of neighbors: %v != %v", c, len(ns), expected)
+			}
+
+			for _, n := range ns {
+				if !containsCell(n.Neighbors(), c) {
+					t.Errorf("%v: neighbor %v should be a neighbor back", c, n)
+				}
+			}
+		}
+	}
+}
+
+func TestCell_KRing(t *testing.T) {
+	c := At(orb.Point{-122.41795063018799, 37.775938728915946}, 9)
+
+	cases := []struct {
+		k      int
+		length int
+	}{
+		{k: 0, length: 1},
+		{k: 1, length: 7},
+		{k: 2, length: 19},
+		{k: 3, length: 37},
+	}
+
+	for _, tc := range cases {
+		ring := c.KRing(tc.k)
+		if len(ring) != tc.length {
+			t.Errorf("k %d: incorrect length: %v != %v", tc.k, len(ring), tc.length)
+		}
+
+		if ring[0] != c {
+			t.Errorf("k %d: first cell should be the origin: %v", tc.k, ring[0])
+		}
+	}
+
+	// pentagons have one less neighbor at each distance
+	pent := initCell.setBaseCell(4).Children(2)[0]
+	if l := len(pent.KRing(2)); l != 16 {
+		t.Errorf("incorrect pentagon k-ring length: %v", l)
+	}
+}
@@ -0,0 +1,46 @@
+package h3
+
+import (
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/planar"
+)
+
+// Polyfill returns the cells, at the given resolution, whose centers are
+// within the polygon. Coordinates are treated as planar lon/lat values,
+// polygons crossing the antimeridian should be split first.
+func Polyfill(p orb.Polygon, res int) Set {
+	set := make(Set)
+	if len(p) == 0 || len(p[0]) == 0 || res < 0 || res > MaxResolution {
+		return set
+	}
+
+	b := p.Bound()
+
+	// The cells overlapping the bound are a connected region that
+	// includes the cell of every polygon vertex.
+	start := At(p[0][0], res)
+	queue := []Cell{start}
+	seen := map[Cell]bool{start: true}
+
+	for len(queue) > 0 {
+		c := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		if planar.PolygonContains(p, c.Center()) {
+			set[c] = true
+		}
+
+		for _, n := range c.Neighbors() {
+			if seen[n] {
+				continue
+			}
+
+			seen[n] = true
+			if n.Boundary().Bound().Intersects(b) {
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	return set
+}
@@ -0,0 +1,69 @@
+package h3
+
+import (
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/planar"
+)
+
+func TestPolyfill(t *testing.T) {
+	poly := orb.Polygon{
+		{
+			{-122.4089866999972145, 37.813318999983238},
+			{-122.3805436999997056, 37.7866302000007224},
+			{-122.3544736999993603, 37.7198061999978478},
+			{-122.5123436999983966, 37.7076131999975672},
+			{-122.5247187000021967, 37.7835871999971715},
+			{-122.4798767000009008, 37.8151571999998453},
+			{-122.4089866999972145, 37.813318999983238},
+		},
+	}
+
+	set := Polyfill(poly, 7)
+	if len(set) < 20 {
+		t.Errorf("should have cells: %v", len(set))
+	}
+
+	for c := range set {
+		if !planar.PolygonContains(poly, c.Center()) {
+			t.Errorf("center of %v should be in polygon", c)
+		}
+	}
+
+	// sample the polygon to find all the cells with a center inside
+	b := poly.Bound()
+	expected := make(Set)
+	for x := b.Min[0]; x <= b.Max[0]; x += 0.001 {
+		for y := b.Min[1]; y <= b.Max[1]; y += 0.001 {
+			c := At(orb.Point{x, y}, 7)
+			if planar.PolygonContains(poly, c.Center()) {
+				expected[c] = true
+			}
+		}
+	}
+
+	if len(set) != len(expected) {
+		t.Errorf("incorrect number of cells: %v != %v", len(set), len(expected))
+	}
+
+	for c := range expected {
+		if !set[c] {
+			t.Errorf("missing cell: %v", c)
+		}
+	}
+
+	// with a hole
+	poly = append(poly, orb.Ring{
+		{-122.46, 37.74}, {-122.42, 37.74}, {-122.42, 37.78}, {-122.46, 37.78}, {-122.46, 37.74},
+	})
+
+	holed := Polyfill(poly, 7)
+	if len(holed) >= len(set) {
+		t.Errorf("hole should remove cells: %v >= %v", len(holed), len(set))
+	}
+
+	if l := len(Polyfill(orb.Polygon{}, 7)); l != 0 {
+		t.Errorf("empty polygon should have no cells: %v", l)
+	}
+}
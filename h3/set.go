@@ -0,0 +1,36 @@
+package h3
+
+import (
+	"github.com/dadadamarine/orb/geojson"
+)
+
+// Set is a map/hash of cells.
+type Set map[Cell]bool
+
+// ToFeatureCollection converts a set of cells into a feature collection.
+// Each feature has an "h3" property with the cell id. Invalid cells are skipped.
+// This method is mostly useful for debugging output.
+func (s Set) ToFeatureCollection() *geojson.FeatureCollection {
+	fc := geojson.NewFeatureCollection()
+	fc.Features = make([]*geojson.Feature, 0, len(s))
+	for c, v := range s {
+		if !v || !c.Valid() {
+			continue
+		}
+
+		f := geojson.NewFeature(c.Boundary())
+		f.Properties["h3"] = c.String()
+		fc.Append(f)
+	}
+
+	return fc
+}
+
+// Merge will merge the given set into the existing set.
+func (s Set) Merge(set Set) {
+	for c, v := range set {
+		if v {
+			s[c] = true
+		}
+	}
+}
@@ -0,0 +1,255 @@
+// Package topology computes the arcs shared by a set of line strings and rings.
+// Lines are split at the points where they meet or diverge, and the resulting
+// pieces, the arcs, are deduplicated so each shared boundary is stored once.
+package topology
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/dadadamarine/orb"
+)
+
+// A Topology collects the parts, line strings and rings, and computes their arcs.
+type Topology struct {
+	parts []*part
+	arcs  []orb.LineString
+	index map[string]int
+}
+
+// a part is a line string or ring that is made of one or more arcs.
+type part struct {
+	line orb.LineString
+	ring bool
+	arcs []int
+}
+
+// New creates a new empty topology.
+func New() *Topology {
+	return &Topology{
+		index: make(map[string]int),
+	}
+}
+
+// AddLineString adds the line string as a part and returns its index.
+func (t *Topology) AddLineString(ls orb.LineString) int {
+	return t.addPart(ls, false)
+}
+
+// AddRing adds the ring as a part and returns its index.
+// The ring will be closed if it's not already.
+func (t *Topology) AddRing(r orb.Ring) int {
+	return t.addPart(orb.LineString(r), true)
+}
+
+func (t *Topology) addPart(ls orb.LineString, ring bool) int {
+	line := make(orb.LineString, 0, len(ls)+1)
+	for i, p := range ls {
+		if i == 0 || !p.Equal(line[len(line)-1]) {
+			line = append(line, p)
+		}
+	}
+
+	if ring && len(line) > 0 && !line[0].Equal(line[len(line)-1]) {
+		line = append(line, line[0])
+	}
+
+	t.parts = append(t.parts, &part{line: line, ring: ring})
+	return len(t.parts) - 1
+}
+
+// Build computes the arcs of all the parts added so far.
+func (t *Topology) Build() {
+	t.arcs = nil
+	t.index = make(map[string]int)
+
+	t.cut(t.junctions())
+}
+
+// Arcs returns the deduplicated arcs. Build must be called first.
+func (t *Topology) Arcs() []orb.LineString {
+	return t.arcs
+}
+
+// Part returns the arcs that make up the part with the given index.
+// A negative value is the one's complement of the arc index and means
+// the arc is used in reverse. Build must be called first.
+func (t *Topology) Part(i int) []int {
+	return t.parts[i].arcs
+}
+
+// IsRing returns true if the part with the given index is a ring.
+func (t *Topology) IsRing(i int) bool {
+	return t.parts[i].ring
+}
+
+// NumParts returns the number of parts added.
+func (t *Topology) NumParts() int {
+	return len(t.parts)
+}
+
+// junctions finds the points where lines meet or split. These are the points
+// that have different neighbors in different parts and the line endpoints.
+func (t *Topology) junctions() map[orb.Point]bool {
+	type neighbors struct {
+		prev, next orb.Point
+	}
+
+	seen := make(map[orb.Point]neighbors)
+	junctions := make(map[orb.Point]bool)
+
+	visit := func(p, prev, next orb.Point) {
+		n, ok := seen[p]
+		if !ok {
+			seen[p] = neighbors{prev: prev, next: next}
+			return
+		}
+
+		if (n.prev == prev && n.next == next) || (n.prev == next && n.next == prev) {
+			return
+		}
+
+		junctions[p] = true
+	}
+
+	for _, p := range t.parts {
+		if len(p.line) == 0 {
+			continue
+		}
+
+		if p.ring {
+			points := p.line[:len(p.line)-1]
+			n := len(points)
+			for i := range points {
+				visit(points[i], points[(i+n-1)%n], points[(i+1)%n])
+			}
+			continue
+		}
+
+		junctions[p.line[0]] = true
+		junctions[p.line[len(p.line)-1]] = true
+		for i := 1; i < len(p.line)-1; i++ {
+			visit(p.line[i], p.line[i-1], p.line[i+1])
+		}
+	}
+
+	return junctions
+}
+
+// cut splits every part at the junctions and dedupes the resulting arcs.
+func (t *Topology) cut(junctions map[orb.Point]bool) {
+	for _, p := range t.parts {
+		p.arcs = nil
+		if len(p.line) == 0 {
+			continue
+		}
+
+		line := p.line
+		if p.ring {
+			points := p.line[:len(p.line)-1]
+
+			start := -1
+			for i, pt := range points {
+				if junctions[pt] {
+					start = i
+					break
+				}
+			}
+
+			if start == -1 {
+				// An isolated ring, rotate to start at the smallest point
+				// so that the same ring in other parts can be found.
+				start = 0
+				for i, pt := range points {
+					if pt[0] < points[start][0] || (pt[0] == points[start][0] && pt[1] < points[start][1]) {
+						start = i
+					}
+				}
+			}
+
+			line = make(orb.LineString, 0, len(p.line))
+			line = append(line, points[start:]...)
+			line = append(line, points[:start]...)
+			line = append(line, points[start])
+		}
+
+		if len(line) == 1 {
+			line = append(line, line[0])
+		}
+
+		s := 0
+		for i := 1; i < len(line); i++ {
+			if i == len(line)-1 || junctions[line[i]] {
+				p.arcs = append(p.arcs, t.addArc(line[s:i+1]))
+				s = i
+			}
+		}
+	}
+}
+
+// addArc returns the index of the arc, adding it if it's new.
+// If the arc exists in the reverse direction the one's complement
+// of that index is returned.
+func (t *Topology) addArc(ls orb.LineString) int {
+	key := arcKey(ls, false)
+	if i, ok := t.index[key]; ok {
+		return i
+	}
+
+	if i, ok := t.index[arcKey(ls, true)]; ok {
+		return ^i
+	}
+
+	i := len(t.arcs)
+	t.index[key] = i
+	t.arcs = append(t.arcs, ls)
+
+	return i
+}
+
+func arcKey(ls orb.LineString, reverse bool) string {
+	buf := make([]byte, 16*len(ls))
+	for i := range ls {
+		p := ls[i]
+		if reverse {
+			p = ls[len(ls)-1-i]
+		}
+
+		binary.LittleEndian.PutUint64(buf[16*i:], math.Float64bits(p[0]))
+		binary.LittleEndian.PutUint64(buf[16*i+8:], math.Float64bits(p[1]))
+	}
+
+	return string(buf)
+}
+
+// Stitch joins the arcs into one line string. The first point of
+// every arc after the first is dropped since it equals the previous end.
+// It will return false if an index is out of range.
+func Stitch(arcs []orb.LineString, indexes []int) (orb.LineString, bool) {
+	var result orb.LineString
+	for i, index := range indexes {
+		reverse := false
+		if index < 0 {
+			index = ^index
+			reverse = true
+		}
+
+		if index >= len(arcs) {
+			return nil, false
+		}
+
+		arc := arcs[index]
+		if reverse {
+			arc = arc.Clone()
+			arc.Reverse()
+		}
+
+		if i > 0 && len(arc) > 0 {
+			arc = arc[1:]
+		}
+
+		result = append(result, arc...)
+	}
+
+	return result, true
+}
@@ -0,0 +1,75 @@
+package topology
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestTopology(t *testing.T) {
+	topo := New()
+	left := topo.AddRing(orb.Ring{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}})
+	right := topo.AddRing(orb.Ring{{1, 0}, {2, 0}, {2, 1}, {1, 1}}) // not closed
+	line := topo.AddLineString(orb.LineString{{1, -1}, {1, 0}, {1, 0}, {2, 0}})
+	topo.Build()
+
+	if n := topo.NumParts(); n != 3 {
+		t.Errorf("incorrect number of parts: %d", n)
+	}
+
+	if !topo.IsRing(right) || topo.IsRing(line) {
+		t.Errorf("incorrect ring flags")
+	}
+
+	arcs := topo.Arcs()
+	expected := []orb.LineString{
+		{{1, 0}, {1, 1}},
+		{{1, 1}, {0, 1}, {0, 0}, {1, 0}},
+		{{1, 0}, {2, 0}},
+		{{2, 0}, {2, 1}, {1, 1}},
+		{{1, -1}, {1, 0}},
+	}
+	if !reflect.DeepEqual(arcs, expected) {
+		t.Errorf("incorrect arcs: %v", arcs)
+	}
+
+	if v := topo.Part(left); !reflect.DeepEqual(v, []int{0, 1}) {
+		t.Errorf("incorrect left arcs: %v", v)
+	}
+
+	if v := topo.Part(right); !reflect.DeepEqual(v, []int{2, 3, ^0}) {
+		t.Errorf("incorrect right arcs: %v", v)
+	}
+
+	if v := topo.Part(line); !reflect.DeepEqual(v, []int{4, 2}) {
+		t.Errorf("incorrect line arcs: %v", v)
+	}
+
+	ls, ok := Stitch(arcs, topo.Part(right))
+	if !ok {
+		t.Fatalf("should stitch")
+	}
+
+	if !ls.Equal(orb.LineString{{1, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 0}}) {
+		t.Errorf("incorrect stitched ring: %v", ls)
+	}
+}
+
+func TestStitch(t *testing.T) {
+	arcs := []orb.LineString{{{0, 0}, {1, 1}}}
+
+	ls, ok := Stitch(arcs, []int{0, ^0})
+	if !ok {
+		t.Fatalf("should stitch")
+	}
+
+	if !ls.Equal(orb.LineString{{0, 0}, {1, 1}, {0, 0}}) {
+		t.Errorf("incorrect line: %v", ls)
+	}
+
+	_, ok = Stitch(arcs, []int{1})
+	if ok {
+		t.Errorf("should fail on out of range index")
+	}
+}
@@ -0,0 +1,29 @@
+package linref_test
+
+import (
+	"fmt"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/linref"
+	"github.com/dadadamarine/orb/planar"
+)
+
+func ExampleLocatePoint() {
+	road := orb.LineString{{0, 0}, {10, 0}, {10, 10}}
+
+	loc := linref.LocatePoint(road, planar.Distance, orb.Point{12, 5})
+	fmt.Println(loc.Point, loc.Distance, loc.Fraction, loc.Side == linref.Right)
+
+	// Output:
+	// [10 5] 15 0.75 true
+}
+
+func ExampleSubstring() {
+	road := orb.LineString{{0, 0}, {10, 0}, {10, 10}}
+
+	part := linref.Substring(road, planar.Distance, 5, 15)
+	fmt.Println(part)
+
+	// Output:
+	// [[5 0] [10 0] [10 5]]
+}
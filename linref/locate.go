@@ -0,0 +1,124 @@
+// Package linref provides linear referencing functions, locating points
+// along a line string and extracting the parts between two measures.
+package linref
+
+import (
+	"math"
+
+	"github.com/dadadamarine/orb"
+)
+
+// Side values of a Location, relative to the direction of the line.
+const (
+	Left  = 1
+	On    = 0
+	Right = -1
+)
+
+// A Location describes the position of a point relative to a line string.
+type Location struct {
+	// Point is the closest point on the line string.
+	Point orb.Point
+
+	// Index of the segment containing the point, i.e. the point
+	// is between ls[Index] and ls[Index+1].
+	Index int
+
+	// Distance along the line string to the point.
+	Distance float64
+
+	// Fraction of the total length of the line string, in the range [0, 1].
+	Fraction float64
+
+	// Offset is the distance from the located point to the line string.
+	Offset float64
+
+	// Side of the line the located point is on, Left, Right or On.
+	Side int
+}
+
+// LocatePoint projects the point onto the line string. The point is projected
+// onto each segment in coordinate space, the distance function is used to pick
+// the closest segment and to measure the distances, so planar.Distance and
+// geo.Distance are both supported. Will panic if the line string is empty.
+func LocatePoint(ls orb.LineString, df orb.DistanceFunc, p orb.Point) Location {
+	if len(ls) == 0 {
+		panic("empty LineString")
+	}
+
+	if len(ls) == 1 {
+		return Location{
+			Point:  ls[0],
+			Offset: df(ls[0], p),
+		}
+	}
+
+	total, dists := measures(ls, df)
+
+	loc := Location{Offset: math.Inf(1)}
+	for i := 0; i < len(ls)-1; i++ {
+		t := projectToSegment(ls[i], ls[i+1], p)
+		snapped := interpolate(ls[i], ls[i+1], t)
+
+		if d := df(snapped, p); d < loc.Offset {
+			loc.Point = snapped
+			loc.Index = i
+			loc.Distance = dists[i] + t*(dists[i+1]-dists[i])
+			loc.Offset = d
+		}
+	}
+
+	if total > 0 {
+		loc.Fraction = loc.Distance / total
+	}
+
+	loc.Side = side(ls[loc.Index], ls[loc.Index+1], p)
+	if loc.Offset == 0 {
+		loc.Side = On
+	}
+
+	return loc
+}
+
+// measures returns the total length and the distance along the
+// line string to each point.
+func measures(ls orb.LineString, df orb.DistanceFunc) (float64, []float64) {
+	dists := make([]float64, len(ls))
+	for i := 1; i < len(ls); i++ {
+		dists[i] = dists[i-1] + df(ls[i-1], ls[i])
+	}
+
+	return dists[len(dists)-1], dists
+}
+
+// projectToSegment returns the position, in the range [0, 1],
+// of the closest point on the segment [a, b].
+func projectToSegment(a, b, p orb.Point) float64 {
+	dx := b[0] - a[0]
+	dy := b[1] - a[1]
+
+	if dx == 0 && dy == 0 {
+		return 0
+	}
+
+	t := ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / (dx*dx + dy*dy)
+	return math.Max(0, math.Min(1, t))
+}
+
+func interpolate(a, b orb.Point, t float64) orb.Point {
+	return orb.Point{
+		a[0] + t*(b[0]-a[0]),
+		a[1] + t*(b[1]-a[1]),
+	}
+}
+
+func side(a, b, p orb.Point) int {
+	cross := (b[0]-a[0])*(p[1]-a[1]) - (b[1]-a[1])*(p[0]-a[0])
+	if cross > 0 {
+		return Left
+	} else if cross < 0 {
+		return Right
+	}
+
+	return On
+}
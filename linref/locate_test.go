@@ -0,0 +1,103 @@
+package linref
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geo"
+	"github.com/dadadamarine/orb/planar"
+)
+
+func TestLocatePoint(t *testing.T) {
+	ls := orb.LineString{{0, 0}, {10, 0}, {10, 10}}
+
+	cases := []struct {
+		name     string
+		point    orb.Point
+		expected Location
+	}{
+		{
+			name:  "left of first segment",
+			point: orb.Point{4, 2},
+			expected: Location{
+				Point: orb.Point{4, 0}, Index: 0,
+				Distance: 4, Fraction: 0.2, Offset: 2, Side: Left,
+			},
+		},
+		{
+			name:  "right of second segment",
+			point: orb.Point{13, 5},
+			expected: Location{
+				Point: orb.Point{10, 5}, Index: 1,
+				Distance: 15, Fraction: 0.75, Offset: 3, Side: Right,
+			},
+		},
+		{
+			name:  "on the line",
+			point: orb.Point{10, 2},
+			expected: Location{
+				Point: orb.Point{10, 2}, Index: 1,
+				Distance: 12, Fraction: 0.6, Offset: 0, Side: On,
+			},
+		},
+		{
+			name:  "before the start",
+			point: orb.Point{-3, -4},
+			expected: Location{
+				Point: orb.Point{0, 0}, Index: 0,
+				Distance: 0, Fraction: 0, Offset: 5, Side: Right,
+			},
+		},
+		{
+			name:  "past the end",
+			point: orb.Point{10, 12},
+			expected: Location{
+				Point: orb.Point{10, 10}, Index: 1,
+				Distance: 20, Fraction: 1, Offset: 2, Side: On,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			loc := LocatePoint(ls, planar.Distance, tc.point)
+			if loc != tc.expected {
+				t.Errorf("incorrect location: %+v != %+v", loc, tc.expected)
+			}
+		})
+	}
+}
+
+func TestLocatePoint_singlePoint(t *testing.T) {
+	loc := LocatePoint(orb.LineString{{1, 1}}, planar.Distance, orb.Point{4, 5})
+
+	expected := Location{Point: orb.Point{1, 1}, Offset: 5}
+	if loc != expected {
+		t.Errorf("incorrect location: %+v", loc)
+	}
+}
+
+func TestLocatePoint_geo(t *testing.T) {
+	ls := orb.LineString{{-122.4, 37.7}, {-122.3, 37.7}, {-122.3, 37.8}}
+	p := orb.Point{-122.35, 37.71}
+
+	loc := LocatePoint(ls, geo.Distance, p)
+	if loc.Index != 0 || loc.Side != Left {
+		t.Errorf("incorrect segment: %+v", loc)
+	}
+
+	expected := geo.Distance(ls[0], orb.Point{-122.35, 37.7})
+	if math.Abs(loc.Distance-expected) > 1 {
+		t.Errorf("incorrect distance: %v != %v", loc.Distance, expected)
+	}
+
+	if math.Abs(loc.Offset-geo.Distance(p, loc.Point)) > 1e-9 {
+		t.Errorf("incorrect offset: %v", loc.Offset)
+	}
+
+	// locating and interpolating should round trip.
+	if v := Interpolate(ls, geo.Distance, loc.Distance); !v.Equal(loc.Point) {
+		t.Errorf("incorrect interpolated point: %v != %v", v, loc.Point)
+	}
+}
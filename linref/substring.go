@@ -0,0 +1,93 @@
+package linref
+
+import (
+	"math"
+	"sort"
+
+	"github.com/dadadamarine/orb"
+)
+
+// Interpolate returns the point at the given distance along the line string.
+// The distance is clamped to the length of the line. Points are linearly
+// interpolated within a segment, the same as the resample package.
+// Will panic if the line string is empty.
+func Interpolate(ls orb.LineString, df orb.DistanceFunc, distance float64) orb.Point {
+	if len(ls) == 0 {
+		panic("empty LineString")
+	}
+
+	_, dists := measures(ls, df)
+	return pointAt(ls, dists, distance)
+}
+
+// Substring returns the part of the line string between the start and end
+// distances along the line. The distances are clamped to the length of the line.
+// If start is greater than end the result will be in the reverse direction.
+// The input line string is not modified. Will panic if the line string is empty.
+func Substring(ls orb.LineString, df orb.DistanceFunc, start, end float64) orb.LineString {
+	if len(ls) == 0 {
+		panic("empty LineString")
+	}
+
+	_, dists := measures(ls, df)
+	return substring(ls, dists, start, end)
+}
+
+// SplitAt splits the line string into two at the given distance along the line.
+// Both results include the point at the split. The input line string is not
+// modified. Will panic if the line string is empty.
+func SplitAt(ls orb.LineString, df orb.DistanceFunc, distance float64) (orb.LineString, orb.LineString) {
+	if len(ls) == 0 {
+		panic("empty LineString")
+	}
+
+	total, dists := measures(ls, df)
+	return substring(ls, dists, 0, distance), substring(ls, dists, distance, total)
+}
+
+func substring(ls orb.LineString, dists []float64, start, end float64) orb.LineString {
+	reverse := false
+	if start > end {
+		start, end = end, start
+		reverse = true
+	}
+
+	total := dists[len(dists)-1]
+	start = math.Max(0, math.Min(total, start))
+	end = math.Max(0, math.Min(total, end))
+
+	result := orb.LineString{pointAt(ls, dists, start)}
+	for i := range ls {
+		if dists[i] > start && dists[i] < end {
+			result = append(result, ls[i])
+		}
+	}
+	result = append(result, pointAt(ls, dists, end))
+
+	if reverse {
+		result.Reverse()
+	}
+
+	return result
+}
+
+// pointAt returns the point at the distance using the precomputed
+// distances to each point.
+func pointAt(ls orb.LineString, dists []float64, distance float64) orb.Point {
+	if distance <= 0 {
+		return ls[0]
+	}
+
+	if distance >= dists[len(dists)-1] {
+		return ls[len(ls)-1]
+	}
+
+	// first point past the distance, must be > 0 from checks above.
+	i := sort.SearchFloat64s(dists, distance)
+	if dists[i] == distance {
+		return ls[i]
+	}
+
+	t := (distance - dists[i-1]) / (dists[i] - dists[i-1])
+	return interpolate(ls[i-1], ls[i], t)
+}
@@ -0,0 +1,108 @@
+package linref
+
+import (
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/planar"
+)
+
+func TestInterpolate(t *testing.T) {
+	ls := orb.LineString{{0, 0}, {10, 0}, {10, 0}, {10, 10}}
+
+	cases := []struct {
+		distance float64
+		expected orb.Point
+	}{
+		{distance: -1, expected: orb.Point{0, 0}},
+		{distance: 0, expected: orb.Point{0, 0}},
+		{distance: 2.5, expected: orb.Point{2.5, 0}},
+		{distance: 10, expected: orb.Point{10, 0}},
+		{distance: 15, expected: orb.Point{10, 5}},
+		{distance: 20, expected: orb.Point{10, 10}},
+		{distance: 25, expected: orb.Point{10, 10}},
+	}
+
+	for _, tc := range cases {
+		v := Interpolate(ls, planar.Distance, tc.distance)
+		if !v.Equal(tc.expected) {
+			t.Errorf("%v: incorrect point: %v != %v", tc.distance, v, tc.expected)
+		}
+	}
+}
+
+func TestSubstring(t *testing.T) {
+	ls := orb.LineString{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+
+	cases := []struct {
+		name       string
+		start, end float64
+		expected   orb.LineString
+	}{
+		{
+			name:  "within a segment",
+			start: 2, end: 4,
+			expected: orb.LineString{{2, 0}, {4, 0}},
+		},
+		{
+			name:  "across vertexes",
+			start: 5, end: 25,
+			expected: orb.LineString{{5, 0}, {10, 0}, {10, 10}, {5, 10}},
+		},
+		{
+			name:  "at vertexes",
+			start: 10, end: 20,
+			expected: orb.LineString{{10, 0}, {10, 10}},
+		},
+		{
+			name:  "reversed",
+			start: 15, end: 5,
+			expected: orb.LineString{{10, 5}, {10, 0}, {5, 0}},
+		},
+		{
+			name:  "clamped",
+			start: -5, end: 50,
+			expected: ls,
+		},
+		{
+			name:  "same point",
+			start: 3, end: 3,
+			expected: orb.LineString{{3, 0}, {3, 0}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := Substring(ls, planar.Distance, tc.start, tc.end)
+			if !v.Equal(tc.expected) {
+				t.Errorf("incorrect substring: %v != %v", v, tc.expected)
+			}
+		})
+	}
+
+	if !ls.Equal(orb.LineString{{0, 0}, {10, 0}, {10, 10}, {0, 10}}) {
+		t.Errorf("input should not be modified: %v", ls)
+	}
+}
+
+func TestSplitAt(t *testing.T) {
+	ls := orb.LineString{{0, 0}, {10, 0}, {10, 10}}
+
+	a, b := SplitAt(ls, planar.Distance, 10)
+	if !a.Equal(orb.LineString{{0, 0}, {10, 0}}) {
+		t.Errorf("incorrect first part: %v", a)
+	}
+
+	if !b.Equal(orb.LineString{{10, 0}, {10, 10}}) {
+		t.Errorf("incorrect second part: %v", b)
+	}
+
+	a, b = SplitAt(ls, planar.Distance, 5)
+	if !a.Equal(orb.LineString{{0, 0}, {5, 0}}) {
+		t.Errorf("incorrect first part: %v", a)
+	}
+
+	if !b.Equal(orb.LineString{{5, 0}, {10, 0}, {10, 10}}) {
+		t.Errorf("incorrect second part: %v", b)
+	}
+}
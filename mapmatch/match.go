@@ -0,0 +1,267 @@
+package mapmatch
+
+import (
+	"math"
+	"sort"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geo"
+	"github.com/dadadamarine/orb/linref"
+)
+
+// maxRouteFactor limits the search for routes between candidates to
+// this factor times the great circle distance between the observed points.
+const maxRouteFactor = 4
+
+// A Matcher matches GPS traces to the edges of a network using a hidden
+// Markov model. The emission probability is based on the distance from
+// the observed point to the edge and the transition probability on the
+// difference between the route distance and the great circle distance.
+type Matcher struct {
+	network *Network
+	opts    options
+}
+
+// A MatchedPoint is the result for one point of the trace.
+type MatchedPoint struct {
+	// Matched is false if there were no edges within the radius
+	// of the point, the other fields will be empty.
+	Matched bool
+
+	// Edge is the index of the matched edge in network.Edges().
+	Edge int
+	ID   interface{}
+
+	// Location on the edge, Location.Point is the snapped point.
+	Location linref.Location
+}
+
+// A Result is the output of matching a trace.
+type Result struct {
+	// Points has an entry for each point of the trace.
+	Points []MatchedPoint
+
+	// Routes are sequences of edge indexes traveled, including the
+	// edges between matched points. If no route could be found between
+	// two consecutive points the match is broken into multiple routes.
+	Routes [][]int
+}
+
+type candidate struct {
+	edge int
+	loc  linref.Location
+}
+
+// New creates a new matcher for the network.
+func New(n *Network, opts ...Option) *Matcher {
+	m := &Matcher{
+		network: n,
+		opts: options{
+			sigma:         10,
+			beta:          10,
+			radius:        50,
+			maxCandidates: 8,
+		},
+	}
+
+	for _, o := range opts {
+		o(&m.opts)
+	}
+
+	return m
+}
+
+// Match matches the lon/lat trace to the network using the Viterbi algorithm.
+// Noisy traces with a lot of points close together can be cleaned up using
+// the simplify and resample packages first.
+func (m *Matcher) Match(trace orb.LineString) *Result {
+	result := &Result{
+		Points: make([]MatchedPoint, len(trace)),
+	}
+
+	var (
+		steps  []int         // the trace indexes of the points in the current chain
+		cands  [][]candidate // the candidates for each step
+		back   [][]int       // the best previous candidate for each candidate
+		scores []float64     // the scores of the last step
+	)
+
+	finish := func() {
+		if len(steps) == 0 {
+			return
+		}
+
+		m.finishChain(result, trace, steps, cands, back, scores)
+		steps, cands, back, scores = nil, nil, nil, nil
+	}
+
+	for i, p := range trace {
+		current := m.candidates(p)
+		if len(current) == 0 {
+			continue
+		}
+
+		emission := make([]float64, len(current))
+		for j, c := range current {
+			emission[j] = -0.5 * math.Pow(c.loc.Offset/m.opts.sigma, 2)
+		}
+
+		if len(steps) > 0 {
+			next, pointers := m.transition(trace[steps[len(steps)-1]], p, cands[len(cands)-1], current, scores)
+			if next != nil {
+				for j := range next {
+					next[j] += emission[j]
+				}
+
+				steps = append(steps, i)
+				cands = append(cands, current)
+				back = append(back, pointers)
+				scores = next
+				continue
+			}
+
+			// no possible transitions so start a new chain
+			finish()
+		}
+
+		steps = append(steps, i)
+		cands = append(cands, current)
+		back = append(back, nil)
+		scores = emission
+	}
+
+	finish()
+	return result
+}
+
+// transition computes the best score for each of the current candidates.
+// Returns nil if none of the current candidates can be reached.
+func (m *Matcher) transition(
+	from, to orb.Point,
+	prev, current []candidate,
+	scores []float64,
+) ([]float64, []int) {
+	gc := geo.Distance(from, to)
+
+	prevPaths := make([]*paths, len(prev))
+	for k, pc := range prev {
+		prevPaths[k] = m.shortestPaths(pc, gc)
+	}
+
+	next := make([]float64, len(current))
+	pointers := make([]int, len(current))
+	found := false
+	for j, c := range current {
+		next[j] = math.Inf(-1)
+		for k, pc := range prev {
+			d := m.routeDistance(pc, c, prevPaths[k])
+			if math.IsInf(d, 1) {
+				continue
+			}
+
+			s := scores[k] - math.Abs(d-gc)/m.opts.beta
+			if s > next[j] {
+				next[j] = s
+				pointers[j] = k
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return next, pointers
+}
+
+// shortestPaths computes the shortest paths from the candidate up to a cutoff
+// based on the great circle distance to the next observed point. Longer routes
+// have a very low transition probability.
+func (m *Matcher) shortestPaths(c candidate, gc float64) *paths {
+	cutoff := maxRouteFactor*gc + 2*m.opts.radius
+	return m.network.shortestPaths(c.edge, c.loc.Distance, cutoff)
+}
+
+func (m *Matcher) routeDistance(a, b candidate, p *paths) float64 {
+	if a.edge == b.edge {
+		return math.Abs(b.loc.Distance - a.loc.Distance)
+	}
+
+	d, _ := p.to(m.network, b.edge, b.loc.Distance)
+	return d
+}
+
+// candidates returns the closest edges within the radius of the point.
+func (m *Matcher) candidates(p orb.Point) []candidate {
+	var result []candidate
+	for _, e := range m.network.nearbyEdges(p, m.opts.radius) {
+		loc := linref.LocatePoint(m.network.edges[e].LineString, geo.Distance, p)
+		if loc.Offset <= m.opts.radius {
+			result = append(result, candidate{edge: e, loc: loc})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].loc.Offset < result[j].loc.Offset
+	})
+
+	if len(result) > m.opts.maxCandidates {
+		result = result[:m.opts.maxCandidates]
+	}
+
+	return result
+}
+
+// finishChain backtracks the best candidates for the chain and adds
+// them, and the route between them, to the result.
+func (m *Matcher) finishChain(
+	result *Result,
+	trace orb.LineString,
+	steps []int,
+	cands [][]candidate,
+	back [][]int,
+	scores []float64,
+) {
+	best := 0
+	for i, s := range scores {
+		if s > scores[best] {
+			best = i
+		}
+	}
+
+	chosen := make([]candidate, len(steps))
+	for i := len(steps) - 1; i >= 0; i-- {
+		chosen[i] = cands[i][best]
+		if back[i] != nil {
+			best = back[i][best]
+		}
+	}
+
+	route := []int{chosen[0].edge}
+	for i, c := range chosen {
+		result.Points[steps[i]] = MatchedPoint{
+			Matched:  true,
+			Edge:     c.edge,
+			ID:       m.network.edges[c.edge].ID,
+			Location: c.loc,
+		}
+
+		if i == 0 || chosen[i-1].edge == c.edge {
+			continue
+		}
+
+		prev := chosen[i-1]
+		p := m.shortestPaths(prev, geo.Distance(trace[steps[i-1]], trace[steps[i]]))
+		_, node := p.to(m.network, c.edge, c.loc.Distance)
+		for _, e := range p.edgesTo(m.network, node) {
+			if e != route[len(route)-1] {
+				route = append(route, e)
+			}
+		}
+
+		route = append(route, c.edge)
+	}
+
+	result.Routes = append(result.Routes, route)
+}
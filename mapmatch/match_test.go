@@ -0,0 +1,136 @@
+package mapmatch
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+)
+
+func TestMatcher_Match(t *testing.T) {
+	m := New(loadNetwork(t))
+
+	trace := orb.LineString{
+		{13.4010, 52.52005},
+		{13.4020, 52.51995},
+		{13.4030, 52.52006},
+		{13.4045, 52.51997},
+		{13.4052, 52.52018}, // closer to c but continues along b
+		{13.4065, 52.52004},
+		{13.4080, 52.51996},
+	}
+
+	result := m.Match(trace)
+	if len(result.Points) != len(trace) {
+		t.Fatalf("incorrect number of points: %d", len(result.Points))
+	}
+
+	ids := make([]interface{}, 0, len(trace))
+	for i, p := range result.Points {
+		if !p.Matched {
+			t.Fatalf("point %d should be matched", i)
+		}
+
+		ids = append(ids, p.ID)
+		if p.Location.Point[1] != 52.520 {
+			t.Errorf("point %d should be snapped to the road: %v", i, p.Location.Point)
+		}
+	}
+
+	expected := []interface{}{"a", "a", "a", "a", "b", "b", "b"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("incorrect edges: %v", ids)
+	}
+
+	if !reflect.DeepEqual(result.Routes, [][]int{{0, 1}}) {
+		t.Errorf("incorrect routes: %v", result.Routes)
+	}
+}
+
+func TestMatcher_Match_aroundTheBlock(t *testing.T) {
+	m := New(loadNetwork(t))
+
+	trace := orb.LineString{
+		{13.4040, 52.52003},
+		{13.40505, 52.5220},
+		{13.4020, 52.52395},
+		{13.39995, 52.5220},
+	}
+
+	result := m.Match(trace)
+	if !reflect.DeepEqual(result.Routes, [][]int{{0, 2, 3, 4}}) {
+		t.Errorf("incorrect routes: %v", result.Routes)
+	}
+}
+
+func TestMatcher_Match_breaks(t *testing.T) {
+	m := New(loadNetwork(t))
+
+	trace := orb.LineString{
+		{13.4010, 52.52005},
+		{13.4020, 52.51995},
+		{13.4150, 52.5270}, // no roads nearby
+		{13.4210, 52.53003},
+		{13.4220, 52.52997},
+	}
+
+	result := m.Match(trace)
+	if result.Points[2].Matched {
+		t.Errorf("point should not be matched: %v", result.Points[2])
+	}
+
+	if v := result.Points[3].ID; v != "f" {
+		t.Errorf("incorrect edge: %v", v)
+	}
+
+	if !reflect.DeepEqual(result.Routes, [][]int{{0}, {5}}) {
+		t.Errorf("incorrect routes: %v", result.Routes)
+	}
+}
+
+func TestMatcher_Match_empty(t *testing.T) {
+	m := New(NewNetwork(nil))
+
+	result := m.Match(orb.LineString{{1, 2}})
+	if result.Points[0].Matched || len(result.Routes) != 0 {
+		t.Errorf("should not match: %v", result)
+	}
+}
+
+func TestNewNetworkFromFeatureCollection(t *testing.T) {
+	n := loadNetwork(t)
+	if l := len(n.Edges()); l != 6 {
+		t.Errorf("incorrect number of edges: %d", l)
+	}
+
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(orb.Point{1, 2}))
+
+	_, err := NewNetworkFromFeatureCollection(fc)
+	if err == nil {
+		t.Errorf("should return error for points")
+	}
+}
+
+func loadNetwork(t testing.TB) *Network {
+	t.Helper()
+
+	data, err := ioutil.ReadFile("testdata/network.geojson")
+	if err != nil {
+		t.Fatalf("unable to read file: %v", err)
+	}
+
+	fc, err := geojson.UnmarshalFeatureCollection(data)
+	if err != nil {
+		t.Fatalf("unable to unmarshal: %v", err)
+	}
+
+	n, err := NewNetworkFromFeatureCollection(fc)
+	if err != nil {
+		t.Fatalf("unable to create network: %v", err)
+	}
+
+	return n
+}
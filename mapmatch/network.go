@@ -0,0 +1,257 @@
+package mapmatch
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geo"
+	"github.com/dadadamarine/orb/geojson"
+	"github.com/dadadamarine/orb/quadtree"
+)
+
+// An Edge is a road in the network with the geometry in lon/lat.
+type Edge struct {
+	ID         interface{}
+	LineString orb.LineString
+}
+
+// A Network is a set of edges that are connected where their end points
+// are equal. The edges can be traveled in both directions.
+type Network struct {
+	edges    []Edge
+	lengths  []float64
+	ends     [][2]int // start and end node of each edge
+	adjacent [][]int  // the edges at each node
+
+	tree       *quadtree.Quadtree
+	maxSegment float64 // the longest segment in meters
+}
+
+// NewNetwork creates a network from the edges. Edges with less than
+// two points are ignored.
+func NewNetwork(edges []Edge) *Network {
+	n := &Network{}
+
+	nodes := make(map[orb.Point]int)
+	node := func(p orb.Point) int {
+		if i, ok := nodes[p]; ok {
+			return i
+		}
+
+		nodes[p] = len(n.adjacent)
+		n.adjacent = append(n.adjacent, nil)
+		return nodes[p]
+	}
+
+	for _, e := range edges {
+		if len(e.LineString) < 2 {
+			continue
+		}
+
+		i := len(n.edges)
+		start, end := node(e.LineString[0]), node(e.LineString[len(e.LineString)-1])
+
+		n.edges = append(n.edges, e)
+		n.lengths = append(n.lengths, geo.Length(e.LineString))
+		n.ends = append(n.ends, [2]int{start, end})
+		n.adjacent[start] = append(n.adjacent[start], i)
+		if end != start {
+			n.adjacent[end] = append(n.adjacent[end], i)
+		}
+	}
+
+	if len(n.edges) == 0 {
+		return n
+	}
+
+	// segments are indexed by their midpoint, searches are padded by
+	// half the longest segment so all the nearby segments are found.
+	bound := n.edges[0].LineString.Bound()
+	for _, e := range n.edges {
+		bound = bound.Union(e.LineString.Bound())
+	}
+
+	n.tree = quadtree.New(bound)
+	for i, e := range n.edges {
+		for j := 1; j < len(e.LineString); j++ {
+			a, b := e.LineString[j-1], e.LineString[j]
+			n.maxSegment = math.Max(n.maxSegment, geo.Distance(a, b))
+
+			n.tree.Add(&segment{
+				edge: i,
+				mid:  orb.Point{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2},
+			})
+		}
+	}
+
+	return n
+}
+
+// NewNetworkFromFeatureCollection creates a network from the LineString and
+// MultiLineString features. The feature ids are used as the edge ids,
+// each line of a MultiLineString is a separate edge with the same id.
+func NewNetworkFromFeatureCollection(fc *geojson.FeatureCollection) (*Network, error) {
+	var edges []Edge
+	for i, f := range fc.Features {
+		switch g := f.Geometry.(type) {
+		case orb.LineString:
+			edges = append(edges, Edge{ID: f.ID, LineString: g})
+		case orb.MultiLineString:
+			for _, ls := range g {
+				edges = append(edges, Edge{ID: f.ID, LineString: ls})
+			}
+		default:
+			return nil, fmt.Errorf("mapmatch: feature %d: geometry must be a line string, got %T", i, f.Geometry)
+		}
+	}
+
+	return NewNetwork(edges), nil
+}
+
+// Edges returns the edges in the network. The matched edges are
+// indexes into this slice.
+func (n *Network) Edges() []Edge {
+	return n.edges
+}
+
+// nearbyEdges returns the edges with a segment that may be within
+// the distance of the point.
+func (n *Network) nearbyEdges(p orb.Point, distance float64) []int {
+	if n.tree == nil {
+		return nil
+	}
+
+	b := geo.NewBoundAroundPoint(p, distance+n.maxSegment/2)
+
+	seen := make(map[int]bool)
+	var result []int
+	for _, s := range n.tree.InBound(nil, b) {
+		e := s.(*segment).edge
+		if !seen[e] {
+			seen[e] = true
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
+
+type segment struct {
+	edge int
+	mid  orb.Point
+}
+
+func (s *segment) Point() orb.Point {
+	return s.mid
+}
+
+// paths are the shortest paths from a position on an edge to the nodes of the network.
+type paths struct {
+	dist map[int]float64
+	prev map[int]int // the edge used to reach the node, -1 for the start nodes.
+}
+
+// shortestPaths runs Dijkstra's algorithm starting from the position, distance
+// along the edge, up to the cutoff distance.
+func (n *Network) shortestPaths(edge int, distance, cutoff float64) *paths {
+	p := &paths{
+		dist: make(map[int]float64),
+		prev: make(map[int]int),
+	}
+
+	queue := &nodeQueue{}
+	push := func(node, prev int, d float64) {
+		if d > cutoff {
+			return
+		}
+
+		if v, ok := p.dist[node]; ok && v <= d {
+			return
+		}
+
+		p.dist[node] = d
+		p.prev[node] = prev
+		heap.Push(queue, queueItem{node: node, dist: d})
+	}
+
+	push(n.ends[edge][0], -1, distance)
+	push(n.ends[edge][1], -1, n.lengths[edge]-distance)
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(queueItem)
+		if item.dist > p.dist[item.node] {
+			continue // stale entry
+		}
+
+		for _, e := range n.adjacent[item.node] {
+			other := n.ends[e][0]
+			if other == item.node {
+				other = n.ends[e][1]
+			}
+
+			push(other, e, item.dist+n.lengths[e])
+		}
+	}
+
+	return p
+}
+
+// to returns the distance to the position on the edge and the node
+// the path enters the edge from, -1 if the position can not be reached.
+func (p *paths) to(n *Network, edge int, distance float64) (float64, int) {
+	best, node := math.Inf(1), -1
+	if d, ok := p.dist[n.ends[edge][0]]; ok && d+distance < best {
+		best, node = d+distance, n.ends[edge][0]
+	}
+
+	if d, ok := p.dist[n.ends[edge][1]]; ok && d+n.lengths[edge]-distance < best {
+		best, node = d+n.lengths[edge]-distance, n.ends[edge][1]
+	}
+
+	return best, node
+}
+
+// edgesTo returns the edges traveled to reach the node, in order.
+func (p *paths) edgesTo(n *Network, node int) []int {
+	var result []int
+	for {
+		e := p.prev[node]
+		if e == -1 {
+			break
+		}
+
+		result = append(result, e)
+		if n.ends[e][0] == node {
+			node = n.ends[e][1]
+		} else {
+			node = n.ends[e][0]
+		}
+	}
+
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return result
+}
+
+type queueItem struct {
+	node int
+	dist float64
+}
+
+type nodeQueue []queueItem
+
+func (q nodeQueue) Len() int            { return len(q) }
+func (q nodeQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q nodeQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *nodeQueue) Push(x interface{}) { *q = append(*q, x.(queueItem)) }
+
+func (q *nodeQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
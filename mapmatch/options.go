@@ -0,0 +1,44 @@
+package mapmatch
+
+type options struct {
+	sigma         float64
+	beta          float64
+	radius        float64
+	maxCandidates int
+}
+
+// An Option is a possible parameter to the matcher.
+type Option func(*options)
+
+// Sigma is the standard deviation, in meters, of the GPS noise.
+// Candidates further from the observed point are less likely. Default is 10.
+func Sigma(meters float64) Option {
+	return func(o *options) {
+		o.sigma = meters
+	}
+}
+
+// Beta, in meters, controls how much the route distance between two candidates
+// may differ from the great circle distance between the observed points.
+// Default is 10.
+func Beta(meters float64) Option {
+	return func(o *options) {
+		o.beta = meters
+	}
+}
+
+// Radius is the maximum distance, in meters, from an observed point to
+// its candidate edges. Default is 50.
+func Radius(meters float64) Option {
+	return func(o *options) {
+		o.radius = meters
+	}
+}
+
+// MaxCandidates is the maximum number of closest edges to consider
+// for each observed point. Default is 8.
+func MaxCandidates(n int) Option {
+	return func(o *options) {
+		o.maxCandidates = n
+	}
+}
@@ -0,0 +1,202 @@
+package maptile
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// MaxHilbertZoom is the deepest zoom that can be represented by a HilbertID.
+const MaxHilbertZoom = Zoom(31)
+
+// A HilbertID identifies a tile by its position along the Hilbert curve,
+// similar to an S2 cell id. The Hilbert index of the tile is followed by
+// a single 1 bit and then 2*(MaxHilbertZoom-z) zero bits. This way the ids
+// of all tiles, of all zooms, share the same key space and the descendants
+// of a tile are a contiguous range of ids around the tile's id.
+// The zero value is not a valid id.
+type HilbertID uint64
+
+// Hilbert returns the index of the tile along the Hilbert curve that
+// visits all the tiles at the tile's zoom. Unlike the quadkey, consecutive
+// indexes are always adjacent tiles. The index of the parent tile is
+// the index of the child divided by 4.
+func (t Tile) Hilbert() uint64 {
+	if t.Z == 0 {
+		return 0
+	}
+
+	x, y := t.X, t.Y
+	n := uint32(1) << (t.Z - 1) << 1
+
+	var d uint64
+	for s := uint32(1) << (t.Z - 1); s > 0; s >>= 1 {
+		var rx, ry uint32
+		if x&s != 0 {
+			rx = 1
+		}
+		if y&s != 0 {
+			ry = 1
+		}
+
+		d += uint64(s) * uint64(s) * uint64((3*rx)^ry)
+		x, y = hilbertRotate(n, x, y, rx, ry)
+	}
+
+	return d
+}
+
+// FromHilbert creates the tile from its index along the Hilbert
+// curve at the given zoom.
+func FromHilbert(d uint64, z Zoom) Tile {
+	var x, y uint32
+	for i := Zoom(0); i < z; i++ {
+		s := uint32(1) << i
+
+		rx := uint32(1 & (d / 2))
+		ry := uint32(1 & (d ^ uint64(rx)))
+		x, y = hilbertRotate(s, x, y, rx, ry)
+
+		x += s * rx
+		y += s * ry
+		d /= 4
+	}
+
+	return Tile{X: x, Y: y, Z: z}
+}
+
+func hilbertRotate(n, x, y, rx, ry uint32) (uint32, uint32) {
+	if ry == 0 {
+		if rx == 1 {
+			x = n - 1 - x
+			y = n - 1 - y
+		}
+
+		return y, x
+	}
+
+	return x, y
+}
+
+// HilbertID returns the Hilbert id of the tile.
+// Returns 0, an invalid id, for tiles deeper than MaxHilbertZoom.
+func (t Tile) HilbertID() HilbertID {
+	if t.Z > MaxHilbertZoom {
+		return 0
+	}
+
+	return HilbertID((t.Hilbert()<<1 | 1) << (2 * (MaxHilbertZoom - t.Z)))
+}
+
+// Valid returns if the id is the id of some tile.
+func (id HilbertID) Valid() bool {
+	return id != 0 && id>>(2*MaxHilbertZoom+1) == 0 && bits.TrailingZeros64(uint64(id))%2 == 0
+}
+
+// Zoom returns the zoom of the tile represented by the id.
+func (id HilbertID) Zoom() Zoom {
+	return MaxHilbertZoom - Zoom(bits.TrailingZeros64(uint64(id))/2)
+}
+
+// Tile returns the tile represented by the id.
+func (id HilbertID) Tile() Tile {
+	z := id.Zoom()
+	return FromHilbert(uint64(id)>>(2*(MaxHilbertZoom-z)+1), z)
+}
+
+func (id HilbertID) lsb() HilbertID {
+	return id & -id
+}
+
+// Parent returns the id of the parent tile.
+// The parent of a zoom 0 id is itself.
+func (id HilbertID) Parent() HilbertID {
+	if id.Zoom() == 0 {
+		return id
+	}
+
+	lsb := id.lsb() << 2
+	return (id & -lsb) | lsb
+}
+
+// Children returns the ids of the 4 children of the tile,
+// in Hilbert curve order. Returns nil for ids at MaxHilbertZoom.
+func (id HilbertID) Children() []HilbertID {
+	if id.Zoom() >= MaxHilbertZoom {
+		return nil
+	}
+
+	lsb := id.lsb()
+	child := id - lsb + lsb>>2
+
+	result := make([]HilbertID, 4)
+	for i := range result {
+		result[i] = child
+		child += lsb >> 1
+	}
+
+	return result
+}
+
+// RangeMin returns the smallest MaxHilbertZoom id contained by this tile.
+func (id HilbertID) RangeMin() HilbertID {
+	return id - (id.lsb() - 1)
+}
+
+// RangeMax returns the largest MaxHilbertZoom id contained by this tile.
+func (id HilbertID) RangeMax() HilbertID {
+	return id + (id.lsb() - 1)
+}
+
+// Contains returns if the given id is fully contained (or equal to) the this id.
+func (id HilbertID) Contains(other HilbertID) bool {
+	return id.RangeMin() <= other && other <= id.RangeMax()
+}
+
+// A HilbertRange is an inclusive range of MaxHilbertZoom ids.
+type HilbertRange struct {
+	Min, Max HilbertID
+}
+
+// Contains returns if all the tiles in the given id are within the range.
+func (r HilbertRange) Contains(id HilbertID) bool {
+	return r.Min <= id.RangeMin() && id.RangeMax() <= r.Max
+}
+
+// HilbertRanges returns the smallest set of sorted, contiguous ranges
+// of MaxHilbertZoom ids that cover the tiles in the set. Tiles deeper
+// than MaxHilbertZoom are skipped.
+func (s Set) HilbertRanges() []HilbertRange {
+	ranges := make([]HilbertRange, 0, len(s))
+	for t, v := range s {
+		if !v || t.Z > MaxHilbertZoom {
+			continue
+		}
+
+		id := t.HilbertID()
+		ranges = append(ranges, HilbertRange{Min: id.RangeMin(), Max: id.RangeMax()})
+	}
+
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Min < ranges[j].Min
+	})
+
+	// MaxHilbertZoom ids are odd so consecutive tiles differ by 2.
+	result := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &result[len(result)-1]
+		if r.Min <= last.Max+2 {
+			if r.Max > last.Max {
+				last.Max = r.Max
+			}
+			continue
+		}
+
+		result = append(result, r)
+	}
+
+	return result
+}
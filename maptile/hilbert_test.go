@@ -0,0 +1,160 @@
+package maptile
+
+import (
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestTileHilbert(t *testing.T) {
+	order := []Tile{New(0, 0, 1), New(0, 1, 1), New(1, 1, 1), New(1, 0, 1)}
+	for i, tile := range order {
+		if d := tile.Hilbert(); d != uint64(i) {
+			t.Errorf("incorrect index for %v: %v != %v", tile, d, i)
+		}
+	}
+
+	for z := Zoom(0); z <= 6; z++ {
+		var prev Tile
+		for d := uint64(0); d < 1<<(2*z); d++ {
+			tile := FromHilbert(d, z)
+			if !tile.Valid() {
+				t.Fatalf("invalid tile: %v", tile)
+			}
+
+			if v := tile.Hilbert(); v != d {
+				t.Fatalf("incorrect round trip: %v != %v", v, d)
+			}
+
+			if v := tile.Parent().Hilbert(); z > 0 && v != d/4 {
+				t.Errorf("parent index should be index/4: %v != %v", v, d/4)
+			}
+
+			if d > 0 {
+				dx := int64(tile.X) - int64(prev.X)
+				dy := int64(tile.Y) - int64(prev.Y)
+				if dx*dx+dy*dy != 1 {
+					t.Errorf("consecutive tiles not adjacent: %v %v", prev, tile)
+				}
+			}
+			prev = tile
+		}
+	}
+}
+
+func TestHilbertID(t *testing.T) {
+	leaf := At(orb.Point{-122.2711, 37.8044}, MaxHilbertZoom)
+	id := leaf.HilbertID()
+
+	if !id.Valid() {
+		t.Fatalf("should be valid")
+	}
+
+	if z := id.Zoom(); z != MaxHilbertZoom {
+		t.Errorf("incorrect zoom: %v", z)
+	}
+
+	if tile := id.Tile(); tile != leaf {
+		t.Errorf("incorrect tile: %v != %v", tile, leaf)
+	}
+
+	if id.Children() != nil {
+		t.Errorf("leaf should not have children")
+	}
+
+	tile := leaf
+	for z := MaxHilbertZoom; z > 0; z-- {
+		parent := id.Parent()
+		tile = tile.Parent()
+
+		if parent != tile.HilbertID() {
+			t.Fatalf("incorrect parent at %d: %v != %v", z, parent.Tile(), tile)
+		}
+
+		if !parent.Contains(id) || !parent.Contains(leaf.HilbertID()) {
+			t.Errorf("parent should contain child")
+		}
+
+		found := false
+		for i, c := range parent.Children() {
+			if c.Parent() != parent {
+				t.Errorf("child parent mismatch")
+			}
+
+			if c == id {
+				found = true
+			}
+
+			if i == 0 && c.RangeMin() != parent.RangeMin() {
+				t.Errorf("first child should start the range")
+			}
+
+			if i == 3 && c.RangeMax() != parent.RangeMax() {
+				t.Errorf("last child should end the range")
+			}
+		}
+
+		if !found {
+			t.Errorf("child not in parent's children")
+		}
+
+		id = parent
+	}
+
+	root := New(0, 0, 0).HilbertID()
+	if root.Parent() != root {
+		t.Errorf("root should be its own parent")
+	}
+
+	if root.RangeMin() != 1 || root.RangeMax() != 1<<63-1 {
+		t.Errorf("incorrect root range: %v %v", root.RangeMin(), root.RangeMax())
+	}
+
+	if New(0, 0, 32).HilbertID().Valid() || HilbertID(2).Valid() || HilbertID(0).Valid() {
+		t.Errorf("should not be valid")
+	}
+}
+
+func TestSetHilbertRanges(t *testing.T) {
+	tile := New(5, 3, 4)
+
+	set := make(Set)
+	for _, c := range tile.Children() {
+		set[c] = true
+	}
+
+	ranges := set.HilbertRanges()
+	if len(ranges) != 1 {
+		t.Fatalf("children should be one range: %v", ranges)
+	}
+
+	id := tile.HilbertID()
+	if ranges[0].Min != id.RangeMin() || ranges[0].Max != id.RangeMax() {
+		t.Errorf("incorrect range: %v", ranges[0])
+	}
+
+	if !ranges[0].Contains(id) {
+		t.Errorf("range should contain tile")
+	}
+
+	// not adjacent along the curve
+	set = Set{
+		FromHilbert(0, 3):  true,
+		FromHilbert(1, 3):  true,
+		FromHilbert(5, 3):  true,
+		FromHilbert(2, 1):  true,
+		FromHilbert(3, 3):  false,
+		FromHilbert(40, 3): true,
+	}
+
+	ranges = set.HilbertRanges()
+	if len(ranges) != 3 {
+		t.Errorf("incorrect number of ranges: %v", ranges)
+	}
+
+	for i := 1; i < len(ranges); i++ {
+		if ranges[i].Min <= ranges[i-1].Max {
+			t.Errorf("ranges should be sorted and disjoint")
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package tilecover
+
+import (
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/maptile"
+)
+
+// HilbertRanges returns the covering set of tiles for the geometry, at
+// the given zoom, as sorted contiguous ranges of maptile.HilbertID values.
+// Objects indexed by the HilbertID of a maptile.MaxHilbertZoom tile can
+// be queried with one range scan per range. Lower zooms return fewer,
+// coarser, ranges.
+func HilbertRanges(g orb.Geometry, z maptile.Zoom) []maptile.HilbertRange {
+	return Geometry(g, z).HilbertRanges()
+}
@@ -0,0 +1,58 @@
+package tilecover
+
+import (
+	"testing"
+
+	"github.com/dadadamarine/orb/maptile"
+)
+
+func TestHilbertRanges(t *testing.T) {
+	f := loadFeature(t, "./testdata/uk.geojson")
+
+	tiles := Geometry(f.Geometry, 9)
+	ranges := HilbertRanges(f.Geometry, 9)
+
+	if len(ranges) == 0 || len(ranges) >= len(tiles) {
+		t.Fatalf("incorrect number of ranges: %d for %d tiles", len(ranges), len(tiles))
+	}
+
+	for tile := range tiles {
+		id := tile.HilbertID()
+
+		found := false
+		for _, r := range ranges {
+			if r.Contains(id) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Errorf("tile not in ranges: %v", tile)
+		}
+	}
+
+	// every range boundary should be a covered tile
+	for _, r := range ranges {
+		if !tiles[ancestor(r.Min, 9)] || !tiles[ancestor(r.Max, 9)] {
+			t.Errorf("range boundary not covered: %v", r)
+		}
+	}
+
+	merged := MergeUp(tiles, 0).HilbertRanges()
+	if len(merged) != len(ranges) {
+		t.Errorf("merged set should have same ranges: %d != %d", len(merged), len(ranges))
+	}
+
+	if r := HilbertRanges(nil, 9); r != nil {
+		t.Errorf("nil geometry should have no ranges: %v", r)
+	}
+}
+
+func ancestor(id maptile.HilbertID, z maptile.Zoom) maptile.Tile {
+	for id.Zoom() > z {
+		id = id.Parent()
+	}
+
+	return id.Tile()
+}
@@ -0,0 +1,138 @@
+// Package network builds graphs from line strings for computing
+// shortest paths and isochrones.
+package network
+
+import (
+	"fmt"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+)
+
+// A Line is an input line string with an id, e.g. the id of a road.
+type Line struct {
+	ID         interface{}
+	LineString orb.LineString
+}
+
+// An Edge connects two nodes of the graph. Edges can be traveled in
+// both directions. If intersections are noded a line can be split into
+// multiple edges with the same id.
+type Edge struct {
+	ID         interface{}
+	From, To   int
+	LineString orb.LineString // from the From node to the To node
+	Weight     float64
+}
+
+// A Graph is a set of nodes connected by edges.
+type Graph struct {
+	Nodes []orb.Point
+	Edges []Edge
+
+	df       orb.DistanceFunc
+	adjacent [][]int // the edges at each node
+}
+
+// New creates a graph from the lines. The lines are connected at shared end
+// points, and optionally at intersections. The edge weights are the length of
+// the line string using the distance function, e.g. planar.Distance or geo.Distance.
+// Lines with less than two points are ignored. The input lines are not modified.
+func New(lines []Line, df orb.DistanceFunc, opts ...Option) *Graph {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.intersections {
+		lines = splitAtIntersections(lines)
+	}
+
+	g := &Graph{df: df}
+
+	nodes := make(map[orb.Point]int)
+	node := func(p orb.Point) int {
+		if i, ok := nodes[p]; ok {
+			return i
+		}
+
+		nodes[p] = len(g.Nodes)
+		g.Nodes = append(g.Nodes, p)
+		g.adjacent = append(g.adjacent, nil)
+		return nodes[p]
+	}
+
+	for _, l := range lines {
+		if len(l.LineString) < 2 {
+			continue
+		}
+
+		e := Edge{
+			ID:         l.ID,
+			From:       node(l.LineString[0]),
+			To:         node(l.LineString[len(l.LineString)-1]),
+			LineString: l.LineString,
+			Weight:     length(l.LineString, df),
+		}
+
+		i := len(g.Edges)
+		g.Edges = append(g.Edges, e)
+		g.adjacent[e.From] = append(g.adjacent[e.From], i)
+		if e.To != e.From {
+			g.adjacent[e.To] = append(g.adjacent[e.To], i)
+		}
+	}
+
+	return g
+}
+
+// NewFromFeatureCollection creates a graph from the LineString and
+// MultiLineString features, the feature ids are used as the edge ids.
+func NewFromFeatureCollection(fc *geojson.FeatureCollection, df orb.DistanceFunc, opts ...Option) (*Graph, error) {
+	var lines []Line
+	for i, f := range fc.Features {
+		switch g := f.Geometry.(type) {
+		case orb.LineString:
+			lines = append(lines, Line{ID: f.ID, LineString: g})
+		case orb.MultiLineString:
+			for _, ls := range g {
+				lines = append(lines, Line{ID: f.ID, LineString: ls})
+			}
+		default:
+			return nil, fmt.Errorf("network: feature %d: geometry must be a line string, got %T", i, f.Geometry)
+		}
+	}
+
+	return New(lines, df, opts...), nil
+}
+
+// NearestNode returns the index of the node closest to the point,
+// -1 if the graph is empty.
+func (g *Graph) NearestNode(p orb.Point) int {
+	best, index := 0.0, -1
+	for i, n := range g.Nodes {
+		if d := g.df(n, p); index == -1 || d < best {
+			best, index = d, i
+		}
+	}
+
+	return index
+}
+
+// other returns the node at the other end of the edge.
+func (g *Graph) other(edge, node int) int {
+	if g.Edges[edge].From == node {
+		return g.Edges[edge].To
+	}
+
+	return g.Edges[edge].From
+}
+
+func length(ls orb.LineString, df orb.DistanceFunc) float64 {
+	sum := 0.0
+	for i := 1; i < len(ls); i++ {
+		sum += df(ls[i-1], ls[i])
+	}
+
+	return sum
+}
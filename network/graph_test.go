@@ -0,0 +1,94 @@
+package network
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+	"github.com/dadadamarine/orb/planar"
+)
+
+func TestNew(t *testing.T) {
+	g := New([]Line{
+		{ID: "a", LineString: orb.LineString{{0, 0}, {1, 0}}},
+		{ID: "b", LineString: orb.LineString{{1, 0}, {1, 2}}},
+		{ID: "c", LineString: orb.LineString{{5, 5}}},
+	}, planar.Distance)
+
+	if l := len(g.Nodes); l != 3 {
+		t.Errorf("incorrect number of nodes: %d", l)
+	}
+
+	expected := []Edge{
+		{ID: "a", From: 0, To: 1, LineString: orb.LineString{{0, 0}, {1, 0}}, Weight: 1},
+		{ID: "b", From: 1, To: 2, LineString: orb.LineString{{1, 0}, {1, 2}}, Weight: 2},
+	}
+	if !reflect.DeepEqual(g.Edges, expected) {
+		t.Errorf("incorrect edges: %v", g.Edges)
+	}
+
+	if v := g.NearestNode(orb.Point{1.1, 1.8}); v != 2 {
+		t.Errorf("incorrect nearest node: %v", v)
+	}
+}
+
+func TestNew_intersections(t *testing.T) {
+	lines := []Line{
+		{ID: "h", LineString: orb.LineString{{0, 1}, {4, 1}}},
+		{ID: "v", LineString: orb.LineString{{2, 0}, {2, 3}}},
+		{ID: "t", LineString: orb.LineString{{3, 1}, {3, 3}}}, // touches the middle of h
+		{ID: "x", LineString: orb.LineString{{10, 10}, {11, 11}}},
+	}
+
+	g := New(lines, planar.Distance)
+	if l := len(g.Edges); l != 4 {
+		t.Errorf("should not split without the option: %d", l)
+	}
+
+	g = New(lines, planar.Distance, NodeIntersections(true))
+
+	var parts []orb.LineString
+	for _, e := range g.Edges {
+		parts = append(parts, e.LineString)
+	}
+
+	expected := []orb.LineString{
+		{{0, 1}, {2, 1}}, {{2, 1}, {3, 1}}, {{3, 1}, {4, 1}},
+		{{2, 0}, {2, 1}}, {{2, 1}, {2, 3}},
+		{{3, 1}, {3, 3}},
+		{{10, 10}, {11, 11}},
+	}
+	if !reflect.DeepEqual(parts, expected) {
+		t.Errorf("incorrect edges: %v", parts)
+	}
+
+	if g.Edges[0].ID != "h" || g.Edges[3].ID != "v" {
+		t.Errorf("should keep the ids")
+	}
+
+	if !lines[0].LineString.Equal(orb.LineString{{0, 1}, {4, 1}}) {
+		t.Errorf("input should not be modified")
+	}
+}
+
+func TestNewFromFeatureCollection(t *testing.T) {
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(orb.LineString{{0, 0}, {1, 0}}))
+	fc.Append(geojson.NewFeature(orb.MultiLineString{{{1, 0}, {2, 0}}, {{2, 0}, {3, 0}}}))
+
+	g, err := NewFromFeatureCollection(fc, planar.Distance)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if l := len(g.Edges); l != 3 {
+		t.Errorf("incorrect number of edges: %d", l)
+	}
+
+	fc.Append(geojson.NewFeature(orb.Point{1, 2}))
+	_, err = NewFromFeatureCollection(fc, planar.Distance)
+	if err == nil {
+		t.Errorf("should return error for points")
+	}
+}
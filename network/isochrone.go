@@ -0,0 +1,81 @@
+package network
+
+import (
+	"sort"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/linref"
+)
+
+// Isochrone returns the convex hull of the parts of the graph that can be
+// reached from the node within the max weight. Edges that are partially
+// reachable are included up to the max weight. Returns nil if the node
+// is not in the graph.
+func (g *Graph) Isochrone(from int, maxWeight float64) orb.Polygon {
+	reached := g.Reachable(from, maxWeight)
+	if reached == nil {
+		return nil
+	}
+
+	var points []orb.Point
+	for node := range reached {
+		points = append(points, g.Nodes[node])
+	}
+
+	for _, e := range g.Edges {
+		if d, ok := reached[e.From]; ok {
+			part := linref.Substring(e.LineString, g.df, 0, maxWeight-d)
+			points = append(points, part...)
+		}
+
+		if d, ok := reached[e.To]; ok {
+			part := linref.Substring(e.LineString, g.df, e.Weight-(maxWeight-d), e.Weight)
+			points = append(points, part...)
+		}
+	}
+
+	return orb.Polygon{convexHull(points)}
+}
+
+// convexHull returns the counter-clockwise convex hull of the points
+// using the monotone chain algorithm.
+func convexHull(points []orb.Point) orb.Ring {
+	sort.Slice(points, func(i, j int) bool {
+		if points[i][0] != points[j][0] {
+			return points[i][0] < points[j][0]
+		}
+
+		return points[i][1] < points[j][1]
+	})
+
+	hull := make(orb.Ring, 0, 2*len(points)+1)
+
+	// lower hull
+	for _, p := range points {
+		for len(hull) >= 2 && turn(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+
+	// upper hull
+	lower := len(hull) + 1
+	for i := len(points) - 2; i >= 0; i-- {
+		p := points[i]
+		for len(hull) >= lower && turn(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+
+	// the last point is the first, so the ring is closed
+	if len(hull) == 1 {
+		hull = append(hull, hull[0])
+	}
+
+	return hull
+}
+
+func turn(a, b, c orb.Point) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
@@ -0,0 +1,135 @@
+package network
+
+import (
+	"sort"
+
+	"github.com/dadadamarine/orb"
+)
+
+type split struct {
+	index int     // the segment index
+	t     float64 // the position along the segment
+	point orb.Point
+}
+
+// splitAtIntersections splits the lines where they intersect each other.
+// Collinear overlapping segments are not split.
+func splitAtIntersections(lines []Line) []Line {
+	bounds := make([]orb.Bound, len(lines))
+	for i, l := range lines {
+		bounds[i] = l.LineString.Bound()
+	}
+
+	splits := make([][]split, len(lines))
+	for i, li := range lines {
+		for j := i + 1; j < len(lines); j++ {
+			lj := lines[j]
+			if len(li.LineString) < 2 || len(lj.LineString) < 2 || !bounds[i].Intersects(bounds[j]) {
+				continue
+			}
+
+			for a := 1; a < len(li.LineString); a++ {
+				for b := 1; b < len(lj.LineString); b++ {
+					p, t, u, ok := intersection(
+						li.LineString[a-1], li.LineString[a],
+						lj.LineString[b-1], lj.LineString[b],
+					)
+					if !ok {
+						continue
+					}
+
+					splits[i] = append(splits[i], split{index: a - 1, t: t, point: p})
+					splits[j] = append(splits[j], split{index: b - 1, t: u, point: p})
+				}
+			}
+		}
+	}
+
+	result := make([]Line, 0, len(lines))
+	for i, l := range lines {
+		result = append(result, splitLine(l, splits[i])...)
+	}
+
+	return result
+}
+
+// splitLine splits the line at the split points. The result are new
+// line strings so the input is not modified.
+func splitLine(l Line, splits []split) []Line {
+	if len(l.LineString) < 2 {
+		return nil
+	}
+
+	sort.Slice(splits, func(i, j int) bool {
+		if splits[i].index != splits[j].index {
+			return splits[i].index < splits[j].index
+		}
+
+		return splits[i].t < splits[j].t
+	})
+
+	var result []Line
+	current := orb.LineString{l.LineString[0]}
+	for k := 1; k < len(l.LineString); k++ {
+		for len(splits) > 0 && splits[0].index == k-1 {
+			p := splits[0].point
+			splits = splits[1:]
+
+			if !p.Equal(current[len(current)-1]) {
+				current = append(current, p)
+			}
+
+			if len(current) >= 2 {
+				result = append(result, Line{ID: l.ID, LineString: current})
+				current = orb.LineString{p}
+			}
+		}
+
+		if !l.LineString[k].Equal(current[len(current)-1]) {
+			current = append(current, l.LineString[k])
+		}
+	}
+
+	if len(current) >= 2 {
+		result = append(result, Line{ID: l.ID, LineString: current})
+	}
+
+	return result
+}
+
+// intersection returns the intersection point of the segments [a, b] and [c, d]
+// and the position along each segment. Uses the end points when the
+// intersection is at an end so the nodes will match exactly.
+func intersection(a, b, c, d orb.Point) (orb.Point, float64, float64, bool) {
+	r := orb.Point{b[0] - a[0], b[1] - a[1]}
+	s := orb.Point{d[0] - c[0], d[1] - c[1]}
+
+	denom := cross(r, s)
+	if denom == 0 {
+		return orb.Point{}, 0, 0, false // parallel or collinear
+	}
+
+	ca := orb.Point{c[0] - a[0], c[1] - a[1]}
+	t := cross(ca, s) / denom
+	u := cross(ca, r) / denom
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return orb.Point{}, 0, 0, false
+	}
+
+	switch {
+	case t == 0:
+		return a, t, u, true
+	case t == 1:
+		return b, t, u, true
+	case u == 0:
+		return c, t, u, true
+	case u == 1:
+		return d, t, u, true
+	}
+
+	return orb.Point{a[0] + t*r[0], a[1] + t*r[1]}, t, u, true
+}
+
+func cross(a, b orb.Point) float64 {
+	return a[0]*b[1] - a[1]*b[0]
+}
@@ -0,0 +1,17 @@
+package network
+
+type options struct {
+	intersections bool
+}
+
+// An Option is a possible parameter when building the graph.
+type Option func(*options)
+
+// NodeIntersections will split the line strings where they intersect,
+// including where an end point touches the middle of another line.
+// By default the line strings are only connected at shared end points.
+func NodeIntersections(yes bool) Option {
+	return func(o *options) {
+		o.intersections = yes
+	}
+}
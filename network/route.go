@@ -0,0 +1,168 @@
+package network
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/dadadamarine/orb"
+)
+
+// A Path is a route through the graph.
+type Path struct {
+	Nodes      []int
+	Edges      []int
+	Weight     float64
+	LineString orb.LineString
+}
+
+// ShortestPath finds the shortest path between the nodes using Dijkstra's
+// algorithm. Returns nil if the nodes are not connected.
+func (g *Graph) ShortestPath(from, to int) *Path {
+	return g.AStar(from, to, nil)
+}
+
+// AStar finds the shortest path between the nodes using the A* algorithm.
+// The heuristic should be the same, or a lower bound of, the distance function
+// used to build the graph, e.g. geo.Distance. A nil heuristic is
+// Dijkstra's algorithm. Returns nil if the nodes are not connected.
+func (g *Graph) AStar(from, to int, heuristic orb.DistanceFunc) *Path {
+	if from < 0 || from >= len(g.Nodes) || to < 0 || to >= len(g.Nodes) {
+		return nil
+	}
+
+	estimate := func(n int) float64 {
+		if heuristic == nil {
+			return 0
+		}
+
+		return heuristic(g.Nodes[n], g.Nodes[to])
+	}
+
+	s := g.search(from, math.Inf(1), estimate, to)
+	if _, ok := s.dist[to]; !ok {
+		return nil
+	}
+
+	return g.path(s, to)
+}
+
+// Reachable returns the weight of the shortest path to all the nodes
+// that can be reached within the max weight.
+func (g *Graph) Reachable(from int, maxWeight float64) map[int]float64 {
+	if from < 0 || from >= len(g.Nodes) {
+		return nil
+	}
+
+	s := g.search(from, maxWeight, nil, -1)
+	return s.dist
+}
+
+type search struct {
+	dist map[int]float64
+	prev map[int]int // the edge used to reach the node, -1 for the start
+}
+
+// search runs Dijkstra's algorithm, or A* if an estimate is provided,
+// stopping when the target is found or the cutoff is reached.
+func (g *Graph) search(from int, cutoff float64, estimate func(int) float64, target int) *search {
+	s := &search{
+		dist: map[int]float64{from: 0},
+		prev: map[int]int{from: -1},
+	}
+
+	done := make(map[int]bool)
+	queue := &nodeQueue{{node: from}}
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(queueItem)
+		if done[item.node] {
+			continue
+		}
+		done[item.node] = true
+
+		if item.node == target {
+			break
+		}
+
+		for _, e := range g.adjacent[item.node] {
+			next := g.other(e, item.node)
+			d := s.dist[item.node] + g.Edges[e].Weight
+			if d > cutoff {
+				continue
+			}
+
+			if v, ok := s.dist[next]; ok && v <= d {
+				continue
+			}
+
+			s.dist[next] = d
+			s.prev[next] = e
+
+			priority := d
+			if estimate != nil {
+				priority += estimate(next)
+			}
+			heap.Push(queue, queueItem{node: next, priority: priority})
+		}
+	}
+
+	return s
+}
+
+// path builds the path to the node from the search results.
+func (g *Graph) path(s *search, to int) *Path {
+	p := &Path{
+		Nodes:  []int{to},
+		Weight: s.dist[to],
+	}
+
+	node := to
+	for s.prev[node] != -1 {
+		e := s.prev[node]
+		node = g.other(e, node)
+
+		p.Edges = append(p.Edges, e)
+		p.Nodes = append(p.Nodes, node)
+	}
+
+	reverseInts(p.Nodes)
+	reverseInts(p.Edges)
+
+	p.LineString = orb.LineString{g.Nodes[p.Nodes[0]]}
+	for i, e := range p.Edges {
+		ls := g.Edges[e].LineString
+		if g.Edges[e].From == p.Nodes[i] {
+			p.LineString = append(p.LineString, ls[1:]...)
+		} else {
+			for j := len(ls) - 2; j >= 0; j-- {
+				p.LineString = append(p.LineString, ls[j])
+			}
+		}
+	}
+
+	return p
+}
+
+func reverseInts(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+type queueItem struct {
+	node     int
+	priority float64
+}
+
+type nodeQueue []queueItem
+
+func (q nodeQueue) Len() int            { return len(q) }
+func (q nodeQueue) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q nodeQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *nodeQueue) Push(x interface{}) { *q = append(*q, x.(queueItem)) }
+
+func (q *nodeQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,97 @@
+package network
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/planar"
+)
+
+// testGraph is a square with a long detour on the bottom.
+//
+//	3 --- 2
+//	|     |
+//	0 ~~~ 1
+func testGraph() *Graph {
+	return New([]Line{
+		{ID: "bottom", LineString: orb.LineString{{0, 0}, {2, -5}, {4, 0}}},
+		{ID: "right", LineString: orb.LineString{{2, 2}, {2, 1}, {4, 0}}},
+		{ID: "top", LineString: orb.LineString{{2, 2}, {0, 2}}},
+		{ID: "left", LineString: orb.LineString{{0, 2}, {0, 0}}},
+		{ID: "island", LineString: orb.LineString{{10, 10}, {11, 10}}},
+	}, planar.Distance)
+}
+
+func TestGraph_ShortestPath(t *testing.T) {
+	g := testGraph()
+
+	for _, h := range []orb.DistanceFunc{nil, planar.Distance} {
+		p := g.AStar(0, 1, h)
+		if p == nil {
+			t.Fatalf("should find path")
+		}
+
+		if !reflect.DeepEqual(p.Edges, []int{3, 2, 1}) {
+			t.Errorf("incorrect edges: %v", p.Edges)
+		}
+
+		if !reflect.DeepEqual(p.Nodes, []int{0, 3, 2, 1}) {
+			t.Errorf("incorrect nodes: %v", p.Nodes)
+		}
+
+		expected := orb.LineString{{0, 0}, {0, 2}, {2, 2}, {2, 1}, {4, 0}}
+		if !p.LineString.Equal(expected) {
+			t.Errorf("incorrect line string: %v", p.LineString)
+		}
+
+		if w := 2 + 2 + 1 + planar.Distance(orb.Point{2, 1}, orb.Point{4, 0}); p.Weight != w {
+			t.Errorf("incorrect weight: %v != %v", p.Weight, w)
+		}
+	}
+}
+
+func TestGraph_ShortestPath_notConnected(t *testing.T) {
+	g := testGraph()
+
+	if p := g.ShortestPath(0, 4); p != nil {
+		t.Errorf("should not find path: %v", p)
+	}
+
+	if p := g.ShortestPath(0, 100); p != nil {
+		t.Errorf("should not find path to missing node: %v", p)
+	}
+
+	p := g.ShortestPath(2, 2)
+	if p == nil || p.Weight != 0 || !p.LineString.Equal(orb.LineString{{2, 2}}) {
+		t.Errorf("incorrect path to self: %v", p)
+	}
+}
+
+func TestGraph_Isochrone(t *testing.T) {
+	g := testGraph()
+
+	reached := g.Reachable(3, 2)
+	if !reflect.DeepEqual(reached, map[int]float64{3: 0, 0: 2, 2: 2}) {
+		t.Errorf("incorrect reachable: %v", reached)
+	}
+
+	iso := g.Isochrone(3, 3)
+	if !iso[0].Closed() || iso[0].Orientation() != orb.CCW {
+		t.Errorf("should be closed and counter-clockwise: %v", iso)
+	}
+
+	for _, p := range []orb.Point{{0, 0}, {0, 2}, {2, 2}, {2, 1.5}} {
+		if !planar.PolygonContains(iso, p) && planar.DistanceFrom(iso, p) > 1e-9 {
+			t.Errorf("should contain %v: %v", p, iso)
+		}
+	}
+
+	if planar.PolygonContains(iso, orb.Point{3, 0.5}) {
+		t.Errorf("should not reach the end of the right edge: %v", iso)
+	}
+
+	if v := g.Isochrone(-1, 3); v != nil {
+		t.Errorf("should be nil for missing node: %v", v)
+	}
+}
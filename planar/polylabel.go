@@ -0,0 +1,274 @@
+package planar
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/dadadamarine/orb"
+)
+
+// Polylabel returns the pole of inaccessibility of the polygon, the interior
+// point farthest from the boundary, and its distance from the boundary. The result
+// is within the precision of the best point. Useful for placing labels inside
+// concave polygons where the centroid can be outside.
+// Based on the mapbox polylabel algorithm, https://github.com/mapbox/polylabel.
+func Polylabel(p orb.Polygon, precision float64) (orb.Point, float64) {
+	if len(p) == 0 || len(p[0]) == 0 {
+		return orb.Point{}, 0
+	}
+
+	b := p[0].Bound()
+	width, height := b.Max[0]-b.Min[0], b.Max[1]-b.Min[1]
+
+	cellSize := math.Min(width, height)
+	if cellSize == 0 {
+		return b.Min, 0
+	}
+
+	// cover the polygon with initial cells
+	queue := &cellQueue{}
+	h := cellSize / 2
+	for x := b.Min[0]; x < b.Max[0]; x += cellSize {
+		for y := b.Min[1]; y < b.Max[1]; y += cellSize {
+			heap.Push(queue, newCell(orb.Point{x + h, y + h}, h, p))
+		}
+	}
+
+	// take the centroid as the first best guess
+	centroid, _ := CentroidArea(p)
+	best := newCell(centroid, 0, p)
+
+	// second guess: the bound center
+	if c := newCell(b.Center(), 0, p); c.d > best.d {
+		best = c
+	}
+
+	for queue.Len() > 0 {
+		c := heap.Pop(queue).(*cell)
+
+		if c.d > best.d {
+			best = c
+		}
+
+		// do not drill down further if there's no chance of a better solution
+		if c.max-best.d <= precision {
+			continue
+		}
+
+		h := c.h / 2
+		heap.Push(queue, newCell(orb.Point{c.center[0] - h, c.center[1] - h}, h, p))
+		heap.Push(queue, newCell(orb.Point{c.center[0] + h, c.center[1] - h}, h, p))
+		heap.Push(queue, newCell(orb.Point{c.center[0] - h, c.center[1] + h}, h, p))
+		heap.Push(queue, newCell(orb.Point{c.center[0] + h, c.center[1] + h}, h, p))
+	}
+
+	return best.center, best.d
+}
+
+// MultiPolygonPolylabel returns the pole of inaccessibility of the polygon
+// with the point farthest from its boundary.
+func MultiPolygonPolylabel(mp orb.MultiPolygon, precision float64) (orb.Point, float64) {
+	var point orb.Point
+	dist := math.Inf(-1)
+	for _, p := range mp {
+		if pt, d := Polylabel(p, precision); d > dist {
+			point, dist = pt, d
+		}
+	}
+
+	if math.IsInf(dist, -1) {
+		return orb.Point{}, 0
+	}
+
+	return point, dist
+}
+
+// PointOnSurface returns a point guaranteed to be inside polygons and
+// multi-polygons, the pole of inaccessibility of the largest polygon.
+// For lines it is the vertex closest to the centroid, ignoring the end points,
+// for points the point closest to the centroid. For collections the geometry
+// with the highest dimension is used. Polygons without an area have no
+// interior, the point is then on the boundary.
+func PointOnSurface(g orb.Geometry) orb.Point {
+	if g == nil {
+		return orb.Point{}
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		return g
+	case orb.MultiPoint:
+		return closestToCentroid(g, []orb.Point(g))
+	case orb.LineString:
+		return closestToCentroid(g, interiorPoints(g))
+	case orb.MultiLineString:
+		var points []orb.Point
+		for _, ls := range g {
+			points = append(points, interiorPoints(ls)...)
+		}
+
+		return closestToCentroid(g, points)
+	case orb.Ring:
+		return polygonPointOnSurface(orb.Polygon{g})
+	case orb.Polygon:
+		return polygonPointOnSurface(g)
+	case orb.MultiPolygon:
+		largest, area := -1, 0.0
+		for i, p := range g {
+			if a := math.Abs(Area(p)); largest == -1 || a > area {
+				largest, area = i, a
+			}
+		}
+
+		if largest == -1 {
+			return orb.Point{}
+		}
+
+		return polygonPointOnSurface(g[largest])
+	case orb.Collection:
+		var best orb.Geometry
+		for _, c := range g {
+			if c == nil {
+				continue
+			}
+
+			if best == nil || c.Dimensions() > best.Dimensions() {
+				best = c
+			}
+		}
+
+		return PointOnSurface(best)
+	case orb.Bound:
+		return g.Center()
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+func polygonPointOnSurface(p orb.Polygon) orb.Point {
+	if len(p) == 0 || len(p[0]) == 0 {
+		return orb.Point{}
+	}
+
+	b := p[0].Bound()
+	precision := math.Max(b.Max[0]-b.Min[0], b.Max[1]-b.Min[1]) / 100
+
+	point, d := Polylabel(p, precision)
+	if d > 0 {
+		return point
+	}
+
+	// polylabel may not find an interior point for very thin polygons.
+	if point, ok := scanLinePoint(p); ok {
+		return point
+	}
+
+	// the polygon has no area and no interior,
+	// the polylabel point is the closest there is.
+	return point
+}
+
+// scanLinePoint finds the middle of the widest interior section of a horizontal
+// line through the middle of the polygon. The line avoids the vertexes.
+func scanLinePoint(p orb.Polygon) (orb.Point, bool) {
+	center := p[0].Bound().Center()[1]
+
+	lo, hi := math.Inf(-1), math.Inf(1)
+	for _, r := range p {
+		for _, pt := range r {
+			if pt[1] <= center {
+				lo = math.Max(lo, pt[1])
+			} else {
+				hi = math.Min(hi, pt[1])
+			}
+		}
+	}
+
+	if math.IsInf(lo, 0) || math.IsInf(hi, 0) {
+		return orb.Point{}, false
+	}
+	y := (lo + hi) / 2
+
+	var xs []float64
+	for _, r := range p {
+		for i := range r {
+			a, b := r[i], r[(i+1)%len(r)]
+			if (a[1] > y) != (b[1] > y) {
+				xs = append(xs, a[0]+(y-a[1])*(b[0]-a[0])/(b[1]-a[1]))
+			}
+		}
+	}
+	sort.Float64s(xs)
+
+	best, width := orb.Point{}, 0.0
+	for i := 0; i+1 < len(xs); i += 2 {
+		if w := xs[i+1] - xs[i]; w > width {
+			best, width = orb.Point{(xs[i] + xs[i+1]) / 2, y}, w
+		}
+	}
+
+	return best, width > 0
+}
+
+func interiorPoints(ls orb.LineString) []orb.Point {
+	if len(ls) > 2 {
+		return ls[1 : len(ls)-1]
+	}
+
+	return ls
+}
+
+func closestToCentroid(g orb.Geometry, points []orb.Point) orb.Point {
+	if len(points) == 0 {
+		return orb.Point{}
+	}
+
+	centroid, _ := CentroidArea(g)
+
+	best := points[0]
+	for _, p := range points[1:] {
+		if DistanceSquared(p, centroid) < DistanceSquared(best, centroid) {
+			best = p
+		}
+	}
+
+	return best
+}
+
+type cell struct {
+	center orb.Point
+	h      float64 // half the cell size
+	d      float64 // distance from the cell center to the polygon
+	max    float64 // max distance to the polygon within the cell
+}
+
+func newCell(center orb.Point, h float64, p orb.Polygon) *cell {
+	d := DistanceFrom(p, center)
+	if !PolygonContains(p, center) {
+		d = -d
+	}
+
+	return &cell{
+		center: center,
+		h:      h,
+		d:      d,
+		max:    d + h*math.Sqrt2,
+	}
+}
+
+// cellQueue is a max heap of cells by the max distance.
+type cellQueue []*cell
+
+func (q cellQueue) Len() int            { return len(q) }
+func (q cellQueue) Less(i, j int) bool  { return q[i].max > q[j].max }
+func (q cellQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *cellQueue) Push(x interface{}) { *q = append(*q, x.(*cell)) }
+
+func (q *cellQueue) Pop() interface{} {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
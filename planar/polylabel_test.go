@@ -0,0 +1,189 @@
+package planar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+// cShape is a C shaped polygon with the centroid outside.
+var cShape = orb.Polygon{{
+	{0, 0}, {10, 0}, {10, 2}, {2, 2}, {2, 8}, {10, 8}, {10, 10}, {0, 10}, {0, 0},
+}}
+
+func TestPolylabel(t *testing.T) {
+	cases := []struct {
+		name     string
+		polygon  orb.Polygon
+		point    orb.Point
+		distance float64
+	}{
+		{
+			name:     "square",
+			polygon:  orb.Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}},
+			point:    orb.Point{2, 2},
+			distance: 2,
+		},
+		{
+			name: "square with hole",
+			polygon: orb.Polygon{
+				{{0, 0}, {6, 0}, {6, 6}, {0, 6}, {0, 0}},
+				{{1, 1}, {1, 5}, {4, 5}, {4, 1}, {1, 1}},
+			},
+			point:    orb.Point{5, 2},
+			distance: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, d := Polylabel(tc.polygon, 0.01)
+			if Distance(p, tc.point) > 0.5 {
+				t.Errorf("incorrect point: %v != %v", p, tc.point)
+			}
+
+			if math.Abs(d-tc.distance) > 0.01 {
+				t.Errorf("incorrect distance: %v != %v", d, tc.distance)
+			}
+		})
+	}
+}
+
+func TestPolylabel_cShape(t *testing.T) {
+	centroid, _ := CentroidArea(cShape)
+	if PolygonContains(cShape, centroid) {
+		t.Fatalf("centroid should be outside for the test: %v", centroid)
+	}
+
+	p, d := Polylabel(cShape, 0.01)
+	if !PolygonContains(cShape, p) {
+		t.Errorf("should be inside: %v", p)
+	}
+
+	if math.Abs(d-DistanceFrom(cShape, p)) > 1e-9 {
+		t.Errorf("incorrect distance: %v != %v", d, DistanceFrom(cShape, p))
+	}
+
+	// the widest parts are the corners, where the inscribed circle
+	// touches the two outer edges and the inner corner.
+	if expected := 2 / (1 + 1/math.Sqrt2); math.Abs(d-expected) > 0.01 {
+		t.Errorf("incorrect distance: %v", d)
+	}
+}
+
+func TestPolylabel_degenerate(t *testing.T) {
+	p, d := Polylabel(orb.Polygon{{{1, 1}, {2, 1}, {1, 1}}}, 1)
+	if !p.Equal(orb.Point{1, 1}) || d != 0 {
+		t.Errorf("incorrect result: %v %v", p, d)
+	}
+
+	p, d = Polylabel(orb.Polygon{}, 1)
+	if !p.Equal(orb.Point{}) || d != 0 {
+		t.Errorf("incorrect result: %v %v", p, d)
+	}
+}
+
+func TestMultiPolygonPolylabel(t *testing.T) {
+	mp := orb.MultiPolygon{
+		{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+		{{{10, 10}, {14, 10}, {14, 14}, {10, 14}, {10, 10}}},
+	}
+
+	p, d := MultiPolygonPolylabel(mp, 0.01)
+	if Distance(p, orb.Point{12, 12}) > 0.02 || math.Abs(d-2) > 0.01 {
+		t.Errorf("incorrect result: %v %v", p, d)
+	}
+}
+
+func TestPointOnSurface(t *testing.T) {
+	thin := orb.Polygon{{{0, 0}, {100, 0}, {100, 0.001}, {0, 0.0005}, {0, 0}}}
+
+	cases := []struct {
+		name   string
+		geom   orb.Geometry
+		inside func(orb.Point) bool
+	}{
+		{
+			name: "c shape",
+			geom: cShape,
+			inside: func(p orb.Point) bool {
+				return PolygonContains(cShape, p) && DistanceFrom(cShape, p) > 0
+			},
+		},
+		{
+			name: "thin",
+			geom: thin,
+			inside: func(p orb.Point) bool {
+				return PolygonContains(thin, p) && DistanceFrom(thin, p) > 0
+			},
+		},
+		{
+			name: "multi polygon",
+			geom: orb.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}, cShape},
+			inside: func(p orb.Point) bool {
+				return PolygonContains(cShape, p) && DistanceFrom(cShape, p) > 0
+			},
+		},
+		{
+			name: "collection",
+			geom: orb.Collection{orb.Point{100, 100}, cShape.Clone()},
+			inside: func(p orb.Point) bool {
+				return PolygonContains(cShape, p)
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := PointOnSurface(tc.geom)
+			if !tc.inside(p) {
+				t.Errorf("should be inside: %v", p)
+			}
+		})
+	}
+}
+
+func TestPointOnSurface_lines(t *testing.T) {
+	ls := orb.LineString{{0, 0}, {1, 5}, {2, 1}, {4, 0}}
+	if p := PointOnSurface(ls); !p.Equal(orb.Point{2, 1}) {
+		t.Errorf("incorrect point: %v", p)
+	}
+
+	if p := PointOnSurface(orb.LineString{{0, 0}, {4, 0}}); !p.Equal(orb.Point{0, 0}) {
+		t.Errorf("should use the end points: %v", p)
+	}
+
+	mp := orb.MultiPoint{{0, 0}, {1, 1}, {5, 5}}
+	if p := PointOnSurface(mp); !p.Equal(orb.Point{1, 1}) {
+		t.Errorf("incorrect point: %v", p)
+	}
+
+	for _, g := range orb.AllGeometries {
+		PointOnSurface(g)
+	}
+}
+
+func TestPointOnSurface_degenerate(t *testing.T) {
+	// no interior, the polylabel point rather than a vertex
+	p := orb.Polygon{{{0, 0}, {2, 2}, {0, 0}}}
+	if pt := PointOnSurface(p); !pt.Equal(orb.Point{1, 1}) {
+		t.Errorf("incorrect point: %v", pt)
+	}
+
+	c := orb.Collection{nil, cShape.Clone(), nil}
+	if pt := PointOnSurface(c); !PolygonContains(cShape, pt) {
+		t.Errorf("should skip nil geometries: %v", pt)
+	}
+
+	if pt := PointOnSurface(orb.Collection{nil}); !pt.Equal(orb.Point{}) {
+		t.Errorf("incorrect point: %v", pt)
+	}
+}
+
+func TestScanLinePoint(t *testing.T) {
+	p, ok := scanLinePoint(cShape)
+	if !ok || !PolygonContains(cShape, p) {
+		t.Errorf("should be inside: %v", p)
+	}
+}
@@ -0,0 +1,216 @@
+package project
+
+import (
+	"math"
+
+	"github.com/dadadamarine/orb"
+)
+
+// LambertConformalConic is the ellipsoidal Lambert conformal conic
+// projection with two standard parallels. For the one standard parallel
+// variant set both parallels to the same latitude and the scale factor.
+type LambertConformalConic struct {
+	Ellipsoid Ellipsoid
+
+	// Lon0 and Lat0 are the false origin in degrees.
+	Lon0, Lat0 float64
+
+	// Lat1 and Lat2 are the standard parallels in degrees.
+	Lat1, Lat2 float64
+
+	// K0 is the scale factor on the standard parallel, zero is the same as 1.
+	K0 float64
+
+	FalseEasting, FalseNorthing float64
+}
+
+type lccParams struct {
+	e, n, af, r0 float64
+}
+
+func (l LambertConformalConic) params() lccParams {
+	e := math.Sqrt(l.Ellipsoid.e2())
+	lat1, lat2 := deg2rad(l.Lat1), deg2rad(l.Lat2)
+
+	m1, t1 := lccM(lat1, e), lccT(lat1, e)
+
+	n := math.Sin(lat1)
+	if l.Lat1 != l.Lat2 {
+		m2, t2 := lccM(lat2, e), lccT(lat2, e)
+		n = (math.Log(m1) - math.Log(m2)) / (math.Log(t1) - math.Log(t2))
+	}
+
+	k0 := l.K0
+	if k0 == 0 {
+		k0 = 1
+	}
+
+	af := l.Ellipsoid.A * k0 * m1 / (n * math.Pow(t1, n))
+	return lccParams{
+		e:  e,
+		n:  n,
+		af: af,
+		r0: af * math.Pow(lccT(deg2rad(l.Lat0), e), n),
+	}
+}
+
+func lccM(lat, e float64) float64 {
+	sin, cos := math.Sincos(lat)
+	return cos / math.Sqrt(1-e*e*sin*sin)
+}
+
+func lccT(lat, e float64) float64 {
+	sin := math.Sin(lat)
+	return math.Tan(math.Pi/4-lat/2) / math.Pow((1-e*sin)/(1+e*sin), e/2)
+}
+
+// Forward projects the lon/lat point, in degrees, to easting and northing.
+func (l LambertConformalConic) Forward(p orb.Point) orb.Point {
+	c := l.params()
+
+	r := 0.0
+	if lat := deg2rad(p[1]); math.Abs(lat) < math.Pi/2 || lat*c.n < 0 {
+		r = c.af * math.Pow(lccT(lat, c.e), c.n)
+	}
+
+	theta := c.n * deg2rad(p[0]-l.Lon0)
+	return orb.Point{
+		l.FalseEasting + r*math.Sin(theta),
+		l.FalseNorthing + c.r0 - r*math.Cos(theta),
+	}
+}
+
+// Inverse projects the easting and northing to a lon/lat point in degrees.
+func (l LambertConformalConic) Inverse(p orb.Point) orb.Point {
+	c := l.params()
+
+	x := p[0] - l.FalseEasting
+	y := c.r0 - (p[1] - l.FalseNorthing)
+
+	sign := 1.0
+	if c.n < 0 {
+		sign = -1
+	}
+
+	r := sign * math.Hypot(x, y)
+	theta := math.Atan2(sign*x, sign*y)
+
+	if r == 0 {
+		return orb.Point{l.Lon0, sign * 90}
+	}
+
+	t := math.Pow(r/c.af, 1/c.n)
+
+	lat := math.Pi/2 - 2*math.Atan(t)
+	for i := 0; i < 15; i++ {
+		sin := math.Sin(lat)
+		next := math.Pi/2 - 2*math.Atan(t*math.Pow((1-c.e*sin)/(1+c.e*sin), c.e/2))
+		if math.Abs(next-lat) < 1e-14 {
+			lat = next
+			break
+		}
+		lat = next
+	}
+
+	return orb.Point{l.Lon0 + rad2deg(theta/c.n), rad2deg(lat)}
+}
+
+// AlbersEqualArea is the ellipsoidal Albers equal area conic projection.
+type AlbersEqualArea struct {
+	Ellipsoid Ellipsoid
+
+	// Lon0 and Lat0 are the false origin in degrees.
+	Lon0, Lat0 float64
+
+	// Lat1 and Lat2 are the standard parallels in degrees.
+	Lat1, Lat2 float64
+
+	FalseEasting, FalseNorthing float64
+}
+
+type albersParams struct {
+	e, e2, n, c, r0 float64
+}
+
+func (a AlbersEqualArea) params() albersParams {
+	e2 := a.Ellipsoid.e2()
+	e := math.Sqrt(e2)
+	lat1, lat2 := deg2rad(a.Lat1), deg2rad(a.Lat2)
+
+	m1, q1 := lccM(lat1, e), albersQ(lat1, e)
+
+	n := math.Sin(lat1)
+	if a.Lat1 != a.Lat2 {
+		m2, q2 := lccM(lat2, e), albersQ(lat2, e)
+		n = (m1*m1 - m2*m2) / (q2 - q1)
+	}
+
+	c := m1*m1 + n*q1
+	return albersParams{
+		e:  e,
+		e2: e2,
+		n:  n,
+		c:  c,
+		r0: a.Ellipsoid.A * math.Sqrt(c-n*albersQ(deg2rad(a.Lat0), e)) / n,
+	}
+}
+
+func albersQ(lat, e float64) float64 {
+	sin := math.Sin(lat)
+	if e == 0 {
+		return 2 * sin
+	}
+
+	return (1 - e*e) * (sin/(1-e*e*sin*sin) - math.Log((1-e*sin)/(1+e*sin))/(2*e))
+}
+
+// Forward projects the lon/lat point, in degrees, to easting and northing.
+func (a AlbersEqualArea) Forward(p orb.Point) orb.Point {
+	c := a.params()
+
+	r := a.Ellipsoid.A * math.Sqrt(c.c-c.n*albersQ(deg2rad(p[1]), c.e)) / c.n
+	theta := c.n * deg2rad(p[0]-a.Lon0)
+
+	return orb.Point{
+		a.FalseEasting + r*math.Sin(theta),
+		a.FalseNorthing + c.r0 - r*math.Cos(theta),
+	}
+}
+
+// Inverse projects the easting and northing to a lon/lat point in degrees.
+func (a AlbersEqualArea) Inverse(p orb.Point) orb.Point {
+	c := a.params()
+
+	x := p[0] - a.FalseEasting
+	y := c.r0 - (p[1] - a.FalseNorthing)
+
+	sign := 1.0
+	if c.n < 0 {
+		sign = -1
+	}
+
+	r := math.Hypot(x, y)
+	theta := math.Atan2(sign*x, sign*y)
+
+	q := (c.c - r*r*c.n*c.n/(a.Ellipsoid.A*a.Ellipsoid.A)) / c.n
+	q = math.Max(-2, math.Min(2, q))
+
+	lat := math.Asin(q / 2)
+	if c.e != 0 {
+		for i := 0; i < 15; i++ {
+			sin, cos := math.Sincos(lat)
+			if math.Abs(cos) < 1e-12 {
+				break
+			}
+
+			es := 1 - c.e2*sin*sin
+			d := es * es / (2 * cos) * (q/(1-c.e2) - sin/es + math.Log((1-c.e*sin)/(1+c.e*sin))/(2*c.e))
+			lat += d
+			if math.Abs(d) < 1e-14 {
+				break
+			}
+		}
+	}
+
+	return orb.Point{a.Lon0 + rad2deg(theta/c.n), rad2deg(lat)}
+}
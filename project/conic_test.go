@@ -0,0 +1,93 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestLambertConformalConic(t *testing.T) {
+	// example from EPSG guidance note 7-2, NAD27 / Texas South Central
+	ft := 1200.0 / 3937
+	lcc := LambertConformalConic{
+		Ellipsoid:    EllipsoidClarke1866,
+		Lon0:         -99,
+		Lat0:         27 + 50/60.0,
+		Lat1:         28 + 23/60.0,
+		Lat2:         30 + 17/60.0,
+		FalseEasting: 2000000 * ft,
+	}
+
+	p := orb.Point{-96, 28.5}
+	expected := orb.Point{2963503.91 * ft, 254759.80 * ft}
+
+	if v := lcc.Forward(p); !pointNear(v, expected, 0.01) {
+		t.Errorf("incorrect forward: %v != %v", v, expected)
+	}
+
+	if v := lcc.Inverse(expected); !pointNear(v, p, 1e-7) {
+		t.Errorf("incorrect inverse: %v != %v", v, p)
+	}
+
+	// one standard parallel
+	lcc.Lat2 = lcc.Lat1
+	lcc.K0 = 0.9999
+	if v := lcc.Inverse(lcc.Forward(p)); !pointNear(v, p, 1e-9) {
+		t.Errorf("incorrect round trip: %v != %v", v, p)
+	}
+
+	// southern hemisphere
+	lcc = LambertConformalConic{
+		Ellipsoid: EllipsoidGRS80,
+		Lon0:      135,
+		Lat0:      0,
+		Lat1:      -18,
+		Lat2:      -36,
+	}
+
+	p = orb.Point{151.2093, -33.8688}
+	if v := lcc.Inverse(lcc.Forward(p)); !pointNear(v, p, 1e-9) {
+		t.Errorf("incorrect southern round trip: %v != %v", v, p)
+	}
+}
+
+func TestAlbersEqualArea(t *testing.T) {
+	// example from Snyder, Map Projections - A Working Manual
+	aea := AlbersEqualArea{
+		Ellipsoid: EllipsoidClarke1866,
+		Lon0:      -96,
+		Lat0:      23,
+		Lat1:      29.5,
+		Lat2:      45.5,
+	}
+
+	p := orb.Point{-75, 35}
+	expected := orb.Point{1885472.7, 1535925.0}
+
+	if v := aea.Forward(p); !pointNear(v, expected, 0.1) {
+		t.Errorf("incorrect forward: %v != %v", v, expected)
+	}
+
+	if v := aea.Inverse(aea.Forward(p)); !pointNear(v, p, 1e-9) {
+		t.Errorf("incorrect inverse: %v != %v", v, p)
+	}
+
+	// southern hemisphere
+	aea = AlbersEqualArea{
+		Ellipsoid: EllipsoidGRS80,
+		Lon0:      132,
+		Lat1:      -18,
+		Lat2:      -36,
+	}
+
+	p = orb.Point{151.2093, -33.8688}
+	if v := aea.Inverse(aea.Forward(p)); !pointNear(v, p, 1e-9) {
+		t.Errorf("incorrect southern round trip: %v != %v", v, p)
+	}
+
+	// sphere
+	aea.Ellipsoid = Ellipsoid{A: orb.EarthRadius}
+	if v := aea.Inverse(aea.Forward(p)); !pointNear(v, p, 1e-9) {
+		t.Errorf("incorrect sphere round trip: %v != %v", v, p)
+	}
+}
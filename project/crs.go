@@ -0,0 +1,217 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dadadamarine/orb"
+)
+
+// ErrUnknownCRS is returned when looking up a coordinate reference
+// system that is not in the registry.
+var ErrUnknownCRS = errors.New("project: unknown crs")
+
+// A Projector converts between lon/lat, in degrees on its ellipsoid,
+// and projected coordinates. TransverseMercator, LambertConformalConic
+// and AlbersEqualArea are Projectors.
+type Projector interface {
+	Forward(orb.Point) orb.Point
+	Inverse(orb.Point) orb.Point
+}
+
+// A CRS is a coordinate reference system with projections to
+// and from WGS84 lon/lat.
+type CRS struct {
+	Name string
+
+	// FromWGS84 projects WGS84 lon/lat to coordinates in this system.
+	FromWGS84 orb.Projection
+
+	// ToWGS84 projects coordinates in this system to WGS84 lon/lat.
+	ToWGS84 orb.Projection
+}
+
+// NewCRS creates a coordinate reference system from a projection and
+// the datum's Helmert parameters to WGS84. A nil projector defines a
+// geographic, lon/lat, system. If the parameters are nil the datum's
+// lon/lat is taken as WGS84, good to about a meter for datums such as
+// ETRS89, NAD83 or GDA94.
+func NewCRS(name string, e Ellipsoid, toWGS84 *Helmert, p Projector) *CRS {
+	crs := &CRS{
+		Name:      name,
+		FromWGS84: identity,
+		ToWGS84:   identity,
+	}
+
+	if toWGS84 != nil {
+		crs.FromWGS84 = toWGS84.FromWGS84(e)
+		crs.ToWGS84 = toWGS84.ToWGS84(e)
+	}
+
+	if p != nil {
+		crs.FromWGS84 = compose(crs.FromWGS84, p.Forward)
+		crs.ToWGS84 = compose(p.Inverse, crs.ToWGS84)
+	}
+
+	return crs
+}
+
+// Transform returns a projection from coordinates in one
+// reference system to another.
+func Transform(from, to *CRS) orb.Projection {
+	return compose(from.ToWGS84, to.FromWGS84)
+}
+
+func identity(p orb.Point) orb.Point {
+	return p
+}
+
+// compose returns a projection that applies a and then b.
+func compose(a, b orb.Projection) orb.Projection {
+	return func(p orb.Point) orb.Point {
+		return b(a(p))
+	}
+}
+
+var registry = map[int]*CRS{}
+
+// Register adds a coordinate reference system to the EPSG registry,
+// replacing any existing definition for the code. It is not safe
+// to call concurrently with EPSG and should be called during init.
+func Register(code int, crs *CRS) {
+	registry[code] = crs
+}
+
+// EPSG returns the coordinate reference system for the EPSG code.
+// Supported are WGS84 lon/lat (4326), Web Mercator (3857), the UTM
+// zones on WGS84 (326xx, 327xx), ETRS89 (258xx) and NAD83 (269xx)
+// as well as a set of national grids and conic projections.
+// Use Register to add others.
+func EPSG(code int) (*CRS, error) {
+	if crs, ok := registry[code]; ok {
+		return crs, nil
+	}
+
+	switch {
+	case 32601 <= code && code <= 32660:
+		zone := code - 32600
+		return NewCRS(fmt.Sprintf("WGS 84 / UTM zone %dN", zone), EllipsoidWGS84, nil, UTM(zone, false)), nil
+	case 32701 <= code && code <= 32760:
+		zone := code - 32700
+		return NewCRS(fmt.Sprintf("WGS 84 / UTM zone %dS", zone), EllipsoidWGS84, nil, UTM(zone, true)), nil
+	case 25828 <= code && code <= 25838:
+		zone := code - 25800
+		tm := UTM(zone, false)
+		tm.Ellipsoid = EllipsoidGRS80
+		return NewCRS(fmt.Sprintf("ETRS89 / UTM zone %dN", zone), EllipsoidGRS80, nil, tm), nil
+	case 26901 <= code && code <= 26923:
+		zone := code - 26900
+		tm := UTM(zone, false)
+		tm.Ellipsoid = EllipsoidGRS80
+		return NewCRS(fmt.Sprintf("NAD83 / UTM zone %dN", zone), EllipsoidGRS80, nil, tm), nil
+	case 31466 <= code && code <= 31469:
+		zone := code - 31464
+		tm := TransverseMercator{
+			Ellipsoid:    EllipsoidBessel1841,
+			Lon0:         float64(3 * zone),
+			K0:           1,
+			FalseEasting: float64(zone)*1000000 + 500000,
+		}
+		return NewCRS(fmt.Sprintf("DHDN / 3-degree Gauss-Kruger zone %d", zone), EllipsoidBessel1841, &dhdn, tm), nil
+	}
+
+	return nil, ErrUnknownCRS
+}
+
+var webMercator = &CRS{
+	Name:      "WGS 84 / Pseudo-Mercator",
+	FromWGS84: WGS84.ToMercator,
+	ToWGS84:   Mercator.ToWGS84,
+}
+
+var (
+	osgb36 = Helmert{Tx: 446.448, Ty: -125.157, Tz: 542.06, Rx: 0.15, Ry: 0.247, Rz: 0.842, S: -20.489}
+	dhdn   = Helmert{Tx: 598.1, Ty: 73.7, Tz: 418.2, Rx: 0.202, Ry: 0.045, Rz: -2.455, S: 6.7}
+)
+
+func init() {
+	Register(4326, NewCRS("WGS 84", EllipsoidWGS84, nil, nil))
+	Register(3857, webMercator)
+
+	Register(4277, NewCRS("OSGB36", EllipsoidAiry1830, &osgb36, nil))
+	Register(27700, NewCRS("OSGB36 / British National Grid", EllipsoidAiry1830, &osgb36,
+		TransverseMercator{
+			Ellipsoid:     EllipsoidAiry1830,
+			Lon0:          -2,
+			Lat0:          49,
+			K0:            0.9996012717,
+			FalseEasting:  400000,
+			FalseNorthing: -100000,
+		},
+	))
+
+	Register(2154, NewCRS("RGF93 / Lambert-93", EllipsoidGRS80, nil,
+		LambertConformalConic{
+			Ellipsoid:     EllipsoidGRS80,
+			Lon0:          3,
+			Lat0:          46.5,
+			Lat1:          49,
+			Lat2:          44,
+			FalseEasting:  700000,
+			FalseNorthing: 6600000,
+		},
+	))
+
+	Register(3034, NewCRS("ETRS89 / LCC Europe", EllipsoidGRS80, nil,
+		LambertConformalConic{
+			Ellipsoid:     EllipsoidGRS80,
+			Lon0:          10,
+			Lat0:          52,
+			Lat1:          35,
+			Lat2:          65,
+			FalseEasting:  4000000,
+			FalseNorthing: 2800000,
+		},
+	))
+
+	Register(2193, NewCRS("NZGD2000 / New Zealand Transverse Mercator 2000", EllipsoidGRS80, nil,
+		TransverseMercator{
+			Ellipsoid:     EllipsoidGRS80,
+			Lon0:          173,
+			K0:            0.9996,
+			FalseEasting:  1600000,
+			FalseNorthing: 10000000,
+		},
+	))
+
+	Register(5070, NewCRS("NAD83 / Conus Albers", EllipsoidGRS80, nil,
+		AlbersEqualArea{
+			Ellipsoid: EllipsoidGRS80,
+			Lon0:      -96,
+			Lat0:      23,
+			Lat1:      29.5,
+			Lat2:      45.5,
+		},
+	))
+
+	Register(3310, NewCRS("NAD83 / California Albers", EllipsoidGRS80, nil,
+		AlbersEqualArea{
+			Ellipsoid:     EllipsoidGRS80,
+			Lon0:          -120,
+			Lat0:          0,
+			Lat1:          34,
+			Lat2:          40.5,
+			FalseNorthing: -4000000,
+		},
+	))
+
+	Register(3577, NewCRS("GDA94 / Australian Albers", EllipsoidGRS80, nil,
+		AlbersEqualArea{
+			Ellipsoid: EllipsoidGRS80,
+			Lon0:      132,
+			Lat0:      0,
+			Lat1:      -18,
+			Lat2:      -36,
+		},
+	))
+}
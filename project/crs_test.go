@@ -0,0 +1,127 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestEPSG(t *testing.T) {
+	cases := []struct {
+		name      string
+		code      int
+		point     orb.Point
+		projected orb.Point
+		tolerance float64
+	}{
+		{
+			name:      "wgs84",
+			code:      4326,
+			point:     orb.Point{1, 2},
+			projected: orb.Point{1, 2},
+		},
+		{
+			name:      "web mercator",
+			code:      3857,
+			point:     orb.Point{-122.416667, 37.783333},
+			projected: orb.Point{-1.3627361035049736e+07, 4.548863085837512e+06},
+			tolerance: 1e-6,
+		},
+		{
+			name:      "utm origin",
+			code:      32633,
+			point:     orb.Point{15, 0},
+			projected: orb.Point{500000, 0},
+			tolerance: 1e-6,
+		},
+		{
+			name:      "british national grid",
+			code:      27700,
+			point:     orb.Point{-0.12462, 51.50072},
+			projected: orb.Point{530268, 179643},
+			tolerance: 5,
+		},
+		{
+			name:      "lambert-93 origin",
+			code:      2154,
+			point:     orb.Point{3, 46.5},
+			projected: orb.Point{700000, 6600000},
+			tolerance: 1e-6,
+		},
+		{
+			name:      "california albers",
+			code:      3310,
+			point:     orb.Point{-120, 0},
+			projected: orb.Point{0, -4000000},
+			tolerance: 1e-6,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			crs, err := EPSG(tc.code)
+			if err != nil {
+				t.Fatalf("lookup error: %v", err)
+			}
+
+			p := crs.FromWGS84(tc.point)
+			if !pointNear(p, tc.projected, tc.tolerance) {
+				t.Errorf("incorrect projection: %v != %v", p, tc.projected)
+			}
+
+			if v := crs.ToWGS84(p); !pointNear(v, tc.point, 1e-7) {
+				t.Errorf("incorrect round trip: %v != %v", v, tc.point)
+			}
+		})
+	}
+
+	codes := []int{4277, 2193, 3034, 3577, 5070, 25832, 26910, 31467, 32601, 32760}
+	for _, code := range codes {
+		crs, err := EPSG(code)
+		if err != nil {
+			t.Errorf("%d: lookup error: %v", code, err)
+			continue
+		}
+
+		if crs.Name == "" {
+			t.Errorf("%d: should have a name", code)
+		}
+	}
+
+	for _, code := range []int{0, 32600, 32661, 99999} {
+		if _, err := EPSG(code); err != ErrUnknownCRS {
+			t.Errorf("%d: should be unknown: %v", code, err)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register(100000, NewCRS("test", EllipsoidWGS84, nil, UTM(1, false)))
+	defer delete(registry, 100000)
+
+	crs, err := EPSG(100000)
+	if err != nil {
+		t.Fatalf("lookup error: %v", err)
+	}
+
+	if crs.Name != "test" {
+		t.Errorf("incorrect crs: %v", crs.Name)
+	}
+}
+
+func TestTransform(t *testing.T) {
+	utm, _ := EPSG(32631)
+	bng, _ := EPSG(27700)
+
+	p := orb.Point{-0.12462, 51.50072}
+	u := utm.FromWGS84(p)
+
+	v := Point(u, Transform(utm, bng))
+	if !pointNear(v, bng.FromWGS84(p), 0.01) {
+		t.Errorf("incorrect transform: %v != %v", v, bng.FromWGS84(p))
+	}
+
+	if b := Point(v, Transform(bng, utm)); !pointNear(b, u, 0.01) {
+		t.Errorf("incorrect reverse transform: %v != %v", b, u)
+	}
+}
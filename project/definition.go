@@ -0,0 +1,147 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/dadadamarine/orb"
+)
+
+// Projection method names used by the PROJ string and WKT parsers.
+const (
+	methodGeographic  = "longlat"
+	methodTM          = "tmerc"
+	methodLCC         = "lcc"
+	methodAlbers      = "aea"
+	methodMercator    = "merc"
+	methodWebMercator = "webmerc"
+)
+
+// definition is a parsed coordinate reference system definition.
+type definition struct {
+	name      string
+	method    string
+	ellipsoid Ellipsoid
+	toWGS84   *Helmert
+
+	// primeMeridian is the longitude of the prime meridian,
+	// in degrees east of Greenwich.
+	primeMeridian float64
+
+	// toMeter is the size of the projected unit in meters,
+	// toDegree the size of the geographic unit in degrees.
+	toMeter, toDegree float64
+
+	lon0, lat0, lat1, lat2 float64
+	k0, x0, y0             float64
+	hasLat1, hasLat2       bool
+}
+
+func newDefinition() *definition {
+	return &definition{
+		method:    methodGeographic,
+		ellipsoid: EllipsoidWGS84,
+		toMeter:   1,
+		toDegree:  1,
+		k0:        1,
+	}
+}
+
+func (d *definition) crs() (*CRS, error) {
+	// parallels default to each other for the one parallel variants
+	if !d.hasLat1 {
+		d.lat1 = d.lat0
+	}
+
+	if !d.hasLat2 {
+		d.lat2 = d.lat1
+	}
+
+	lon0 := d.lon0 + d.primeMeridian
+
+	var p Projector
+	switch d.method {
+	case methodGeographic:
+		if d.primeMeridian != 0 || d.toDegree != 1 {
+			p = geographic{primeMeridian: d.primeMeridian, toDegree: d.toDegree}
+		}
+	case methodTM:
+		p = TransverseMercator{
+			Ellipsoid:     d.ellipsoid,
+			Lon0:          lon0,
+			Lat0:          d.lat0,
+			K0:            d.k0,
+			FalseEasting:  d.x0,
+			FalseNorthing: d.y0,
+		}
+	case methodLCC:
+		p = LambertConformalConic{
+			Ellipsoid:     d.ellipsoid,
+			Lon0:          lon0,
+			Lat0:          d.lat0,
+			Lat1:          d.lat1,
+			Lat2:          d.lat2,
+			K0:            d.k0,
+			FalseEasting:  d.x0,
+			FalseNorthing: d.y0,
+		}
+	case methodAlbers:
+		p = AlbersEqualArea{
+			Ellipsoid:     d.ellipsoid,
+			Lon0:          lon0,
+			Lat0:          d.lat0,
+			Lat1:          d.lat1,
+			Lat2:          d.lat2,
+			FalseEasting:  d.x0,
+			FalseNorthing: d.y0,
+		}
+	case methodMercator, methodWebMercator:
+		// only the spherical Mercator used by web maps is supported
+		if d.method == methodMercator && d.ellipsoid != (Ellipsoid{A: EllipsoidWGS84.A}) {
+			return nil, fmt.Errorf("project: unsupported projection: ellipsoidal %s", d.method)
+		}
+
+		crs := *webMercator
+		if d.name != "" {
+			crs.Name = d.name
+		}
+
+		return &crs, nil
+	default:
+		return nil, fmt.Errorf("project: unsupported projection: %s", d.method)
+	}
+
+	if d.method != methodGeographic && d.toMeter != 1 {
+		p = linearUnit{Projector: p, toMeter: d.toMeter}
+	}
+
+	return NewCRS(d.name, d.ellipsoid, d.toWGS84, p), nil
+}
+
+// geographic is a lon/lat system with a non Greenwich prime meridian
+// or units other than degrees.
+type geographic struct {
+	primeMeridian, toDegree float64
+}
+
+func (g geographic) Forward(p orb.Point) orb.Point {
+	return orb.Point{(p[0] - g.primeMeridian) / g.toDegree, p[1] / g.toDegree}
+}
+
+func (g geographic) Inverse(p orb.Point) orb.Point {
+	return orb.Point{p[0]*g.toDegree + g.primeMeridian, p[1] * g.toDegree}
+}
+
+// linearUnit scales projected meters into other units such as feet.
+type linearUnit struct {
+	Projector
+	toMeter float64
+}
+
+func (l linearUnit) Forward(p orb.Point) orb.Point {
+	p = l.Projector.Forward(p)
+	return orb.Point{p[0] / l.toMeter, p[1] / l.toMeter}
+}
+
+func (l linearUnit) Inverse(p orb.Point) orb.Point {
+	return l.Projector.Inverse(orb.Point{p[0] * l.toMeter, p[1] * l.toMeter})
+}
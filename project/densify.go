@@ -0,0 +1,201 @@
+package project
+
+import (
+	"math"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/planar"
+)
+
+// maxDensifyDepth limits the number of times an edge is halved,
+// at most 2^maxDensifyDepth vertices are added per edge.
+const maxDensifyDepth = 16
+
+// A Densifier projects geometry adding vertices along the edges so the
+// projected edges follow the true projected curve. The projection helpers
+// only transform the vertices, so a long straight edge in lon/lat becomes
+// a straight edge in the projection instead of a curve. Unlike the helpers
+// new geometry is returned and the input is not modified.
+type Densifier struct {
+	Projection orb.Projection
+
+	// Tolerance is the max distance, in projected units, between a
+	// projected edge and the projected midpoint of the original edge.
+	// Edges are halved until the midpoint is within the tolerance.
+	Tolerance float64
+}
+
+// Densify returns a densifier for the projection and tolerance.
+// The tolerance is in projected units and should be greater than zero.
+func Densify(proj orb.Projection, tolerance float64) *Densifier {
+	return &Densifier{
+		Projection: proj,
+		Tolerance:  tolerance,
+	}
+}
+
+// Geometry projects any geometry, adding vertices along its edges.
+func (d *Densifier) Geometry(g orb.Geometry) orb.Geometry {
+	if g == nil {
+		return nil
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		return d.Point(g)
+	case orb.MultiPoint:
+		return d.MultiPoint(g)
+	case orb.LineString:
+		return d.LineString(g)
+	case orb.MultiLineString:
+		return d.MultiLineString(g)
+	case orb.Ring:
+		return d.Ring(g)
+	case orb.Polygon:
+		return d.Polygon(g)
+	case orb.MultiPolygon:
+		return d.MultiPolygon(g)
+	case orb.Collection:
+		return d.Collection(g)
+	case orb.Bound:
+		return d.Bound(g)
+	}
+
+	panic("geometry type not supported")
+}
+
+// Point projects the point, there are no edges to densify.
+func (d *Densifier) Point(p orb.Point) orb.Point {
+	return d.Projection(p)
+}
+
+// MultiPoint projects the points, there are no edges to densify.
+func (d *Densifier) MultiPoint(mp orb.MultiPoint) orb.MultiPoint {
+	result := make(orb.MultiPoint, 0, len(mp))
+	for _, p := range mp {
+		result = append(result, d.Projection(p))
+	}
+
+	return result
+}
+
+// LineString projects the line string, adding vertices along its edges.
+func (d *Densifier) LineString(ls orb.LineString) orb.LineString {
+	if len(ls) == 0 {
+		return orb.LineString{}
+	}
+
+	prev, pprev := ls[0], d.Projection(ls[0])
+
+	result := make(orb.LineString, 0, len(ls))
+	result = append(result, pprev)
+	for _, p := range ls[1:] {
+		pp := d.Projection(p)
+
+		result = d.edge(result, prev, p, pprev, pp, 0)
+		result = append(result, pp)
+
+		prev, pprev = p, pp
+	}
+
+	return result
+}
+
+// edge appends the vertices needed between a and b, excluding
+// the endpoints. pa and pb are the projected a and b.
+func (d *Densifier) edge(result orb.LineString, a, b, pa, pb orb.Point, depth int) orb.LineString {
+	if depth >= maxDensifyDepth {
+		return result
+	}
+
+	m := orb.Point{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2}
+	pm := d.Projection(m)
+
+	// stop at points the projection can not represent, such as poles in Mercator
+	if !finite(pm) || !finite(pa) || !finite(pb) {
+		return result
+	}
+
+	if planar.DistanceFromSegment(pa, pb, pm) <= d.Tolerance {
+		return result
+	}
+
+	result = d.edge(result, a, m, pa, pm, depth+1)
+	result = append(result, pm)
+	return d.edge(result, m, b, pm, pb, depth+1)
+}
+
+// MultiLineString projects the line strings, adding vertices along the edges.
+func (d *Densifier) MultiLineString(mls orb.MultiLineString) orb.MultiLineString {
+	result := make(orb.MultiLineString, 0, len(mls))
+	for _, ls := range mls {
+		result = append(result, d.LineString(ls))
+	}
+
+	return result
+}
+
+// Ring projects the ring, adding vertices along its edges.
+func (d *Densifier) Ring(r orb.Ring) orb.Ring {
+	return orb.Ring(d.LineString(orb.LineString(r)))
+}
+
+// Polygon projects the polygon, adding vertices along the edges of its rings.
+func (d *Densifier) Polygon(p orb.Polygon) orb.Polygon {
+	result := make(orb.Polygon, 0, len(p))
+	for _, r := range p {
+		result = append(result, d.Ring(r))
+	}
+
+	return result
+}
+
+// MultiPolygon projects the polygons, adding vertices along the edges.
+func (d *Densifier) MultiPolygon(mp orb.MultiPolygon) orb.MultiPolygon {
+	result := make(orb.MultiPolygon, 0, len(mp))
+	for _, p := range mp {
+		result = append(result, d.Polygon(p))
+	}
+
+	return result
+}
+
+// Collection projects the geometries, adding vertices along the edges.
+func (d *Densifier) Collection(c orb.Collection) orb.Collection {
+	result := make(orb.Collection, 0, len(c))
+	for _, g := range c {
+		result = append(result, d.Geometry(g))
+	}
+
+	return result
+}
+
+// Bound returns the extent of the projected bound, found by densifying
+// its edges. This is the true projected extent, within the tolerance,
+// as the extremes of a projected rectangle are on its boundary. It does
+// not hold if the bound contains a point the projection can not represent
+// or where it is not continuous, such as a pole in a polar projection.
+func (d *Densifier) Bound(b orb.Bound) orb.Bound {
+	var bound orb.Bound
+
+	first := true
+	for _, p := range d.Ring(b.ToRing()) {
+		if !finite(p) {
+			continue
+		}
+
+		if first {
+			bound = orb.Bound{Min: p, Max: p}
+			first = false
+		} else {
+			bound = bound.Extend(p)
+		}
+	}
+
+	return bound
+}
+
+func finite(p orb.Point) bool {
+	return !math.IsNaN(p[0]) && !math.IsInf(p[0], 0) &&
+		!math.IsNaN(p[1]) && !math.IsInf(p[1], 0)
+}
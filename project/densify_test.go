@@ -0,0 +1,135 @@
+package project
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/planar"
+)
+
+func TestDensifier_LineString(t *testing.T) {
+	lcc, err := EPSG(3034)
+	if err != nil {
+		t.Fatalf("lookup error: %v", err)
+	}
+
+	// a parallel across Europe, straight in lon/lat but an arc in the conic
+	ls := orb.LineString{{-10, 50}, {30, 50}}
+	d := Densify(lcc.FromWGS84, 10)
+
+	result := d.LineString(ls.Clone())
+	if len(result) <= 2 {
+		t.Fatalf("should add vertices: %v", result)
+	}
+
+	if !pointNear(result[0], lcc.FromWGS84(ls[0]), 1e-9) {
+		t.Errorf("incorrect first point: %v", result[0])
+	}
+
+	if !pointNear(result[len(result)-1], lcc.FromWGS84(ls[1]), 1e-9) {
+		t.Errorf("incorrect last point: %v", result[len(result)-1])
+	}
+
+	// every point along the parallel should be close to the result
+	for lon := -10.0; lon <= 30; lon += 0.25 {
+		p := lcc.FromWGS84(orb.Point{lon, 50})
+
+		dist := math.Inf(1)
+		for i := 1; i < len(result); i++ {
+			dist = math.Min(dist, planar.DistanceFromSegment(result[i-1], result[i], p))
+		}
+
+		if dist > 10 {
+			t.Errorf("point at %v is %v from the line", lon, dist)
+		}
+	}
+
+	// input should not be modified
+	if !ls.Equal(orb.LineString{{-10, 50}, {30, 50}}) {
+		t.Errorf("input modified: %v", ls)
+	}
+
+	// a linear projection needs no extra points
+	result = Densify(WGS84.ToMercator, 1).LineString(orb.LineString{{-10, 50}, {30, 50}})
+	if len(result) != 2 {
+		t.Errorf("should not add vertices: %v", result)
+	}
+
+	if v := Densify(lcc.FromWGS84, 1).LineString(nil); len(v) != 0 {
+		t.Errorf("should be empty: %v", v)
+	}
+}
+
+func TestDensifier_Polygon(t *testing.T) {
+	lcc, err := EPSG(3034)
+	if err != nil {
+		t.Fatalf("lookup error: %v", err)
+	}
+
+	poly := orb.Polygon{orb.Bound{Min: orb.Point{-10, 35}, Max: orb.Point{30, 65}}.ToRing()}
+
+	d := Densify(lcc.FromWGS84, 100)
+	result := d.Polygon(poly)
+
+	if len(result[0]) <= len(poly[0]) {
+		t.Errorf("should add vertices: %d", len(result[0]))
+	}
+
+	if !result[0].Closed() {
+		t.Errorf("ring should be closed")
+	}
+
+	// the projected area is more than the area of the projected corners
+	sparse := Polygon(poly.Clone(), lcc.FromWGS84)
+	if a, s := planar.Area(result), planar.Area(sparse); a <= s {
+		t.Errorf("area should be larger: %v <= %v", a, s)
+	}
+}
+
+func TestDensifier_Bound(t *testing.T) {
+	lcc, err := EPSG(3034)
+	if err != nil {
+		t.Fatalf("lookup error: %v", err)
+	}
+
+	b := orb.Bound{Min: orb.Point{-10, 35}, Max: orb.Point{30, 65}}
+	result := Densify(lcc.FromWGS84, 1).Bound(b)
+
+	// parallels curve around the pole, the southern extent is the middle
+	// of the bottom edge on the central meridian, not a corner
+	expected := lcc.FromWGS84(orb.Point{10, 35})
+	if math.Abs(result.Min[1]-expected[1]) > 1 {
+		t.Errorf("incorrect min y: %v != %v", result.Min[1], expected[1])
+	}
+
+	// the northern extent is at a top corner
+	expected = lcc.FromWGS84(orb.Point{-10, 65})
+	if math.Abs(result.Max[1]-expected[1]) > 1 {
+		t.Errorf("incorrect max y: %v != %v", result.Max[1], expected[1])
+	}
+
+	// contains the corners
+	corners := Bound(b, lcc.FromWGS84)
+	if !result.Contains(corners.Min) || !result.Contains(corners.Max) {
+		t.Errorf("should contain the corners: %v, %v", result, corners)
+	}
+
+	if result.Min[1] >= corners.Min[1] {
+		t.Errorf("should be larger than the corners: %v >= %v", result.Min[1], corners.Min[1])
+	}
+
+	// unrepresentable points are skipped
+	result = Densify(WGS84.ToMercator, 1).Bound(orb.Bound{Min: orb.Point{-180, -90}, Max: orb.Point{180, 80}})
+	if math.IsInf(result.Min[1], 0) || math.IsNaN(result.Min[1]) {
+		t.Errorf("should skip the pole: %v", result)
+	}
+}
+
+func TestDensifier_Geometry(t *testing.T) {
+	d := Densify(Mercator.ToWGS84, 1)
+	for _, g := range orb.AllGeometries {
+		// should not panic with unsupported type
+		d.Geometry(g)
+	}
+}
@@ -0,0 +1,81 @@
+package project
+
+import (
+	"math"
+
+	"github.com/dadadamarine/orb"
+)
+
+// An Ellipsoid is the reference surface of a geodetic datum.
+type Ellipsoid struct {
+	// A is the semi-major axis in meters.
+	A float64
+
+	// InvF is the inverse flattening. Zero defines a sphere.
+	InvF float64
+}
+
+// Common reference ellipsoids.
+var (
+	EllipsoidWGS84             = Ellipsoid{A: 6378137, InvF: 298.257223563}
+	EllipsoidGRS80             = Ellipsoid{A: 6378137, InvF: 298.257222101}
+	EllipsoidAiry1830          = Ellipsoid{A: 6377563.396, InvF: 299.3249646}
+	EllipsoidBessel1841        = Ellipsoid{A: 6377397.155, InvF: 299.1528128}
+	EllipsoidClarke1866        = Ellipsoid{A: 6378206.4, InvF: 294.978698213898}
+	EllipsoidInternational1924 = Ellipsoid{A: 6378388, InvF: 297}
+)
+
+// flattening returns the flattening, f = (a-b)/a.
+func (e Ellipsoid) flattening() float64 {
+	if e.InvF == 0 {
+		return 0
+	}
+
+	return 1 / e.InvF
+}
+
+// e2 returns the square of the first eccentricity.
+func (e Ellipsoid) e2() float64 {
+	f := e.flattening()
+	return f * (2 - f)
+}
+
+// toGeocentric converts a lon/lat point, in degrees, with the given
+// ellipsoidal height to earth centered, earth fixed coordinates.
+func (e Ellipsoid) toGeocentric(p orb.Point, h float64) [3]float64 {
+	lon, lat := deg2rad(p[0]), deg2rad(p[1])
+	e2 := e.e2()
+
+	sin, cos := math.Sincos(lat)
+	n := e.A / math.Sqrt(1-e2*sin*sin)
+
+	return [3]float64{
+		(n + h) * cos * math.Cos(lon),
+		(n + h) * cos * math.Sin(lon),
+		(n*(1-e2) + h) * sin,
+	}
+}
+
+// fromGeocentric converts earth centered, earth fixed coordinates
+// to a lon/lat point in degrees. The height is discarded.
+func (e Ellipsoid) fromGeocentric(c [3]float64) orb.Point {
+	e2 := e.e2()
+	p := math.Hypot(c[0], c[1])
+	lon := math.Atan2(c[1], c[0])
+
+	// iterate the latitude, converges to below a micrometer in a few steps
+	lat := math.Atan2(c[2], p*(1-e2))
+	for i := 0; i < 10; i++ {
+		sin := math.Sin(lat)
+		n := e.A / math.Sqrt(1-e2*sin*sin)
+
+		next := math.Atan2(c[2]+e2*n*sin, p)
+		if math.Abs(next-lat) < 1e-14 {
+			lat = next
+			break
+		}
+		lat = next
+	}
+
+	return orb.Point{rad2deg(lon), rad2deg(lat)}
+}
@@ -0,0 +1,74 @@
+package project
+
+import (
+	"math"
+
+	"github.com/dadadamarine/orb"
+)
+
+const arcSecond = math.Pi / (180 * 3600)
+
+// Helmert are the parameters of a 7 parameter similarity transformation
+// between geocentric coordinates of two datums. The rotations use the
+// position vector convention, EPSG method 9606, same as the PROJ
+// +towgs84 parameter. Coordinate frame rotations, EPSG method 9607,
+// can be used by negating the rotations.
+type Helmert struct {
+	// Tx, Ty, Tz are the translations in meters.
+	Tx, Ty, Tz float64
+
+	// Rx, Ry, Rz are the rotations in arc seconds.
+	Rx, Ry, Rz float64
+
+	// S is the scale difference in parts per million.
+	S float64
+}
+
+// apply transforms the geocentric coordinates.
+func (h Helmert) apply(c [3]float64) [3]float64 {
+	rx, ry, rz := h.Rx*arcSecond, h.Ry*arcSecond, h.Rz*arcSecond
+	s := 1 + h.S*1e-6
+
+	return [3]float64{
+		h.Tx + s*(c[0]-rz*c[1]+ry*c[2]),
+		h.Ty + s*(rz*c[0]+c[1]-rx*c[2]),
+		h.Tz + s*(-ry*c[0]+rx*c[1]+c[2]),
+	}
+}
+
+// applyInverse reverses the transformation by solving the linear system.
+func (h Helmert) applyInverse(c [3]float64) [3]float64 {
+	rx, ry, rz := h.Rx*arcSecond, h.Ry*arcSecond, h.Rz*arcSecond
+	s := 1 + h.S*1e-6
+
+	x := (c[0] - h.Tx) / s
+	y := (c[1] - h.Ty) / s
+	z := (c[2] - h.Tz) / s
+
+	// the inverse of the rotation matrix
+	//   |  1  -rz  ry |
+	//   |  rz  1  -rx |
+	//   | -ry  rx  1  |
+	det := 1 + rx*rx + ry*ry + rz*rz
+	return [3]float64{
+		((1+rx*rx)*x + (rz+rx*ry)*y + (rx*rz-ry)*z) / det,
+		((rx*ry-rz)*x + (1+ry*ry)*y + (rx+ry*rz)*z) / det,
+		((ry+rx*rz)*x + (ry*rz-rx)*y + (1+rz*rz)*z) / det,
+	}
+}
+
+// ToWGS84 returns a projection from lon/lat on the ellipsoid of a datum,
+// whose parameters to WGS84 these are, to WGS84 lon/lat.
+func (h Helmert) ToWGS84(e Ellipsoid) orb.Projection {
+	return func(p orb.Point) orb.Point {
+		return EllipsoidWGS84.fromGeocentric(h.apply(e.toGeocentric(p, 0)))
+	}
+}
+
+// FromWGS84 returns a projection from WGS84 lon/lat to lon/lat on
+// the ellipsoid of the datum whose parameters to WGS84 these are.
+func (h Helmert) FromWGS84(e Ellipsoid) orb.Projection {
+	return func(p orb.Point) orb.Point {
+		return e.fromGeocentric(h.applyInverse(EllipsoidWGS84.toGeocentric(p, 0)))
+	}
+}
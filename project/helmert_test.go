@@ -0,0 +1,50 @@
+package project
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestEllipsoid_Geocentric(t *testing.T) {
+	points := []orb.Point{
+		{0, 0},
+		{-122.416667, 37.783333},
+		{151.2093, -33.8688},
+		{45, 89.9},
+	}
+
+	for _, p := range points {
+		c := EllipsoidWGS84.toGeocentric(p, 0)
+		if v := EllipsoidWGS84.fromGeocentric(c); !pointNear(v, p, 1e-11) {
+			t.Errorf("incorrect round trip: %v != %v", v, p)
+		}
+	}
+
+	c := EllipsoidWGS84.toGeocentric(orb.Point{0, 90}, 0)
+	if math.Abs(c[2]-6356752.314245) > 1e-6 {
+		t.Errorf("incorrect polar radius: %v", c[2])
+	}
+}
+
+func TestHelmert(t *testing.T) {
+	// OSGB36 lon/lat differs from WGS84 by about 100 meters
+	p := orb.Point{-0.12462, 51.50072}
+
+	osgb := osgb36.FromWGS84(EllipsoidAiry1830)(p)
+	d := math.Hypot((osgb[0]-p[0])*math.Cos(p[1]*math.Pi/180), osgb[1]-p[1]) * 111320
+	if d < 50 || d > 150 {
+		t.Errorf("incorrect shift: %v", d)
+	}
+
+	// the height is not kept so the round trip is only good to about a millimeter
+	if v := osgb36.ToWGS84(EllipsoidAiry1830)(osgb); !pointNear(v, p, 1e-7) {
+		t.Errorf("incorrect round trip: %v != %v", v, p)
+	}
+
+	// the zero transformation does nothing
+	if v := (Helmert{}).ToWGS84(EllipsoidWGS84)(p); !pointNear(v, p, 1e-11) {
+		t.Errorf("should not change point: %v != %v", v, p)
+	}
+}
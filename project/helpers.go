@@ -1,5 +1,6 @@
-// Package project defines projections to and from Mercator and WGS84
-// along with helpers to apply them to orb geometry types.
+// Package project defines projections to and from Mercator and WGS84,
+// coordinate reference systems by EPSG code, along with helpers to
+// apply them to orb geometry types.
 package project
 
 import "github.com/dadadamarine/orb"
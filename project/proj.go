@@ -0,0 +1,243 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidDefinition is returned when a PROJ string or WKT
+// coordinate reference system definition can not be parsed.
+var ErrInvalidDefinition = errors.New("project: invalid crs definition")
+
+// ellipsoids by PROJ +ellps name.
+var projEllipsoids = map[string]Ellipsoid{
+	"WGS84":  EllipsoidWGS84,
+	"GRS80":  EllipsoidGRS80,
+	"airy":   EllipsoidAiry1830,
+	"bessel": EllipsoidBessel1841,
+	"clrk66": EllipsoidClarke1866,
+	"intl":   EllipsoidInternational1924,
+}
+
+type datum struct {
+	ellipsoid Ellipsoid
+	toWGS84   *Helmert
+}
+
+// datums by PROJ +datum name.
+var projDatums = map[string]datum{
+	"WGS84":   {ellipsoid: EllipsoidWGS84},
+	"NAD83":   {ellipsoid: EllipsoidGRS80},
+	"OSGB36":  {ellipsoid: EllipsoidAiry1830, toWGS84: &osgb36},
+	"potsdam": {ellipsoid: EllipsoidBessel1841, toWGS84: &dhdn},
+}
+
+// linear units by PROJ +units name, in meters.
+var projUnits = map[string]float64{
+	"m":     1,
+	"km":    1000,
+	"ft":    0.3048,
+	"us-ft": 1200.0 / 3937,
+}
+
+// ParseProj parses a PROJ string, such as
+// "+proj=utm +zone=33 +datum=WGS84 +units=m +no_defs", into a
+// coordinate reference system. Supported projections are longlat, utm,
+// tmerc, lcc, aea and the spherical merc used by Web Mercator.
+func ParseProj(s string) (*CRS, error) {
+	params := map[string]string{}
+	for _, f := range strings.Fields(s) {
+		f = strings.TrimPrefix(f, "+")
+		if f == "" {
+			continue
+		}
+
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) == 2 {
+			params[kv[0]] = kv[1]
+		} else {
+			params[kv[0]] = ""
+		}
+	}
+
+	proj, ok := params["proj"]
+	if !ok {
+		return nil, ErrInvalidDefinition
+	}
+
+	d := newDefinition()
+	if err := d.projEllipsoid(params); err != nil {
+		return nil, err
+	}
+
+	var err error
+	number := func(key string, def float64) float64 {
+		v, ok := params[key]
+		if !ok || err != nil {
+			return def
+		}
+
+		var f float64
+		f, err = strconv.ParseFloat(v, 64)
+		return f
+	}
+
+	d.lon0 = number("lon_0", 0)
+	d.lat0 = number("lat_0", 0)
+	d.x0 = number("x_0", 0)
+	d.y0 = number("y_0", 0)
+	d.k0 = number("k_0", number("k", 1))
+
+	if _, ok := params["lat_1"]; ok {
+		d.lat1, d.hasLat1 = number("lat_1", 0), true
+	}
+
+	if _, ok := params["lat_2"]; ok {
+		d.lat2, d.hasLat2 = number("lat_2", 0), true
+	}
+
+	if pm, ok := params["pm"]; ok {
+		switch pm {
+		case "greenwich":
+		case "paris":
+			d.primeMeridian = 2.33722917
+		default:
+			d.primeMeridian = number("pm", 0)
+		}
+	}
+
+	if u, ok := params["units"]; ok {
+		if d.toMeter, ok = projUnits[u]; !ok {
+			return nil, fmt.Errorf("project: unsupported units: %s", u)
+		}
+	} else if _, ok := params["to_meter"]; ok {
+		d.toMeter = number("to_meter", 1)
+	}
+
+	switch proj {
+	case "longlat", "latlong", "lonlat", "latlon":
+		d.method = methodGeographic
+	case "tmerc", "etmerc":
+		d.method = methodTM
+	case "utm":
+		zone, zerr := strconv.Atoi(params["zone"])
+		if zerr != nil || zone < 1 || zone > 60 {
+			return nil, ErrInvalidDefinition
+		}
+
+		d.method = methodTM
+		d.lon0 = float64(6*zone - 183)
+		d.k0 = 0.9996
+		d.x0 = 500000
+		if _, ok := params["south"]; ok {
+			d.y0 = 10000000
+		}
+	case "lcc":
+		d.method = methodLCC
+	case "aea":
+		d.method = methodAlbers
+	case "merc":
+		d.method = methodMercator
+	default:
+		return nil, fmt.Errorf("project: unsupported projection: %s", proj)
+	}
+
+	if err != nil {
+		return nil, ErrInvalidDefinition
+	}
+
+	return d.crs()
+}
+
+// projEllipsoid sets the ellipsoid and datum shift from the parameters.
+func (d *definition) projEllipsoid(params map[string]string) error {
+	if name, ok := params["datum"]; ok {
+		dt, ok := projDatums[name]
+		if !ok {
+			return fmt.Errorf("project: unsupported datum: %s", name)
+		}
+
+		d.ellipsoid = dt.ellipsoid
+		d.toWGS84 = dt.toWGS84
+	}
+
+	if name, ok := params["ellps"]; ok {
+		e, ok := projEllipsoids[name]
+		if !ok {
+			return fmt.Errorf("project: unsupported ellipsoid: %s", name)
+		}
+
+		d.ellipsoid = e
+	}
+
+	// an explicit size of the ellipsoid or sphere
+	if r, ok := params["R"]; ok {
+		v, err := strconv.ParseFloat(r, 64)
+		if err != nil {
+			return ErrInvalidDefinition
+		}
+
+		d.ellipsoid = Ellipsoid{A: v}
+	}
+
+	if a, ok := params["a"]; ok {
+		v, err := strconv.ParseFloat(a, 64)
+		if err != nil {
+			return ErrInvalidDefinition
+		}
+
+		d.ellipsoid = Ellipsoid{A: v}
+		if rf, ok := params["rf"]; ok {
+			if d.ellipsoid.InvF, err = strconv.ParseFloat(rf, 64); err != nil {
+				return ErrInvalidDefinition
+			}
+		} else if b, ok := params["b"]; ok {
+			v, err := strconv.ParseFloat(b, 64)
+			if err != nil {
+				return ErrInvalidDefinition
+			}
+
+			if v != d.ellipsoid.A {
+				d.ellipsoid.InvF = d.ellipsoid.A / (d.ellipsoid.A - v)
+			}
+		}
+	}
+
+	if t, ok := params["towgs84"]; ok {
+		h, err := parseHelmert(strings.Split(t, ","))
+		if err != nil {
+			return err
+		}
+
+		d.toWGS84 = h
+	}
+
+	return nil
+}
+
+// parseHelmert parses 3 or 7 parameters, zero parameters are nil.
+func parseHelmert(values []string) (*Helmert, error) {
+	if len(values) != 3 && len(values) != 7 {
+		return nil, ErrInvalidDefinition
+	}
+
+	var v [7]float64
+	for i, s := range values {
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil || math.IsNaN(f) {
+			return nil, ErrInvalidDefinition
+		}
+
+		v[i] = f
+	}
+
+	h := Helmert{Tx: v[0], Ty: v[1], Tz: v[2], Rx: v[3], Ry: v[4], Rz: v[5], S: v[6]}
+	if h == (Helmert{}) {
+		return nil, nil
+	}
+
+	return &h, nil
+}
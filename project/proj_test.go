@@ -0,0 +1,143 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestParseProj(t *testing.T) {
+	cases := []struct {
+		name string
+		def  string
+		code int
+	}{
+		{
+			name: "wgs84",
+			def:  "+proj=longlat +datum=WGS84 +no_defs",
+			code: 4326,
+		},
+		{
+			name: "web mercator",
+			def:  "+proj=merc +a=6378137 +b=6378137 +lat_ts=0 +lon_0=0 +x_0=0 +y_0=0 +k=1 +units=m +nadgrids=@null +wktext +no_defs",
+			code: 3857,
+		},
+		{
+			name: "utm",
+			def:  "+proj=utm +zone=31 +datum=WGS84 +units=m +no_defs",
+			code: 32631,
+		},
+		{
+			name: "utm south",
+			def:  "+proj=utm +zone=56 +south +datum=WGS84 +units=m +no_defs",
+			code: 32756,
+		},
+		{
+			name: "british national grid",
+			def: "+proj=tmerc +lat_0=49 +lon_0=-2 +k=0.9996012717 +x_0=400000 +y_0=-100000 +ellps=airy " +
+				"+towgs84=446.448,-125.157,542.06,0.15,0.247,0.842,-20.489 +units=m +no_defs",
+			code: 27700,
+		},
+		{
+			name: "lambert-93",
+			def:  "+proj=lcc +lat_0=46.5 +lon_0=3 +lat_1=49 +lat_2=44 +x_0=700000 +y_0=6600000 +ellps=GRS80 +units=m +no_defs",
+			code: 2154,
+		},
+		{
+			name: "conus albers",
+			def:  "+proj=aea +lat_0=23 +lon_0=-96 +lat_1=29.5 +lat_2=45.5 +x_0=0 +y_0=0 +datum=NAD83 +units=m +no_defs",
+			code: 5070,
+		},
+		{
+			name: "gauss kruger",
+			def:  "+proj=tmerc +lat_0=0 +lon_0=9 +k=1 +x_0=3500000 +y_0=0 +datum=potsdam +units=m +no_defs",
+			code: 31467,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			crs, err := ParseProj(tc.def)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+
+			compareCRS(t, crs, tc.code)
+		})
+	}
+}
+
+func TestParseProj_units(t *testing.T) {
+	crs, err := ParseProj("+proj=utm +zone=31 +datum=WGS84 +units=us-ft")
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	p := orb.Point{3, 0}
+	expected := orb.Point{500000 * 3937 / 1200.0, 0}
+	if v := crs.FromWGS84(p); !pointNear(v, expected, 1e-6) {
+		t.Errorf("incorrect point: %v != %v", v, expected)
+	}
+
+	if v := crs.ToWGS84(expected); !pointNear(v, p, 1e-9) {
+		t.Errorf("incorrect inverse: %v != %v", v, p)
+	}
+
+	// prime meridian
+	crs, err = ParseProj("+proj=longlat +ellps=WGS84 +pm=paris")
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	if v := crs.FromWGS84(orb.Point{2.33722917, 48}); !pointNear(v, orb.Point{0, 48}, 1e-9) {
+		t.Errorf("incorrect prime meridian: %v", v)
+	}
+}
+
+func TestParseProj_errors(t *testing.T) {
+	cases := []string{
+		"",
+		"+datum=WGS84",
+		"+proj=utm +zone=61",
+		"+proj=tmerc +lat_0=abc",
+		"+proj=tmerc +towgs84=1,2",
+		"+proj=robin",
+		"+proj=merc +datum=WGS84",
+		"+proj=longlat +ellps=unknown",
+		"+proj=utm +zone=31 +units=furlong",
+	}
+
+	for _, def := range cases {
+		if _, err := ParseProj(def); err == nil {
+			t.Errorf("%q: should return error", def)
+		}
+	}
+}
+
+// compareCRS checks the crs projects like the one in the EPSG registry.
+func compareCRS(t testing.TB, crs *CRS, code int) {
+	t.Helper()
+
+	expected, err := EPSG(code)
+	if err != nil {
+		t.Fatalf("lookup error: %v", err)
+	}
+
+	points := []orb.Point{
+		{-0.12462, 51.50072},
+		{2.3522219, 48.856614},
+		{-100, 40},
+		{151.2093, -33.8688},
+	}
+
+	for _, p := range points {
+		v, e := crs.FromWGS84(p), expected.FromWGS84(p)
+		if !pointNear(v, e, 1e-6) {
+			t.Errorf("incorrect projection: %v != %v", v, e)
+		}
+
+		if v := crs.ToWGS84(e); !pointNear(v, expected.ToWGS84(e), 1e-9) {
+			t.Errorf("incorrect inverse: %v != %v", v, expected.ToWGS84(e))
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package project
+
+import (
+	"math"
+
+	"github.com/dadadamarine/orb"
+)
+
+// TransverseMercator is the ellipsoidal transverse Mercator projection,
+// computed using the Krüger series. It is accurate to a millimeter
+// within a few thousand kilometers of the central meridian.
+type TransverseMercator struct {
+	Ellipsoid Ellipsoid
+
+	// Lon0 and Lat0 are the natural origin in degrees.
+	Lon0, Lat0 float64
+
+	// K0 is the scale factor on the central meridian.
+	K0 float64
+
+	FalseEasting, FalseNorthing float64
+}
+
+// UTM returns the transverse Mercator projection of the WGS84
+// Universal Transverse Mercator zone, 1 to 60.
+func UTM(zone int, south bool) TransverseMercator {
+	tm := TransverseMercator{
+		Ellipsoid:    EllipsoidWGS84,
+		Lon0:         float64(6*zone - 183),
+		K0:           0.9996,
+		FalseEasting: 500000,
+	}
+
+	if south {
+		tm.FalseNorthing = 10000000
+	}
+
+	return tm
+}
+
+type tmercSeries struct {
+	e, a, k0 float64
+	n0       float64 // northing of the origin latitude
+
+	alpha, beta [3]float64
+}
+
+func (tm TransverseMercator) series() tmercSeries {
+	f := tm.Ellipsoid.flattening()
+	n := f / (2 - f)
+	n2, n3 := n*n, n*n*n
+
+	s := tmercSeries{
+		e:  math.Sqrt(tm.Ellipsoid.e2()),
+		a:  tm.Ellipsoid.A / (1 + n) * (1 + n2/4 + n2*n2/64),
+		k0: tm.K0,
+		alpha: [3]float64{
+			n/2 - 2*n2/3 + 5*n3/16,
+			13*n2/48 - 3*n3/5,
+			61 * n3 / 240,
+		},
+		beta: [3]float64{
+			n/2 - 2*n2/3 + 37*n3/96,
+			n2/48 + n3/15,
+			17 * n3 / 480,
+		},
+	}
+
+	if s.k0 == 0 {
+		s.k0 = 1
+	}
+
+	// the meridian arc to the origin latitude
+	xi := s.conformal(deg2rad(tm.Lat0))
+	s.n0 = xi
+	for j, a := range s.alpha {
+		s.n0 += a * math.Sin(2*float64(j+1)*xi)
+	}
+	s.n0 *= s.k0 * s.a
+
+	return s
+}
+
+// conformal returns the conformal latitude.
+func (s tmercSeries) conformal(lat float64) float64 {
+	sin := math.Sin(lat)
+	return math.Atan(math.Sinh(math.Atanh(sin) - s.e*math.Atanh(s.e*sin)))
+}
+
+// Forward projects the lon/lat point, in degrees, to easting and northing.
+func (tm TransverseMercator) Forward(p orb.Point) orb.Point {
+	s := tm.series()
+
+	lat := deg2rad(p[1])
+	dlon := deg2rad(p[0] - tm.Lon0)
+
+	t := math.Tan(s.conformal(lat))
+	xi := math.Atan2(t, math.Cos(dlon))
+	eta := math.Atanh(math.Sin(dlon) / math.Sqrt(1+t*t))
+
+	x, y := eta, xi
+	for j, a := range s.alpha {
+		k := 2 * float64(j+1)
+		x += a * math.Cos(k*xi) * math.Sinh(k*eta)
+		y += a * math.Sin(k*xi) * math.Cosh(k*eta)
+	}
+
+	return orb.Point{
+		tm.FalseEasting + s.k0*s.a*x,
+		tm.FalseNorthing + s.k0*s.a*y - s.n0,
+	}
+}
+
+// Inverse projects the easting and northing to a lon/lat point in degrees.
+func (tm TransverseMercator) Inverse(p orb.Point) orb.Point {
+	s := tm.series()
+
+	xi := (p[1] - tm.FalseNorthing + s.n0) / (s.k0 * s.a)
+	eta := (p[0] - tm.FalseEasting) / (s.k0 * s.a)
+
+	xi1, eta1 := xi, eta
+	for j, b := range s.beta {
+		k := 2 * float64(j+1)
+		xi1 -= b * math.Sin(k*xi) * math.Cosh(k*eta)
+		eta1 -= b * math.Cos(k*xi) * math.Sinh(k*eta)
+	}
+
+	// iterate the latitude from the conformal latitude
+	chi := math.Asin(math.Sin(xi1) / math.Cosh(eta1))
+	t := math.Tan(math.Pi/4 + chi/2)
+
+	lat := chi
+	for i := 0; i < 15; i++ {
+		sin := math.Sin(lat)
+		next := 2*math.Atan(t*math.Pow((1+s.e*sin)/(1-s.e*sin), s.e/2)) - math.Pi/2
+		if math.Abs(next-lat) < 1e-14 {
+			lat = next
+			break
+		}
+		lat = next
+	}
+
+	lon := math.Atan2(math.Sinh(eta1), math.Cos(xi1))
+
+	return orb.Point{tm.Lon0 + rad2deg(lon), rad2deg(lat)}
+}
@@ -0,0 +1,90 @@
+package project
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestTransverseMercator(t *testing.T) {
+	// example from the Ordnance Survey guide to coordinate systems in Great Britain
+	tm := TransverseMercator{
+		Ellipsoid:     EllipsoidAiry1830,
+		Lon0:          -2,
+		Lat0:          49,
+		K0:            0.9996012717,
+		FalseEasting:  400000,
+		FalseNorthing: -100000,
+	}
+
+	p := orb.Point{1 + 43/60.0 + 4.5177/3600, 52 + 39/60.0 + 27.2531/3600}
+	expected := orb.Point{651409.903, 313177.270}
+
+	if v := tm.Forward(p); !pointNear(v, expected, 0.001) {
+		t.Errorf("incorrect forward: %v != %v", v, expected)
+	}
+
+	if v := tm.Inverse(expected); !pointNear(v, p, 1e-8) {
+		t.Errorf("incorrect inverse: %v != %v", v, p)
+	}
+}
+
+func TestUTM(t *testing.T) {
+	cases := []struct {
+		name  string
+		zone  int
+		south bool
+		point orb.Point
+	}{
+		{
+			name:  "central meridian",
+			zone:  33,
+			point: orb.Point{15, 0},
+		},
+		{
+			name:  "north",
+			zone:  10,
+			point: orb.Point{-122.416667, 37.783333},
+		},
+		{
+			name:  "south",
+			zone:  56,
+			south: true,
+			point: orb.Point{151.2093, -33.8688},
+		},
+		{
+			name:  "zone edge",
+			zone:  31,
+			point: orb.Point{6, 70},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tm := UTM(tc.zone, tc.south)
+
+			p := tm.Forward(tc.point)
+			if p[0] < 160000 || p[0] > 840000 {
+				t.Errorf("easting out of range: %v", p[0])
+			}
+
+			if v := tm.Inverse(p); !pointNear(v, tc.point, 1e-9) {
+				t.Errorf("incorrect round trip: %v != %v", v, tc.point)
+			}
+		})
+	}
+
+	// the equator on the central meridian is the false origin
+	if v := UTM(33, false).Forward(orb.Point{15, 0}); !pointNear(v, orb.Point{500000, 0}, 1e-6) {
+		t.Errorf("incorrect origin: %v", v)
+	}
+
+	if v := UTM(33, true).Forward(orb.Point{15, 0}); !pointNear(v, orb.Point{500000, 10000000}, 1e-6) {
+		t.Errorf("incorrect south origin: %v", v)
+	}
+}
+
+func pointNear(p1, p2 orb.Point, tolerance float64) bool {
+	return math.Abs(p1[0]-p2[0]) <= tolerance && math.Abs(p1[1]-p2[1]) <= tolerance
+}
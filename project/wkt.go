@@ -0,0 +1,484 @@
+package project
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseWKT parses an OGC well-known text coordinate reference system
+// definition, as found in shapefile .prj files or the GeoPackage
+// gpkg_spatial_ref_sys table, into a coordinate reference system.
+// WKT1, including the ESRI dialect, and the common parts of WKT2 are
+// supported for geographic systems and the transverse Mercator, Lambert
+// conformal conic, Albers equal area and Web Mercator projections.
+func ParseWKT(s string) (*CRS, error) {
+	node, err := parseWKTNode(s)
+	if err != nil {
+		return nil, err
+	}
+
+	d := newDefinition()
+	if err := d.wktCRS(node); err != nil {
+		return nil, err
+	}
+
+	return d.crs()
+}
+
+// wktNode is a keyword with its values, quoted strings, numbers or nodes.
+type wktNode struct {
+	keyword string
+	values  []interface{}
+}
+
+type wktParser struct {
+	s   string
+	pos int
+}
+
+func parseWKTNode(s string) (*wktNode, error) {
+	p := &wktParser{s: s}
+
+	n, err := p.node()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.pos != len(p.s) {
+		return nil, ErrInvalidDefinition
+	}
+
+	return n, nil
+}
+
+func (p *wktParser) skipSpace() {
+	for p.pos < len(p.s) && unicode.IsSpace(rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *wktParser) node() (*wktNode, error) {
+	p.skipSpace()
+
+	start := p.pos
+	for p.pos < len(p.s) && (isWKTLetter(p.s[p.pos])) {
+		p.pos++
+	}
+
+	n := &wktNode{keyword: strings.ToUpper(p.s[start:p.pos])}
+	if n.keyword == "" {
+		return nil, ErrInvalidDefinition
+	}
+
+	p.skipSpace()
+	if p.pos >= len(p.s) || (p.s[p.pos] != '[' && p.s[p.pos] != '(') {
+		// keywords without values, such as axis directions
+		return n, nil
+	}
+
+	closing := byte(']')
+	if p.s[p.pos] == '(' {
+		closing = ')'
+	}
+	p.pos++
+
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.s) {
+			return nil, ErrInvalidDefinition
+		}
+
+		switch c := p.s[p.pos]; {
+		case c == '"':
+			str, err := p.quoted()
+			if err != nil {
+				return nil, err
+			}
+			n.values = append(n.values, str)
+		case c == '-' || c == '+' || c == '.' || ('0' <= c && c <= '9'):
+			start := p.pos
+			for p.pos < len(p.s) && strings.IndexByte("+-.eE0123456789", p.s[p.pos]) >= 0 {
+				p.pos++
+			}
+
+			f, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+			if err != nil {
+				return nil, ErrInvalidDefinition
+			}
+			n.values = append(n.values, f)
+		default:
+			child, err := p.node()
+			if err != nil {
+				return nil, err
+			}
+			n.values = append(n.values, child)
+		}
+
+		p.skipSpace()
+		if p.pos >= len(p.s) {
+			return nil, ErrInvalidDefinition
+		}
+
+		if p.s[p.pos] == closing {
+			p.pos++
+			return n, nil
+		}
+
+		if p.s[p.pos] != ',' {
+			return nil, ErrInvalidDefinition
+		}
+		p.pos++
+	}
+}
+
+// quoted reads a quoted string, quotes are escaped by doubling them.
+func (p *wktParser) quoted() (string, error) {
+	p.pos++
+
+	var sb strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+
+		if c != '"' {
+			sb.WriteByte(c)
+			continue
+		}
+
+		if p.pos < len(p.s) && p.s[p.pos] == '"' {
+			sb.WriteByte('"')
+			p.pos++
+			continue
+		}
+
+		return sb.String(), nil
+	}
+
+	return "", ErrInvalidDefinition
+}
+
+func isWKTLetter(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// child returns the first child node with one of the keywords.
+func (n *wktNode) child(keywords ...string) *wktNode {
+	for _, v := range n.values {
+		c, ok := v.(*wktNode)
+		if !ok {
+			continue
+		}
+
+		for _, k := range keywords {
+			if c.keyword == k {
+				return c
+			}
+		}
+	}
+
+	return nil
+}
+
+// children returns all the child nodes with the keyword.
+func (n *wktNode) children(keyword string) []*wktNode {
+	var result []*wktNode
+	for _, v := range n.values {
+		if c, ok := v.(*wktNode); ok && c.keyword == keyword {
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
+
+func (n *wktNode) str(i int) string {
+	if i >= len(n.values) {
+		return ""
+	}
+
+	s, _ := n.values[i].(string)
+	return s
+}
+
+func (n *wktNode) num(i int) (float64, bool) {
+	if i >= len(n.values) {
+		return 0, false
+	}
+
+	f, ok := n.values[i].(float64)
+	return f, ok
+}
+
+// wktCRS reads the definition from the root node.
+func (d *definition) wktCRS(n *wktNode) error {
+	d.name = n.str(0)
+
+	switch n.keyword {
+	case "GEOGCS", "GEOGCRS", "GEODCRS", "GEOGRAPHICCRS", "GEODETICCRS":
+		return d.wktGeographic(n)
+	case "PROJCS", "PROJCRS", "PROJECTEDCRS":
+		return d.wktProjected(n)
+	case "BOUNDCRS":
+		return d.wktBound(n)
+	}
+
+	return fmt.Errorf("project: unsupported crs: %s", n.keyword)
+}
+
+func (d *definition) wktGeographic(n *wktNode) error {
+	datum := n.child("DATUM", "GEODETICDATUM", "TRF")
+	if datum == nil {
+		return ErrInvalidDefinition
+	}
+
+	spheroid := datum.child("SPHEROID", "ELLIPSOID")
+	if spheroid == nil {
+		return ErrInvalidDefinition
+	}
+
+	a, ok1 := spheroid.num(1)
+	rf, ok2 := spheroid.num(2)
+	if !ok1 || !ok2 || a <= 0 {
+		return ErrInvalidDefinition
+	}
+
+	if u := spheroid.child("LENGTHUNIT"); u != nil {
+		f, _ := u.num(1)
+		a *= f
+	}
+
+	d.ellipsoid = Ellipsoid{A: a, InvF: rf}
+
+	if t := datum.child("TOWGS84"); t != nil {
+		values := make([]string, 0, len(t.values))
+		for i := range t.values {
+			f, ok := t.num(i)
+			if !ok {
+				return ErrInvalidDefinition
+			}
+			values = append(values, strconv.FormatFloat(f, 'g', -1, 64))
+		}
+
+		h, err := parseHelmert(values)
+		if err != nil {
+			return err
+		}
+		d.toWGS84 = h
+	} else if h, ok := wktDatumShifts[normalizeWKTName(datum.str(0))]; ok {
+		d.toWGS84 = &h
+	}
+
+	// angular unit as radians per unit
+	d.toDegree = 1
+	if u := n.child("UNIT", "ANGLEUNIT"); u != nil {
+		if f, ok := u.num(1); ok && f > 0 {
+			d.toDegree = snapUnit(rad2deg(f))
+		}
+	}
+
+	if pm := n.child("PRIMEM"); pm != nil {
+		v, _ := pm.num(1)
+
+		// WKT2 prime meridians can have their own units
+		if u := pm.child("ANGLEUNIT"); u != nil {
+			f, _ := u.num(1)
+			v = rad2deg(v * f)
+		} else {
+			v *= d.toDegree
+		}
+
+		d.primeMeridian = v
+	}
+
+	return nil
+}
+
+func (d *definition) wktProjected(n *wktNode) error {
+	base := n.child("GEOGCS", "BASEGEOGCRS", "BASEGEODCRS")
+	if base == nil {
+		return ErrInvalidDefinition
+	}
+
+	if err := d.wktGeographic(base); err != nil {
+		return err
+	}
+	angle := d.toDegree
+	d.toDegree = 1
+
+	// linear unit as meters per unit, in WKT2 it can also be on the axes
+	d.toMeter = 1
+	unit := n.child("UNIT", "LENGTHUNIT")
+	if unit == nil {
+		if cs := n.child("CS"); cs != nil {
+			unit = cs.child("LENGTHUNIT")
+		}
+
+		if axis := n.child("AXIS"); unit == nil && axis != nil {
+			unit = axis.child("LENGTHUNIT")
+		}
+	}
+
+	if unit != nil {
+		if f, ok := unit.num(1); ok && f > 0 {
+			d.toMeter = f
+		}
+	}
+
+	// WKT1 has the projection and parameters in the root,
+	// WKT2 within a conversion.
+	var method *wktNode
+	params := n
+	if conv := n.child("CONVERSION"); conv != nil {
+		params = conv
+		method = conv.child("METHOD", "PROJECTION")
+	} else {
+		method = n.child("PROJECTION")
+	}
+
+	if method == nil {
+		return ErrInvalidDefinition
+	}
+
+	switch normalizeWKTName(method.str(0)) {
+	case "transversemercator", "gausskruger":
+		d.method = methodTM
+	case "lambertconformalconic", "lambertconformalconic1sp", "lambertconformalconic2sp",
+		"lambertconicconformal1sp", "lambertconicconformal2sp":
+		d.method = methodLCC
+	case "albersconicequalarea", "albers", "albersequalarea":
+		d.method = methodAlbers
+	case "mercator", "mercator1sp":
+		d.method = methodMercator
+	case "popularvisualisationpseudomercator", "mercatorauxiliarysphere", "pseudomercator":
+		d.method = methodWebMercator
+	default:
+		return fmt.Errorf("project: unsupported projection: %s", method.str(0))
+	}
+
+	for _, p := range params.children("PARAMETER") {
+		v, ok := p.num(1)
+		if !ok {
+			return ErrInvalidDefinition
+		}
+
+		// WKT2 parameters have their own units
+		angleUnit, lengthUnit := angle, d.toMeter
+		if u := p.child("ANGLEUNIT"); u != nil {
+			f, _ := u.num(1)
+			angleUnit = snapUnit(rad2deg(f))
+		}
+
+		if u := p.child("LENGTHUNIT"); u != nil {
+			lengthUnit, _ = u.num(1)
+		}
+
+		switch normalizeWKTName(p.str(0)) {
+		case "latitudeoforigin", "latitudeofnaturalorigin", "latitudeofcenter", "latitudeoffalseorigin", "latitudeofprojectioncentre":
+			d.lat0 = v * angleUnit
+		case "centralmeridian", "longitudeofnaturalorigin", "longitudeofcenter", "longitudeoffalseorigin", "longitudeoforigin", "longitudeofprojectioncentre":
+			d.lon0 = v * angleUnit
+		case "standardparallel1", "latitudeof1ststandardparallel":
+			d.lat1, d.hasLat1 = v*angleUnit, true
+		case "standardparallel2", "latitudeof2ndstandardparallel":
+			d.lat2, d.hasLat2 = v*angleUnit, true
+		case "scalefactor", "scalefactoratnaturalorigin":
+			d.k0 = v
+		case "falseeasting", "eastingatfalseorigin", "eastingoffalseorigin":
+			d.x0 = v * lengthUnit
+		case "falsenorthing", "northingatfalseorigin", "northingoffalseorigin":
+			d.y0 = v * lengthUnit
+		}
+	}
+
+	return nil
+}
+
+// wktBound reads a WKT2 bound crs, a crs with a transformation to WGS84.
+func (d *definition) wktBound(n *wktNode) error {
+	source := n.child("SOURCECRS")
+	if source == nil || len(source.values) == 0 {
+		return ErrInvalidDefinition
+	}
+
+	crs, ok := source.values[0].(*wktNode)
+	if !ok {
+		return ErrInvalidDefinition
+	}
+
+	if err := d.wktCRS(crs); err != nil {
+		return err
+	}
+
+	t := n.child("ABRIDGEDTRANSFORMATION")
+	if t == nil {
+		return nil
+	}
+
+	var h Helmert
+	for _, p := range t.children("PARAMETER") {
+		v, _ := p.num(1)
+
+		switch normalizeWKTName(p.str(0)) {
+		case "xaxistranslation":
+			h.Tx = v
+		case "yaxistranslation":
+			h.Ty = v
+		case "zaxistranslation":
+			h.Tz = v
+		case "xaxisrotation":
+			h.Rx = v
+		case "yaxisrotation":
+			h.Ry = v
+		case "zaxisrotation":
+			h.Rz = v
+		case "scaledifference":
+			h.S = v
+		}
+	}
+
+	// coordinate frame rotations have the opposite sign
+	if m := t.child("METHOD"); m != nil && strings.Contains(normalizeWKTName(m.str(0)), "coordinateframe") {
+		h.Rx, h.Ry, h.Rz = -h.Rx, -h.Ry, -h.Rz
+	}
+
+	if h != (Helmert{}) {
+		d.toWGS84 = &h
+	}
+
+	return nil
+}
+
+// snapUnit removes the rounding error of units defined with
+// limited precision, such as 0.0174532925199433 radians per degree.
+func snapUnit(f float64) float64 {
+	if math.Abs(f-1) < 1e-12 {
+		return 1
+	}
+
+	return f
+}
+
+// wktDatumShifts are used for datums without TOWGS84 parameters,
+// as is common in ESRI .prj files.
+var wktDatumShifts = map[string]Helmert{
+	"osgb1936":                    osgb36,
+	"dosgb1936":                   osgb36,
+	"deutscheshauptdreiecksnetz":  dhdn,
+	"ddeutscheshauptdreiecksnetz": dhdn,
+}
+
+// normalizeWKTName lower cases the name and removes everything
+// but letters and digits, so "Lambert_Conformal_Conic_2SP" and
+// "Lambert Conformal Conic (2SP)" are the same.
+func normalizeWKTName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
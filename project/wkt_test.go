@@ -0,0 +1,204 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestParseWKT(t *testing.T) {
+	cases := []struct {
+		name string
+		def  string
+		code int
+	}{
+		{
+			name: "wgs84",
+			def: `GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563,AUTHORITY["EPSG","7030"]],
+				AUTHORITY["EPSG","6326"]],PRIMEM["Greenwich",0,AUTHORITY["EPSG","8901"]],
+				UNIT["degree",0.0174532925199433,AUTHORITY["EPSG","9122"]],AUTHORITY["EPSG","4326"]]`,
+			code: 4326,
+		},
+		{
+			name: "esri web mercator",
+			def: `PROJCS["WGS_1984_Web_Mercator_Auxiliary_Sphere",GEOGCS["GCS_WGS_1984",DATUM["D_WGS_1984",
+				SPHEROID["WGS_1984",6378137.0,298.257223563]],PRIMEM["Greenwich",0.0],UNIT["Degree",0.0174532925199433]],
+				PROJECTION["Mercator_Auxiliary_Sphere"],PARAMETER["False_Easting",0.0],PARAMETER["False_Northing",0.0],
+				PARAMETER["Central_Meridian",0.0],PARAMETER["Standard_Parallel_1",0.0],
+				PARAMETER["Auxiliary_Sphere_Type",0.0],UNIT["Meter",1.0]]`,
+			code: 3857,
+		},
+		{
+			name: "utm",
+			def: `PROJCS["WGS 84 / UTM zone 31N",GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563]],
+				PRIMEM["Greenwich",0],UNIT["degree",0.0174532925199433]],PROJECTION["Transverse_Mercator"],
+				PARAMETER["latitude_of_origin",0],PARAMETER["central_meridian",3],PARAMETER["scale_factor",0.9996],
+				PARAMETER["false_easting",500000],PARAMETER["false_northing",0],UNIT["metre",1],
+				AXIS["Easting",EAST],AXIS["Northing",NORTH],AUTHORITY["EPSG","32631"]]`,
+			code: 32631,
+		},
+		{
+			name: "british national grid",
+			def: `PROJCS["OSGB 1936 / British National Grid",GEOGCS["OSGB 1936",DATUM["OSGB_1936",
+				SPHEROID["Airy 1830",6377563.396,299.3249646],TOWGS84[446.448,-125.157,542.06,0.15,0.247,0.842,-20.489]],
+				PRIMEM["Greenwich",0],UNIT["degree",0.0174532925199433]],PROJECTION["Transverse_Mercator"],
+				PARAMETER["latitude_of_origin",49],PARAMETER["central_meridian",-2],PARAMETER["scale_factor",0.9996012717],
+				PARAMETER["false_easting",400000],PARAMETER["false_northing",-100000],UNIT["metre",1]]`,
+			code: 27700,
+		},
+		{
+			name: "esri british national grid without towgs84",
+			def: `PROJCS["British_National_Grid",GEOGCS["GCS_OSGB_1936",DATUM["D_OSGB_1936",
+				SPHEROID["Airy_1830",6377563.396,299.3249646]],PRIMEM["Greenwich",0.0],UNIT["Degree",0.0174532925199433]],
+				PROJECTION["Transverse_Mercator"],PARAMETER["False_Easting",400000.0],PARAMETER["False_Northing",-100000.0],
+				PARAMETER["Central_Meridian",-2.0],PARAMETER["Scale_Factor",0.9996012717],
+				PARAMETER["Latitude_Of_Origin",49.0],UNIT["Meter",1.0]]`,
+			code: 27700,
+		},
+		{
+			name: "esri lambert-93",
+			def: `PROJCS["RGF_1993_Lambert_93",GEOGCS["GCS_RGF_1993",DATUM["D_RGF_1993",
+				SPHEROID["GRS_1980",6378137.0,298.257222101]],PRIMEM["Greenwich",0.0],UNIT["Degree",0.0174532925199433]],
+				PROJECTION["Lambert_Conformal_Conic"],PARAMETER["False_Easting",700000.0],
+				PARAMETER["False_Northing",6600000.0],PARAMETER["Central_Meridian",3.0],
+				PARAMETER["Standard_Parallel_1",49.0],PARAMETER["Standard_Parallel_2",44.0],
+				PARAMETER["Latitude_Of_Origin",46.5],UNIT["Meter",1.0]]`,
+			code: 2154,
+		},
+		{
+			name: "albers",
+			def: `PROJCS["NAD83 / Conus Albers",GEOGCS["NAD83",DATUM["North_American_Datum_1983",
+				SPHEROID["GRS 1980",6378137,298.257222101],TOWGS84[0,0,0,0,0,0,0]],PRIMEM["Greenwich",0],
+				UNIT["degree",0.0174532925199433]],PROJECTION["Albers_Conic_Equal_Area"],
+				PARAMETER["standard_parallel_1",29.5],PARAMETER["standard_parallel_2",45.5],
+				PARAMETER["latitude_of_center",23],PARAMETER["longitude_of_center",-96],
+				PARAMETER["false_easting",0],PARAMETER["false_northing",0],UNIT["metre",1]]`,
+			code: 5070,
+		},
+		{
+			name: "wkt2 lambert-93",
+			def: `PROJCRS["RGF93 v1 / Lambert-93",
+				BASEGEOGCRS["RGF93 v1",
+					DATUM["Reseau Geodesique Francais 1993 v1",
+						ELLIPSOID["GRS 1980",6378137,298.257222101,LENGTHUNIT["metre",1]]],
+					PRIMEM["Greenwich",0,ANGLEUNIT["degree",0.0174532925199433]],
+					ID["EPSG",4171]],
+				CONVERSION["Lambert-93",
+					METHOD["Lambert Conic Conformal (2SP)",ID["EPSG",9802]],
+					PARAMETER["Latitude of false origin",46.5,ANGLEUNIT["degree",0.0174532925199433]],
+					PARAMETER["Longitude of false origin",3,ANGLEUNIT["degree",0.0174532925199433]],
+					PARAMETER["Latitude of 1st standard parallel",49,ANGLEUNIT["degree",0.0174532925199433]],
+					PARAMETER["Latitude of 2nd standard parallel",44,ANGLEUNIT["degree",0.0174532925199433]],
+					PARAMETER["Easting at false origin",700000,LENGTHUNIT["metre",1]],
+					PARAMETER["Northing at false origin",6600000,LENGTHUNIT["metre",1]]],
+				CS[Cartesian,2],
+					AXIS["easting (X)",east,ORDER[1],LENGTHUNIT["metre",1]],
+					AXIS["northing (Y)",north,ORDER[2],LENGTHUNIT["metre",1]],
+				USAGE[SCOPE["Engineering survey, topographic mapping."],AREA["France"],BBOX[41.15,-9.86,51.56,10.38]],
+				ID["EPSG",2154]]`,
+			code: 2154,
+		},
+		{
+			name: "wkt2 bound crs",
+			def: `BOUNDCRS[
+				SOURCECRS[
+					PROJCRS["OSGB36 / British National Grid",
+						BASEGEOGCRS["OSGB36",DATUM["Ordnance Survey of Great Britain 1936",
+							ELLIPSOID["Airy 1830",6377563.396,299.3249646,LENGTHUNIT["metre",1]]],
+							PRIMEM["Greenwich",0,ANGLEUNIT["degree",0.0174532925199433]]],
+						CONVERSION["British National Grid",METHOD["Transverse Mercator"],
+							PARAMETER["Latitude of natural origin",49,ANGLEUNIT["degree",0.0174532925199433]],
+							PARAMETER["Longitude of natural origin",-2,ANGLEUNIT["degree",0.0174532925199433]],
+							PARAMETER["Scale factor at natural origin",0.9996012717,SCALEUNIT["unity",1]],
+							PARAMETER["False easting",400000,LENGTHUNIT["metre",1]],
+							PARAMETER["False northing",-100000,LENGTHUNIT["metre",1]]],
+						CS[Cartesian,2],AXIS["(E)",east,ORDER[1],LENGTHUNIT["metre",1]],
+						AXIS["(N)",north,ORDER[2],LENGTHUNIT["metre",1]]]],
+				TARGETCRS[GEOGCRS["WGS 84",DATUM["World Geodetic System 1984",
+					ELLIPSOID["WGS 84",6378137,298.257223563,LENGTHUNIT["metre",1]]],
+					PRIMEM["Greenwich",0,ANGLEUNIT["degree",0.0174532925199433]],CS[ellipsoidal,2],
+					AXIS["latitude",north,ORDER[1],ANGLEUNIT["degree",0.0174532925199433]],
+					AXIS["longitude",east,ORDER[2],ANGLEUNIT["degree",0.0174532925199433]]]],
+				ABRIDGEDTRANSFORMATION["OSGB36 to WGS 84 (6)",
+					METHOD["Position Vector transformation (geog2D domain)"],
+					PARAMETER["X-axis translation",446.448],PARAMETER["Y-axis translation",-125.157],
+					PARAMETER["Z-axis translation",542.06],PARAMETER["X-axis rotation",0.15],
+					PARAMETER["Y-axis rotation",0.247],PARAMETER["Z-axis rotation",0.842],
+					PARAMETER["Scale difference",-20.489]]]`,
+			code: 27700,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			crs, err := ParseWKT(tc.def)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+
+			if crs.Name == "" {
+				t.Errorf("should have a name")
+			}
+
+			compareCRS(t, crs, tc.code)
+		})
+	}
+}
+
+func TestParseWKT_units(t *testing.T) {
+	// NAD83 / Texas South Central (ftUS)
+	def := `PROJCS["NAD83 / Texas South Central (ftUS)",GEOGCS["NAD83",DATUM["North_American_Datum_1983",
+		SPHEROID["GRS 1980",6378137,298.257222101]],PRIMEM["Greenwich",0],UNIT["degree",0.0174532925199433]],
+		PROJECTION["Lambert_Conformal_Conic_2SP"],PARAMETER["standard_parallel_1",30.28333333333333],
+		PARAMETER["standard_parallel_2",28.38333333333333],PARAMETER["latitude_of_origin",27.83333333333333],
+		PARAMETER["central_meridian",-99],PARAMETER["false_easting",1968500],PARAMETER["false_northing",13123333.333],
+		UNIT["US survey foot",0.3048006096012192]]`
+
+	crs, err := ParseWKT(def)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	// the false origin is in feet
+	p := orb.Point{-99, 27 + 50/60.0}
+	if v := crs.FromWGS84(p); !pointNear(v, orb.Point{1968500, 13123333.333}, 1e-3) {
+		t.Errorf("incorrect origin: %v", v)
+	}
+
+	if v := crs.ToWGS84(crs.FromWGS84(orb.Point{-96, 29})); !pointNear(v, orb.Point{-96, 29}, 1e-9) {
+		t.Errorf("incorrect round trip: %v", v)
+	}
+
+	// grads and a paris prime meridian
+	def = `GEOGCS["NTF (Paris)",DATUM["Nouvelle_Triangulation_Francaise_Paris",
+		SPHEROID["Clarke 1880 (IGN)",6378249.2,293.4660212936269]],
+		PRIMEM["Paris",2.5969213],UNIT["grad",0.01570796326794897]]`
+
+	crs, err = ParseWKT(def)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	if v := crs.ToWGS84(orb.Point{0, 50}); !pointNear(v, orb.Point{2.33722917, 45}, 1e-6) {
+		t.Errorf("incorrect geographic: %v", v)
+	}
+}
+
+func TestParseWKT_errors(t *testing.T) {
+	cases := []string{
+		"",
+		"GEOGCS",
+		`GEOGCS["WGS 84"`,
+		`GEOGCS["WGS 84",DATUM["WGS_1984"]]`,
+		`GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563]]] extra`,
+		`GEOCCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563]]]`,
+		`PROJCS["x",GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563]]],PROJECTION["Robinson"]]`,
+		`PROJCS["x",GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563]]],PROJECTION["Mercator_1SP"]]`,
+	}
+
+	for _, def := range cases {
+		if _, err := ParseWKT(def); err == nil {
+			t.Errorf("%q: should return error", def)
+		}
+	}
+}
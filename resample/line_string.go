@@ -44,36 +44,36 @@ func ToInterval(ls orb.LineString, df orb.DistanceFunc, dist float64) orb.LineSt
 }
 
 func resample(ls orb.LineString, dists []float64, totalDistance float64, totalPoints int) orb.LineString {
-	points := make([]orb.Point, 1, totalPoints)
-	points[0] = ls[0] // start stays the same
+	points := make([]orb.Point, 0, totalPoints)
+	resampleWalk(dists, totalDistance, totalPoints, func(i int, percent float64) {
+		points = append(points, interpolate(ls[i], ls[i+1], percent))
+	})
+
+	return orb.LineString(points)
+}
+
+// resampleWalk calls the function with the segment index and the percent
+// along that segment for each of the totalPoints evenly spaced points.
+func resampleWalk(dists []float64, totalDistance float64, totalPoints int, f func(i int, percent float64)) {
+	f(0, 0) // start stays the same
+	if totalPoints == 1 {
+		return
+	}
 
 	step := 1
 	dist := 0.0
 
 	currentDistance := totalDistance / float64(totalPoints-1)
-	// declare here and update had nice performance benefits need to retest
-	currentSeg := [2]orb.Point{}
-	for i := 0; i < len(ls)-1; i++ {
-		currentSeg[0] = ls[i]
-		currentSeg[1] = ls[i+1]
+	for i := 0; i < len(dists) && step < totalPoints-1; i++ {
+		nextDistance := dist + dists[i]
 
-		currentSegDistance := dists[i]
-		nextDistance := dist + currentSegDistance
-
-		for currentDistance <= nextDistance {
+		for step < totalPoints-1 && currentDistance <= nextDistance {
 			// need to add a point
-			percent := (currentDistance - dist) / currentSegDistance
-			points = append(points, orb.Point{
-				currentSeg[0][0] + percent*(currentSeg[1][0]-currentSeg[0][0]),
-				currentSeg[0][1] + percent*(currentSeg[1][1]-currentSeg[0][1]),
-			})
+			f(i, (currentDistance-dist)/dists[i])
 
 			// move to the next distance we want
 			step++
 			currentDistance = totalDistance * float64(step) / float64(totalPoints-1)
-			if step == totalPoints-1 { // weird round off error on my machine
-				currentDistance = totalDistance
-			}
 		}
 
 		// past the current point in the original segment, so move to the next one
@@ -81,11 +81,20 @@ func resample(ls orb.LineString, dists []float64, totalDistance float64, totalPo
 	}
 
 	// end stays the same, to handle round off errors
-	if totalPoints != 1 { // for 1, we want the first point
-		points[totalPoints-1] = ls[len(ls)-1]
+	f(len(dists)-1, 1)
+}
+
+func interpolate(a, b orb.Point, percent float64) orb.Point {
+	if percent == 0 {
+		return a
+	} else if percent == 1 {
+		return b
 	}
 
-	return orb.LineString(points)
+	return orb.Point{
+		a[0] + percent*(b[0]-a[0]),
+		a[1] + percent*(b[1]-a[1]),
+	}
 }
 
 // resampleEdgeCases is used to handle edge case for
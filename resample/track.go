@@ -0,0 +1,171 @@
+package resample
+
+import (
+	"time"
+
+	"github.com/dadadamarine/orb"
+)
+
+// A Track is a line string with per point attributes, such as a GPS track.
+// Times and each of the channels, if set, must be the same length as the
+// line string. Channels are arbitrary values, e.g. speed or elevation,
+// that are linearly interpolated along with the points.
+type Track struct {
+	LineString orb.LineString
+	Times      []time.Time
+	Channels   [][]float64
+}
+
+// Resample converts the track into totalPoints-1 evenly spaced segments
+// interpolating the times and channels. Unlike the Resample function
+// the track is not modified.
+func (t Track) Resample(df orb.DistanceFunc, totalPoints int) Track {
+	t.validate()
+	if totalPoints <= 0 {
+		return Track{}
+	}
+
+	if len(t.LineString) <= 1 {
+		return t.Clone()
+	}
+
+	total, dists := t.distances(df)
+	return t.walk(totalPoints, func(f func(int, float64)) {
+		resampleWalk(dists, total, totalPoints, f)
+	})
+}
+
+// ToInterval converts the track into evenly spaced points of about the given
+// distance interpolating the times and channels. Unlike the ToInterval function
+// the track is not modified.
+func (t Track) ToInterval(df orb.DistanceFunc, dist float64) Track {
+	t.validate()
+	if dist <= 0 {
+		return Track{}
+	}
+
+	if len(t.LineString) <= 1 {
+		return t.Clone()
+	}
+
+	total, dists := t.distances(df)
+	totalPoints := int(total/dist) + 1
+	return t.walk(totalPoints, func(f func(int, float64)) {
+		resampleWalk(dists, total, totalPoints, f)
+	})
+}
+
+// ToTimeInterval resamples the track to points at exact multiples of the
+// interval after the first time. The last point is only included if it falls
+// on the interval. The times must be set and sorted in ascending order.
+func (t Track) ToTimeInterval(interval time.Duration) Track {
+	t.validate()
+	if interval <= 0 || len(t.Times) == 0 {
+		return Track{}
+	}
+
+	if len(t.LineString) <= 1 {
+		return t.Clone()
+	}
+
+	start := t.Times[0]
+	totalPoints := int(t.Times[len(t.Times)-1].Sub(start)/interval) + 1
+
+	return t.walk(totalPoints, func(f func(int, float64)) {
+		i := 0
+		for step := 0; step < totalPoints; step++ {
+			current := start.Add(time.Duration(step) * interval)
+			for i < len(t.Times)-2 && !current.Before(t.Times[i+1]) {
+				i++
+			}
+
+			percent := 0.0
+			if d := t.Times[i+1].Sub(t.Times[i]); d > 0 {
+				percent = float64(current.Sub(t.Times[i])) / float64(d)
+			}
+
+			f(i, percent)
+		}
+	})
+}
+
+// Clone returns a deep copy of the track.
+func (t Track) Clone() Track {
+	result := Track{
+		LineString: t.LineString.Clone(),
+	}
+
+	if t.Times != nil {
+		result.Times = append([]time.Time(nil), t.Times...)
+	}
+
+	if t.Channels != nil {
+		result.Channels = make([][]float64, len(t.Channels))
+		for i, c := range t.Channels {
+			result.Channels[i] = append([]float64(nil), c...)
+		}
+	}
+
+	return result
+}
+
+// walk builds a new track from the segment index and percent along
+// that segment produced by the walker.
+func (t Track) walk(totalPoints int, walker func(func(int, float64))) Track {
+	result := Track{
+		LineString: make(orb.LineString, 0, totalPoints),
+	}
+
+	if t.Times != nil {
+		result.Times = make([]time.Time, 0, totalPoints)
+	}
+
+	if t.Channels != nil {
+		result.Channels = make([][]float64, len(t.Channels))
+		for i := range t.Channels {
+			result.Channels[i] = make([]float64, 0, totalPoints)
+		}
+	}
+
+	walker(func(i int, percent float64) {
+		result.LineString = append(result.LineString, interpolate(t.LineString[i], t.LineString[i+1], percent))
+
+		if t.Times != nil {
+			d := t.Times[i+1].Sub(t.Times[i])
+			result.Times = append(result.Times, t.Times[i].Add(time.Duration(percent*float64(d))))
+		}
+
+		for j, c := range t.Channels {
+			result.Channels[j] = append(result.Channels[j], c[i]+percent*(c[i+1]-c[i]))
+		}
+	})
+
+	return result
+}
+
+// distances precomputes the total and intermediate distances. If all the
+// points are the same each segment is given the same weight so the
+// times and channels are still interpolated.
+func (t Track) distances(df orb.DistanceFunc) (float64, []float64) {
+	total, dists := precomputeDistances(t.LineString, df)
+	if total == 0 {
+		for i := range dists {
+			dists[i] = 1
+		}
+		total = float64(len(dists))
+	}
+
+	return total, dists
+}
+
+func (t Track) validate() {
+	if t.Times != nil && len(t.Times) != len(t.LineString) {
+		panic("resample: times must be the same length as the line string")
+	}
+
+	for _, c := range t.Channels {
+		if len(c) != len(t.LineString) {
+			panic("resample: channels must be the same length as the line string")
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package resample
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/planar"
+)
+
+func testTrack() Track {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	return Track{
+		LineString: orb.LineString{{0, 0}, {10, 0}, {10, 10}},
+		Times:      []time.Time{start, start.Add(10 * time.Second), start.Add(30 * time.Second)},
+		Channels:   [][]float64{{0, 10, 20}},
+	}
+}
+
+func TestTrack_Resample(t *testing.T) {
+	track := testTrack()
+	original := track.Clone()
+
+	result := track.Resample(planar.Distance, 5)
+
+	expected := orb.LineString{{0, 0}, {5, 0}, {10, 0}, {10, 5}, {10, 10}}
+	if !result.LineString.Equal(expected) {
+		t.Errorf("incorrect line string: %v", result.LineString)
+	}
+
+	start := track.Times[0]
+	times := []time.Time{
+		start,
+		start.Add(5 * time.Second),
+		start.Add(10 * time.Second),
+		start.Add(20 * time.Second),
+		start.Add(30 * time.Second),
+	}
+	if !reflect.DeepEqual(result.Times, times) {
+		t.Errorf("incorrect times: %v", result.Times)
+	}
+
+	if !reflect.DeepEqual(result.Channels, [][]float64{{0, 5, 10, 15, 20}}) {
+		t.Errorf("incorrect channels: %v", result.Channels)
+	}
+
+	if !reflect.DeepEqual(track, original) {
+		t.Errorf("track should not be modified")
+	}
+}
+
+func TestTrack_ToInterval(t *testing.T) {
+	track := Track{LineString: orb.LineString{{0, 0}, {10, 0}}}
+
+	result := track.ToInterval(planar.Distance, 2.5)
+
+	expected := orb.LineString{{0, 0}, {2.5, 0}, {5, 0}, {7.5, 0}, {10, 0}}
+	if !result.LineString.Equal(expected) {
+		t.Errorf("incorrect line string: %v", result.LineString)
+	}
+
+	if result.Times != nil || result.Channels != nil {
+		t.Errorf("should not add attributes: %v %v", result.Times, result.Channels)
+	}
+}
+
+func TestTrack_ToTimeInterval(t *testing.T) {
+	track := testTrack()
+
+	result := track.ToTimeInterval(8 * time.Second)
+
+	expected := orb.LineString{{0, 0}, {8, 0}, {10, 3}, {10, 7}}
+	if !result.LineString.Equal(expected) {
+		t.Errorf("incorrect line string: %v", result.LineString)
+	}
+
+	for i, v := range result.Times {
+		if d := v.Sub(track.Times[0]); d != time.Duration(i)*8*time.Second {
+			t.Errorf("incorrect time %d: %v", i, d)
+		}
+	}
+
+	if !reflect.DeepEqual(result.Channels, [][]float64{{0, 8, 13, 17}}) {
+		t.Errorf("incorrect channels: %v", result.Channels)
+	}
+}
+
+func TestTrack_samePoints(t *testing.T) {
+	track := testTrack()
+	track.LineString = orb.LineString{{1, 1}, {1, 1}, {1, 1}}
+
+	result := track.Resample(planar.Distance, 5)
+	if len(result.LineString) != 5 || len(result.Times) != 5 {
+		t.Fatalf("incorrect length: %v", result)
+	}
+
+	if !reflect.DeepEqual(result.Channels, [][]float64{{0, 5, 10, 15, 20}}) {
+		t.Errorf("incorrect channels: %v", result.Channels)
+	}
+}
+
+func TestTrack_lengthMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("should panic")
+		}
+	}()
+
+	track := testTrack()
+	track.Channels = [][]float64{{1}}
+	track.Resample(planar.Distance, 5)
+}
@@ -176,3 +176,13 @@ func benchmarkData() orb.LineString {
 
 	return ls
 }
+
+func BenchmarkCoverage(b *testing.B) {
+	ls := benchmarkData()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Coverage(DouglasPeucker(0.1)).LineString(ls)
+	}
+}
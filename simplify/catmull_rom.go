@@ -0,0 +1,198 @@
+package simplify
+
+import (
+	"math"
+
+	"github.com/dadadamarine/orb"
+)
+
+var _ orb.Simplifier = &CatmullRomSmoother{}
+
+// A CatmullRomSmoother resamples lines along a Catmull-Rom spline passing
+// through all the original points. Closed lines and rings are smoothed
+// all the way around.
+//
+// Smoothing adds new points so the geometry will not be modified in place.
+// The index map, if requested, gives the index of the original point
+// at the start of the segment each new point was created on.
+type CatmullRomSmoother struct {
+	// Segments is the number of new segments each original segment is split into.
+	Segments int
+
+	// Alpha is the knot parameterization, 0 for uniform, 0.5 for centripetal
+	// and 1 for chordal. Centripetal avoids cusps and self intersections.
+	Alpha float64
+}
+
+// CatmullRom creates a new centripetal CatmullRomSmoother.
+func CatmullRom(segments int) *CatmullRomSmoother {
+	return &CatmullRomSmoother{
+		Segments: segments,
+		Alpha:    0.5,
+	}
+}
+
+func (s *CatmullRomSmoother) simplify(ls orb.LineString, wim bool) (orb.LineString, []int) {
+	var indexMap []int
+	if s.Segments <= 1 {
+		if wim {
+			indexMap = make([]int, len(ls))
+			for i := range ls {
+				indexMap[i] = i
+			}
+		}
+		return ls, indexMap
+	}
+
+	closed := ls[0].Equal(ls[len(ls)-1])
+
+	points := ls
+	if closed {
+		points = ls[:len(ls)-1]
+	}
+	n := len(points)
+
+	// control point with wrapping for closed lines and
+	// reflected phantom points for the ends of open lines.
+	control := func(i int) orb.Point {
+		if closed {
+			return points[((i%n)+n)%n]
+		}
+
+		if i < 0 {
+			return orb.Point{2*points[0][0] - points[1][0], 2*points[0][1] - points[1][1]}
+		}
+
+		if i >= n {
+			return orb.Point{2*points[n-1][0] - points[n-2][0], 2*points[n-1][1] - points[n-2][1]}
+		}
+
+		return points[i]
+	}
+
+	segments := n - 1
+	if closed {
+		segments = n
+	}
+
+	result := make(orb.LineString, 0, segments*s.Segments+1)
+	if wim {
+		indexMap = make([]int, 0, segments*s.Segments+1)
+	}
+
+	for i := 0; i < segments; i++ {
+		p0, p1, p2, p3 := control(i-1), control(i), control(i+1), control(i+2)
+
+		result = append(result, p1)
+		for k := 1; k < s.Segments; k++ {
+			result = append(result, catmullRom(p0, p1, p2, p3, s.Alpha, float64(k)/float64(s.Segments)))
+		}
+
+		if wim {
+			for k := 0; k < s.Segments; k++ {
+				indexMap = append(indexMap, i)
+			}
+		}
+	}
+
+	result = append(result, ls[len(ls)-1])
+	if wim {
+		indexMap = append(indexMap, len(ls)-1)
+	}
+
+	return result, indexMap
+}
+
+// catmullRom computes the point at the fraction along the spline segment
+// from p1 to p2 using the Barry-Goldman pyramidal formulation.
+func catmullRom(p0, p1, p2, p3 orb.Point, alpha, fraction float64) orb.Point {
+	knot := func(t float64, a, b orb.Point) float64 {
+		d := math.Pow(math.Hypot(b[0]-a[0], b[1]-a[1]), alpha)
+		if d == 0 {
+			// coincident points, use a unit interval to avoid dividing by zero.
+			d = 1
+		}
+		return t + d
+	}
+
+	t0 := 0.0
+	t1 := knot(t0, p0, p1)
+	t2 := knot(t1, p1, p2)
+	t3 := knot(t2, p2, p3)
+
+	t := t1 + fraction*(t2-t1)
+
+	a1 := interpolate(p0, p1, (t-t0)/(t1-t0))
+	a2 := interpolate(p1, p2, (t-t1)/(t2-t1))
+	a3 := interpolate(p2, p3, (t-t2)/(t3-t2))
+
+	b1 := interpolate(a1, a2, (t-t0)/(t2-t0))
+	b2 := interpolate(a2, a3, (t-t1)/(t3-t1))
+
+	return interpolate(b1, b2, (t-t1)/(t2-t1))
+}
+
+// Simplify will run the simplification for any geometry type.
+func (s *CatmullRomSmoother) Simplify(g orb.Geometry) orb.Geometry {
+	return simplify(s, g)
+}
+
+// LineString will simplify the linestring using this simplifier.
+func (s *CatmullRomSmoother) LineString(ls orb.LineString) orb.LineString {
+	return lineString(s, ls)
+}
+
+// MultiLineString will simplify the multi-linestring using this simplifier.
+func (s *CatmullRomSmoother) MultiLineString(mls orb.MultiLineString) orb.MultiLineString {
+	return multiLineString(s, mls)
+}
+
+// Ring will simplify the ring using this simplifier.
+func (s *CatmullRomSmoother) Ring(r orb.Ring) orb.Ring {
+	return ring(s, r)
+}
+
+// Polygon will simplify the polygon using this simplifier.
+func (s *CatmullRomSmoother) Polygon(p orb.Polygon) orb.Polygon {
+	return polygon(s, p)
+}
+
+// MultiPolygon will simplify the multi-polygon using this simplifier.
+func (s *CatmullRomSmoother) MultiPolygon(mp orb.MultiPolygon) orb.MultiPolygon {
+	return multiPolygon(s, mp)
+}
+
+// Collection will simplify the collection using this simplifier.
+func (s *CatmullRomSmoother) Collection(c orb.Collection) orb.Collection {
+	return collection(s, c)
+}
+
+// LineStringIndexMap will simplify the linestring using this simplifier
+// and return the indexes of the original points that remain.
+func (s *CatmullRomSmoother) LineStringIndexMap(ls orb.LineString) (orb.LineString, []int) {
+	return lineStringIndexMap(s, ls)
+}
+
+// MultiLineStringIndexMap will simplify the multi-linestring using this simplifier
+// and return the indexes of the original points that remain for each linestring.
+func (s *CatmullRomSmoother) MultiLineStringIndexMap(mls orb.MultiLineString) (orb.MultiLineString, [][]int) {
+	return multiLineStringIndexMap(s, mls)
+}
+
+// RingIndexMap will simplify the ring using this simplifier
+// and return the indexes of the original points that remain.
+func (s *CatmullRomSmoother) RingIndexMap(r orb.Ring) (orb.Ring, []int) {
+	return ringIndexMap(s, r)
+}
+
+// PolygonIndexMap will simplify the polygon using this simplifier
+// and return the indexes of the original points that remain for each ring.
+func (s *CatmullRomSmoother) PolygonIndexMap(p orb.Polygon) (orb.Polygon, [][]int) {
+	return polygonIndexMap(s, p)
+}
+
+// MultiPolygonIndexMap will simplify the multi-polygon using this simplifier
+// and return the indexes of the original points that remain for each ring.
+func (s *CatmullRomSmoother) MultiPolygonIndexMap(mp orb.MultiPolygon) (orb.MultiPolygon, [][][]int) {
+	return multiPolygonIndexMap(s, mp)
+}
@@ -0,0 +1,77 @@
+package simplify
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestCatmullRom(t *testing.T) {
+	ls := orb.LineString{{0, 0}, {1, 1}, {2, 0}, {3, 1}}
+
+	v, im := CatmullRom(4).simplify(ls.Clone(), true)
+	if len(v) != 3*4+1 {
+		t.Fatalf("incorrect number of points: %d", len(v))
+	}
+
+	// passes through all the original points
+	for i, p := range ls {
+		if !v[4*i].Equal(p) {
+			t.Errorf("should pass through point %d: %v", i, v[4*i])
+		}
+	}
+
+	expected := []int{0, 0, 0, 0, 1, 1, 1, 1, 2, 2, 2, 2, 3}
+	if !reflect.DeepEqual(im, expected) {
+		t.Errorf("incorrect index map: %v", im)
+	}
+
+	// smooth peak at the middle of the second segment, symmetric input.
+	if mid := v[6]; math.Abs(mid[0]-1.5) > 1e-9 || math.Abs(mid[1]-0.5) > 1e-9 {
+		t.Errorf("incorrect mid point: %v", mid)
+	}
+
+	if v[2][1] <= 0.5 {
+		t.Errorf("curve should bend out: %v", v[2])
+	}
+}
+
+func TestCatmullRom_closed(t *testing.T) {
+	r := orb.Ring{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}
+
+	v := CatmullRom(3).Ring(r)
+	if len(v) != 4*3+1 {
+		t.Fatalf("incorrect number of points: %d", len(v))
+	}
+
+	if !v.Closed() {
+		t.Errorf("should be closed")
+	}
+
+	// the curve should bulge outside the square between corners.
+	if v[1][1] >= 0 {
+		t.Errorf("should bulge out: %v", v[1])
+	}
+}
+
+func TestCatmullRom_noSegments(t *testing.T) {
+	ls := orb.LineString{{0, 0}, {1, 1}, {2, 0}}
+
+	v, im := CatmullRom(1).simplify(ls.Clone(), true)
+	if !v.Equal(ls) || !reflect.DeepEqual(im, []int{0, 1, 2}) {
+		t.Errorf("should not change: %v %v", v, im)
+	}
+}
+
+func TestCatmullRom_coincidentPoints(t *testing.T) {
+	ls := orb.LineString{{0, 0}, {0, 0}, {1, 1}}
+
+	v := CatmullRom(2).LineString(ls)
+	for _, p := range v {
+		if math.IsNaN(p[0]) || math.IsNaN(p[1]) {
+			t.Fatalf("should not produce NaN: %v", v)
+		}
+	}
+}
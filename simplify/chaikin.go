@@ -0,0 +1,161 @@
+package simplify
+
+import (
+	"github.com/dadadamarine/orb"
+)
+
+var _ orb.Simplifier = &ChaikinSmoother{}
+
+// A ChaikinSmoother smooths lines using Chaikin's corner cutting algorithm.
+// Every iteration replaces each corner with two points at 1/4 and 3/4 along
+// the adjacent segments, doubling the number of points. The endpoints of open
+// lines are kept, closed lines and rings are smoothed all the way around.
+//
+// Smoothing adds new points so the geometry will not be modified in place.
+// The index map, if requested, gives the index of the original point
+// at the start of the segment each new point was created on.
+type ChaikinSmoother struct {
+	Iterations int
+}
+
+// Chaikin creates a new ChaikinSmoother.
+func Chaikin(iterations int) *ChaikinSmoother {
+	return &ChaikinSmoother{
+		Iterations: iterations,
+	}
+}
+
+func (s *ChaikinSmoother) simplify(ls orb.LineString, wim bool) (orb.LineString, []int) {
+	var indexMap []int
+	if wim {
+		indexMap = make([]int, len(ls))
+		for i := range ls {
+			indexMap[i] = i
+		}
+	}
+
+	closed := ls[0].Equal(ls[len(ls)-1])
+	for iter := 0; iter < s.Iterations && len(ls) > 2; iter++ {
+		result := make(orb.LineString, 0, 2*len(ls))
+
+		var im []int
+		if wim {
+			im = make([]int, 0, 2*len(ls))
+		}
+
+		if !closed {
+			result = append(result, ls[0])
+			if wim {
+				im = append(im, indexMap[0])
+			}
+		}
+
+		for i := 0; i < len(ls)-1; i++ {
+			a, b := ls[i], ls[i+1]
+
+			// the ends of open lines are kept so the outer cuts are skipped.
+			if closed || i != 0 {
+				result = append(result, interpolate(a, b, 0.25))
+				if wim {
+					im = append(im, indexMap[i])
+				}
+			}
+
+			if closed || i != len(ls)-2 {
+				result = append(result, interpolate(a, b, 0.75))
+				if wim {
+					im = append(im, indexMap[i])
+				}
+			}
+		}
+
+		if closed {
+			result = append(result, result[0])
+			if wim {
+				im = append(im, im[0])
+			}
+		} else {
+			result = append(result, ls[len(ls)-1])
+			if wim {
+				im = append(im, indexMap[len(ls)-1])
+			}
+		}
+
+		ls = result
+		indexMap = im
+	}
+
+	return ls, indexMap
+}
+
+func interpolate(a, b orb.Point, percent float64) orb.Point {
+	return orb.Point{
+		a[0] + percent*(b[0]-a[0]),
+		a[1] + percent*(b[1]-a[1]),
+	}
+}
+
+// Simplify will run the simplification for any geometry type.
+func (s *ChaikinSmoother) Simplify(g orb.Geometry) orb.Geometry {
+	return simplify(s, g)
+}
+
+// LineString will simplify the linestring using this simplifier.
+func (s *ChaikinSmoother) LineString(ls orb.LineString) orb.LineString {
+	return lineString(s, ls)
+}
+
+// MultiLineString will simplify the multi-linestring using this simplifier.
+func (s *ChaikinSmoother) MultiLineString(mls orb.MultiLineString) orb.MultiLineString {
+	return multiLineString(s, mls)
+}
+
+// Ring will simplify the ring using this simplifier.
+func (s *ChaikinSmoother) Ring(r orb.Ring) orb.Ring {
+	return ring(s, r)
+}
+
+// Polygon will simplify the polygon using this simplifier.
+func (s *ChaikinSmoother) Polygon(p orb.Polygon) orb.Polygon {
+	return polygon(s, p)
+}
+
+// MultiPolygon will simplify the multi-polygon using this simplifier.
+func (s *ChaikinSmoother) MultiPolygon(mp orb.MultiPolygon) orb.MultiPolygon {
+	return multiPolygon(s, mp)
+}
+
+// Collection will simplify the collection using this simplifier.
+func (s *ChaikinSmoother) Collection(c orb.Collection) orb.Collection {
+	return collection(s, c)
+}
+
+// LineStringIndexMap will simplify the linestring using this simplifier
+// and return the indexes of the original points that remain.
+func (s *ChaikinSmoother) LineStringIndexMap(ls orb.LineString) (orb.LineString, []int) {
+	return lineStringIndexMap(s, ls)
+}
+
+// MultiLineStringIndexMap will simplify the multi-linestring using this simplifier
+// and return the indexes of the original points that remain for each linestring.
+func (s *ChaikinSmoother) MultiLineStringIndexMap(mls orb.MultiLineString) (orb.MultiLineString, [][]int) {
+	return multiLineStringIndexMap(s, mls)
+}
+
+// RingIndexMap will simplify the ring using this simplifier
+// and return the indexes of the original points that remain.
+func (s *ChaikinSmoother) RingIndexMap(r orb.Ring) (orb.Ring, []int) {
+	return ringIndexMap(s, r)
+}
+
+// PolygonIndexMap will simplify the polygon using this simplifier
+// and return the indexes of the original points that remain for each ring.
+func (s *ChaikinSmoother) PolygonIndexMap(p orb.Polygon) (orb.Polygon, [][]int) {
+	return polygonIndexMap(s, p)
+}
+
+// MultiPolygonIndexMap will simplify the multi-polygon using this simplifier
+// and return the indexes of the original points that remain for each ring.
+func (s *ChaikinSmoother) MultiPolygonIndexMap(mp orb.MultiPolygon) (orb.MultiPolygon, [][][]int) {
+	return multiPolygonIndexMap(s, mp)
+}
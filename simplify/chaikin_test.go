@@ -0,0 +1,79 @@
+package simplify
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestChaikin(t *testing.T) {
+	cases := []struct {
+		name       string
+		iterations int
+		ls         orb.LineString
+		expected   orb.LineString
+		indexMap   []int
+	}{
+		{
+			name:       "no iterations",
+			iterations: 0,
+			ls:         orb.LineString{{0, 0}, {4, 4}, {8, 0}},
+			expected:   orb.LineString{{0, 0}, {4, 4}, {8, 0}},
+			indexMap:   []int{0, 1, 2},
+		},
+		{
+			name:       "open line",
+			iterations: 1,
+			ls:         orb.LineString{{0, 0}, {4, 4}, {8, 0}},
+			expected:   orb.LineString{{0, 0}, {3, 3}, {5, 3}, {8, 0}},
+			indexMap:   []int{0, 0, 1, 2},
+		},
+		{
+			name:       "closed line",
+			iterations: 1,
+			ls:         orb.LineString{{0, 0}, {4, 0}, {4, 4}, {0, 0}},
+			expected: orb.LineString{
+				{1, 0}, {3, 0}, {4, 1}, {4, 3}, {3, 3}, {1, 1}, {1, 0},
+			},
+			indexMap: []int{0, 0, 1, 1, 2, 2, 0},
+		},
+		{
+			name:       "two iterations",
+			iterations: 2,
+			ls:         orb.LineString{{0, 0}, {4, 4}, {8, 0}},
+			expected:   orb.LineString{{0, 0}, {2.25, 2.25}, {3.5, 3}, {4.5, 3}, {5.75, 2.25}, {8, 0}},
+			indexMap:   []int{0, 0, 0, 0, 1, 2},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, im := Chaikin(tc.iterations).simplify(tc.ls, true)
+			if !v.Equal(tc.expected) {
+				t.Log(v)
+				t.Log(tc.expected)
+				t.Errorf("incorrect line")
+			}
+
+			if !reflect.DeepEqual(im, tc.indexMap) {
+				t.Log(im)
+				t.Log(tc.indexMap)
+				t.Errorf("incorrect index map")
+			}
+		})
+	}
+}
+
+func TestChaikin_Ring(t *testing.T) {
+	r := orb.Ring{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}
+	r = Chaikin(3).Ring(r)
+
+	if !r.Closed() {
+		t.Errorf("ring should be closed")
+	}
+
+	if len(r) != 4*8+1 {
+		t.Errorf("incorrect number of points: %d", len(r))
+	}
+}
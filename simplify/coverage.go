@@ -0,0 +1,428 @@
+package simplify
+
+import (
+	"math"
+	"sort"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/internal/topology"
+	"github.com/dadadamarine/orb/planar"
+)
+
+var _ orb.Simplifier = &CoverageSimplifier{}
+
+// A CoverageSimplifier simplifies a set of geometries while preserving
+// the boundaries they share. The line strings and rings are split into
+// arcs where they meet and each arc is simplified once using the wrapped
+// simplifier, so adjacent polygons will not develop gaps or overlaps.
+// Original points are added back to the arcs until the result has
+// no intersections that were not in the input.
+type CoverageSimplifier struct {
+	Simplifier orb.Simplifier
+}
+
+// Coverage creates a new CoverageSimplifier wrapping the given simplifier,
+// e.g. simplify.Coverage(simplify.DouglasPeucker(threshold)).
+func Coverage(s orb.Simplifier) *CoverageSimplifier {
+	return &CoverageSimplifier{
+		Simplifier: s,
+	}
+}
+
+// Geometries simplifies the set of geometries together, preserving the
+// boundaries shared between them. The input geometries are not modified.
+// Points, MultiPoints and Bounds are returned unchanged.
+func (s *CoverageSimplifier) Geometries(gs []orb.Geometry) []orb.Geometry {
+	topo := topology.New()
+	for _, g := range gs {
+		addToTopology(topo, g)
+	}
+	topo.Build()
+
+	arcs := s.simplifyArcs(topo)
+
+	c := &coverageBuilder{topo: topo, arcs: arcs}
+	result := make([]orb.Geometry, len(gs))
+	for i, g := range gs {
+		result[i] = c.geometry(g)
+	}
+
+	return result
+}
+
+// Simplify will run the simplification for any geometry type.
+// All the parts of the geometry are simplified together.
+func (s *CoverageSimplifier) Simplify(g orb.Geometry) orb.Geometry {
+	return s.Geometries([]orb.Geometry{g})[0]
+}
+
+// LineString will simplify the linestring using this simplifier.
+func (s *CoverageSimplifier) LineString(ls orb.LineString) orb.LineString {
+	return s.Simplify(ls).(orb.LineString)
+}
+
+// MultiLineString will simplify the multi-linestring using this simplifier.
+func (s *CoverageSimplifier) MultiLineString(mls orb.MultiLineString) orb.MultiLineString {
+	return s.Simplify(mls).(orb.MultiLineString)
+}
+
+// Ring will simplify the ring using this simplifier.
+func (s *CoverageSimplifier) Ring(r orb.Ring) orb.Ring {
+	return s.Simplify(r).(orb.Ring)
+}
+
+// Polygon will simplify the polygon using this simplifier.
+func (s *CoverageSimplifier) Polygon(p orb.Polygon) orb.Polygon {
+	return s.Simplify(p).(orb.Polygon)
+}
+
+// MultiPolygon will simplify the multi-polygon using this simplifier.
+// The polygons are considered a coverage and their shared boundaries preserved.
+func (s *CoverageSimplifier) MultiPolygon(mp orb.MultiPolygon) orb.MultiPolygon {
+	return s.Simplify(mp).(orb.MultiPolygon)
+}
+
+// Collection will simplify the collection using this simplifier.
+// The geometries are simplified together and their shared boundaries preserved.
+func (s *CoverageSimplifier) Collection(c orb.Collection) orb.Collection {
+	return s.Simplify(c).(orb.Collection)
+}
+
+func addToTopology(topo *topology.Topology, geom orb.Geometry) {
+	switch g := geom.(type) {
+	case orb.LineString:
+		topo.AddLineString(g)
+	case orb.MultiLineString:
+		for _, ls := range g {
+			topo.AddLineString(ls)
+		}
+	case orb.Ring:
+		topo.AddRing(g)
+	case orb.Polygon:
+		for _, r := range g {
+			topo.AddRing(r)
+		}
+	case orb.MultiPolygon:
+		for _, p := range g {
+			for _, r := range p {
+				topo.AddRing(r)
+			}
+		}
+	case orb.Collection:
+		for _, c := range g {
+			addToTopology(topo, c)
+		}
+	}
+}
+
+// coverageBuilder rebuilds the geometries from the simplified arcs.
+// Geometries must be visited in the same order as they were added.
+type coverageBuilder struct {
+	topo *topology.Topology
+	arcs []orb.LineString
+	next int
+}
+
+func (c *coverageBuilder) part() orb.LineString {
+	ls, _ := topology.Stitch(c.arcs, c.topo.Part(c.next))
+	c.next++
+
+	return ls
+}
+
+func (c *coverageBuilder) geometry(geom orb.Geometry) orb.Geometry {
+	switch g := geom.(type) {
+	case orb.LineString:
+		return c.part()
+	case orb.MultiLineString:
+		mls := make(orb.MultiLineString, 0, len(g))
+		for range g {
+			mls = append(mls, c.part())
+		}
+		return mls
+	case orb.Ring:
+		return c.ring(g)
+	case orb.Polygon:
+		return c.polygon(g)
+	case orb.MultiPolygon:
+		mp := make(orb.MultiPolygon, 0, len(g))
+		for _, p := range g {
+			mp = append(mp, c.polygon(p))
+		}
+		return mp
+	case orb.Collection:
+		col := make(orb.Collection, 0, len(g))
+		for _, child := range g {
+			col = append(col, c.geometry(child))
+		}
+		return col
+	}
+
+	// nil, points and bounds are returned unchanged
+	return geom
+}
+
+func (c *coverageBuilder) polygon(p orb.Polygon) orb.Polygon {
+	result := make(orb.Polygon, 0, len(p))
+	for _, r := range p {
+		result = append(result, c.ring(r))
+	}
+
+	return result
+}
+
+// ring stitches the next part and rotates it to start
+// at the same point as the original ring, if that point was kept.
+func (c *coverageBuilder) ring(original orb.Ring) orb.Ring {
+	r := orb.Ring(c.part())
+	if len(r) < 2 || len(original) == 0 {
+		return r
+	}
+
+	for i, p := range r[:len(r)-1] {
+		if p != original[0] {
+			continue
+		}
+
+		result := make(orb.Ring, 0, len(r))
+		result = append(result, r[i:len(r)-1]...)
+		result = append(result, r[:i]...)
+		return append(result, r[i])
+	}
+
+	return r
+}
+
+// simplifyArcs simplifies every arc and then refines the arcs by adding
+// back original points until no new intersections or collapsed rings remain.
+func (s *CoverageSimplifier) simplifyArcs(topo *topology.Topology) []orb.LineString {
+	originals := topo.Arcs()
+
+	arcs := make([]*coverageArc, len(originals))
+	for i, o := range originals {
+		arcs[i] = &coverageArc{
+			original: o,
+			breaks:   []int{0, len(o) - 1},
+		}
+		arcs[i].simplify(s.Simplifier)
+	}
+
+	for {
+		refine := findConflicts(arcs)
+
+		// rings must not collapse to a line or point.
+		current := make([]orb.LineString, len(arcs))
+		for i, a := range arcs {
+			current[i] = a.current
+		}
+
+		for i := 0; i < topo.NumParts(); i++ {
+			if !topo.IsRing(i) {
+				continue
+			}
+
+			ls, _ := topology.Stitch(current, topo.Part(i))
+			if len(ls) >= 4 && math.Abs(planar.Area(orb.Ring(ls))) > 0 {
+				continue
+			}
+
+			for _, index := range topo.Part(i) {
+				if index < 0 {
+					index = ^index
+				}
+
+				for k := 0; k < len(arcs[index].breaks)-1; k++ {
+					refine.add(index, k)
+				}
+			}
+		}
+
+		refined := false
+		for i, pieces := range refine {
+			if arcs[i].refine(pieces) {
+				arcs[i].simplify(s.Simplifier)
+				refined = true
+			}
+		}
+
+		if !refined {
+			return current
+		}
+	}
+}
+
+// coverageArc is an arc that is simplified in pieces between
+// break points. Refining the arc adds break points so more
+// of the original points are kept.
+type coverageArc struct {
+	original orb.LineString
+	breaks   []int
+
+	current orb.LineString
+	pieces  []int // the piece for every segment of current
+}
+
+func (a *coverageArc) simplify(s orb.Simplifier) {
+	a.current = append(a.current[:0], a.original[0])
+	a.pieces = a.pieces[:0]
+
+	for k := 0; k < len(a.breaks)-1; k++ {
+		start, end := a.breaks[k], a.breaks[k+1]
+
+		piece := a.original[start : end+1].Clone()
+		ls := s.LineString(piece)
+
+		// the endpoints are the break points and must be kept.
+		if len(ls) < 2 {
+			ls = orb.LineString{a.original[start], a.original[end]}
+		}
+		ls[0] = a.original[start]
+		ls[len(ls)-1] = a.original[end]
+
+		for _, p := range ls[1:] {
+			a.current = append(a.current, p)
+			a.pieces = append(a.pieces, k)
+		}
+	}
+}
+
+// refine adds a break point in the middle of the given pieces.
+// Returns false if the pieces already keep all the original points.
+func (a *coverageArc) refine(pieces map[int]struct{}) bool {
+	breaks := make([]int, 0, len(a.breaks)+len(pieces))
+
+	refined := false
+	for k := 0; k < len(a.breaks)-1; k++ {
+		start, end := a.breaks[k], a.breaks[k+1]
+		breaks = append(breaks, start)
+
+		if _, ok := pieces[k]; ok && end-start > 1 {
+			breaks = append(breaks, (start+end)/2)
+			refined = true
+		}
+	}
+	breaks = append(breaks, a.breaks[len(a.breaks)-1])
+
+	a.breaks = breaks
+	return refined
+}
+
+// refinements is the set of pieces to refine keyed by arc index.
+type refinements map[int]map[int]struct{}
+
+func (r refinements) add(arc, piece int) {
+	if r[arc] == nil {
+		r[arc] = make(map[int]struct{})
+	}
+	r[arc][piece] = struct{}{}
+}
+
+type coverageSegment struct {
+	a, b  orb.Point
+	bound orb.Bound
+	arc   int
+	piece int
+}
+
+// findConflicts returns the pieces of the arcs that have segments
+// intersecting other segments at points other than their shared endpoints.
+func findConflicts(arcs []*coverageArc) refinements {
+	var segments []coverageSegment
+	for i, a := range arcs {
+		for j := 0; j < len(a.current)-1; j++ {
+			segments = append(segments, coverageSegment{
+				a:     a.current[j],
+				b:     a.current[j+1],
+				bound: orb.MultiPoint{a.current[j], a.current[j+1]}.Bound(),
+				arc:   i,
+				piece: a.pieces[j],
+			})
+		}
+	}
+
+	// sweep along the x axis only comparing segments that overlap.
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].bound.Min[0] < segments[j].bound.Min[0]
+	})
+
+	result := refinements{}
+	for i := range segments {
+		s1 := &segments[i]
+		for j := i + 1; j < len(segments) && segments[j].bound.Min[0] <= s1.bound.Max[0]; j++ {
+			s2 := &segments[j]
+			if !s1.bound.Intersects(s2.bound) {
+				continue
+			}
+
+			if segmentsConflict(s1.a, s1.b, s2.a, s2.b) {
+				result.add(s1.arc, s1.piece)
+				result.add(s2.arc, s2.piece)
+			}
+		}
+	}
+
+	return result
+}
+
+// segmentsConflict returns true if the segments ab and cd intersect
+// at anything other than a single shared endpoint.
+func segmentsConflict(a, b, c, d orb.Point) bool {
+	if (a == c && b == d) || (a == d && b == c) {
+		return a != b
+	}
+
+	o1 := orient(a, b, c)
+	o2 := orient(a, b, d)
+	o3 := orient(c, d, a)
+	o4 := orient(c, d, b)
+
+	// proper crossing
+	if o1*o2 < 0 && o3*o4 < 0 {
+		return true
+	}
+
+	var shared, other1, other2 orb.Point
+	switch {
+	case a == c:
+		shared, other1, other2 = a, b, d
+	case a == d:
+		shared, other1, other2 = a, b, c
+	case b == c:
+		shared, other1, other2 = b, a, d
+	case b == d:
+		shared, other1, other2 = b, a, c
+	default:
+		// no shared endpoints, any touching is a conflict.
+		return (o1 == 0 && onSegment(a, b, c)) ||
+			(o2 == 0 && onSegment(a, b, d)) ||
+			(o3 == 0 && onSegment(c, d, a)) ||
+			(o4 == 0 && onSegment(c, d, b))
+	}
+
+	// with a shared endpoint the segments can only overlap if collinear.
+	if orient(shared, other1, other2) != 0 {
+		return false
+	}
+
+	return onSegment(shared, other1, other2) || onSegment(shared, other2, other1)
+}
+
+// orient returns the sign of the cross product of ab and ac.
+func orient(a, b, c orb.Point) float64 {
+	v := (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+	if v > 0 {
+		return 1
+	}
+
+	if v < 0 {
+		return -1
+	}
+
+	return 0
+}
+
+// onSegment returns true if the collinear point p is within the bound of ab.
+func onSegment(a, b, p orb.Point) bool {
+	return p[0] >= math.Min(a[0], b[0]) && p[0] <= math.Max(a[0], b[0]) &&
+		p[1] >= math.Min(a[1], b[1]) && p[1] <= math.Max(a[1], b[1])
+}
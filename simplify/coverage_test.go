@@ -0,0 +1,264 @@
+package simplify
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestCoverage_sharedBoundary(t *testing.T) {
+	left := orb.Polygon{{
+		{0, 0}, {1, 0}, {1.01, 0.25}, {0.99, 0.5}, {1.01, 0.75}, {1, 1}, {0, 1}, {0, 0},
+	}}
+	right := orb.Polygon{{
+		{1, 0}, {2, 0}, {2, 1}, {1, 1}, {1.01, 0.75}, {0.99, 0.5}, {1.01, 0.25}, {1, 0},
+	}}
+
+	result := Coverage(DouglasPeucker(0.1)).Geometries([]orb.Geometry{left, right})
+
+	expectedLeft := orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}
+	if !orb.Equal(result[0], expectedLeft) {
+		t.Errorf("incorrect left: %v", result[0])
+	}
+
+	expectedRight := orb.Polygon{{{1, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 0}}}
+	if !orb.Equal(result[1], expectedRight) {
+		t.Errorf("incorrect right: %v", result[1])
+	}
+
+	// input should not be modified
+	if len(left[0]) != 8 || len(right[0]) != 8 {
+		t.Errorf("input was modified")
+	}
+}
+
+func TestCoverage_noIntersections(t *testing.T) {
+	line := orb.LineString{{0, 0}, {2.5, 0.5}, {5, 1}, {7.5, 0.5}, {10, 0}}
+	obstacle := orb.LineString{{5, 0.5}, {5, -3}}
+
+	// without the coverage simplifier the line would cross the obstacle.
+	plain := DouglasPeucker(2).LineString(line.Clone())
+	if !segmentsConflict(plain[0], plain[1], obstacle[0], obstacle[1]) {
+		t.Fatalf("test case should have a conflict: %v", plain)
+	}
+
+	result := Coverage(DouglasPeucker(2)).Geometries([]orb.Geometry{line, obstacle})
+
+	expected := orb.LineString{{0, 0}, {5, 1}, {10, 0}}
+	if !orb.Equal(result[0], expected) {
+		t.Errorf("incorrect line: %v", result[0])
+	}
+
+	if !orb.Equal(result[1], obstacle) {
+		t.Errorf("obstacle should not change: %v", result[1])
+	}
+}
+
+func TestCoverage_ringCollapse(t *testing.T) {
+	r := orb.Ring{{0, 0}, {1, 0}, {1, 1}, {0.5, 1.1}, {0, 1}, {0, 0}}
+
+	result := Coverage(DouglasPeucker(10)).Ring(r)
+	if len(result) < 4 {
+		t.Errorf("ring should not collapse: %v", result)
+	}
+
+	if !result.Closed() {
+		t.Errorf("ring should be closed: %v", result)
+	}
+}
+
+func TestCoverage_orientation(t *testing.T) {
+	ccw := wigglyCircle(orb.Point{0, 0}, 10, 200)
+	cw := reversed(ccw)
+	hole := reversed(wigglyCircle(orb.Point{0, 0}, 5, 200))
+
+	s := Coverage(DouglasPeucker(0.5))
+
+	for _, r := range []orb.Ring{ccw, cw} {
+		result := s.Ring(r.Clone())
+		if len(result) < 4 || len(result) >= len(r)/2 {
+			t.Errorf("ring should be simplified: %d -> %d points", len(r), len(result))
+		}
+	}
+
+	p := s.Polygon(orb.Polygon{ccw.Clone(), hole.Clone()})
+	if len(p) != 2 {
+		t.Fatalf("hole should be kept: %v", len(p))
+	}
+
+	for i, r := range p {
+		if len(r) < 4 || len(r) >= 100 {
+			t.Errorf("ring %d should be simplified: %d points", i, len(r))
+		}
+	}
+}
+
+func TestCoverage_sharedBoundaryWithHoles(t *testing.T) {
+	// a wiggly shared edge at x = 10, the left shell is clockwise
+	edge := orb.LineString{}
+	for i := 0; i <= 100; i++ {
+		y := float64(i) / 10
+		edge = append(edge, orb.Point{10 + 0.05*math.Sin(float64(i)), y})
+	}
+
+	left := orb.Ring{{0, 10}}
+	left = append(left, reversed(orb.Ring(edge))...)
+	left = append(left, orb.Point{0, 0}, orb.Point{0, 10})
+
+	right := orb.Ring{}
+	right = append(right, edge...)
+	right = append(right, orb.Point{20, 10}, orb.Point{20, 0}, edge[0])
+
+	leftHole := reversed(wigglyCircle(orb.Point{5, 5}, 2, 200))
+	rightHole := reversed(wigglyCircle(orb.Point{15, 5}, 2, 200))
+
+	result := Coverage(DouglasPeucker(0.5)).Geometries([]orb.Geometry{
+		orb.Polygon{left, leftHole},
+		orb.Polygon{right, rightHole},
+	})
+
+	originals := [][2]orb.Ring{{left, leftHole}, {right, rightHole}}
+	for i, g := range result {
+		p := g.(orb.Polygon)
+		if len(p) != 2 {
+			t.Fatalf("%d: hole should be kept: %v", i, len(p))
+		}
+
+		for j, r := range p {
+			if len(r) < 4 || len(r) >= len(originals[i][j])/2 {
+				t.Errorf("%d: ring %d should be simplified: %d -> %d points",
+					i, j, len(originals[i][j]), len(r))
+			}
+		}
+	}
+
+	// the points kept on the shared edge must be the same for both polygons
+	onEdge := map[orb.Point]bool{}
+	for _, p := range edge {
+		onEdge[p] = true
+	}
+
+	shared := func(r orb.Ring) map[orb.Point]bool {
+		m := map[orb.Point]bool{}
+		for _, p := range r {
+			if onEdge[p] {
+				m[p] = true
+			}
+		}
+		return m
+	}
+
+	l, r := shared(result[0].(orb.Polygon)[0]), shared(result[1].(orb.Polygon)[0])
+	if len(l) < 2 || len(l) >= len(edge) {
+		t.Errorf("shared edge should be simplified: %d points", len(l))
+	}
+
+	if len(l) != len(r) {
+		t.Fatalf("shared edge should match: %d != %d", len(l), len(r))
+	}
+
+	for p := range l {
+		if !r[p] {
+			t.Errorf("shared edge should match, missing %v", p)
+		}
+	}
+}
+
+// wigglyCircle returns a counter clockwise closed ring with n+1 points.
+func wigglyCircle(center orb.Point, radius float64, n int) orb.Ring {
+	r := make(orb.Ring, 0, n+1)
+	for i := 0; i < n; i++ {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		d := radius * (1 + 0.01*math.Sin(7*a))
+		r = append(r, orb.Point{center[0] + d*math.Cos(a), center[1] + d*math.Sin(a)})
+	}
+
+	return append(r, r[0])
+}
+
+func reversed(r orb.Ring) orb.Ring {
+	result := make(orb.Ring, 0, len(r))
+	for i := len(r) - 1; i >= 0; i-- {
+		result = append(result, r[i])
+	}
+
+	return result
+}
+
+func TestCoverage_Simplify(t *testing.T) {
+	s := Coverage(DouglasPeucker(0))
+	for _, g := range orb.AllGeometries {
+		s.Simplify(g)
+	}
+
+	mp := orb.MultiPolygon{
+		{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+		{{{1, 0}, {2, 0}, {1, 1}, {1, 0}}},
+	}
+
+	result := s.MultiPolygon(mp)
+	if !result.Equal(mp) {
+		t.Errorf("zero threshold should not change: %v", result)
+	}
+}
+
+func TestSegmentsConflict(t *testing.T) {
+	cases := []struct {
+		name       string
+		a, b, c, d orb.Point
+		conflict   bool
+	}{
+		{
+			name: "crossing",
+			a:    orb.Point{0, 0}, b: orb.Point{2, 2},
+			c: orb.Point{0, 2}, d: orb.Point{2, 0},
+			conflict: true,
+		},
+		{
+			name: "disjoint",
+			a:    orb.Point{0, 0}, b: orb.Point{1, 0},
+			c: orb.Point{0, 1}, d: orb.Point{1, 1},
+			conflict: false,
+		},
+		{
+			name: "shared endpoint",
+			a:    orb.Point{0, 0}, b: orb.Point{1, 0},
+			c: orb.Point{1, 0}, d: orb.Point{1, 1},
+			conflict: false,
+		},
+		{
+			name: "shared endpoint collinear",
+			a:    orb.Point{0, 0}, b: orb.Point{1, 0},
+			c: orb.Point{1, 0}, d: orb.Point{2, 0},
+			conflict: false,
+		},
+		{
+			name: "shared endpoint overlap",
+			a:    orb.Point{0, 0}, b: orb.Point{2, 0},
+			c: orb.Point{0, 0}, d: orb.Point{1, 0},
+			conflict: true,
+		},
+		{
+			name: "touching",
+			a:    orb.Point{0, 0}, b: orb.Point{2, 0},
+			c: orb.Point{1, 0}, d: orb.Point{1, 1},
+			conflict: true,
+		},
+		{
+			name: "same segment",
+			a:    orb.Point{0, 0}, b: orb.Point{2, 0},
+			c: orb.Point{2, 0}, d: orb.Point{0, 0},
+			conflict: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := segmentsConflict(tc.a, tc.b, tc.c, tc.d)
+			if v != tc.conflict {
+				t.Errorf("incorrect conflict: %v != %v", v, tc.conflict)
+			}
+		})
+	}
+}
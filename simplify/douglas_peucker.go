@@ -24,7 +24,7 @@ func (s *DouglasPeuckerSimplifier) simplify(ls orb.LineString, wim bool) (orb.Li
 	mask[0] = 1
 	mask[len(mask)-1] = 1
 
-	found := dpWorker(ls, s.Threshold, mask)
+	found := dpWorker(ls, s.Threshold, nil, mask)
 	var indexMap []int
 	if wim {
 		indexMap = make([]int, 0, found)
@@ -47,7 +47,8 @@ func (s *DouglasPeuckerSimplifier) simplify(ls orb.LineString, wim bool) (orb.Li
 // dpWorker does the recursive threshold checks.
 // Using a stack array with a stackLength variable resulted in
 // 4x speed improvement over calling the function recursively.
-func dpWorker(ls orb.LineString, threshold float64, mask []byte) int {
+// If not nil, the squared distance of each point is multiplied by its weight.
+func dpWorker(ls orb.LineString, threshold float64, weights []float64, mask []byte) int {
 	found := 2
 
 	var stack []int
@@ -63,6 +64,10 @@ func dpWorker(ls orb.LineString, threshold float64, mask []byte) int {
 
 		for i := start + 1; i < end; i++ {
 			dist := planar.DistanceFromSegmentSquared(ls[start], ls[end], ls[i])
+			if weights != nil {
+				dist *= weights[i]
+			}
+
 			if dist > maxDist {
 				maxDist = dist
 				maxIndex = i
@@ -117,3 +122,33 @@ func (s *DouglasPeuckerSimplifier) MultiPolygon(mp orb.MultiPolygon) orb.MultiPo
 func (s *DouglasPeuckerSimplifier) Collection(c orb.Collection) orb.Collection {
 	return collection(s, c)
 }
+
+// LineStringIndexMap will simplify the linestring using this simplifier
+// and return the indexes of the original points that remain.
+func (s *DouglasPeuckerSimplifier) LineStringIndexMap(ls orb.LineString) (orb.LineString, []int) {
+	return lineStringIndexMap(s, ls)
+}
+
+// MultiLineStringIndexMap will simplify the multi-linestring using this simplifier
+// and return the indexes of the original points that remain for each linestring.
+func (s *DouglasPeuckerSimplifier) MultiLineStringIndexMap(mls orb.MultiLineString) (orb.MultiLineString, [][]int) {
+	return multiLineStringIndexMap(s, mls)
+}
+
+// RingIndexMap will simplify the ring using this simplifier
+// and return the indexes of the original points that remain.
+func (s *DouglasPeuckerSimplifier) RingIndexMap(r orb.Ring) (orb.Ring, []int) {
+	return ringIndexMap(s, r)
+}
+
+// PolygonIndexMap will simplify the polygon using this simplifier
+// and return the indexes of the original points that remain for each ring.
+func (s *DouglasPeuckerSimplifier) PolygonIndexMap(p orb.Polygon) (orb.Polygon, [][]int) {
+	return polygonIndexMap(s, p)
+}
+
+// MultiPolygonIndexMap will simplify the multi-polygon using this simplifier
+// and return the indexes of the original points that remain for each ring.
+func (s *DouglasPeuckerSimplifier) MultiPolygonIndexMap(mp orb.MultiPolygon) (orb.MultiPolygon, [][][]int) {
+	return multiPolygonIndexMap(s, mp)
+}
@@ -0,0 +1,235 @@
+package simplify
+
+import (
+	"math"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/project"
+)
+
+var (
+	_ orb.Simplifier = &GeoDouglasPeuckerSimplifier{}
+	_ orb.Simplifier = &GeoVisvalingamSimplifier{}
+)
+
+// A GeoDouglasPeuckerSimplifier runs the Douglas-Peucker algorithm on lon/lat
+// geometry with a threshold in meters. Distances are computed in the mercator
+// projection and scaled by the mercator scale factor at each point, so the
+// same threshold removes the same amount of detail at any latitude.
+type GeoDouglasPeuckerSimplifier struct {
+	Threshold float64 // meters
+}
+
+// GeoDouglasPeucker creates a new GeoDouglasPeuckerSimplifier.
+func GeoDouglasPeucker(meters float64) *GeoDouglasPeuckerSimplifier {
+	return &GeoDouglasPeuckerSimplifier{
+		Threshold: meters,
+	}
+}
+
+func (s *GeoDouglasPeuckerSimplifier) simplify(ls orb.LineString, wim bool) (orb.LineString, []int) {
+	projected, weights := toScaledMercator(ls)
+
+	mask := make([]byte, len(ls))
+	mask[0] = 1
+	mask[len(mask)-1] = 1
+
+	found := dpWorker(projected, s.Threshold, weights, mask)
+	var indexMap []int
+	if wim {
+		indexMap = make([]int, 0, found)
+	}
+
+	count := 0
+	for i, v := range mask {
+		if v == 1 {
+			ls[count] = ls[i]
+			count++
+			if wim {
+				indexMap = append(indexMap, i)
+			}
+		}
+	}
+
+	return ls[:count], indexMap
+}
+
+// Simplify will run the simplification for any geometry type.
+func (s *GeoDouglasPeuckerSimplifier) Simplify(g orb.Geometry) orb.Geometry {
+	return simplify(s, g)
+}
+
+// LineString will simplify the linestring using this simplifier.
+func (s *GeoDouglasPeuckerSimplifier) LineString(ls orb.LineString) orb.LineString {
+	return lineString(s, ls)
+}
+
+// MultiLineString will simplify the multi-linestring using this simplifier.
+func (s *GeoDouglasPeuckerSimplifier) MultiLineString(mls orb.MultiLineString) orb.MultiLineString {
+	return multiLineString(s, mls)
+}
+
+// Ring will simplify the ring using this simplifier.
+func (s *GeoDouglasPeuckerSimplifier) Ring(r orb.Ring) orb.Ring {
+	return ring(s, r)
+}
+
+// Polygon will simplify the polygon using this simplifier.
+func (s *GeoDouglasPeuckerSimplifier) Polygon(p orb.Polygon) orb.Polygon {
+	return polygon(s, p)
+}
+
+// MultiPolygon will simplify the multi-polygon using this simplifier.
+func (s *GeoDouglasPeuckerSimplifier) MultiPolygon(mp orb.MultiPolygon) orb.MultiPolygon {
+	return multiPolygon(s, mp)
+}
+
+// Collection will simplify the collection using this simplifier.
+func (s *GeoDouglasPeuckerSimplifier) Collection(c orb.Collection) orb.Collection {
+	return collection(s, c)
+}
+
+// LineStringIndexMap will simplify the linestring using this simplifier
+// and return the indexes of the original points that remain.
+func (s *GeoDouglasPeuckerSimplifier) LineStringIndexMap(ls orb.LineString) (orb.LineString, []int) {
+	return lineStringIndexMap(s, ls)
+}
+
+// MultiLineStringIndexMap will simplify the multi-linestring using this simplifier
+// and return the indexes of the original points that remain for each linestring.
+func (s *GeoDouglasPeuckerSimplifier) MultiLineStringIndexMap(mls orb.MultiLineString) (orb.MultiLineString, [][]int) {
+	return multiLineStringIndexMap(s, mls)
+}
+
+// RingIndexMap will simplify the ring using this simplifier
+// and return the indexes of the original points that remain.
+func (s *GeoDouglasPeuckerSimplifier) RingIndexMap(r orb.Ring) (orb.Ring, []int) {
+	return ringIndexMap(s, r)
+}
+
+// PolygonIndexMap will simplify the polygon using this simplifier
+// and return the indexes of the original points that remain for each ring.
+func (s *GeoDouglasPeuckerSimplifier) PolygonIndexMap(p orb.Polygon) (orb.Polygon, [][]int) {
+	return polygonIndexMap(s, p)
+}
+
+// MultiPolygonIndexMap will simplify the multi-polygon using this simplifier
+// and return the indexes of the original points that remain for each ring.
+func (s *GeoDouglasPeuckerSimplifier) MultiPolygonIndexMap(mp orb.MultiPolygon) (orb.MultiPolygon, [][][]int) {
+	return multiPolygonIndexMap(s, mp)
+}
+
+// A GeoVisvalingamSimplifier runs the Visvalingam-Whyatt algorithm on lon/lat
+// geometry with a threshold in square meters. Triangle areas are computed in the
+// mercator projection and scaled by the mercator scale factor of the middle point.
+type GeoVisvalingamSimplifier struct {
+	Threshold float64 // square meters
+	ToKeep    int
+}
+
+// GeoVisvalingam creates a new GeoVisvalingamSimplifier.
+func GeoVisvalingam(squareMeters float64, minPointsToKeep int) *GeoVisvalingamSimplifier {
+	return &GeoVisvalingamSimplifier{
+		Threshold: squareMeters,
+		ToKeep:    minPointsToKeep,
+	}
+}
+
+// GeoVisvalingamThreshold runs the Visvalingam-Whyatt algorithm removing
+// triangles whose area, in square meters, is below the threshold.
+func GeoVisvalingamThreshold(squareMeters float64) *GeoVisvalingamSimplifier {
+	return GeoVisvalingam(squareMeters, 0)
+}
+
+func (s *GeoVisvalingamSimplifier) simplify(ls orb.LineString, wim bool) (orb.LineString, []int) {
+	projected, weights := toScaledMercator(ls)
+	area := func(i1, i2, i3 int) float64 {
+		return doubleTriangleArea(projected, i1, i2, i3) * weights[i2]
+	}
+
+	return visvalingam(ls, area, s.Threshold, s.ToKeep, wim)
+}
+
+// Simplify will run the simplification for any geometry type.
+func (s *GeoVisvalingamSimplifier) Simplify(g orb.Geometry) orb.Geometry {
+	return simplify(s, g)
+}
+
+// LineString will simplify the linestring using this simplifier.
+func (s *GeoVisvalingamSimplifier) LineString(ls orb.LineString) orb.LineString {
+	return lineString(s, ls)
+}
+
+// MultiLineString will simplify the multi-linestring using this simplifier.
+func (s *GeoVisvalingamSimplifier) MultiLineString(mls orb.MultiLineString) orb.MultiLineString {
+	return multiLineString(s, mls)
+}
+
+// Ring will simplify the ring using this simplifier.
+func (s *GeoVisvalingamSimplifier) Ring(r orb.Ring) orb.Ring {
+	return ring(s, r)
+}
+
+// Polygon will simplify the polygon using this simplifier.
+func (s *GeoVisvalingamSimplifier) Polygon(p orb.Polygon) orb.Polygon {
+	return polygon(s, p)
+}
+
+// MultiPolygon will simplify the multi-polygon using this simplifier.
+func (s *GeoVisvalingamSimplifier) MultiPolygon(mp orb.MultiPolygon) orb.MultiPolygon {
+	return multiPolygon(s, mp)
+}
+
+// Collection will simplify the collection using this simplifier.
+func (s *GeoVisvalingamSimplifier) Collection(c orb.Collection) orb.Collection {
+	return collection(s, c)
+}
+
+// LineStringIndexMap will simplify the linestring using this simplifier
+// and return the indexes of the original points that remain.
+func (s *GeoVisvalingamSimplifier) LineStringIndexMap(ls orb.LineString) (orb.LineString, []int) {
+	return lineStringIndexMap(s, ls)
+}
+
+// MultiLineStringIndexMap will simplify the multi-linestring using this simplifier
+// and return the indexes of the original points that remain for each linestring.
+func (s *GeoVisvalingamSimplifier) MultiLineStringIndexMap(mls orb.MultiLineString) (orb.MultiLineString, [][]int) {
+	return multiLineStringIndexMap(s, mls)
+}
+
+// RingIndexMap will simplify the ring using this simplifier
+// and return the indexes of the original points that remain.
+func (s *GeoVisvalingamSimplifier) RingIndexMap(r orb.Ring) (orb.Ring, []int) {
+	return ringIndexMap(s, r)
+}
+
+// PolygonIndexMap will simplify the polygon using this simplifier
+// and return the indexes of the original points that remain for each ring.
+func (s *GeoVisvalingamSimplifier) PolygonIndexMap(p orb.Polygon) (orb.Polygon, [][]int) {
+	return polygonIndexMap(s, p)
+}
+
+// MultiPolygonIndexMap will simplify the multi-polygon using this simplifier
+// and return the indexes of the original points that remain for each ring.
+func (s *GeoVisvalingamSimplifier) MultiPolygonIndexMap(mp orb.MultiPolygon) (orb.MultiPolygon, [][][]int) {
+	return multiPolygonIndexMap(s, mp)
+}
+
+// toScaledMercator projects the line string to mercator and returns the
+// weights to convert squared mercator lengths at each point into square meters.
+// Mercator distances are stretched by the scale factor at the latitude.
+func toScaledMercator(ls orb.LineString) (orb.LineString, []float64) {
+	projected := make(orb.LineString, len(ls))
+	weights := make([]float64, len(ls))
+
+	for i, p := range ls {
+		projected[i] = project.WGS84.ToMercator(p)
+
+		// clamp so the poles do not panic or divide by zero.
+		lat := math.Max(-89.9, math.Min(89.9, p[1]))
+		sf := project.MercatorScaleFactor(orb.Point{p[0], lat})
+		weights[i] = 1 / (sf * sf)
+	}
+
+	return projected, weights
+}
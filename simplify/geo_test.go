@@ -0,0 +1,73 @@
+package simplify
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geo"
+)
+
+// offsetLine returns a line about 10km long heading east at the given latitude
+// with a middle point offset to the north by the given distance in meters.
+func offsetLine(lat, offset float64) orb.LineString {
+	start := orb.Point{10, lat}
+	end := geo.PointAtBearingAndDistance(start, 90, 10000)
+	mid := geo.Midpoint(start, end)
+
+	return orb.LineString{start, geo.PointAtBearingAndDistance(mid, 0, offset), end}
+}
+
+func TestGeoDouglasPeucker(t *testing.T) {
+	for _, lat := range []float64{0, 45, 70, -80} {
+		ls := offsetLine(lat, 100)
+
+		v, im := GeoDouglasPeucker(50).simplify(ls.Clone(), true)
+		if len(v) != 3 || !reflect.DeepEqual(im, []int{0, 1, 2}) {
+			t.Errorf("lat %v: should keep point: %v", lat, im)
+		}
+
+		v, im = GeoDouglasPeucker(150).simplify(ls.Clone(), true)
+		if len(v) != 2 || !reflect.DeepEqual(im, []int{0, 2}) {
+			t.Errorf("lat %v: should remove point: %v", lat, im)
+		}
+	}
+}
+
+func TestGeoVisvalingam(t *testing.T) {
+	for _, lat := range []float64{0, 45, 70, -80} {
+		// triangle area is 10000*100/2 = 500000 square meters
+		ls := offsetLine(lat, 100)
+
+		v, im := GeoVisvalingamThreshold(250000).simplify(ls.Clone(), true)
+		if len(v) != 3 || !reflect.DeepEqual(im, []int{0, 1, 2}) {
+			t.Errorf("lat %v: should keep point: %v", lat, im)
+		}
+
+		v, im = GeoVisvalingamThreshold(750000).simplify(ls.Clone(), true)
+		if len(v) != 2 || !reflect.DeepEqual(im, []int{0, 2}) {
+			t.Errorf("lat %v: should remove point: %v", lat, im)
+		}
+
+		v, _ = GeoVisvalingam(750000, 3).simplify(ls.Clone(), false)
+		if len(v) != 3 {
+			t.Errorf("lat %v: should keep minimum points: %v", lat, v)
+		}
+	}
+}
+
+func TestGeoSimplifiers_Simplify(t *testing.T) {
+	simplifiers := []orb.Simplifier{
+		GeoDouglasPeucker(10),
+		GeoVisvalingamThreshold(10),
+	}
+
+	for _, s := range simplifiers {
+		for _, g := range orb.AllGeometries {
+			s.Simplify(g)
+		}
+
+		// poles should not panic
+		s.LineString(orb.LineString{{0, 90}, {10, 89}, {20, 90}})
+	}
+}
@@ -113,6 +113,59 @@ func collection(s simplifier, c orb.Collection) orb.Collection {
 	return c
 }
 
+func lineStringIndexMap(s simplifier, ls orb.LineString) (orb.LineString, []int) {
+	return runSimplifyWithIndexes(s, ls)
+}
+
+func multiLineStringIndexMap(s simplifier, mls orb.MultiLineString) (orb.MultiLineString, [][]int) {
+	indexMaps := make([][]int, len(mls))
+	for i := range mls {
+		mls[i], indexMaps[i] = runSimplifyWithIndexes(s, mls[i])
+	}
+	return mls, indexMaps
+}
+
+func ringIndexMap(s simplifier, r orb.Ring) (orb.Ring, []int) {
+	ls, im := runSimplifyWithIndexes(s, orb.LineString(r))
+	return orb.Ring(ls), im
+}
+
+// polygonIndexMap matches polygon, rings that are removed will also
+// not have an index map so the result lines up with the returned polygon.
+func polygonIndexMap(s simplifier, p orb.Polygon) (orb.Polygon, [][]int) {
+	indexMaps := make([][]int, 0, len(p))
+
+	count := 0
+	for i := range p {
+		ls, im := runSimplifyWithIndexes(s, orb.LineString(p[i]))
+		if i != 0 && len(ls) <= 2 {
+			continue
+		}
+
+		p[count] = orb.Ring(ls)
+		indexMaps = append(indexMaps, im)
+		count++
+	}
+	return p[:count], indexMaps
+}
+
+func multiPolygonIndexMap(s simplifier, mp orb.MultiPolygon) (orb.MultiPolygon, [][][]int) {
+	indexMaps := make([][][]int, 0, len(mp))
+
+	count := 0
+	for i := range mp {
+		p, im := polygonIndexMap(s, mp[i])
+		if len(p[0]) <= 2 {
+			continue
+		}
+
+		mp[count] = p
+		indexMaps = append(indexMaps, im)
+		count++
+	}
+	return mp[:count], indexMaps
+}
+
 func runSimplify(s simplifier, ls orb.LineString) orb.LineString {
 	if len(ls) <= 2 {
 		return ls
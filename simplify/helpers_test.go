@@ -1,6 +1,7 @@
 package simplify
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/dadadamarine/orb"
@@ -11,6 +12,20 @@ func TestSimplify(t *testing.T) {
 	for _, g := range orb.AllGeometries {
 		simplify(r, g)
 	}
+
+	simplifiers := []simplifier{
+		ReumannWitkam(10),
+		Lang(10, 5),
+		ZhaoSaalfeld(10),
+		Chaikin(2),
+		CatmullRom(4),
+	}
+
+	for _, s := range simplifiers {
+		for _, g := range orb.AllGeometries {
+			simplify(s, g)
+		}
+	}
 }
 
 func TestPolygon(t *testing.T) {
@@ -36,3 +51,56 @@ func TestMultiPolygon(t *testing.T) {
 		t.Errorf("should remove empty polygon")
 	}
 }
+
+func TestIndexMap(t *testing.T) {
+	s := DouglasPeucker(0.5)
+
+	ls, im := s.LineStringIndexMap(orb.LineString{{0, 0}, {1, 0.1}, {2, 0}, {2, 2}})
+	if !ls.Equal(orb.LineString{{0, 0}, {2, 0}, {2, 2}}) || !reflect.DeepEqual(im, []int{0, 2, 3}) {
+		t.Errorf("incorrect linestring: %v %v", ls, im)
+	}
+
+	mls, ims := s.MultiLineStringIndexMap(orb.MultiLineString{
+		{{0, 0}, {1, 0.1}, {2, 0}},
+		{{0, 0}, {1, 1}},
+	})
+	if len(mls) != 2 || !reflect.DeepEqual(ims, [][]int{{0, 2}, {0, 1}}) {
+		t.Errorf("incorrect multi-linestring: %v %v", mls, ims)
+	}
+
+	r, im := s.RingIndexMap(orb.Ring{{0, 0}, {1, 0.1}, {2, 0}, {2, 2}, {0, 2}, {0, 0}})
+	if len(r) != 5 || !reflect.DeepEqual(im, []int{0, 2, 3, 4, 5}) {
+		t.Errorf("incorrect ring: %v %v", r, im)
+	}
+}
+
+func TestPolygonIndexMap(t *testing.T) {
+	p := orb.Polygon{
+		{{0, 0}, {1, 0}, {1, 1}, {0, 0}},
+		{{0, 0}, {0, 0}},
+		{{0, 0}, {0.5, 0.01}, {1, 0}, {1, 1}, {0, 0}},
+	}
+
+	p, ims := DouglasPeucker(0.1).PolygonIndexMap(p)
+	if len(p) != 2 {
+		t.Errorf("should remove empty ring")
+	}
+
+	if !reflect.DeepEqual(ims, [][]int{{0, 1, 2, 3}, {0, 2, 3, 4}}) {
+		t.Errorf("incorrect index maps: %v", ims)
+	}
+
+	mp := orb.MultiPolygon{
+		{{{0, 0}, {0, 0}}},
+		{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+	}
+
+	mp, mims := DouglasPeucker(0).MultiPolygonIndexMap(mp)
+	if len(mp) != 1 {
+		t.Errorf("should remove empty polygon")
+	}
+
+	if !reflect.DeepEqual(mims, [][][]int{{{0, 1, 2, 3}}}) {
+		t.Errorf("incorrect index maps: %v", mims)
+	}
+}
@@ -0,0 +1,139 @@
+package simplify
+
+import (
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/planar"
+)
+
+var _ orb.Simplifier = &LangSimplifier{}
+
+// A LangSimplifier wraps the Lang algorithm. From the current key point
+// it looks ahead a fixed number of points and shrinks the window until all
+// the intermediate points are within the threshold of the window's segment.
+type LangSimplifier struct {
+	Threshold float64 // euclidean distance
+	LookAhead int
+}
+
+// Lang creates a new LangSimplifier. The look ahead is the max number of
+// points that can be removed in one step, values less than 2 will look
+// ahead to the end of the line.
+func Lang(threshold float64, lookAhead int) *LangSimplifier {
+	return &LangSimplifier{
+		Threshold: threshold,
+		LookAhead: lookAhead,
+	}
+}
+
+func (s *LangSimplifier) simplify(ls orb.LineString, wim bool) (orb.LineString, []int) {
+	var indexMap []int
+	if wim {
+		indexMap = append(indexMap, 0)
+	}
+
+	lookAhead := s.LookAhead
+	if lookAhead < 2 {
+		lookAhead = len(ls)
+	}
+
+	threshold := s.Threshold * s.Threshold
+
+	count := 1
+	key := 0
+	for key < len(ls)-1 {
+		end := key + lookAhead
+		if end > len(ls)-1 {
+			end = len(ls) - 1
+		}
+
+		for end > key+1 && !withinThreshold(ls, key, end, threshold) {
+			end--
+		}
+
+		key = end
+		ls[count] = ls[key]
+		count++
+		if wim {
+			indexMap = append(indexMap, key)
+		}
+	}
+
+	return ls[:count], indexMap
+}
+
+// withinThreshold returns true if all the points between start and end
+// are within the squared threshold of the segment from start to end.
+func withinThreshold(ls orb.LineString, start, end int, threshold float64) bool {
+	for i := start + 1; i < end; i++ {
+		if planar.DistanceFromSegmentSquared(ls[start], ls[end], ls[i]) > threshold {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Simplify will run the simplification for any geometry type.
+func (s *LangSimplifier) Simplify(g orb.Geometry) orb.Geometry {
+	return simplify(s, g)
+}
+
+// LineString will simplify the linestring using this simplifier.
+func (s *LangSimplifier) LineString(ls orb.LineString) orb.LineString {
+	return lineString(s, ls)
+}
+
+// MultiLineString will simplify the multi-linestring using this simplifier.
+func (s *LangSimplifier) MultiLineString(mls orb.MultiLineString) orb.MultiLineString {
+	return multiLineString(s, mls)
+}
+
+// Ring will simplify the ring using this simplifier.
+func (s *LangSimplifier) Ring(r orb.Ring) orb.Ring {
+	return ring(s, r)
+}
+
+// Polygon will simplify the polygon using this simplifier.
+func (s *LangSimplifier) Polygon(p orb.Polygon) orb.Polygon {
+	return polygon(s, p)
+}
+
+// MultiPolygon will simplify the multi-polygon using this simplifier.
+func (s *LangSimplifier) MultiPolygon(mp orb.MultiPolygon) orb.MultiPolygon {
+	return multiPolygon(s, mp)
+}
+
+// Collection will simplify the collection using this simplifier.
+func (s *LangSimplifier) Collection(c orb.Collection) orb.Collection {
+	return collection(s, c)
+}
+
+// LineStringIndexMap will simplify the linestring using this simplifier
+// and return the indexes of the original points that remain.
+func (s *LangSimplifier) LineStringIndexMap(ls orb.LineString) (orb.LineString, []int) {
+	return lineStringIndexMap(s, ls)
+}
+
+// MultiLineStringIndexMap will simplify the multi-linestring using this simplifier
+// and return the indexes of the original points that remain for each linestring.
+func (s *LangSimplifier) MultiLineStringIndexMap(mls orb.MultiLineString) (orb.MultiLineString, [][]int) {
+	return multiLineStringIndexMap(s, mls)
+}
+
+// RingIndexMap will simplify the ring using this simplifier
+// and return the indexes of the original points that remain.
+func (s *LangSimplifier) RingIndexMap(r orb.Ring) (orb.Ring, []int) {
+	return ringIndexMap(s, r)
+}
+
+// PolygonIndexMap will simplify the polygon using this simplifier
+// and return the indexes of the original points that remain for each ring.
+func (s *LangSimplifier) PolygonIndexMap(p orb.Polygon) (orb.Polygon, [][]int) {
+	return polygonIndexMap(s, p)
+}
+
+// MultiPolygonIndexMap will simplify the multi-polygon using this simplifier
+// and return the indexes of the original points that remain for each ring.
+func (s *LangSimplifier) MultiPolygonIndexMap(mp orb.MultiPolygon) (orb.MultiPolygon, [][][]int) {
+	return multiPolygonIndexMap(s, mp)
+}
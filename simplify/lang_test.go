@@ -0,0 +1,69 @@
+package simplify
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestLang(t *testing.T) {
+	cases := []struct {
+		name      string
+		threshold float64
+		lookAhead int
+		ls        orb.LineString
+		expected  orb.LineString
+		indexMap  []int
+	}{
+		{
+			name:      "no reduction",
+			threshold: 0.1,
+			lookAhead: 4,
+			ls:        orb.LineString{{0, 0}, {1, 1}, {2, 0}},
+			expected:  orb.LineString{{0, 0}, {1, 1}, {2, 0}},
+			indexMap:  []int{0, 1, 2},
+		},
+		{
+			name:      "limited by look ahead",
+			threshold: 0.1,
+			lookAhead: 2,
+			ls:        orb.LineString{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {4, 0}, {5, 0}},
+			expected:  orb.LineString{{0, 0}, {2, 0}, {4, 0}, {5, 0}},
+			indexMap:  []int{0, 2, 4, 5},
+		},
+		{
+			name:      "unlimited look ahead",
+			threshold: 0.1,
+			lookAhead: 0,
+			ls:        orb.LineString{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {4, 0}, {5, 0}},
+			expected:  orb.LineString{{0, 0}, {5, 0}},
+			indexMap:  []int{0, 5},
+		},
+		{
+			name:      "reduction",
+			threshold: 0.5,
+			lookAhead: 4,
+			ls:        orb.LineString{{0, 0}, {1, 0.2}, {2, 0}, {3, 3}, {4, 6}, {5, 6}},
+			expected:  orb.LineString{{0, 0}, {2, 0}, {4, 6}, {5, 6}},
+			indexMap:  []int{0, 2, 4, 5},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, im := Lang(tc.threshold, tc.lookAhead).simplify(tc.ls, true)
+			if !v.Equal(tc.expected) {
+				t.Log(v)
+				t.Log(tc.expected)
+				t.Errorf("incorrect line")
+			}
+
+			if !reflect.DeepEqual(im, tc.indexMap) {
+				t.Log(im)
+				t.Log(tc.indexMap)
+				t.Errorf("incorrect index map")
+			}
+		})
+	}
+}
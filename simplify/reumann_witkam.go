@@ -0,0 +1,143 @@
+package simplify
+
+import (
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/planar"
+)
+
+var _ orb.Simplifier = &ReumannWitkamSimplifier{}
+
+// A ReumannWitkamSimplifier wraps the Reumann-Witkam algorithm.
+// It processes the line in a single pass and is suited for streaming data.
+type ReumannWitkamSimplifier struct {
+	Threshold float64 // euclidean distance
+}
+
+// ReumannWitkam creates a new ReumannWitkamSimplifier. Points within the
+// threshold of the infinite line through the current key point and its
+// successor are removed.
+func ReumannWitkam(threshold float64) *ReumannWitkamSimplifier {
+	return &ReumannWitkamSimplifier{
+		Threshold: threshold,
+	}
+}
+
+func (s *ReumannWitkamSimplifier) simplify(ls orb.LineString, wim bool) (orb.LineString, []int) {
+	var indexMap []int
+	if wim {
+		indexMap = append(indexMap, 0)
+	}
+
+	threshold := s.Threshold * s.Threshold
+
+	count := 1
+	key := 0
+	for key < len(ls)-2 {
+		a, b := ls[key], ls[key+1]
+
+		i := key + 2
+		for i < len(ls) && distanceFromLineSquared(a, b, ls[i]) <= threshold {
+			i++
+		}
+
+		if i == len(ls) {
+			break
+		}
+
+		// the point before the first one outside the strip is the next key.
+		key = i - 1
+		ls[count] = ls[key]
+		count++
+		if wim {
+			indexMap = append(indexMap, key)
+		}
+	}
+
+	ls[count] = ls[len(ls)-1]
+	count++
+	if wim {
+		indexMap = append(indexMap, len(ls)-1)
+	}
+
+	return ls[:count], indexMap
+}
+
+// distanceFromLineSquared returns the squared distance from the point
+// to the infinite line through a and b.
+func distanceFromLineSquared(a, b, p orb.Point) float64 {
+	dx := b[0] - a[0]
+	dy := b[1] - a[1]
+
+	l2 := dx*dx + dy*dy
+	if l2 == 0 {
+		return planar.DistanceSquared(a, p)
+	}
+
+	cross := dx*(p[1]-a[1]) - dy*(p[0]-a[0])
+	return cross * cross / l2
+}
+
+// Simplify will run the simplification for any geometry type.
+func (s *ReumannWitkamSimplifier) Simplify(g orb.Geometry) orb.Geometry {
+	return simplify(s, g)
+}
+
+// LineString will simplify the linestring using this simplifier.
+func (s *ReumannWitkamSimplifier) LineString(ls orb.LineString) orb.LineString {
+	return lineString(s, ls)
+}
+
+// MultiLineString will simplify the multi-linestring using this simplifier.
+func (s *ReumannWitkamSimplifier) MultiLineString(mls orb.MultiLineString) orb.MultiLineString {
+	return multiLineString(s, mls)
+}
+
+// Ring will simplify the ring using this simplifier.
+func (s *ReumannWitkamSimplifier) Ring(r orb.Ring) orb.Ring {
+	return ring(s, r)
+}
+
+// Polygon will simplify the polygon using this simplifier.
+func (s *ReumannWitkamSimplifier) Polygon(p orb.Polygon) orb.Polygon {
+	return polygon(s, p)
+}
+
+// MultiPolygon will simplify the multi-polygon using this simplifier.
+func (s *ReumannWitkamSimplifier) MultiPolygon(mp orb.MultiPolygon) orb.MultiPolygon {
+	return multiPolygon(s, mp)
+}
+
+// Collection will simplify the collection using this simplifier.
+func (s *ReumannWitkamSimplifier) Collection(c orb.Collection) orb.Collection {
+	return collection(s, c)
+}
+
+// LineStringIndexMap will simplify the linestring using this simplifier
+// and return the indexes of the original points that remain.
+func (s *ReumannWitkamSimplifier) LineStringIndexMap(ls orb.LineString) (orb.LineString, []int) {
+	return lineStringIndexMap(s, ls)
+}
+
+// MultiLineStringIndexMap will simplify the multi-linestring using this simplifier
+// and return the indexes of the original points that remain for each linestring.
+func (s *ReumannWitkamSimplifier) MultiLineStringIndexMap(mls orb.MultiLineString) (orb.MultiLineString, [][]int) {
+	return multiLineStringIndexMap(s, mls)
+}
+
+// RingIndexMap will simplify the ring using this simplifier
+// and return the indexes of the original points that remain.
+func (s *ReumannWitkamSimplifier) RingIndexMap(r orb.Ring) (orb.Ring, []int) {
+	return ringIndexMap(s, r)
+}
+
+// PolygonIndexMap will simplify the polygon using this simplifier
+// and return the indexes of the original points that remain for each ring.
+func (s *ReumannWitkamSimplifier) PolygonIndexMap(p orb.Polygon) (orb.Polygon, [][]int) {
+	return polygonIndexMap(s, p)
+}
+
+// MultiPolygonIndexMap will simplify the multi-polygon using this simplifier
+// and return the indexes of the original points that remain for each ring.
+func (s *ReumannWitkamSimplifier) MultiPolygonIndexMap(mp orb.MultiPolygon) (orb.MultiPolygon, [][][]int) {
+	return multiPolygonIndexMap(s, mp)
+}
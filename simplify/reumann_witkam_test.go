@@ -0,0 +1,57 @@
+package simplify
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestReumannWitkam(t *testing.T) {
+	cases := []struct {
+		name      string
+		threshold float64
+		ls        orb.LineString
+		expected  orb.LineString
+		indexMap  []int
+	}{
+		{
+			name:      "no reduction",
+			threshold: 0.1,
+			ls:        orb.LineString{{0, 0}, {1, 1}, {2, 0}},
+			expected:  orb.LineString{{0, 0}, {1, 1}, {2, 0}},
+			indexMap:  []int{0, 1, 2},
+		},
+		{
+			name:      "removes collinear points",
+			threshold: 0,
+			ls:        orb.LineString{{0, 0}, {1, 0}, {2, 0}, {3, 0}},
+			expected:  orb.LineString{{0, 0}, {3, 0}},
+			indexMap:  []int{0, 3},
+		},
+		{
+			name:      "reduction",
+			threshold: 0.5,
+			ls:        orb.LineString{{0, 0}, {1, 0}, {2, 0.2}, {3, 0}, {4, 2}, {5, 4}, {6, 4}},
+			expected:  orb.LineString{{0, 0}, {3, 0}, {5, 4}, {6, 4}},
+			indexMap:  []int{0, 3, 5, 6},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, im := ReumannWitkam(tc.threshold).simplify(tc.ls, true)
+			if !v.Equal(tc.expected) {
+				t.Log(v)
+				t.Log(tc.expected)
+				t.Errorf("incorrect line")
+			}
+
+			if !reflect.DeepEqual(im, tc.indexMap) {
+				t.Log(im)
+				t.Log(tc.indexMap)
+				t.Errorf("incorrect index map")
+			}
+		})
+	}
+}
@@ -36,8 +36,24 @@ func VisvalingamKeep(toKeep int) *VisvalingamSimplifier {
 }
 
 func (s *VisvalingamSimplifier) simplify(ls orb.LineString, wim bool) (orb.LineString, []int) {
+	area := func(i1, i2, i3 int) float64 {
+		return doubleTriangleArea(ls, i1, i2, i3)
+	}
+
+	return visvalingam(ls, area, s.Threshold, s.ToKeep, wim)
+}
+
+// visvalingam runs the algorithm using the area function to compute
+// the double area of the triangle defined by the point indexes.
+func visvalingam(
+	ls orb.LineString,
+	area func(i1, i2, i3 int) float64,
+	threshold float64,
+	toKeep int,
+	wim bool,
+) (orb.LineString, []int) {
 	var indexMap []int
-	if len(ls) <= s.ToKeep {
+	if len(ls) <= toKeep {
 		if wim {
 			// create identify map
 			indexMap = make([]int, len(ls))
@@ -49,7 +65,7 @@ func (s *VisvalingamSimplifier) simplify(ls orb.LineString, wim bool) (orb.LineS
 	}
 
 	// edge cases checked, get on with it
-	threshold := s.Threshold * 2 // triangle area is doubled to save the multiply :)
+	threshold *= 2 // triangle area is doubled to save the multiply :)
 	removed := 0
 
 	// build the initial minheap linked list.
@@ -68,7 +84,7 @@ func (s *VisvalingamSimplifier) simplify(ls orb.LineString, wim bool) (orb.LineS
 	for i := 1; i < len(ls)-1; i++ {
 		item := &items[i]
 
-		item.area = doubleTriangleArea(ls, i-1, i, i+1)
+		item.area = area(i-1, i, i+1)
 		item.pointIndex = i
 		item.previous = previous
 
@@ -89,7 +105,7 @@ func (s *VisvalingamSimplifier) simplify(ls orb.LineString, wim bool) (orb.LineS
 	// run through the reduction process
 	for len(heap) > 0 {
 		current := heap.Pop()
-		if current.area > threshold || len(ls)-removed <= s.ToKeep {
+		if current.area > threshold || len(ls)-removed <= toKeep {
 			break
 		}
 
@@ -103,25 +119,25 @@ func (s *VisvalingamSimplifier) simplify(ls orb.LineString, wim bool) (orb.LineS
 
 		// figure out the new areas
 		if previous.previous != nil {
-			area := doubleTriangleArea(ls,
+			a := area(
 				previous.previous.pointIndex,
 				previous.pointIndex,
 				next.pointIndex,
 			)
 
-			area = math.Max(area, current.area)
-			heap.Update(previous, area)
+			a = math.Max(a, current.area)
+			heap.Update(previous, a)
 		}
 
 		if next.next != nil {
-			area := doubleTriangleArea(ls,
+			a := area(
 				previous.pointIndex,
 				next.pointIndex,
 				next.next.pointIndex,
 			)
 
-			area = math.Max(area, current.area)
-			heap.Update(next, area)
+			a = math.Max(a, current.area)
+			heap.Update(next, a)
 		}
 	}
 
@@ -288,3 +304,33 @@ func (s *VisvalingamSimplifier) MultiPolygon(mp orb.MultiPolygon) orb.MultiPolyg
 func (s *VisvalingamSimplifier) Collection(c orb.Collection) orb.Collection {
 	return collection(s, c)
 }
+
+// LineStringIndexMap will simplify the linestring using this simplifier
+// and return the indexes of the original points that remain.
+func (s *VisvalingamSimplifier) LineStringIndexMap(ls orb.LineString) (orb.LineString, []int) {
+	return lineStringIndexMap(s, ls)
+}
+
+// MultiLineStringIndexMap will simplify the multi-linestring using this simplifier
+// and return the indexes of the original points that remain for each linestring.
+func (s *VisvalingamSimplifier) MultiLineStringIndexMap(mls orb.MultiLineString) (orb.MultiLineString, [][]int) {
+	return multiLineStringIndexMap(s, mls)
+}
+
+// RingIndexMap will simplify the ring using this simplifier
+// and return the indexes of the original points that remain.
+func (s *VisvalingamSimplifier) RingIndexMap(r orb.Ring) (orb.Ring, []int) {
+	return ringIndexMap(s, r)
+}
+
+// PolygonIndexMap will simplify the polygon using this simplifier
+// and return the indexes of the original points that remain for each ring.
+func (s *VisvalingamSimplifier) PolygonIndexMap(p orb.Polygon) (orb.Polygon, [][]int) {
+	return polygonIndexMap(s, p)
+}
+
+// MultiPolygonIndexMap will simplify the multi-polygon using this simplifier
+// and return the indexes of the original points that remain for each ring.
+func (s *VisvalingamSimplifier) MultiPolygonIndexMap(mp orb.MultiPolygon) (orb.MultiPolygon, [][][]int) {
+	return multiPolygonIndexMap(s, mp)
+}
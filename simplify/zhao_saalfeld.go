@@ -0,0 +1,165 @@
+package simplify
+
+import (
+	"math"
+
+	"github.com/dadadamarine/orb"
+)
+
+var _ orb.Simplifier = &ZhaoSaalfeldSimplifier{}
+
+// A ZhaoSaalfeldSimplifier wraps the Zhao-Saalfeld sleeve-fitting algorithm.
+// A sleeve of the threshold width is extended from the current key point for
+// as long as all the points fit. It processes the line in a single pass
+// and is suited for streaming data.
+type ZhaoSaalfeldSimplifier struct {
+	Threshold float64 // euclidean distance
+}
+
+// ZhaoSaalfeld creates a new ZhaoSaalfeldSimplifier. Removed points will be
+// within the threshold of the simplified line.
+func ZhaoSaalfeld(threshold float64) *ZhaoSaalfeldSimplifier {
+	return &ZhaoSaalfeldSimplifier{
+		Threshold: threshold,
+	}
+}
+
+func (s *ZhaoSaalfeldSimplifier) simplify(ls orb.LineString, wim bool) (orb.LineString, []int) {
+	var indexMap []int
+	if wim {
+		indexMap = append(indexMap, 0)
+	}
+
+	// The sector of directions from the key point that keep all the points
+	// within the sleeve. Angles are relative to the first constraining point.
+	var ref, lo, hi float64
+	constrained := false
+
+	count := 1
+	key := 0
+	for i := 1; i < len(ls); i++ {
+		dx := ls[i][0] - ls[key][0]
+		dy := ls[i][1] - ls[key][1]
+
+		d := math.Hypot(dx, dy)
+		if d <= s.Threshold {
+			// any direction will be within the threshold of this point.
+			continue
+		}
+
+		angle := math.Atan2(dy, dx)
+		delta := math.Asin(s.Threshold / d)
+
+		if !constrained {
+			ref, lo, hi = angle, -delta, delta
+			constrained = true
+			continue
+		}
+
+		rel := normalizeAngle(angle - ref)
+		if rel < lo || rel > hi {
+			// this point is outside the sleeve, the previous point is the new key.
+			key = i - 1
+			ls[count] = ls[key]
+			count++
+			if wim {
+				indexMap = append(indexMap, key)
+			}
+
+			constrained = false
+			i = key
+			continue
+		}
+
+		lo = math.Max(lo, rel-delta)
+		hi = math.Min(hi, rel+delta)
+	}
+
+	if key != len(ls)-1 {
+		ls[count] = ls[len(ls)-1]
+		count++
+		if wim {
+			indexMap = append(indexMap, len(ls)-1)
+		}
+	}
+
+	return ls[:count], indexMap
+}
+
+// normalizeAngle returns the angle in the range [-pi, pi].
+func normalizeAngle(a float64) float64 {
+	for a > math.Pi {
+		a -= 2 * math.Pi
+	}
+
+	for a < -math.Pi {
+		a += 2 * math.Pi
+	}
+
+	return a
+}
+
+// Simplify will run the simplification for any geometry type.
+func (s *ZhaoSaalfeldSimplifier) Simplify(g orb.Geometry) orb.Geometry {
+	return simplify(s, g)
+}
+
+// LineString will simplify the linestring using this simplifier.
+func (s *ZhaoSaalfeldSimplifier) LineString(ls orb.LineString) orb.LineString {
+	return lineString(s, ls)
+}
+
+// MultiLineString will simplify the multi-linestring using this simplifier.
+func (s *ZhaoSaalfeldSimplifier) MultiLineString(mls orb.MultiLineString) orb.MultiLineString {
+	return multiLineString(s, mls)
+}
+
+// Ring will simplify the ring using this simplifier.
+func (s *ZhaoSaalfeldSimplifier) Ring(r orb.Ring) orb.Ring {
+	return ring(s, r)
+}
+
+// Polygon will simplify the polygon using this simplifier.
+func (s *ZhaoSaalfeldSimplifier) Polygon(p orb.Polygon) orb.Polygon {
+	return polygon(s, p)
+}
+
+// MultiPolygon will simplify the multi-polygon using this simplifier.
+func (s *ZhaoSaalfeldSimplifier) MultiPolygon(mp orb.MultiPolygon) orb.MultiPolygon {
+	return multiPolygon(s, mp)
+}
+
+// Collection will simplify the collection using this simplifier.
+func (s *ZhaoSaalfeldSimplifier) Collection(c orb.Collection) orb.Collection {
+	return collection(s, c)
+}
+
+// LineStringIndexMap will simplify the linestring using this simplifier
+// and return the indexes of the original points that remain.
+func (s *ZhaoSaalfeldSimplifier) LineStringIndexMap(ls orb.LineString) (orb.LineString, []int) {
+	return lineStringIndexMap(s, ls)
+}
+
+// MultiLineStringIndexMap will simplify the multi-linestring using this simplifier
+// and return the indexes of the original points that remain for each linestring.
+func (s *ZhaoSaalfeldSimplifier) MultiLineStringIndexMap(mls orb.MultiLineString) (orb.MultiLineString, [][]int) {
+	return multiLineStringIndexMap(s, mls)
+}
+
+// RingIndexMap will simplify the ring using this simplifier
+// and return the indexes of the original points that remain.
+func (s *ZhaoSaalfeldSimplifier) RingIndexMap(r orb.Ring) (orb.Ring, []int) {
+	return ringIndexMap(s, r)
+}
+
+// PolygonIndexMap will simplify the polygon using this simplifier
+// and return the indexes of the original points that remain for each ring.
+func (s *ZhaoSaalfeldSimplifier) PolygonIndexMap(p orb.Polygon) (orb.Polygon, [][]int) {
+	return polygonIndexMap(s, p)
+}
+
+// MultiPolygonIndexMap will simplify the multi-polygon using this simplifier
+// and return the indexes of the original points that remain for each ring.
+func (s *ZhaoSaalfeldSimplifier) MultiPolygonIndexMap(mp orb.MultiPolygon) (orb.MultiPolygon, [][][]int) {
+	return multiPolygonIndexMap(s, mp)
+}
@@ -0,0 +1,85 @@
+package simplify
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/planar"
+)
+
+func TestZhaoSaalfeld(t *testing.T) {
+	cases := []struct {
+		name      string
+		threshold float64
+		ls        orb.LineString
+		expected  orb.LineString
+		indexMap  []int
+	}{
+		{
+			name:      "no reduction",
+			threshold: 0.1,
+			ls:        orb.LineString{{0, 0}, {1, 1}, {2, 0}},
+			expected:  orb.LineString{{0, 0}, {1, 1}, {2, 0}},
+			indexMap:  []int{0, 1, 2},
+		},
+		{
+			name:      "straight line",
+			threshold: 0.1,
+			ls:        orb.LineString{{0, 0}, {1, 0}, {2, 0.05}, {3, 0}, {4, 0}},
+			expected:  orb.LineString{{0, 0}, {4, 0}},
+			indexMap:  []int{0, 4},
+		},
+		{
+			name:      "corner",
+			threshold: 0.1,
+			ls:        orb.LineString{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {2, 2}},
+			expected:  orb.LineString{{0, 0}, {2, 0}, {2, 2}},
+			indexMap:  []int{0, 2, 4},
+		},
+		{
+			name:      "close points",
+			threshold: 1,
+			ls:        orb.LineString{{0, 0}, {0.1, 0.5}, {0.2, -0.5}, {5, 0}},
+			expected:  orb.LineString{{0, 0}, {5, 0}},
+			indexMap:  []int{0, 3},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, im := ZhaoSaalfeld(tc.threshold).simplify(tc.ls, true)
+			if !v.Equal(tc.expected) {
+				t.Log(v)
+				t.Log(tc.expected)
+				t.Errorf("incorrect line")
+			}
+
+			if !reflect.DeepEqual(im, tc.indexMap) {
+				t.Log(im)
+				t.Log(tc.indexMap)
+				t.Errorf("incorrect index map")
+			}
+		})
+	}
+}
+
+func TestZhaoSaalfeld_BenchmarkData(t *testing.T) {
+	ls := benchmarkData()
+	threshold := 2.0
+
+	r, im := ZhaoSaalfeld(threshold).simplify(ls.Clone(), true)
+	if len(r) >= len(ls) {
+		t.Errorf("should reduce the line")
+	}
+
+	// all removed points should be within the threshold of the result.
+	for k := 0; k < len(im)-1; k++ {
+		for i := im[k] + 1; i < im[k+1]; i++ {
+			d := planar.DistanceFromSegment(r[k], r[k+1], ls[i])
+			if d > threshold+1e-9 {
+				t.Fatalf("point %d is %v from the line", i, d)
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package triangulate
+
+import (
+	"math"
+
+	"github.com/dadadamarine/orb"
+)
+
+// ConstrainedDelaunay triangulates the polygon, with holes, so the triangles are
+// as close to Delaunay as possible while keeping the edges of the rings. This
+// avoids most of the long thin triangles created by ear clipping.
+func ConstrainedDelaunay(p orb.Polygon) *Triangulation {
+	t := &Triangulation{}
+	t.addPolygon(p, constrainedDelaunay)
+	return t
+}
+
+// ConstrainedDelaunayMultiPolygon triangulates each of the polygons using
+// a constrained Delaunay triangulation. The triangulation points are the
+// flattened points of all the polygons.
+func ConstrainedDelaunayMultiPolygon(mp orb.MultiPolygon) *Triangulation {
+	t := &Triangulation{}
+	for _, p := range mp {
+		t.addPolygon(p, constrainedDelaunay)
+	}
+
+	return t
+}
+
+// constrainedDelaunay starts with the ear clipping triangulation, which
+// contains all the ring edges, and flips the interior edges until
+// the triangulation is locally Delaunay.
+func constrainedDelaunay(points []orb.Point, holes []int) []int {
+	triangles := earcut(points, holes)
+	flip(points, triangles)
+	return triangles
+}
+
+// flip runs Lawson's flip algorithm, the ring edges are constrained since
+// they only have one triangle. The triangles are updated in place.
+func flip(points []orb.Point, triangles []int) {
+	edges := make(map[[2]int]int, len(triangles))
+	skip := make(map[[2]int]bool)
+
+	stack := make([][2]int, 0, len(triangles))
+	for i := range triangles {
+		t := i / 3
+		e := [2]int{triangles[i], triangles[3*t+(i+1)%3]}
+
+		if _, ok := edges[e]; ok || e[0] == e[1] {
+			// degenerate triangles, the edge can not be flipped
+			skip[e] = true
+			skip[[2]int{e[1], e[0]}] = true
+		}
+
+		edges[e] = t
+		stack = append(stack, e)
+	}
+
+	// to protect against flipping forever due to round off errors
+	limit := len(triangles)*len(triangles) + 100
+	for len(stack) > 0 && limit > 0 {
+		limit--
+
+		e := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		a, b := e[0], e[1]
+		if skip[e] {
+			continue
+		}
+
+		t1, ok1 := edges[[2]int{a, b}]
+		t2, ok2 := edges[[2]int{b, a}]
+		if !ok1 || !ok2 {
+			continue // a ring edge
+		}
+
+		c := third(triangles, t1, a, b)
+		d := third(triangles, t2, a, b)
+		if !inCircle(points[a], points[b], points[c], points[d]) {
+			continue
+		}
+
+		// the new triangles must be valid, the quadrilateral convex.
+		if orient(points[a], points[d], points[c]) <= 0 || orient(points[d], points[b], points[c]) <= 0 {
+			continue
+		}
+
+		triangles[3*t1], triangles[3*t1+1], triangles[3*t1+2] = a, d, c
+		triangles[3*t2], triangles[3*t2+1], triangles[3*t2+2] = d, b, c
+
+		delete(edges, [2]int{a, b})
+		delete(edges, [2]int{b, a})
+
+		edges[[2]int{a, d}] = t1
+		edges[[2]int{d, c}] = t1
+		edges[[2]int{c, a}] = t1
+		edges[[2]int{d, b}] = t2
+		edges[[2]int{b, c}] = t2
+		edges[[2]int{c, d}] = t2
+
+		stack = append(stack, [2]int{a, d}, [2]int{d, b}, [2]int{b, c}, [2]int{c, a})
+	}
+}
+
+// third returns the vertex of the triangle that is not a or b.
+func third(triangles []int, t, a, b int) int {
+	for _, v := range triangles[3*t : 3*t+3] {
+		if v != a && v != b {
+			return v
+		}
+	}
+
+	return -1
+}
+
+// orient is twice the signed area of the triangle, positive if counter-clockwise.
+func orient(a, b, c orb.Point) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
+
+// inCircle returns true if d is strictly inside the circumcircle of the
+// counter-clockwise triangle abc. The tolerance avoids flipping back and
+// forth for cocircular points.
+func inCircle(a, b, c, d orb.Point) bool {
+	adx, ady := a[0]-d[0], a[1]-d[1]
+	bdx, bdy := b[0]-d[0], b[1]-d[1]
+	cdx, cdy := c[0]-d[0], c[1]-d[1]
+
+	alift := adx*adx + ady*ady
+	blift := bdx*bdx + bdy*bdy
+	clift := cdx*cdx + cdy*cdy
+
+	det := alift*(bdx*cdy-cdx*bdy) -
+		blift*(adx*cdy-cdx*ady) +
+		clift*(adx*bdy-bdx*ady)
+
+	permanent := alift*(math.Abs(bdx*cdy)+math.Abs(cdx*bdy)) +
+		blift*(math.Abs(adx*cdy)+math.Abs(cdx*ady)) +
+		clift*(math.Abs(adx*bdy)+math.Abs(bdx*ady))
+
+	return det > 1e-12*permanent
+}
@@ -0,0 +1,98 @@
+package triangulate
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+)
+
+func TestConstrainedDelaunay(t *testing.T) {
+	// a long thin polygon where ear clipping creates a fan of
+	// thin triangles from the first point.
+	var ring orb.Ring
+	for i := 0; i <= 10; i++ {
+		ring = append(ring, orb.Point{float64(i), 0})
+	}
+	for i := 10; i >= 0; i-- {
+		ring = append(ring, orb.Point{float64(i), 1 + 0.01*float64(i%2)})
+	}
+	ring = append(ring, ring[0])
+
+	p := orb.Polygon{ring}
+
+	tri := ConstrainedDelaunay(p)
+	checkTriangulation(t, p, tri)
+	checkDelaunay(t, tri)
+
+	earcut := Earcut(p)
+	if minAngle(tri) <= minAngle(earcut) {
+		t.Errorf("should have better shaped triangles: %v <= %v", minAngle(tri), minAngle(earcut))
+	}
+}
+
+func TestConstrainedDelaunay_data(t *testing.T) {
+	files := []string{"donut", "russia", "spiked", "uk"}
+
+	for _, f := range files {
+		t.Run(f, func(t *testing.T) {
+			g := loadGeometry(t, "testdata/"+f+".geojson")
+
+			var tri *Triangulation
+			switch g := g.(type) {
+			case orb.Polygon:
+				tri = ConstrainedDelaunay(g)
+			case orb.MultiPolygon:
+				tri = ConstrainedDelaunayMultiPolygon(g)
+			}
+
+			checkTriangulation(t, g, tri)
+			checkDelaunay(t, tri)
+		})
+	}
+}
+
+// checkDelaunay checks that no interior edge can be flipped.
+func checkDelaunay(t testing.TB, tri *Triangulation) {
+	t.Helper()
+
+	edges := make(map[[2]int]int)
+	for i := range tri.Triangles {
+		tr := i / 3
+		edges[[2]int{tri.Triangles[i], tri.Triangles[3*tr+(i+1)%3]}] = tr
+	}
+
+	for e, t1 := range edges {
+		t2, ok := edges[[2]int{e[1], e[0]}]
+		if !ok {
+			continue
+		}
+
+		a, b := e[0], e[1]
+		c := third(tri.Triangles, t1, a, b)
+		d := third(tri.Triangles, t2, a, b)
+
+		pa, pb, pc, pd := tri.Points[a], tri.Points[b], tri.Points[c], tri.Points[d]
+		if inCircle(pa, pb, pc, pd) && orient(pa, pd, pc) > 0 && orient(pd, pb, pc) > 0 {
+			t.Errorf("edge %v should be flipped", e)
+		}
+	}
+}
+
+// minAngle returns the smallest angle, in radians, of all the triangles.
+func minAngle(tri *Triangulation) float64 {
+	min := math.Inf(1)
+	for _, p := range tri.MultiPolygon() {
+		r := p[0]
+		for i := 0; i < 3; i++ {
+			a, b, c := r[(i+2)%3], r[i], r[(i+1)%3]
+			v1 := orb.Point{a[0] - b[0], a[1] - b[1]}
+			v2 := orb.Point{c[0] - b[0], c[1] - b[1]}
+
+			angle := math.Abs(math.Atan2(v1[0]*v2[1]-v1[1]*v2[0], v1[0]*v2[0]+v1[1]*v2[1]))
+			min = math.Min(min, angle)
+		}
+	}
+
+	return min
+}
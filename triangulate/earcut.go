@@ -0,0 +1,558 @@
+package triangulate
+
+import (
+	"math"
+	"sort"
+
+	"github.com/dadadamarine/orb"
+)
+
+// The ear clipping below is a port of the mapbox earcut library,
+// https://github.com/mapbox/earcut, without the z-order hashing.
+// It is distributed under the original license:
+//
+// ISC License
+//
+// Copyright (c) 2016, Mapbox
+//
+// Permission to use, copy, modify, and/or distribute this software for any purpose
+// with or without fee is hereby granted, provided that the above copyright notice
+// and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY AND
+// FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR OTHER
+// TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR PERFORMANCE OF
+// THIS SOFTWARE.
+
+// Earcut triangulates the polygon, with holes, using ear clipping.
+// This is fast but can create long thin triangles.
+func Earcut(p orb.Polygon) *Triangulation {
+	t := &Triangulation{}
+	t.addPolygon(p, earcut)
+	return t
+}
+
+// EarcutMultiPolygon triangulates each polygon using ear clipping.
+// The triangulation points are the flattened points of all the polygons.
+func EarcutMultiPolygon(mp orb.MultiPolygon) *Triangulation {
+	t := &Triangulation{}
+	for _, p := range mp {
+		t.addPolygon(p, earcut)
+	}
+
+	return t
+}
+
+type node struct {
+	i          int // the index of the point in the triangulation
+	x, y       float64
+	prev, next *node
+	steiner    bool
+}
+
+// earcut triangulates the points, holes are the indexes of the start
+// of each hole ring. Returns the triangle indexes relative to the points.
+func earcut(points []orb.Point, holes []int) []int {
+	outerLen := len(points)
+	if len(holes) > 0 {
+		outerLen = holes[0]
+	}
+
+	var triangles []int
+	outer := linkedList(points, 0, outerLen, true)
+	if outer == nil || outer.next == outer.prev {
+		return triangles
+	}
+
+	if len(holes) > 0 {
+		outer = eliminateHoles(points, holes, outer)
+	}
+
+	return earcutLinked(outer, triangles, 0)
+}
+
+// linkedList creates a circular doubly linked list from the points
+// in the specified winding order.
+func linkedList(points []orb.Point, start, end int, clockwise bool) *node {
+	var last *node
+	if clockwise == (signedArea(points, start, end) > 0) {
+		for i := start; i < end; i++ {
+			last = insertNode(i, points[i], last)
+		}
+	} else {
+		for i := end - 1; i >= start; i-- {
+			last = insertNode(i, points[i], last)
+		}
+	}
+
+	if last != nil && equals(last, last.next) {
+		removeNode(last)
+		last = last.next
+	}
+
+	return last
+}
+
+// filterPoints eliminates colinear or duplicate points.
+func filterPoints(start, end *node) *node {
+	if start == nil {
+		return start
+	}
+
+	if end == nil {
+		end = start
+	}
+
+	p := start
+	for {
+		again := false
+		if !p.steiner && (equals(p, p.next) || area(p.prev, p, p.next) == 0) {
+			removeNode(p)
+			p = p.prev
+			end = p
+			if p == p.next {
+				break
+			}
+			again = true
+		} else {
+			p = p.next
+		}
+
+		if !again && p == end {
+			break
+		}
+	}
+
+	return end
+}
+
+// earcutLinked is the main ear slicing loop which triangulates a polygon
+// given as a linked list.
+func earcutLinked(ear *node, triangles []int, pass int) []int {
+	if ear == nil {
+		return triangles
+	}
+
+	stop := ear
+	for ear.prev != ear.next {
+		prev, next := ear.prev, ear.next
+
+		if isEar(ear) {
+			triangles = append(triangles, prev.i, ear.i, next.i)
+			removeNode(ear)
+
+			// skipping the next vertex leads to less sliver triangles
+			ear = next.next
+			stop = next.next
+			continue
+		}
+
+		ear = next
+
+		// if we looped through the whole remaining polygon and can't find any more ears
+		if ear == stop {
+			switch pass {
+			case 0:
+				// try filtering points and slicing again
+				triangles = earcutLinked(filterPoints(ear, nil), triangles, 1)
+			case 1:
+				// if this didn't work, try curing all small self-intersections locally
+				ear, triangles = cureLocalIntersections(filterPoints(ear, nil), triangles)
+				triangles = earcutLinked(ear, triangles, 2)
+			case 2:
+				// as a last resort, try splitting the remaining polygon into two
+				triangles = splitEarcut(ear, triangles)
+			}
+
+			return triangles
+		}
+	}
+
+	return triangles
+}
+
+// isEar checks whether a polygon node forms a valid ear with adjacent nodes.
+func isEar(ear *node) bool {
+	a, b, c := ear.prev, ear, ear.next
+	if area(a, b, c) >= 0 {
+		return false // reflex, can't be an ear
+	}
+
+	x0, x1 := math.Min(a.x, math.Min(b.x, c.x)), math.Max(a.x, math.Max(b.x, c.x))
+	y0, y1 := math.Min(a.y, math.Min(b.y, c.y)), math.Max(a.y, math.Max(b.y, c.y))
+
+	// now make sure we don't have other points inside the potential ear
+	for p := c.next; p != a; p = p.next {
+		if p.x >= x0 && p.x <= x1 && p.y >= y0 && p.y <= y1 &&
+			pointInTriangle(a.x, a.y, b.x, b.y, c.x, c.y, p.x, p.y) &&
+			area(p.prev, p, p.next) >= 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// cureLocalIntersections goes through all polygon nodes and cures small local self-intersections.
+func cureLocalIntersections(start *node, triangles []int) (*node, []int) {
+	p := start
+	for {
+		a, b := p.prev, p.next.next
+
+		if !equals(a, b) && intersects(a, p, p.next, b) && locallyInside(a, b) && locallyInside(b, a) {
+			triangles = append(triangles, a.i, p.i, b.i)
+
+			// remove two nodes involved
+			removeNode(p)
+			removeNode(p.next)
+
+			p = b
+			start = b
+		}
+
+		p = p.next
+		if p == start {
+			break
+		}
+	}
+
+	return filterPoints(p, nil), triangles
+}
+
+// splitEarcut tries to split the polygon into two and triangulate them independently.
+func splitEarcut(start *node, triangles []int) []int {
+	// look for a valid diagonal that divides the polygon into two
+	a := start
+	for {
+		for b := a.next.next; b != a.prev; b = b.next {
+			if a.i != b.i && isValidDiagonal(a, b) {
+				// split the polygon in two by the diagonal
+				c := splitPolygon(a, b)
+
+				// filter colinear points around the cuts
+				a = filterPoints(a, a.next)
+				c = filterPoints(c, c.next)
+
+				// run earcut on each half
+				triangles = earcutLinked(a, triangles, 0)
+				return earcutLinked(c, triangles, 0)
+			}
+		}
+
+		a = a.next
+		if a == start {
+			return triangles
+		}
+	}
+}
+
+// eliminateHoles links every hole into the outer loop, producing a
+// single-ring polygon without holes.
+func eliminateHoles(points []orb.Point, holes []int, outer *node) *node {
+	queue := make([]*node, 0, len(holes))
+	for i, start := range holes {
+		end := len(points)
+		if i < len(holes)-1 {
+			end = holes[i+1]
+		}
+
+		list := linkedList(points, start, end, false)
+		if list == nil {
+			continue
+		}
+
+		if list == list.next {
+			list.steiner = true
+		}
+		queue = append(queue, getLeftmost(list))
+	}
+
+	sort.SliceStable(queue, func(i, j int) bool {
+		return queue[i].x < queue[j].x
+	})
+
+	// process holes from left to right
+	for _, h := range queue {
+		outer = eliminateHole(h, outer)
+	}
+
+	return outer
+}
+
+// eliminateHole finds a bridge between vertices that connects the
+// hole with the outer ring and links it.
+func eliminateHole(hole, outer *node) *node {
+	bridge := findHoleBridge(hole, outer)
+	if bridge == nil {
+		return outer
+	}
+
+	bridgeReverse := splitPolygon(bridge, hole)
+
+	// filter collinear points around the cuts
+	filterPoints(bridgeReverse, bridgeReverse.next)
+	return filterPoints(bridge, bridge.next)
+}
+
+// findHoleBridge uses David Eberly's algorithm for finding a bridge
+// between the hole and the outer polygon.
+func findHoleBridge(hole, outer *node) *node {
+	p := outer
+	hx, hy := hole.x, hole.y
+	qx := math.Inf(-1)
+	var m *node
+
+	// find a segment intersected by a ray from the hole's leftmost point to the left;
+	// segment's endpoint with lesser x will be potential connection point
+	for {
+		if hy <= p.y && hy >= p.next.y && p.next.y != p.y {
+			x := p.x + (hy-p.y)*(p.next.x-p.x)/(p.next.y-p.y)
+			if x <= hx && x > qx {
+				qx = x
+				m = p
+				if p.x >= p.next.x {
+					m = p.next
+				}
+
+				if x == hx {
+					return m // hole touches outer segment, pick leftmost endpoint
+				}
+			}
+		}
+
+		p = p.next
+		if p == outer {
+			break
+		}
+	}
+
+	if m == nil {
+		return nil
+	}
+
+	// look for points inside the triangle of hole point, segment intersection and endpoint;
+	// if there are no points found, we have a valid connection;
+	// otherwise choose the point of the minimum angle with the ray as connection point
+	stop := m
+	mx, my := m.x, m.y
+	tanMin := math.Inf(1)
+
+	p = m
+	for {
+		ax, cx := qx, hx
+		if hy < my {
+			ax, cx = hx, qx
+		}
+
+		if hx >= p.x && p.x >= mx && hx != p.x &&
+			pointInTriangle(ax, hy, mx, my, cx, hy, p.x, p.y) {
+
+			tan := math.Abs(hy-p.y) / (hx - p.x) // tangential
+			if locallyInside(p, hole) &&
+				(tan < tanMin || (tan == tanMin && (p.x > m.x || (p.x == m.x && sectorContainsSector(m, p))))) {
+				m = p
+				tanMin = tan
+			}
+		}
+
+		p = p.next
+		if p == stop {
+			break
+		}
+	}
+
+	return m
+}
+
+// sectorContainsSector checks whether sector defined by m is in sector defined by p.
+func sectorContainsSector(m, p *node) bool {
+	return area(m.prev, m, p.prev) < 0 && area(p.next, m, m.next) < 0
+}
+
+// getLeftmost finds the leftmost node of a polygon ring.
+func getLeftmost(start *node) *node {
+	p, leftmost := start, start
+	for {
+		if p.x < leftmost.x || (p.x == leftmost.x && p.y < leftmost.y) {
+			leftmost = p
+		}
+
+		p = p.next
+		if p == start {
+			return leftmost
+		}
+	}
+}
+
+// pointInTriangle checks if a point lies within a convex triangle.
+func pointInTriangle(ax, ay, bx, by, cx, cy, px, py float64) bool {
+	return (cx-px)*(ay-py) >= (ax-px)*(cy-py) &&
+		(ax-px)*(by-py) >= (bx-px)*(ay-py) &&
+		(bx-px)*(cy-py) >= (cx-px)*(by-py)
+}
+
+// isValidDiagonal checks if a diagonal between two polygon nodes is valid,
+// lies in polygon interior.
+func isValidDiagonal(a, b *node) bool {
+	return a.next.i != b.i && a.prev.i != b.i && !intersectsPolygon(a, b) && // doesn't intersect other edges
+		(locallyInside(a, b) && locallyInside(b, a) && middleInside(a, b) && // locally visible
+			(area(a.prev, a, b.prev) != 0 || area(a, b.prev, b) != 0) || // does not create opposite-facing sectors
+			equals(a, b) && area(a.prev, a, a.next) > 0 && area(b.prev, b, b.next) > 0) // special zero-length case
+}
+
+// area is the signed area of a triangle.
+func area(p, q, r *node) float64 {
+	return (q.y-p.y)*(r.x-q.x) - (q.x-p.x)*(r.y-q.y)
+}
+
+func equals(p1, p2 *node) bool {
+	return p1.x == p2.x && p1.y == p2.y
+}
+
+// intersects checks if two segments intersect.
+func intersects(p1, q1, p2, q2 *node) bool {
+	o1 := sign(area(p1, q1, p2))
+	o2 := sign(area(p1, q1, q2))
+	o3 := sign(area(p2, q2, p1))
+	o4 := sign(area(p2, q2, q1))
+
+	if o1 != o2 && o3 != o4 {
+		return true // general case
+	}
+
+	if o1 == 0 && onSegment(p1, p2, q1) {
+		return true // p1, q1 and p2 are collinear and p2 lies on p1q1
+	}
+
+	if o2 == 0 && onSegment(p1, q2, q1) {
+		return true // p1, q1 and q2 are collinear and q2 lies on p1q1
+	}
+
+	if o3 == 0 && onSegment(p2, p1, q2) {
+		return true // p2, q2 and p1 are collinear and p1 lies on p2q2
+	}
+
+	if o4 == 0 && onSegment(p2, q1, q2) {
+		return true // p2, q2 and q1 are collinear and q1 lies on p2q2
+	}
+
+	return false
+}
+
+// onSegment checks if the point q lies on segment pr, for collinear points.
+func onSegment(p, q, r *node) bool {
+	return q.x <= math.Max(p.x, r.x) && q.x >= math.Min(p.x, r.x) &&
+		q.y <= math.Max(p.y, r.y) && q.y >= math.Min(p.y, r.y)
+}
+
+func sign(v float64) int {
+	if v > 0 {
+		return 1
+	} else if v < 0 {
+		return -1
+	}
+
+	return 0
+}
+
+// intersectsPolygon checks if a polygon diagonal intersects any polygon segments.
+func intersectsPolygon(a, b *node) bool {
+	p := a
+	for {
+		if p.i != a.i && p.next.i != a.i && p.i != b.i && p.next.i != b.i &&
+			intersects(p, p.next, a, b) {
+			return true
+		}
+
+		p = p.next
+		if p == a {
+			return false
+		}
+	}
+}
+
+// locallyInside checks if a polygon diagonal is locally inside the polygon.
+func locallyInside(a, b *node) bool {
+	if area(a.prev, a, a.next) < 0 {
+		return area(a, b, a.next) >= 0 && area(a, a.prev, b) >= 0
+	}
+
+	return area(a, b, a.prev) < 0 || area(a, a.next, b) < 0
+}
+
+// middleInside checks if the middle point of a polygon diagonal is inside the polygon.
+func middleInside(a, b *node) bool {
+	p := a
+	inside := false
+	px, py := (a.x+b.x)/2, (a.y+b.y)/2
+	for {
+		if ((p.y > py) != (p.next.y > py)) && p.next.y != p.y &&
+			(px < (p.next.x-p.x)*(py-p.y)/(p.next.y-p.y)+p.x) {
+			inside = !inside
+		}
+
+		p = p.next
+		if p == a {
+			return inside
+		}
+	}
+}
+
+// splitPolygon links two polygon vertices with a bridge; if the vertices belong
+// to the same ring, it splits polygon into two; if one belongs to the outer ring
+// and another to a hole, it merges it into a single ring.
+func splitPolygon(a, b *node) *node {
+	a2 := &node{i: a.i, x: a.x, y: a.y}
+	b2 := &node{i: b.i, x: b.x, y: b.y}
+	an, bp := a.next, b.prev
+
+	a.next = b
+	b.prev = a
+
+	a2.next = an
+	an.prev = a2
+
+	b2.next = a2
+	a2.prev = b2
+
+	bp.next = b2
+	b2.prev = bp
+
+	return b2
+}
+
+// insertNode creates a node and optionally links it with the previous one
+// in a circular doubly linked list.
+func insertNode(i int, p orb.Point, last *node) *node {
+	n := &node{i: i, x: p[0], y: p[1]}
+
+	if last == nil {
+		n.prev = n
+		n.next = n
+	} else {
+		n.next = last.next
+		n.prev = last
+		last.next.prev = n
+		last.next = n
+	}
+
+	return n
+}
+
+func removeNode(p *node) {
+	p.next.prev = p.prev
+	p.prev.next = p.next
+}
+
+func signedArea(points []orb.Point, start, end int) float64 {
+	sum := 0.0
+	for i, j := start, end-1; i < end; i++ {
+		sum += (points[j][0] - points[i][0]) * (points[i][1] + points[j][1])
+		j = i
+	}
+
+	return sum
+}
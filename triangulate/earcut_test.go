@@ -0,0 +1,186 @@
+package triangulate
+
+import (
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/geojson"
+	"github.com/dadadamarine/orb/planar"
+)
+
+func TestEarcut(t *testing.T) {
+	cases := []struct {
+		name      string
+		polygon   orb.Polygon
+		triangles int
+	}{
+		{
+			name:      "square",
+			polygon:   orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+			triangles: 2,
+		},
+		{
+			name:      "not closed clockwise",
+			polygon:   orb.Polygon{{{0, 0}, {0, 1}, {1, 1}, {1, 0}}},
+			triangles: 2,
+		},
+		{
+			name:      "concave",
+			polygon:   orb.Polygon{{{0, 0}, {4, 0}, {4, 4}, {2, 1}, {0, 4}, {0, 0}}},
+			triangles: 3,
+		},
+		{
+			name: "hole",
+			polygon: orb.Polygon{
+				{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+				{{1, 1}, {1, 3}, {3, 3}, {3, 1}, {1, 1}},
+			},
+			triangles: 8,
+		},
+		{
+			name:      "collinear points",
+			polygon:   orb.Polygon{{{0, 0}, {1, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+			triangles: 3,
+		},
+		{
+			name:      "degenerate",
+			polygon:   orb.Polygon{{{0, 0}, {1, 1}, {0, 0}}},
+			triangles: 0,
+		},
+		{
+			name:      "empty",
+			polygon:   orb.Polygon{},
+			triangles: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, tri := range []*Triangulation{Earcut(tc.polygon), ConstrainedDelaunay(tc.polygon)} {
+				if l := len(tri.Triangles) / 3; l != tc.triangles {
+					t.Errorf("incorrect number of triangles: %d != %d", l, tc.triangles)
+				}
+
+				checkTriangulation(t, tc.polygon, tri)
+			}
+		})
+	}
+}
+
+func TestEarcut_points(t *testing.T) {
+	p := orb.Polygon{
+		{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+		{{1, 1}, {1, 3}, {3, 3}, {3, 1}, {1, 1}},
+	}
+
+	tri := Earcut(p)
+	expected := []orb.Point{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {1, 1}, {1, 3}, {3, 3}, {3, 1}}
+	if !orb.MultiPoint(tri.Points).Equal(orb.MultiPoint(expected)) {
+		t.Errorf("incorrect points: %v", tri.Points)
+	}
+}
+
+func TestEarcutMultiPolygon(t *testing.T) {
+	mp := orb.MultiPolygon{
+		{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+		{{{2, 0}, {3, 0}, {3, 1}, {2, 0}}},
+	}
+
+	for _, tri := range []*Triangulation{EarcutMultiPolygon(mp), ConstrainedDelaunayMultiPolygon(mp)} {
+		if l := len(tri.Triangles) / 3; l != 3 {
+			t.Errorf("incorrect number of triangles: %d", l)
+		}
+
+		if l := len(tri.Points); l != 7 {
+			t.Errorf("incorrect number of points: %d", l)
+		}
+
+		checkTriangulation(t, mp, tri)
+	}
+}
+
+func TestEarcut_data(t *testing.T) {
+	files := []string{"donut", "russia", "spiked", "uk"}
+
+	for _, f := range files {
+		t.Run(f, func(t *testing.T) {
+			g := loadGeometry(t, "testdata/"+f+".geojson")
+
+			var tri *Triangulation
+			switch g := g.(type) {
+			case orb.Polygon:
+				tri = Earcut(g)
+			case orb.MultiPolygon:
+				tri = EarcutMultiPolygon(g)
+			}
+
+			checkTriangulation(t, g, tri)
+		})
+	}
+}
+
+// checkTriangulation checks the triangles are counter-clockwise
+// and have the same area as the polygon.
+func checkTriangulation(t testing.TB, g orb.Geometry, tri *Triangulation) {
+	t.Helper()
+
+	sum := 0.0
+	for _, p := range tri.MultiPolygon() {
+		a := planar.Area(p)
+		if a < 0 {
+			t.Errorf("triangle should be counter-clockwise: %v", p)
+		}
+
+		sum += a
+	}
+
+	expected := 0.0
+	switch g := g.(type) {
+	case orb.Polygon:
+		expected = polygonArea(g)
+	case orb.MultiPolygon:
+		for _, p := range g {
+			expected += polygonArea(p)
+		}
+	}
+
+	if math.Abs(sum-expected) > 1e-9*math.Max(1, expected) {
+		t.Errorf("incorrect area: %v != %v", sum, expected)
+	}
+}
+
+func polygonArea(p orb.Polygon) float64 {
+	sum := 0.0
+	for i, r := range p {
+		a := math.Abs(planar.Area(r))
+		if i > 0 {
+			a = -a
+		}
+		sum += a
+	}
+
+	return sum
+}
+
+func loadGeometry(t testing.TB, path string) orb.Geometry {
+	t.Helper()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read file: %v", err)
+	}
+
+	f, err := geojson.UnmarshalFeature(data)
+	if err == nil {
+		return f.Geometry
+	}
+
+	g, err := geojson.UnmarshalGeometry(data)
+	if err != nil {
+		t.Fatalf("unable to unmarshal: %v", err)
+	}
+
+	return g.Geometry()
+}
@@ -0,0 +1,446 @@
+package triangulate
+
+import (
+	"math"
+	"sort"
+
+	"github.com/dadadamarine/orb"
+)
+
+// The sweep-hull Delaunay triangulation below is a port of the mapbox
+// delaunator library, https://github.com/mapbox/delaunator.
+// It is distributed under the original license:
+//
+// ISC License
+//
+// Copyright (c) 2017, Mapbox
+//
+// Permission to use, copy, modify, and/or distribute this software for any purpose
+// with or without fee is hereby granted, provided that the above copyright notice
+// and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY AND
+// FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR OTHER
+// TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR PERFORMANCE OF
+// THIS SOFTWARE.
+
+// Delaunay computes the Delaunay triangulation of the points. The triangulation
+// points are a copy of the input, duplicate points are not used in any triangle.
+// If all the points are collinear there will be no triangles.
+func Delaunay(points orb.MultiPoint) *Triangulation {
+	t := &Triangulation{
+		Points: append([]orb.Point(nil), points...),
+	}
+
+	d := &delaunator{points: t.Points}
+	d.triangulate()
+
+	// delaunator triangles are clockwise, so reverse them
+	t.Triangles = d.triangles
+	for i := 0; i < len(t.Triangles); i += 3 {
+		t.Triangles[i+1], t.Triangles[i+2] = t.Triangles[i+2], t.Triangles[i+1]
+	}
+
+	return t
+}
+
+// DelaunayPointers computes the Delaunay triangulation of the pointers,
+// e.g. the result of a quadtree search. The triangulation points are in
+// the same order as the pointers.
+func DelaunayPointers(pointers []orb.Pointer) *Triangulation {
+	points := make(orb.MultiPoint, len(pointers))
+	for i, p := range pointers {
+		points[i] = p.Point()
+	}
+
+	return Delaunay(points)
+}
+
+const epsilon = 1.0 / (1 << 52)
+
+type delaunator struct {
+	points []orb.Point
+
+	triangles []int
+	halfedges []int
+
+	hullPrev  []int
+	hullNext  []int
+	hullTri   []int
+	hullHash  []int
+	hullStart int
+
+	cx, cy float64
+	stack  []int
+}
+
+func (d *delaunator) triangulate() {
+	n := len(d.points)
+	if n < 3 {
+		return
+	}
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	ids := make([]int, n)
+	for i, p := range d.points {
+		minX, minY = math.Min(minX, p[0]), math.Min(minY, p[1])
+		maxX, maxY = math.Max(maxX, p[0]), math.Max(maxY, p[1])
+		ids[i] = i
+	}
+	cx, cy := (minX+maxX)/2, (minY+maxY)/2
+
+	// pick a seed point close to the center
+	i0, i1, i2 := -1, -1, -1
+	minDist := math.Inf(1)
+	for i, p := range d.points {
+		if v := dist(cx, cy, p[0], p[1]); v < minDist {
+			i0, minDist = i, v
+		}
+	}
+	p0 := d.points[i0]
+
+	// find the point closest to the seed
+	minDist = math.Inf(1)
+	for i, p := range d.points {
+		if i == i0 {
+			continue
+		}
+
+		if v := dist(p0[0], p0[1], p[0], p[1]); v < minDist && v > 0 {
+			i1, minDist = i, v
+		}
+	}
+
+	if i1 == -1 {
+		return // all points are the same
+	}
+	p1 := d.points[i1]
+
+	// find the third point which forms the smallest circumcircle with the first two
+	minRadius := math.Inf(1)
+	for i, p := range d.points {
+		if i == i0 || i == i1 {
+			continue
+		}
+
+		if r := circumradius(p0, p1, p); r < minRadius {
+			i2, minRadius = i, r
+		}
+	}
+
+	if math.IsInf(minRadius, 1) || math.IsNaN(minRadius) {
+		return // all points are collinear
+	}
+	p2 := d.points[i2]
+
+	// swap the order of the seed points for clockwise orientation
+	if orient2d(p0, p1, p2) < 0 {
+		i1, i2 = i2, i1
+		p1, p2 = p2, p1
+	}
+
+	d.cx, d.cy = circumcenter(p0, p1, p2)
+
+	dists := make([]float64, n)
+	for i, p := range d.points {
+		dists[i] = dist(p[0], p[1], d.cx, d.cy)
+	}
+
+	// sort the points by distance from the seed triangle circumcenter
+	sort.Slice(ids, func(i, j int) bool {
+		return dists[ids[i]] < dists[ids[j]]
+	})
+
+	// set up the seed triangle as the starting hull
+	hashSize := int(math.Ceil(math.Sqrt(float64(n))))
+	d.hullPrev = make([]int, n)
+	d.hullNext = make([]int, n)
+	d.hullTri = make([]int, n)
+	d.hullHash = make([]int, hashSize)
+	for i := range d.hullHash {
+		d.hullHash[i] = -1
+	}
+
+	d.hullStart = i0
+	d.hullNext[i0], d.hullPrev[i2] = i1, i1
+	d.hullNext[i1], d.hullPrev[i0] = i2, i2
+	d.hullNext[i2], d.hullPrev[i1] = i0, i0
+
+	d.hullTri[i0], d.hullTri[i1], d.hullTri[i2] = 0, 1, 2
+
+	d.hullHash[d.hashKey(p0)] = i0
+	d.hullHash[d.hashKey(p1)] = i1
+	d.hullHash[d.hashKey(p2)] = i2
+
+	maxTriangles := 2*n - 5
+	d.triangles = make([]int, 0, 3*maxTriangles)
+	d.halfedges = make([]int, 0, 3*maxTriangles)
+	d.addTriangle(i0, i1, i2, -1, -1, -1)
+
+	var prev orb.Point
+	for k, i := range ids {
+		p := d.points[i]
+
+		// skip near-duplicate points
+		if k > 0 && math.Abs(p[0]-prev[0]) <= epsilon && math.Abs(p[1]-prev[1]) <= epsilon {
+			continue
+		}
+		prev = p
+
+		// skip seed triangle points
+		if i == i0 || i == i1 || i == i2 {
+			continue
+		}
+
+		// find a visible edge on the convex hull using edge hash
+		start := 0
+		key := d.hashKey(p)
+		for j := 0; j < hashSize; j++ {
+			start = d.hullHash[(key+j)%hashSize]
+			if start != -1 && start != d.hullNext[start] {
+				break
+			}
+		}
+
+		start = d.hullPrev[start]
+		e := start
+		for {
+			q := d.hullNext[e]
+			if orient2d(p, d.points[e], d.points[q]) < 0 {
+				break
+			}
+
+			e = q
+			if e == start {
+				e = -1
+				break
+			}
+		}
+
+		if e == -1 {
+			continue // likely a near-duplicate point; skip it
+		}
+
+		// add the first triangle from the point
+		t := d.addTriangle(e, i, d.hullNext[e], -1, -1, d.hullTri[e])
+
+		// recursively flip triangles from the point until they satisfy the Delaunay condition
+		d.hullTri[i] = d.legalize(t + 2)
+		d.hullTri[e] = t // keep track of boundary triangles on the hull
+
+		// walk forward through the hull, adding more triangles and flipping recursively
+		next := d.hullNext[e]
+		for {
+			q := d.hullNext[next]
+			if orient2d(p, d.points[next], d.points[q]) >= 0 {
+				break
+			}
+
+			t = d.addTriangle(next, i, q, d.hullTri[i], -1, d.hullTri[next])
+			d.hullTri[i] = d.legalize(t + 2)
+			d.hullNext[next] = next // mark as removed
+			next = q
+		}
+
+		// walk backward from the other side, adding more triangles and flipping
+		if e == start {
+			for {
+				q := d.hullPrev[e]
+				if orient2d(p, d.points[q], d.points[e]) >= 0 {
+					break
+				}
+
+				t = d.addTriangle(q, i, e, -1, d.hullTri[e], d.hullTri[q])
+				d.legalize(t + 2)
+				d.hullTri[q] = t
+				d.hullNext[e] = e // mark as removed
+				e = q
+			}
+		}
+
+		// update the hull indices
+		d.hullStart = e
+		d.hullPrev[i] = e
+		d.hullNext[e] = i
+		d.hullPrev[next] = i
+		d.hullNext[i] = next
+
+		// save the two new edges in the hash table
+		d.hullHash[d.hashKey(p)] = i
+		d.hullHash[d.hashKey(d.points[e])] = e
+	}
+}
+
+func (d *delaunator) hashKey(p orb.Point) int {
+	size := len(d.hullHash)
+
+	a := pseudoAngle(p[0]-d.cx, p[1]-d.cy)
+	if math.IsNaN(a) {
+		return 0 // the point is the circumcenter
+	}
+
+	return int(math.Floor(a*float64(size))) % size
+}
+
+// legalize flips the triangles until they satisfy the Delaunay condition.
+//
+//	      pl                    pl
+//	     /||\                  /  \
+//	  al/ || \bl            al/    \a
+//	   /  ||  \              /      \
+//	  /  a||b  \    flip    /___ar___\
+//	p0\   ||   /p1   =>   p0\---bl---/p1
+//	   \  ||  /              \      /
+//	  ar\ || /br             b\    /br
+//	     \||/                  \  /
+//	      pr                    pr
+func (d *delaunator) legalize(a int) int {
+	d.stack = d.stack[:0]
+
+	ar := 0
+	for {
+		b := d.halfedges[a]
+
+		a0 := a - a%3
+		ar = a0 + (a+2)%3
+
+		if b == -1 { // convex hull edge
+			if len(d.stack) == 0 {
+				break
+			}
+
+			a = d.stack[len(d.stack)-1]
+			d.stack = d.stack[:len(d.stack)-1]
+			continue
+		}
+
+		b0 := b - b%3
+		al := a0 + (a+1)%3
+		bl := b0 + (b+2)%3
+
+		p0 := d.triangles[ar]
+		pr := d.triangles[a]
+		pl := d.triangles[al]
+		p1 := d.triangles[bl]
+
+		illegal := inCircleCW(d.points[p0], d.points[pr], d.points[pl], d.points[p1])
+		if illegal {
+			d.triangles[a] = p1
+			d.triangles[b] = p0
+
+			hbl := d.halfedges[bl]
+
+			// edge swapped on the other side of the hull (rare); fix the halfedge reference
+			if hbl == -1 {
+				e := d.hullStart
+				for {
+					if d.hullTri[e] == bl {
+						d.hullTri[e] = a
+						break
+					}
+
+					e = d.hullPrev[e]
+					if e == d.hullStart {
+						break
+					}
+				}
+			}
+
+			d.link(a, hbl)
+			d.link(b, d.halfedges[ar])
+			d.link(ar, bl)
+
+			br := b0 + (b+1)%3
+			d.stack = append(d.stack, br)
+		} else {
+			if len(d.stack) == 0 {
+				break
+			}
+
+			a = d.stack[len(d.stack)-1]
+			d.stack = d.stack[:len(d.stack)-1]
+		}
+	}
+
+	return ar
+}
+
+func (d *delaunator) link(a, b int) {
+	d.halfedges[a] = b
+	if b != -1 {
+		d.halfedges[b] = a
+	}
+}
+
+// addTriangle adds a new triangle given vertex indices and adjacent half-edge ids.
+func (d *delaunator) addTriangle(i0, i1, i2, a, b, c int) int {
+	t := len(d.triangles)
+	d.triangles = append(d.triangles, i0, i1, i2)
+	d.halfedges = append(d.halfedges, -1, -1, -1)
+
+	d.link(t, a)
+	d.link(t+1, b)
+	d.link(t+2, c)
+
+	return t
+}
+
+// pseudoAngle monotonically increases with real angle,
+// but doesn't need expensive trigonometry.
+func pseudoAngle(dx, dy float64) float64 {
+	p := dx / (math.Abs(dx) + math.Abs(dy))
+	if dy > 0 {
+		return (3 - p) / 4
+	}
+
+	return (1 + p) / 4
+}
+
+func dist(ax, ay, bx, by float64) float64 {
+	dx, dy := ax-bx, ay-by
+	return dx*dx + dy*dy
+}
+
+// orient2d is negative if the points are counter-clockwise.
+func orient2d(a, b, c orb.Point) float64 {
+	return (a[1]-c[1])*(b[0]-c[0]) - (a[0]-c[0])*(b[1]-c[1])
+}
+
+// inCircleCW returns true if p is inside the circumcircle
+// of the clockwise triangle abc.
+func inCircleCW(a, b, c, p orb.Point) bool {
+	dx, dy := a[0]-p[0], a[1]-p[1]
+	ex, ey := b[0]-p[0], b[1]-p[1]
+	fx, fy := c[0]-p[0], c[1]-p[1]
+
+	ap := dx*dx + dy*dy
+	bp := ex*ex + ey*ey
+	cp := fx*fx + fy*fy
+
+	return dx*(ey*cp-bp*fy)-dy*(ex*cp-bp*fx)+ap*(ex*fy-ey*fx) < 0
+}
+
+func circumradius(a, b, c orb.Point) float64 {
+	x, y := circumcenterOffset(a, b, c)
+	return x*x + y*y
+}
+
+func circumcenter(a, b, c orb.Point) (float64, float64) {
+	x, y := circumcenterOffset(a, b, c)
+	return a[0] + x, a[1] + y
+}
+
+func circumcenterOffset(a, b, c orb.Point) (float64, float64) {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	ex, ey := c[0]-a[0], c[1]-a[1]
+
+	bl := dx*dx + dy*dy
+	cl := ex*ex + ey*ey
+	d := 0.5 / (dx*ey - dy*ex)
+
+	return (ey*bl - dy*cl) * d, (dx*cl - ex*bl) * d
+}
@@ -0,0 +1,164 @@
+package triangulate
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/quadtree"
+)
+
+func TestDelaunay(t *testing.T) {
+	cases := []struct {
+		name      string
+		points    orb.MultiPoint
+		triangles int
+	}{
+		{
+			name:      "square",
+			points:    orb.MultiPoint{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+			triangles: 2,
+		},
+		{
+			name:      "square with center",
+			points:    orb.MultiPoint{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {1, 1}},
+			triangles: 4,
+		},
+		{
+			name:      "duplicates",
+			points:    orb.MultiPoint{{0, 0}, {1, 0}, {0, 1}, {1, 0}, {0, 0}},
+			triangles: 1,
+		},
+		{
+			name:      "collinear",
+			points:    orb.MultiPoint{{0, 0}, {1, 1}, {2, 2}, {3, 3}},
+			triangles: 0,
+		},
+		{
+			name:      "same point",
+			points:    orb.MultiPoint{{1, 1}, {1, 1}, {1, 1}},
+			triangles: 0,
+		},
+		{
+			name:      "two points",
+			points:    orb.MultiPoint{{0, 0}, {1, 1}},
+			triangles: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tri := Delaunay(tc.points)
+			if l := len(tri.Triangles) / 3; l != tc.triangles {
+				t.Errorf("incorrect number of triangles: %d != %d", l, tc.triangles)
+			}
+
+			for _, p := range tri.MultiPolygon() {
+				if orient(p[0][0], p[0][1], p[0][2]) <= 0 {
+					t.Errorf("triangle should be counter-clockwise: %v", p)
+				}
+			}
+		})
+	}
+}
+
+func TestDelaunay_random(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	points := make(orb.MultiPoint, 1000)
+	for i := range points {
+		points[i] = orb.Point{r.Float64() * 100, r.Float64() * 100}
+	}
+
+	// include some points on a grid to test cocircular points.
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 10; j++ {
+			points = append(points, orb.Point{float64(i) * 10, float64(j) * 10})
+		}
+	}
+
+	tri := Delaunay(points)
+
+	// the triangles should cover the convex hull.
+	sum := 0.0
+	for _, p := range tri.MultiPolygon() {
+		a := orient(p[0][0], p[0][1], p[0][2]) / 2
+		if a <= 0 {
+			t.Errorf("triangle should be counter-clockwise: %v", p)
+		}
+		sum += a
+	}
+
+	hull := convexHullArea(points)
+	if math.Abs(sum-hull) > 1e-6 {
+		t.Errorf("incorrect area: %v != %v", sum, hull)
+	}
+
+	// no point should be inside a triangle's circumcircle.
+	for i := 0; i < len(tri.Triangles); i += 3 {
+		a, b, c := tri.Points[tri.Triangles[i]], tri.Points[tri.Triangles[i+1]], tri.Points[tri.Triangles[i+2]]
+		for _, p := range points {
+			if inCircle(a, b, c, p) {
+				t.Fatalf("%v is inside the circumcircle of %v %v %v", p, a, b, c)
+			}
+		}
+	}
+}
+
+func TestDelaunayPointers(t *testing.T) {
+	qt := quadtree.New(orb.Bound{Max: orb.Point{10, 10}})
+	qt.Add(orb.Point{1, 1})
+	qt.Add(orb.Point{5, 1})
+	qt.Add(orb.Point{3, 4})
+
+	pointers := qt.InBound(nil, qt.Bound())
+
+	tri := DelaunayPointers(pointers)
+	if l := len(tri.Triangles); l != 3 {
+		t.Errorf("incorrect number of triangles: %d", l/3)
+	}
+
+	for i, p := range pointers {
+		if !tri.Points[i].Equal(p.Point()) {
+			t.Errorf("points should be in the same order")
+		}
+	}
+}
+
+func convexHullArea(points orb.MultiPoint) float64 {
+	// gift wrapping, fine for the test sizes.
+	start := 0
+	for i, p := range points {
+		if p[0] < points[start][0] || (p[0] == points[start][0] && p[1] < points[start][1]) {
+			start = i
+		}
+	}
+
+	hull := orb.Ring{points[start]}
+	current := start
+	for {
+		next := (current + 1) % len(points)
+		for i, p := range points {
+			o := orient(points[current], points[next], p)
+			if o < 0 || (o == 0 && dist(points[current][0], points[current][1], p[0], p[1]) >
+				dist(points[current][0], points[current][1], points[next][0], points[next][1])) {
+				next = i
+			}
+		}
+
+		current = next
+		if current == start {
+			break
+		}
+		hull = append(hull, points[current])
+	}
+
+	sum := 0.0
+	for i := range hull {
+		a, b := hull[i], hull[(i+1)%len(hull)]
+		sum += a[0]*b[1] - b[0]*a[1]
+	}
+
+	return sum / 2
+}
@@ -0,0 +1,58 @@
+// Package triangulate triangulates polygons, with holes, using ear clipping
+// or a constrained Delaunay triangulation.
+package triangulate
+
+import (
+	"github.com/dadadamarine/orb"
+)
+
+// A Triangulation is a set of triangles over the flattened points
+// of the rings of the input polygons.
+type Triangulation struct {
+	// Points are the points of all the rings, in order, without
+	// the repeated closing point of each ring.
+	Points []orb.Point
+
+	// Triangles are indexes into Points, every three indexes
+	// form a counter-clockwise triangle.
+	Triangles []int
+}
+
+// MultiPolygon returns the triangles as polygons with a single closed ring.
+func (t *Triangulation) MultiPolygon() orb.MultiPolygon {
+	mp := make(orb.MultiPolygon, 0, len(t.Triangles)/3)
+	for i := 0; i+2 < len(t.Triangles); i += 3 {
+		a, b, c := t.Points[t.Triangles[i]], t.Points[t.Triangles[i+1]], t.Points[t.Triangles[i+2]]
+		mp = append(mp, orb.Polygon{{a, b, c, a}})
+	}
+
+	return mp
+}
+
+// addPolygon flattens the polygon points and adds the triangles
+// computed by the triangulator.
+func (t *Triangulation) addPolygon(p orb.Polygon, triangulator func([]orb.Point, []int) []int) {
+	if len(p) == 0 {
+		return
+	}
+
+	offset := len(t.Points)
+
+	var points []orb.Point
+	var holes []int
+	for i, r := range p {
+		if len(r) > 1 && r.Closed() {
+			r = r[:len(r)-1]
+		}
+
+		if i > 0 {
+			holes = append(holes, len(points))
+		}
+		points = append(points, r...)
+	}
+
+	t.Points = append(t.Points, points...)
+	for _, i := range triangulator(points, holes) {
+		t.Triangles = append(t.Triangles, i+offset)
+	}
+}
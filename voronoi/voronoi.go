@@ -0,0 +1,143 @@
+// Package voronoi computes Voronoi diagrams, the area closest to each of
+// a set of points, using the Delaunay triangulation of the points.
+package voronoi
+
+import (
+	"math"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/clip"
+	"github.com/dadadamarine/orb/triangulate"
+)
+
+// Polygons computes the Voronoi cell of each of the points clipped to the bound.
+// The cells are counter-clockwise and in the same order as the points. Duplicate
+// points have the same cell. Cells outside of the bound will be nil.
+func Polygons(points orb.MultiPoint, bound orb.Bound) []orb.Polygon {
+	if len(points) == 0 {
+		return nil
+	}
+
+	// only the first of any duplicate points is used
+	first := make([]int, len(points))
+	seen := make(map[orb.Point]int, len(points))
+	for i, p := range points {
+		if j, ok := seen[p]; ok {
+			first[i] = j
+		} else {
+			seen[p] = i
+			first[i] = i
+		}
+	}
+
+	neighbors := delaunayNeighbors(points, first)
+
+	// start with a box around everything so points outside
+	// of the bound can still clip the cells inside.
+	box := bound.Union(points.Bound())
+	box = box.Pad(math.Max(box.Max[0]-box.Min[0], box.Max[1]-box.Min[1]) + 1)
+
+	cells := make([]orb.Polygon, len(points))
+	for i, p := range points {
+		if first[i] != i {
+			cells[i] = cells[first[i]]
+			continue
+		}
+
+		ring := orb.Ring{
+			box.Min, {box.Max[0], box.Min[1]},
+			box.Max, {box.Min[0], box.Max[1]},
+		}
+
+		for _, j := range neighbors[i] {
+			ring = clipHalfPlane(ring, p, points[j])
+		}
+
+		if len(ring) < 3 {
+			continue
+		}
+
+		ring = append(ring, ring[0])
+		cells[i] = clip.Polygon(bound, orb.Polygon{ring})
+	}
+
+	return cells
+}
+
+// PointerPolygons computes the Voronoi cell of each of the pointers,
+// e.g. the result of a quadtree search, clipped to the bound.
+func PointerPolygons(pointers []orb.Pointer, bound orb.Bound) []orb.Polygon {
+	points := make(orb.MultiPoint, len(pointers))
+	for i, p := range pointers {
+		points[i] = p.Point()
+	}
+
+	return Polygons(points, bound)
+}
+
+// delaunayNeighbors returns the neighbors of each point in the Delaunay
+// triangulation. If there are no triangles, e.g. the points are collinear,
+// all the other points are used.
+func delaunayNeighbors(points orb.MultiPoint, first []int) [][]int {
+	neighbors := make([][]int, len(points))
+
+	tri := triangulate.Delaunay(points)
+	if len(tri.Triangles) == 0 {
+		for i := range points {
+			if first[i] != i {
+				continue
+			}
+
+			for j := range points {
+				if first[j] == j && j != i {
+					neighbors[i] = append(neighbors[i], j)
+				}
+			}
+		}
+
+		return neighbors
+	}
+
+	// each interior edge is in two triangles, only add them once.
+	edges := make(map[[2]int]bool, len(tri.Triangles))
+	for i, a := range tri.Triangles {
+		b := tri.Triangles[i-i%3+(i+1)%3]
+		if edges[[2]int{b, a}] {
+			continue
+		}
+		edges[[2]int{a, b}] = true
+
+		neighbors[a] = append(neighbors[a], b)
+		neighbors[b] = append(neighbors[b], a)
+	}
+
+	return neighbors
+}
+
+// clipHalfPlane clips the open ring to the half of the plane that is
+// closer to p than to q.
+func clipHalfPlane(ring orb.Ring, p, q orb.Point) orb.Ring {
+	n := orb.Point{q[0] - p[0], q[1] - p[1]}
+	m := orb.Point{(p[0] + q[0]) / 2, (p[1] + q[1]) / 2}
+
+	side := func(a orb.Point) float64 {
+		return (a[0]-m[0])*n[0] + (a[1]-m[1])*n[1]
+	}
+
+	result := make(orb.Ring, 0, len(ring)+1)
+	for k := range ring {
+		a, b := ring[k], ring[(k+1)%len(ring)]
+		fa, fb := side(a), side(b)
+
+		if fa <= 0 {
+			result = append(result, a)
+		}
+
+		if (fa <= 0) != (fb <= 0) {
+			t := fa / (fa - fb)
+			result = append(result, orb.Point{a[0] + t*(b[0]-a[0]), a[1] + t*(b[1]-a[1])})
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,133 @@
+package voronoi
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/dadadamarine/orb"
+	"github.com/dadadamarine/orb/planar"
+	"github.com/dadadamarine/orb/quadtree"
+)
+
+func TestPolygons(t *testing.T) {
+	bound := orb.Bound{Max: orb.Point{4, 4}}
+	points := orb.MultiPoint{{1, 1}, {3, 1}, {3, 3}, {1, 3}}
+
+	cells := Polygons(points, bound)
+
+	expected := []orb.Bound{
+		{Min: orb.Point{0, 0}, Max: orb.Point{2, 2}},
+		{Min: orb.Point{2, 0}, Max: orb.Point{4, 2}},
+		{Min: orb.Point{2, 2}, Max: orb.Point{4, 4}},
+		{Min: orb.Point{0, 2}, Max: orb.Point{2, 4}},
+	}
+
+	for i, c := range cells {
+		if !c.Bound().Equal(expected[i]) {
+			t.Errorf("incorrect cell %d: %v", i, c)
+		}
+
+		if a := planar.Area(c); math.Abs(a-4) > 1e-9 {
+			t.Errorf("incorrect area %d: %v", i, a)
+		}
+	}
+}
+
+func TestPolygons_degenerate(t *testing.T) {
+	bound := orb.Bound{Max: orb.Point{4, 4}}
+
+	// collinear points split the bound into strips.
+	cells := Polygons(orb.MultiPoint{{1, 2}, {2, 2}, {3, 2}}, bound)
+	areas := []float64{6, 4, 6}
+	for i, c := range cells {
+		if a := planar.Area(c); math.Abs(a-areas[i]) > 1e-9 {
+			t.Errorf("incorrect area %d: %v", i, a)
+		}
+	}
+
+	// a single point is the whole bound.
+	cells = Polygons(orb.MultiPoint{{1, 1}}, bound)
+	if !cells[0].Bound().Equal(bound) {
+		t.Errorf("incorrect cell: %v", cells[0])
+	}
+
+	// duplicates share a cell.
+	cells = Polygons(orb.MultiPoint{{1, 1}, {3, 3}, {1, 1}}, bound)
+	if !cells[0].Equal(cells[2]) || planar.Area(cells[0]) != 8 {
+		t.Errorf("incorrect duplicate cells: %v", cells)
+	}
+
+	// cells outside of the bound are nil.
+	cells = Polygons(orb.MultiPoint{{1, 1}, {2, 1}, {1, 2}, {10, 10}}, bound)
+	if cells[3] != nil {
+		t.Errorf("cell should be nil: %v", cells[3])
+	}
+
+	if v := Polygons(nil, bound); v != nil {
+		t.Errorf("should be nil: %v", v)
+	}
+}
+
+func TestPolygons_random(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	bound := orb.Bound{Min: orb.Point{10, 10}, Max: orb.Point{90, 90}}
+
+	points := make(orb.MultiPoint, 500)
+	for i := range points {
+		points[i] = orb.Point{r.Float64() * 100, r.Float64() * 100}
+	}
+
+	cells := Polygons(points, bound)
+
+	// the cells should cover the bound.
+	sum := 0.0
+	for _, c := range cells {
+		if c == nil {
+			continue
+		}
+
+		a := planar.Area(c)
+		if a <= 0 {
+			t.Errorf("cell should be counter-clockwise: %v", c)
+		}
+		sum += a
+	}
+
+	if math.Abs(sum-6400) > 1e-6 {
+		t.Errorf("incorrect total area: %v", sum)
+	}
+
+	// random points in the bound should be in the cell of the closest point.
+	for k := 0; k < 500; k++ {
+		p := orb.Point{10 + r.Float64()*80, 10 + r.Float64()*80}
+
+		closest := 0
+		for i := range points {
+			if planar.DistanceSquared(p, points[i]) < planar.DistanceSquared(p, points[closest]) {
+				closest = i
+			}
+		}
+
+		if !planar.PolygonContains(cells[closest], p) {
+			t.Errorf("%v should be in the cell of %v", p, points[closest])
+		}
+	}
+}
+
+func TestPointerPolygons(t *testing.T) {
+	bound := orb.Bound{Max: orb.Point{4, 4}}
+
+	qt := quadtree.New(bound)
+	qt.Add(orb.Point{1, 2})
+	qt.Add(orb.Point{3, 2})
+
+	pointers := qt.InBound(nil, bound)
+	cells := PointerPolygons(pointers, bound)
+
+	for i, p := range pointers {
+		if !planar.PolygonContains(cells[i], p.Point()) {
+			t.Errorf("cell should contain the point: %v", cells[i])
+		}
+	}
+}